@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/uiansol/zentube/internal/adapters/database"
+)
+
+const dbUsage = `usage:
+  zentube db backup [--out FILE]
+  zentube db export [--format jsonl|csv] [--table NAME] [--out FILE]
+  zentube db restore --from FILE    (stop the server first)
+  zentube db vacuum                 (stop the server first)`
+
+// runDB implements `zentube db backup|export|restore|vacuum`
+func runDB(args []string) error {
+	if len(args) == 0 {
+		return errors.New(dbUsage)
+	}
+
+	cfg, err := loadCommandConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	switch args[0] {
+	case "backup":
+		return runDBBackup(ctx, cfg.Database.Path, cfg.Database.Backup.Dir, args[1:])
+	case "export":
+		return runDBExport(ctx, cfg.Database.Path, args[1:])
+	case "restore":
+		return runDBRestore(ctx, cfg.Database.Path, args[1:])
+	case "vacuum":
+		return runDBVacuum(ctx, cfg.Database.Path)
+	default:
+		return errors.New(dbUsage)
+	}
+}
+
+// runDBBackup writes a verified snapshot while the server may still be running
+func runDBBackup(ctx context.Context, dbPath, backupDir string, args []string) error {
+	flags := flag.NewFlagSet("db backup", flag.ContinueOnError)
+	out := flags.String("out", "", "backup file (default: timestamped file in database.backup.dir)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	path := *out
+	if path == "" {
+		var err error
+		path, err = database.NewBackupStore(dbPath, backupDir).CreateBackup(ctx)
+		if err != nil {
+			return err
+		}
+	} else {
+		db, err := database.Open(dbPath)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		if err := database.BackupTo(ctx, db, path); err != nil {
+			return err
+		}
+		if err := database.Verify(ctx, path); err != nil {
+			return fmt.Errorf("backup failed verification: %w", err)
+		}
+	}
+
+	fmt.Println("backup written to " + path)
+	return nil
+}
+
+// runDBExport writes a user-data table as JSON lines or CSV
+func runDBExport(ctx context.Context, dbPath string, args []string) error {
+	flags := flag.NewFlagSet("db export", flag.ContinueOnError)
+	format := flags.String("format", database.FormatJSONL, "output format: jsonl or csv")
+	table := flags.String("table", "search_history", fmt.Sprintf("table to export, one of %v", database.ExportTables))
+	out := flags.String("out", "", "output file (default: stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	db, err := database.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	count, err := database.Export(ctx, db, *table, *format, w)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d rows from %s\n", count, *table)
+	return nil
+}
+
+// runDBRestore replaces the database with a verified backup
+func runDBRestore(ctx context.Context, dbPath string, args []string) error {
+	flags := flag.NewFlagSet("db restore", flag.ContinueOnError)
+	from := flags.String("from", "", "backup file to restore")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *from == "" {
+		return errors.New("--from is required")
+	}
+
+	src, err := filepath.Abs(*from)
+	if err != nil {
+		return err
+	}
+
+	safetyCopy, err := database.Restore(ctx, src, dbPath)
+	if safetyCopy != "" {
+		fmt.Println("previous database saved to " + safetyCopy)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("restored " + dbPath + " from " + src)
+	return nil
+}
+
+// runDBVacuum converts an existing database to incremental auto-vacuum
+// The server's scheduled optimize never runs a full VACUUM itself
+func runDBVacuum(ctx context.Context, dbPath string) error {
+	db, err := database.Open(dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	converted, err := database.EnableIncrementalVacuum(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	if !converted {
+		fmt.Println(dbPath + " already uses incremental auto-vacuum")
+		return nil
+	}
+	fmt.Println("vacuumed " + dbPath + " and enabled incremental auto-vacuum")
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"syscall"
 	"time"
 
@@ -17,20 +18,65 @@ import (
 	"github.com/uiansol/zentube/internal/adapters/http/handlers"
 	"github.com/uiansol/zentube/internal/adapters/http/middleware"
 	"github.com/uiansol/zentube/internal/adapters/http/routes"
+	"github.com/uiansol/zentube/internal/adapters/mail"
+	"github.com/uiansol/zentube/internal/adapters/webhook"
 	"github.com/uiansol/zentube/internal/adapters/youtube"
+	"github.com/uiansol/zentube/internal/cache"
 	"github.com/uiansol/zentube/internal/config"
+	"github.com/uiansol/zentube/internal/ports"
 	"github.com/uiansol/zentube/internal/usecases"
 	"github.com/uiansol/zentube/web/templates/pages"
 	"golang.org/x/time/rate"
 )
 
 func main() {
-	if err := run(); err != nil {
+	if err := dispatch(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// dispatch runs the requested subcommand (the server by default)
+func dispatch(args []string) error {
+	if len(args) == 0 {
+		return run()
+	}
+
+	switch args[0] {
+	case "serve":
+		return run()
+	case "migrate":
+		return runMigrate(args[1:])
+	case "db":
+		return runDB(args[1:])
+	default:
+		return fmt.Errorf("unknown command %q (usage: zentube [serve|migrate|db])", args[0])
+	}
+}
+
+// loadCommandConfig loads configuration for maintenance commands
+// Unlike run, it doesn't require the YouTube API key
+func loadCommandConfig() (*config.Config, error) {
+	// .env is optional for maintenance commands
+	_ = config.LoadEnv()
+
+	cfg, err := config.LoadConfig("configs/config.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if cfg.Database.Path == "" {
+		return nil, fmt.Errorf("database.path cannot be empty")
+	}
+
+	// Ensure the database directory exists
+	if err := os.MkdirAll(filepath.Dir(cfg.Database.Path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	return cfg, nil
+}
+
 func run() error {
 	// Determine environment and initialize structured logger
 	env := config.GetEnvironment()
@@ -81,25 +127,83 @@ func run() error {
 	}
 
 	// Initialize database
-	// Ensure the database directory exists
-	dbDir := filepath.Dir(cfg.Database.Path)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		return fmt.Errorf("failed to create database directory: %w", err)
-	}
-
-	dbRepo, err := database.NewSQLiteRepository(cfg.Database.Path)
+	store, err := openStorage(cfg, logger)
 	if err != nil {
-		return fmt.Errorf("failed to initialize database: %w", err)
+		return err
 	}
 
-	logger.Info("database initialized",
-		slog.String("path", cfg.Database.Path),
+	// Initialize use cases
+	historyWriter := usecases.NewHistoryWriter(store.history, store.videos, usecases.HistoryWriterConfig{
+		QueueSize:     cfg.Database.HistoryWriter.QueueSize,
+		BatchSize:     cfg.Database.HistoryWriter.BatchSize,
+		FlushInterval: cfg.Database.HistoryWriter.FlushInterval,
+		Overflow:      cfg.Database.HistoryWriter.Overflow,
+		BlockTimeout:  cfg.Database.HistoryWriter.BlockTimeout,
+	}, logger.With(slog.String("component", "history_writer")))
+
+	searchCache := cache.NewCache(cfg.Cache.MaxEntries, cfg.Cache.TTL)
+	searchVideos := usecases.NewSearchVideosWithCache(ytClient, store.history, searchCache, usecases.CachePolicy{
+		ResultsTTL: cfg.Cache.TTL,
+		EmptyTTL:   cfg.Cache.EmptyTTL,
+		ErrorTTL:   cfg.Cache.ErrorTTL,
+		ErrorCodes: cfg.Cache.ErrorCodes,
+	}).WithHistoryWriter(historyWriter)
+	watchHistory := usecases.NewWatchHistory(store.watchEvents, store.videoProgress)
+	profileLimits := make(map[string]usecases.BudgetLimits, len(cfg.Budget.Profiles))
+	for name, limits := range cfg.Budget.Profiles {
+		profileLimits[name] = usecases.BudgetLimits{Daily: limits.Daily, Weekly: limits.Weekly}
+	}
+	budgets := usecases.NewBudgets(store.watchEvents,
+		usecases.BudgetLimits{Daily: cfg.Budget.Daily, Weekly: cfg.Budget.Weekly}, profileLimits)
+	budgetHandler := handlers.NewBudgetHandler(budgets, usecases.NewFocusSessions(store.focusSessions, cfg.Budget.FocusSession))
+	searchIntents := usecases.NewSearchIntents(store.intents, cfg.Intents.Enabled)
+	searchVideos.WithBudget(budgets).WithIntents(searchIntents)
+	ytHandler := handlers.NewYouTubeHandler(searchVideos, watchHistory, budgetHandler, searchIntents, cfg.YouTube.MaxResults)
+	healthHandler := handlers.NewHealthHandler(store.pinger, logger)
+	historyHandler := handlers.NewHistoryHandler(
+		usecases.NewFindSearchHistory(store.history),
+		usecases.NewListSearchHistory(store.history),
+		usecases.NewDeleteSearchHistory(store.history),
 	)
+	insightsHandler := handlers.NewInsightsHandler(usecases.NewGetSearchInsights(store.history))
+	savedHandler := handlers.NewSavedHandler(usecases.NewSavedVideos(store.saved))
+	watchHandler := handlers.NewWatchHandler(watchHistory)
+	collectionsHandler := handlers.NewCollectionsHandler(usecases.NewCollections(store.collections, store.videos), cfg.Syndication.Enabled)
 
-	// Initialize use cases
-	searchVideos := usecases.NewSearchVideos(ytClient, dbRepo)
-	ytHandler := handlers.NewYouTubeHandler(searchVideos, cfg.YouTube.MaxResults)
-	healthHandler := handlers.NewHealthHandler(dbRepo.DB(), logger)
+	// Channel uploads come from the free RSS feeds unless the Data API is configured
+	var channelSource ports.ChannelSource = youtube.NewRSSChannelSource(&http.Client{Timeout: 10 * time.Second}, youtube.ChannelFeedURL)
+	if cfg.Subscriptions.Source == config.SourceAPI {
+		channelSource = ytClient
+	}
+	subscriptions := usecases.NewSubscriptions(store.subscriptions, channelSource,
+		logger.With(slog.String("component", "subscriptions")))
+	feedHandler := handlers.NewFeedHandler(subscriptions)
+	savedSearches := usecases.NewSavedSearches(store.savedSearches, ytClient, cfg.YouTube.MaxResults,
+		logger.With(slog.String("component", "saved_searches")))
+	savedSearches.WithBudget(budgets)
+	savedSearchesHandler := handlers.NewSavedSearchesHandler(savedSearches, cfg.Syndication.Enabled)
+
+	// Webhooks are queued by polls and refreshes, then sent by the dispatcher
+	endpoints := make([]usecases.WebhookEndpoint, 0, len(cfg.Webhooks.Endpoints))
+	for _, e := range cfg.Webhooks.Endpoints {
+		endpoints = append(endpoints, usecases.WebhookEndpoint{
+			Name:   e.Name,
+			URL:    e.URL,
+			Secret: e.Secret,
+			Format: usecases.WebhookFormat(e.Format),
+			Events: e.Events,
+		})
+	}
+	webhooks := usecases.NewWebhooks(store.webhooks, webhook.NewHTTPSender(&http.Client{Timeout: 10 * time.Second}), endpoints,
+		usecases.WebhookRetryPolicy{
+			MaxAttempts: cfg.Webhooks.MaxAttempts,
+			Backoff:     cfg.Webhooks.Backoff,
+			MaxBackoff:  cfg.Webhooks.MaxBackoff,
+		}, cfg.App.BaseURL, logger.With(slog.String("component", "webhooks")))
+	if cfg.WebhooksEnabled() {
+		subscriptions.WithNotifier(webhooks)
+		savedSearches.WithNotifier(webhooks)
+	}
 
 	// Setup Gin router (disable default middleware, we'll add our own)
 	// Set Gin mode based on environment
@@ -119,12 +223,31 @@ func run() error {
 	r.Use(middleware.Middleware(logger))                    // Structured logging
 	r.Use(middleware.SecurityHeaders())                     // Security headers
 	r.Use(middleware.RateLimit(rate.Limit(10), 20, logger)) // 10 req/sec, burst 20
+	r.Use(middleware.Profile(budgets.HasProfile))           // Viewing profile for budgets
 
 	// Register routes
 	routes.RegisterRoutes(r, ytHandler, healthHandler)
+	routes.RegisterHistoryRoutes(r, historyHandler)
+	routes.RegisterInsightsRoutes(r, insightsHandler)
+	routes.RegisterSavedRoutes(r, savedHandler)
+	routes.RegisterWatchRoutes(r, watchHandler)
+	routes.RegisterBudgetRoutes(r, budgetHandler)
+	routes.RegisterIntentRoutes(r, handlers.NewIntentsHandler(searchIntents))
+	routes.RegisterCollectionRoutes(r, collectionsHandler)
+	routes.RegisterFeedRoutes(r, feedHandler)
+	routes.RegisterSavedSearchRoutes(r, savedSearchesHandler)
+	if cfg.Syndication.Enabled {
+		routes.RegisterSyndicationRoutes(r, handlers.NewSyndicationHandler(
+			usecases.NewSyndication(store.savedSearches, store.collections, cfg.App.Name), cfg.App.BaseURL))
+	}
+	if cfg.AdminEnabled() {
+		routes.RegisterAdminRoutes(r, handlers.NewAdminHandler(searchCache, historyWriter), cfg.Admin.Token)
+		routes.RegisterAdminWebhookRoutes(r, handlers.NewWebhooksHandler(webhooks), cfg.Admin.Token)
+		logger.Info("admin routes enabled", slog.String("path", "/admin"))
+	}
 
 	// Ensure templates compile (helps catch errors early)
-	_ = pages.HomePage("", nil)
+	_ = pages.HomePage("", nil, false, nil, nil)
 
 	// Determine port
 	port := cfg.App.Port
@@ -142,6 +265,141 @@ func run() error {
 		MaxHeaderBytes: 1 << 20, // 1 MB
 	}
 
+	// Background jobs share a context that is cancelled on shutdown
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	var jobs sync.WaitGroup
+
+	// Start history writer (drains its queue when jobsCtx is cancelled)
+	// writerDone is closed once Run returns; the database must stay open until then
+	writerDone := make(chan struct{})
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		defer close(writerDone)
+		historyWriter.Run(jobsCtx)
+	}()
+
+	// Start cache warmer (pre-fetches popular queries from search history)
+	if cfg.Cache.Warmer.Enabled {
+		warmer := usecases.NewCacheWarmer(searchVideos, store.history, usecases.CacheWarmerConfig{
+			TopN:         cfg.Cache.Warmer.TopN,
+			Window:       cfg.Cache.Warmer.Window,
+			Interval:     cfg.Cache.Warmer.Interval,
+			RefreshAhead: cfg.Cache.Warmer.RefreshAhead,
+			QuotaPerRun:  cfg.Cache.Warmer.QuotaPerRun,
+			MaxResults:   cfg.YouTube.MaxResults,
+		}, logger.With(slog.String("component", "cache_warmer")))
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			warmer.Run(jobsCtx)
+		}()
+	}
+
+	// Start subscription polling (fetches new uploads of followed channels)
+	poller := usecases.NewSubscriptionPoller(subscriptions, cfg.Subscriptions.PollInterval,
+		logger.With(slog.String("component", "subscription_poller")))
+
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		poller.Run(jobsCtx)
+	}()
+
+	// Start saved search refresh (re-runs due searches within the per-run call limit)
+	refresher := usecases.NewSavedSearchRefresher(savedSearches, usecases.SavedSearchRefresherConfig{
+		Interval:     cfg.SavedSearches.Interval,
+		RefreshEvery: cfg.SavedSearches.RefreshEvery,
+		CallsPerRun:  cfg.SavedSearches.CallsPerRun,
+	}, logger.With(slog.String("component", "saved_search_refresher")))
+
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		refresher.Run(jobsCtx)
+	}()
+
+	// Start webhook delivery (sends queued events, retries failures and prunes the log)
+	if cfg.WebhooksEnabled() {
+		dispatcher := usecases.NewWebhookDispatcher(webhooks, usecases.WebhookDispatcherConfig{
+			Interval:  cfg.Webhooks.Interval,
+			BatchSize: 50, // Deliveries attempted per pass; the rest wait for the next one
+			Keep:      cfg.Webhooks.Keep,
+		}, logger.With(slog.String("component", "webhook_dispatcher")))
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			dispatcher.Run(jobsCtx)
+		}()
+	}
+
+	// Start email digests (daily or weekly summary of new videos)
+	if cfg.Digest.Enabled {
+		var transport mail.Transport = mail.NewSMTPTransport(mail.SMTPConfig{
+			Host:     cfg.Digest.SMTP.Host,
+			Port:     cfg.Digest.SMTP.Port,
+			Username: cfg.Digest.SMTP.Username,
+			Password: cfg.Digest.SMTP.Password,
+			Security: cfg.Digest.SMTP.Security,
+		})
+		if cfg.Digest.DryRun {
+			transport = mail.NewEMLWriter(cfg.Digest.DryRunDir)
+			logger.Info("digest dry run: writing .eml files instead of sending", slog.String("dir", cfg.Digest.DryRunDir))
+		}
+		digests := usecases.NewDigests(store.subscriptions, store.savedSearches, store.digests,
+			mail.NewDigestMailer(transport, cfg.Digest.From, cfg.Digest.To, cfg.App.Name, cfg.App.BaseURL))
+		digestJob := usecases.NewDigestJob(digests, usecases.DigestJobConfig{
+			Interval:  cfg.Digest.Interval,
+			Every:     cfg.Digest.Every(),
+			SendEmpty: cfg.Digest.SendEmpty,
+		}, logger.With(slog.String("component", "digest")))
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			digestJob.Run(jobsCtx)
+		}()
+	}
+
+	// Start search history retention (prunes, rolls up and vacuums in batches)
+	if ret := cfg.Database.Retention; ret.Enabled() && store.sqlite != nil {
+		retention := usecases.NewHistoryRetention(store.sqlite, usecases.HistoryRetentionConfig{
+			MaxAge:           ret.MaxAge,
+			MaxRows:          ret.MaxRows,
+			BatchSize:        ret.BatchSize,
+			BatchPause:       ret.BatchPause,
+			Rollup:           ret.Rollup,
+			Interval:         ret.Interval,
+			OptimizeInterval: ret.OptimizeInterval,
+		}, logger.With(slog.String("component", "history_retention")))
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			retention.Run(jobsCtx)
+		}()
+	}
+
+	// Start scheduled backups (VACUUM INTO, rotated)
+	if cfg.Database.Backup.Interval > 0 && store.sqlite != nil {
+		backups := usecases.NewBackupJob(
+			database.NewBackupStore(cfg.Database.Path, cfg.Database.Backup.Dir),
+			cfg.Database.Backup.Interval,
+			cfg.Database.Backup.Keep,
+			logger.With(slog.String("component", "backup")),
+		)
+
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			backups.Run(jobsCtx)
+		}()
+	}
+
 	// Start server in a goroutine
 	go func() {
 		logger.Info("server started",
@@ -170,10 +428,38 @@ func run() error {
 		logger.Error("server forced to shutdown", slog.Any("error", err))
 	}
 
-	// Now it's safe to close the database (all HTTP handlers have completed)
-	logger.Info("closing database connection")
-	if err := dbRepo.Close(); err != nil {
-		logger.Error("error closing database", slog.Any("error", err))
+	// Stop background jobs before closing the database they depend on
+	// They get their own 10 seconds, starting once the HTTP server has stopped,
+	// so a slow server shutdown doesn't cut the history writer's drain short
+	// The history writer flushes queued searches before it returns
+	stopJobs()
+	stopCtx, cancelStop := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelStop()
+	jobsDone := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		close(jobsDone)
+	}()
+	select {
+	case <-jobsDone:
+	case <-stopCtx.Done():
+		logger.Warn("background jobs did not stop in time",
+			slog.Int("history_queued", historyWriter.Stats().Queued),
+		)
+	}
+
+	// Closing the database under a draining history writer would fail its last
+	// writes, so the database is only closed once the writer has returned
+	select {
+	case <-writerDone:
+		logger.Info("closing database connection")
+		if err := store.close(); err != nil {
+			logger.Error("error closing database", slog.Any("error", err))
+		}
+	default:
+		logger.Error("history writer is still draining, leaving the database open",
+			slog.Any("stats", historyWriter.Stats()),
+		)
 	}
 
 	logger.Info("server exited gracefully")
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/uiansol/zentube/internal/adapters/database"
+)
+
+const migrateUsage = "usage: zentube migrate <up|down|status> [--dry-run] [--steps N]"
+
+// runMigrate implements `zentube migrate up|down|status`
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	action := args[0]
+
+	flags := flag.NewFlagSet("migrate "+action, flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print migrations that would run without applying them")
+	steps := flags.Int("steps", 1, "number of migrations to revert (down only)")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	cfg, err := loadCommandConfig()
+	if err != nil {
+		return err
+	}
+
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	migrator, err := database.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	switch action {
+	case "up":
+		migrations, err := migrator.Up(ctx, *dryRun)
+		printMigrations("apply", migrations, *dryRun)
+		return err
+	case "down":
+		if *steps < 1 {
+			return errors.New("--steps must be at least 1")
+		}
+		migrations, err := migrator.Down(ctx, *steps, *dryRun)
+		printMigrations("revert", migrations, *dryRun)
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		printStatus(statuses)
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// printMigrations reports which migrations were (or would be) applied or reverted
+func printMigrations(verb string, migrations []database.Migration, dryRun bool) {
+	if len(migrations) == 0 {
+		fmt.Println("nothing to " + verb)
+		return
+	}
+
+	prefix := verb + "ed"
+	if verb == "apply" {
+		prefix = "applied"
+	}
+	if dryRun {
+		prefix = "would " + verb
+	}
+
+	for _, m := range migrations {
+		fmt.Printf("%s %04d_%s\n", prefix, m.Version, m.Name)
+	}
+}
+
+// printStatus prints a table of migrations and their applied state
+func printStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range statuses {
+		status, appliedAt := "pending", "-"
+		if s.Applied {
+			status, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, status, appliedAt)
+	}
+	w.Flush()
+}
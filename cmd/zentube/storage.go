@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/uiansol/zentube/internal/adapters/database"
+	"github.com/uiansol/zentube/internal/adapters/http/handlers"
+	"github.com/uiansol/zentube/internal/adapters/memory"
+	"github.com/uiansol/zentube/internal/config"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// historyStore is what the server needs from a search history backend
+type historyStore interface {
+	ports.SearchHistoryRepository
+	ports.SearchHistoryFullText
+	ports.SearchAnalytics
+}
+
+// storage holds the adapters provided by the configured database backend
+type storage struct {
+	history       historyStore
+	videos        ports.VideoRepository // nil with the memory backend
+	saved         ports.SavedVideoRepository
+	collections   ports.CollectionRepository
+	subscriptions ports.SubscriptionRepository
+	savedSearches ports.SavedSearchRepository
+	webhooks      ports.WebhookDeliveryRepository
+	digests       ports.DigestRepository
+	watchEvents   ports.WatchEventRepository
+	videoProgress ports.VideoProgressRepository
+	focusSessions ports.FocusSessionRepository
+	intents       ports.SearchIntentRepository
+	sqlite        *database.SQLiteRepository // nil with the memory backend (no retention or backups)
+	pinger        handlers.Pinger
+	close         func() error
+}
+
+// openStorage opens the database backend selected by database.backend
+func openStorage(cfg *config.Config, logger *slog.Logger) (*storage, error) {
+	if cfg.Database.Backend == config.BackendMemory {
+		return openMemoryStorage(logger)
+	}
+
+	// Ensure the database directory exists
+	dbDir := filepath.Dir(cfg.Database.Path)
+	if err := os.MkdirAll(dbDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	dbRepo, err := database.NewSQLiteRepository(cfg.Database.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	logger.Info("database initialized",
+		slog.String("path", cfg.Database.Path),
+		slog.String("driver", database.DriverName),
+	)
+
+	store := sqliteStorage(dbRepo)
+	store.history = dbRepo
+	store.videos = dbRepo
+	store.sqlite = dbRepo
+	store.pinger = dbRepo.DB()
+	store.close = dbRepo.Close
+	return store, nil
+}
+
+// openMemoryStorage keeps search history in memory
+// Only search history has an in-memory adapter; every other feature runs on a
+// throwaway SQLite database in a temporary directory that is removed on close
+func openMemoryStorage(logger *slog.Logger) (*storage, error) {
+	dir, err := os.MkdirTemp("", "zentube-memory-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary database directory: %w", err)
+	}
+
+	dbRepo, err := database.NewSQLiteRepository(filepath.Join(dir, "zentube.db"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to initialize temporary database: %w", err)
+	}
+
+	logger.Warn("using in-memory search history, nothing will be persisted",
+		slog.String("temporary_database", dir),
+	)
+
+	history := memory.NewSearchHistoryRepository()
+	store := sqliteStorage(dbRepo)
+	store.history = history
+	store.pinger = history
+	store.close = func() error {
+		err := dbRepo.Close()
+		if rmErr := os.RemoveAll(dir); rmErr != nil && err == nil {
+			err = fmt.Errorf("failed to remove temporary database: %w", rmErr)
+		}
+		return err
+	}
+	return store, nil
+}
+
+// sqliteStorage returns storage with every feature repository backed by repo
+// Search history, the video catalog and the maintenance jobs are left to the caller
+func sqliteStorage(repo *database.SQLiteRepository) *storage {
+	return &storage{
+		saved:         repo,
+		collections:   repo,
+		subscriptions: repo,
+		savedSearches: repo,
+		webhooks:      repo,
+		digests:       repo,
+		watchEvents:   repo,
+		videoProgress: repo,
+		focusSessions: repo,
+		intents:       repo,
+	}
+}
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Backup file naming: <prefix><UTC timestamp>.db, so lexical order is chronological
+const (
+	backupPrefix     = "zentube-"
+	backupSuffix     = ".db"
+	backupTimeFormat = "20060102T150405Z"
+)
+
+// BackupTo writes a consistent snapshot of db to dest using VACUUM INTO
+// VACUUM INTO only reads the source, so under WAL it blocks neither readers
+// nor the writer, as long as db isn't the server's single-connection writer
+// pool (see BackupStore). It can't run on a query_only reader either
+func BackupTo(ctx context.Context, db *sql.DB, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup destination %q already exists", dest)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	if _, err := db.ExecContext(ctx, `VACUUM INTO ?`, dest); err != nil {
+		return fmt.Errorf("failed to back up database: %w", err)
+	}
+
+	return nil
+}
+
+// Verify checks that a database file passes integrity_check and that its
+// applied migrations are known to this binary
+func Verify(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("failed to stat database: %w", err)
+	}
+
+	db, err := sql.Open(DriverName, buildDSN(path, connOptions{ReadOnly: true}))
+	if err != nil {
+		return fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return fmt.Errorf("failed to run integrity_check: %w", err)
+	}
+	var problems []string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan row: %w", err)
+		}
+		if line != "ok" {
+			problems = append(problems, line)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("integrity_check failed: %s", strings.Join(problems, "; "))
+	}
+
+	migrator, err := NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	return migrator.Verify(ctx)
+}
+
+// Restore replaces the database at dest with the verified backup at src
+// The current database is first snapshotted next to dest (dest.pre-restore-<timestamp>)
+// The server must not be running while restoring
+func Restore(ctx context.Context, src, dest string) (string, error) {
+	if err := Verify(ctx, src); err != nil {
+		return "", fmt.Errorf("backup failed verification: %w", err)
+	}
+
+	var safetyCopy string
+	if _, err := os.Stat(dest); err == nil {
+		current, err := Open(dest)
+		if err != nil {
+			return "", err
+		}
+		safetyCopy = dest + ".pre-restore-" + time.Now().UTC().Format(backupTimeFormat)
+		err = BackupTo(ctx, current, safetyCopy)
+		current.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to snapshot current database: %w", err)
+		}
+	}
+
+	// Copy to a temp file in the same directory, then rename atomically
+	tmp := dest + ".restore-tmp"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return safetyCopy, err
+	}
+
+	// Stale WAL files would be replayed on top of the restored database
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dest + suffix); err != nil && !os.IsNotExist(err) {
+			os.Remove(tmp)
+			return safetyCopy, fmt.Errorf("failed to remove %s file: %w", suffix, err)
+		}
+	}
+
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return safetyCopy, fmt.Errorf("failed to replace database: %w", err)
+	}
+
+	return safetyCopy, nil
+}
+
+// copyFile copies src to dst and syncs it to disk
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("failed to open backup: %w", err)
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to copy backup: %w", err)
+	}
+
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to sync file: %w", err)
+	}
+
+	return out.Close()
+}
+
+// BackupStore creates timestamped backups in a directory and rotates old ones
+// Every backup opens its own connection to the database, so it never takes
+// the server's writer connection away from writes
+type BackupStore struct {
+	dbPath string
+	dir    string
+}
+
+// NewBackupStore creates a backup store snapshotting the database at dbPath into dir
+func NewBackupStore(dbPath, dir string) *BackupStore {
+	return &BackupStore{dbPath: dbPath, dir: dir}
+}
+
+// CreateBackup writes a new verified, timestamped backup and returns its path
+func (s *BackupStore) CreateBackup(ctx context.Context) (string, error) {
+	dest := filepath.Join(s.dir, backupPrefix+time.Now().UTC().Format(backupTimeFormat)+backupSuffix)
+
+	db, err := openPool(buildDSN(s.dbPath, connOptions{}), 1, 1)
+	if err != nil {
+		return "", err
+	}
+	err = BackupTo(ctx, db, dest)
+	db.Close()
+	if err != nil {
+		return "", err
+	}
+
+	if err := Verify(ctx, dest); err != nil {
+		os.Remove(dest)
+		return "", fmt.Errorf("backup failed verification: %w", err)
+	}
+
+	return dest, nil
+}
+
+// PruneBackups deletes all but the newest keep backups and returns the removed paths
+func (s *BackupStore) PruneBackups(keep int) ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list backups: %w", err)
+	}
+
+	var backups []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() && strings.HasPrefix(name, backupPrefix) && strings.HasSuffix(name, backupSuffix) {
+			backups = append(backups, name)
+		}
+	}
+
+	if len(backups) <= keep {
+		return nil, nil
+	}
+
+	// Newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	var removed []string
+	for _, name := range backups[keep:] {
+		path := filepath.Join(s.dir, name)
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("failed to remove backup %q: %w", name, err)
+		}
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "zentube.db")
+	repo, err := NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "golang", Results: 3, CreatedAt: time.Now()}))
+
+	// Act - back up while the repository is open
+	store := NewBackupStore(dbPath, filepath.Join(dir, "backups"))
+	backup, err := store.CreateBackup(ctx)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "after-backup", Results: 1, CreatedAt: time.Now()}))
+	require.NoError(t, repo.Close())
+
+	safetyCopy, err := Restore(ctx, backup, dbPath)
+	require.NoError(t, err)
+
+	// Assert - restored database only has the row from before the backup
+	assert.FileExists(t, safetyCopy)
+
+	restored, err := NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+	defer restored.Close()
+
+	history, err := restored.GetLast(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "golang", history[0].Query)
+}
+
+func TestBackupAndPing_DoNotWaitForTheWriter(t *testing.T) {
+	// Arrange - a long write transaction holds the single writer connection
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "zentube.db")
+	repo, err := NewSQLiteRepository(dbPath)
+	require.NoError(t, err)
+	defer repo.Close()
+
+	ctx := context.Background()
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "golang", Results: 3, CreatedAt: time.Now()}))
+
+	tx, err := repo.db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, `INSERT INTO search_history (query, results, created_at) VALUES ('pending', 0, CURRENT_TIMESTAMP)`)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(ctx, 3*time.Second)
+	defer cancel()
+
+	// Act
+	pingErr := repo.DB().PingContext(ctx)
+	backup, backupErr := NewBackupStore(dbPath, filepath.Join(dir, "backups")).CreateBackup(ctx)
+
+	// Assert - the backup holds the committed row, not the pending one
+	require.NoError(t, pingErr)
+	require.NoError(t, backupErr)
+
+	snapshot, err := NewSQLiteRepository(backup)
+	require.NoError(t, err)
+	defer snapshot.Close()
+	history, err := snapshot.GetLast(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "golang", history[0].Query)
+}
+
+func TestRestore_RejectsCorruptBackup(t *testing.T) {
+	dir := t.TempDir()
+	bad := filepath.Join(dir, "bad.db")
+	require.NoError(t, os.WriteFile(bad, []byte("not a database"), 0644))
+
+	_, err := Restore(context.Background(), bad, filepath.Join(dir, "zentube.db"))
+
+	assert.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "zentube.db"))
+}
+
+func TestBackupStore_PruneBackups(t *testing.T) {
+	// Arrange
+	dir := t.TempDir()
+	for _, name := range []string{
+		"zentube-20260101T000000Z.db",
+		"zentube-20260102T000000Z.db",
+		"zentube-20260103T000000Z.db",
+		"unrelated.db",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), nil, 0644))
+	}
+
+	// Act
+	removed, err := NewBackupStore("", dir).PruneBackups(2)
+
+	// Assert - oldest backup goes, unrelated files stay
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "zentube-20260101T000000Z.db")}, removed)
+	assert.FileExists(t, filepath.Join(dir, "unrelated.db"))
+}
+
+func TestExport(t *testing.T) {
+	// Arrange
+	repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "zentube.db"))
+	require.NoError(t, err)
+	defer repo.Close()
+
+	ctx := context.Background()
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: `say "hi", go`, Results: 2, CreatedAt: time.Now()}))
+
+	// Act
+	var jsonl, csvOut bytes.Buffer
+	n1, err1 := Export(ctx, repo.DB(), "search_history", FormatJSONL, &jsonl)
+	n2, err2 := Export(ctx, repo.DB(), "search_history", FormatCSV, &csvOut)
+	_, err3 := Export(ctx, repo.DB(), "schema_migrations", FormatCSV, &bytes.Buffer{})
+
+	// Assert
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	assert.Equal(t, 1, n1)
+	assert.Equal(t, 1, n2)
+	assert.Contains(t, jsonl.String(), `"query":"say \"hi\", go"`)
+	assert.True(t, strings.HasPrefix(csvOut.String(), "id,query,results,created_at,cache_hit,intent_id\n"))
+	assert.Error(t, err3)
+}
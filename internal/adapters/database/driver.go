@@ -0,0 +1,14 @@
+package database
+
+// connOptions are the per-connection settings a pool is opened with
+// Every connection also gets WAL (unless read-only), foreign keys and a 5s busy timeout
+// Each driver file translates them into its own DSN syntax (see buildDSN)
+type connOptions struct {
+	ReadOnly    bool // Open the file read-only (backup verification)
+	QueryOnly   bool // Reject writes (reader pool)
+	TxImmediate bool // BEGIN IMMEDIATE instead of DEFERRED (writer pool)
+	// IncrementalVacuum creates new database files with incremental auto-vacuum
+	// (writer pool). It has to be set before WAL initializes the file and has
+	// no effect on existing databases (see EnableIncrementalVacuum)
+	IncrementalVacuum bool
+}
@@ -0,0 +1,38 @@
+//go:build !modernc && (sqlite_fts5 || fts5 || libsqlite3)
+
+package database
+
+import (
+	"net/url"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// DriverName is the database/sql driver the adapter is built with
+// mattn/go-sqlite3 (CGO) is used when FTS5 is compiled in (-tags sqlite_fts5,
+// as make build does); every other build gets modernc.org/sqlite
+const DriverName = "sqlite3"
+
+// buildDSN renders connection options as a mattn/go-sqlite3 DSN
+func buildDSN(path string, opts connOptions) string {
+	params := url.Values{}
+	params.Set("_timeout", "5000")
+	params.Set("_fk", "true")
+
+	if opts.IncrementalVacuum {
+		params.Set("_auto_vacuum", "incremental")
+	}
+	if opts.ReadOnly {
+		params.Set("mode", "ro")
+	} else {
+		params.Set("_journal_mode", "WAL")
+	}
+	if opts.QueryOnly {
+		params.Set("_query_only", "true")
+	}
+	if opts.TxImmediate {
+		params.Set("_txlock", "immediate")
+	}
+
+	return "file:" + path + "?" + params.Encode()
+}
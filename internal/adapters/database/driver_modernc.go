@@ -0,0 +1,43 @@
+//go:build modernc || !(sqlite_fts5 || fts5 || libsqlite3)
+
+package database
+
+import (
+	"net/url"
+
+	_ "modernc.org/sqlite"
+)
+
+// DriverName is the database/sql driver the adapter is built with
+// modernc.org/sqlite is pure Go, so CGO_ENABLED=0 builds work, and always has
+// FTS5; it is also the fallback for builds without the sqlite_fts5 tag, since
+// mattn/go-sqlite3 would lack FTS5 there
+const DriverName = "sqlite"
+
+// buildDSN renders connection options as a modernc.org/sqlite DSN
+// _time_format=sqlite stores times in the same format as mattn/go-sqlite3,
+// so databases can move between builds and date() works on stored values
+func buildDSN(path string, opts connOptions) string {
+	params := url.Values{}
+	// Pragmas run in order, so auto_vacuum comes before journal_mode
+	if opts.IncrementalVacuum {
+		params.Add("_pragma", "auto_vacuum(incremental)")
+	}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "foreign_keys(1)")
+	params.Set("_time_format", "sqlite")
+
+	if opts.ReadOnly {
+		params.Set("mode", "ro")
+	} else {
+		params.Add("_pragma", "journal_mode(WAL)")
+	}
+	if opts.QueryOnly {
+		params.Add("_pragma", "query_only(1)")
+	}
+	if opts.TxImmediate {
+		params.Set("_txlock", "immediate")
+	}
+
+	return "file:" + path + "?" + params.Encode()
+}
@@ -0,0 +1,155 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportTables lists the tables holding user data, in export order
+// Add new user-data tables here so `zentube db export` picks them up
+var ExportTables = []string{
+	"search_intents",
+	"search_history",
+	"search_history_daily",
+	"videos",
+	"search_results",
+	"saved_videos",
+	"collections",
+	"collection_items",
+	"subscriptions",
+	"feed_items",
+	"saved_searches",
+	"saved_search_results",
+	"watch_events",
+	"video_progress",
+	"focus_sessions",
+}
+
+// Export formats
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+)
+
+// Export writes every row of table to w as JSON lines or CSV (with header)
+// Only tables listed in ExportTables can be exported
+func Export(ctx context.Context, db *sql.DB, table, format string, w io.Writer) (int, error) {
+	if !isExportTable(table) {
+		return 0, fmt.Errorf("table %q cannot be exported (expected one of %v)", table, ExportTables)
+	}
+
+	// Table name is checked against ExportTables above, so interpolation is safe
+	rows, err := db.QueryContext(ctx, `SELECT * FROM `+table)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	var write func(values []interface{}) error
+	var flush func() error
+
+	switch format {
+	case FormatJSONL:
+		enc := json.NewEncoder(w)
+		write = func(values []interface{}) error {
+			record := make(map[string]interface{}, len(columns))
+			for i, col := range columns {
+				record[col] = exportValue(values[i])
+			}
+			return enc.Encode(record)
+		}
+		flush = func() error { return nil }
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return 0, fmt.Errorf("failed to write header: %w", err)
+		}
+		write = func(values []interface{}) error {
+			record := make([]string, len(values))
+			for i, v := range values {
+				record[i] = csvValue(exportValue(v))
+			}
+			return cw.Write(record)
+		}
+		flush = func() error {
+			cw.Flush()
+			return cw.Error()
+		}
+	default:
+		return 0, fmt.Errorf("unknown export format %q (expected %s or %s)", format, FormatJSONL, FormatCSV)
+	}
+
+	count := 0
+	values := make([]interface{}, len(columns))
+	ptrs := make([]interface{}, len(columns))
+	for i := range values {
+		ptrs[i] = &values[i]
+	}
+
+	for rows.Next() {
+		if err := rows.Scan(ptrs...); err != nil {
+			return count, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if err := write(values); err != nil {
+			return count, fmt.Errorf("failed to write row: %w", err)
+		}
+		count++
+	}
+
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return count, flush()
+}
+
+// isExportTable reports whether table is in ExportTables
+func isExportTable(table string) bool {
+	for _, t := range ExportTables {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// exportValue normalizes driver values for encoding
+func exportValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case []byte:
+		return string(val)
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	default:
+		return val
+	}
+}
+
+// csvValue renders a normalized value as a CSV field
+func csvValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		return fmt.Sprint(val)
+	}
+}
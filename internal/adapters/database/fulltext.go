@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// Full-text search over search history.
+//
+// The FTS5 virtual tables and their sync triggers are created by migrations.
+// FTS5 is only compiled into mattn/go-sqlite3 with the sqlite_fts5 build tag,
+// so builds without it use modernc.org/sqlite instead (see driver_modernc.go)
+// and requireFTS5 rejects any other SQLite that lacks it before migrating.
+
+// ErrFTS5Unavailable is returned when the SQLite library has no FTS5 module
+var ErrFTS5Unavailable = errors.New("sqlite was built without FTS5: build with -tags sqlite_fts5 (make build) or -tags modernc")
+
+// Highlight delimiters; control characters can't appear in validated queries
+const (
+	highlightStart = "\x02"
+	highlightEnd   = "\x03"
+)
+
+// requireFTS5 fails with ErrFTS5Unavailable if the FTS5 module is missing
+func requireFTS5(ctx context.Context, db *sql.DB) error {
+	var hasFTS5 bool
+	if err := db.QueryRowContext(ctx, `SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&hasFTS5); err != nil {
+		return fmt.Errorf("failed to detect fts5: %w", err)
+	}
+	if !hasFTS5 {
+		return ErrFTS5Unavailable
+	}
+	return nil
+}
+
+// matchExpression turns free text into a safe MATCH expression
+// Every word becomes a quoted prefix term, so FTS operators in user input are inert
+// Returns "" if the text has no searchable words
+func matchExpression(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		terms = append(terms, `"`+w+`"*`)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// highlightColumn returns a SQL expression for column of the given FTS5 table
+// with matches wrapped in the highlight delimiters (see splitHighlight)
+func highlightColumn(table string, column int) string {
+	return fmt.Sprintf("highlight(%s, %d, '%s', '%s')", table, column, highlightStart, highlightEnd)
+}
+
+// splitHighlight converts delimited highlight output into renderable segments
+func splitHighlight(s string) []entities.TextSegment {
+	var segments []entities.TextSegment
+	for s != "" {
+		start := strings.Index(s, highlightStart)
+		if start < 0 {
+			segments = append(segments, entities.TextSegment{Text: s})
+			break
+		}
+		if start > 0 {
+			segments = append(segments, entities.TextSegment{Text: s[:start]})
+		}
+
+		s = s[start+len(highlightStart):]
+		end := strings.Index(s, highlightEnd)
+		if end < 0 {
+			segments = append(segments, entities.TextSegment{Text: s, Match: true})
+			break
+		}
+		segments = append(segments, entities.TextSegment{Text: s[:end], Match: true})
+		s = s[end+len(highlightEnd):]
+	}
+	return segments
+}
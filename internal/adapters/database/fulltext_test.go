@@ -0,0 +1,190 @@
+package database
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestSQLiteRepository_FullTextSearch(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	for i, q := range []string{"rust async talk", "golang generics", "Rust async talk", "rust async talk", "python"} {
+		require.NoError(t, repo.Save(ctx, &entities.SearchHistory{
+			Query: q, Results: 5, CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	// Act - prefix matching, case-insensitive, duplicates collapsed
+	matches, err := repo.FullTextSearch(ctx, "rus tal", 10)
+
+	// Assert
+	require.NoError(t, err)
+	queries := make([]string, 0, len(matches))
+	for _, m := range matches {
+		queries = append(queries, m.Query)
+	}
+	assert.ElementsMatch(t, []string{"rust async talk", "Rust async talk"}, queries)
+
+	var highlighted []string
+	for _, seg := range matches[0].Highlighted {
+		if seg.Match {
+			highlighted = append(highlighted, seg.Text)
+		}
+	}
+	assert.Len(t, highlighted, 2)
+}
+
+func TestSQLiteRepository_FullTextSearch_SyncsWithDeletes(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "kubernetes storage", Results: 1, CreatedAt: time.Now().Add(-48 * time.Hour)}))
+
+	// Act
+	_, err := repo.PruneOlderThan(ctx, time.Now().Add(-24*time.Hour), 100, false)
+	require.NoError(t, err)
+	matches, err := repo.FullTextSearch(ctx, "kubernetes", 10)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestSQLiteRepository_FullTextSearch_FindsSearchesByVideo(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	older := &entities.SearchHistory{Query: "conference talks", Results: 2, CreatedAt: time.Now().Add(-2 * time.Hour)}
+	latest := &entities.SearchHistory{Query: "systems programming", Results: 1, CreatedAt: time.Now().Add(-time.Hour)}
+	require.NoError(t, repo.Save(ctx, older))
+	require.NoError(t, repo.Save(ctx, latest))
+
+	ferris := entities.Video{ID: "v1", Title: "Ferris explains ownership", Channel: "RustConf", Description: "Borrow checker deep dive"}
+	require.NoError(t, repo.SaveSearchResults(ctx, older.ID, []entities.Video{
+		ferris,
+		{ID: "v2", Title: "Keynote", Channel: "GopherCon"},
+	}))
+	require.NoError(t, repo.SaveSearchResults(ctx, latest.ID, []entities.Video{ferris}))
+
+	// Act
+	byTitle, err := repo.FullTextSearch(ctx, "ferris", 10)
+	require.NoError(t, err)
+	byDescription, err := repo.FullTextSearch(ctx, "borrow", 10)
+	require.NoError(t, err)
+
+	// Assert - the video is attached to the latest search that returned it
+	require.Len(t, byTitle, 1)
+	assert.Equal(t, "systems programming", byTitle[0].Query)
+	require.Len(t, byTitle[0].Videos, 1)
+	assert.Equal(t, "v1", byTitle[0].Videos[0].ID)
+	assert.Equal(t, []entities.TextSegment{
+		{Text: "Ferris", Match: true},
+		{Text: " explains ownership"},
+	}, byTitle[0].Videos[0].HighlightedTitle)
+
+	require.Len(t, byDescription, 1)
+	assert.Equal(t, "v1", byDescription[0].Videos[0].ID)
+}
+
+func TestSQLiteRepository_FullTextSearch_VideoIndexFollowsCatalog(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	search := &entities.SearchHistory{Query: "talks", Results: 1, CreatedAt: time.Now()}
+	require.NoError(t, repo.Save(ctx, search))
+	require.NoError(t, repo.SaveSearchResults(ctx, search.ID, []entities.Video{
+		{ID: "v1", Title: "Kubernetes storage", Channel: "c", Description: "volumes explained"},
+	}))
+
+	// Act - the title changes upstream and the next result has no description
+	require.NoError(t, repo.SaveSearchResults(ctx, search.ID, []entities.Video{
+		{ID: "v1", Title: "Persistent volumes in practice", Channel: "c"},
+	}))
+	oldTitle, err := repo.FullTextSearch(ctx, "kubernetes", 10)
+	require.NoError(t, err)
+	newTitle, err := repo.FullTextSearch(ctx, "persistent", 10)
+	require.NoError(t, err)
+	description, err := repo.FullTextSearch(ctx, "explained", 10)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Empty(t, oldTitle)
+	require.Len(t, newTitle, 1)
+	assert.Equal(t, "talks", newTitle[0].Query)
+	assert.Len(t, description, 1, "a missing description keeps the stored one")
+
+	video, err := repo.GetByID(ctx, "v1")
+	require.NoError(t, err)
+	assert.Equal(t, "volumes explained", video.Description)
+}
+
+func TestSQLiteRepository_FullTextSearch_ReplacesStartupIndex(t *testing.T) {
+	// Arrange - a database from before the FTS migration, with the index older
+	// binaries created at startup (FTS4 needs a driver modernc doesn't match,
+	// so a stale FTS5 index with a leftover trigger stands in for it)
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	older := fstest.MapFS{}
+	entries, err := fs.ReadDir(migrationFiles, "migrations")
+	require.NoError(t, err)
+	for _, e := range entries {
+		if e.Name() >= "0015_" {
+			continue
+		}
+		data, err := fs.ReadFile(migrationFiles, "migrations/"+e.Name())
+		require.NoError(t, err)
+		older["migrations/"+e.Name()] = &fstest.MapFile{Data: data}
+	}
+	migrator, err := NewMigratorFS(db, older, "migrations")
+	require.NoError(t, err)
+	_, err = migrator.Up(ctx, false)
+	require.NoError(t, err)
+
+	for _, stmt := range []string{
+		`INSERT INTO search_history (query, results, created_at) VALUES ('rust async talk', 3, CURRENT_TIMESTAMP)`,
+		`CREATE VIRTUAL TABLE search_history_fts USING fts5(query, content='search_history', content_rowid='id')`,
+		`CREATE TRIGGER search_history_fts_bd BEFORE DELETE ON search_history BEGIN SELECT 1; END`,
+	} {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+
+	// Act
+	repo, err := NewSQLiteRepository(path)
+	require.NoError(t, err)
+	defer repo.Close()
+	matches, err := repo.FullTextSearch(ctx, "rust", 10)
+
+	// Assert - existing rows are indexed in the new FTS5 table
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "rust async talk", matches[0].Query)
+
+	var leftovers int
+	require.NoError(t, repo.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'search_history_fts_bd'`).Scan(&leftovers))
+	assert.Zero(t, leftovers)
+}
+
+func TestMatchExpression_NeutralizesOperators(t *testing.T) {
+	assert.Equal(t, `"foo"* "OR"* "bar"*`, matchExpression(`foo" OR -bar`+"\x00"))
+	assert.Equal(t, `"foo"* "NEAR"*`, matchExpression(`foo NEAR`))
+	assert.Equal(t, "", matchExpression(`"*"`))
+}
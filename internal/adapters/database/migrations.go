@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Embedded migrations, named <version>_<name>.up.sql / <version>_<name>.down.sql
+// Versions must be unique and are applied in ascending order
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// Migration is a single versioned schema change
+type Migration struct {
+	Version  int
+	Name     string
+	UpSQL    string
+	DownSQL  string // Empty if the migration cannot be reverted
+	Checksum string // SHA-256 of UpSQL, stored when applied
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// ErrChecksumMismatch is returned when an applied migration was edited afterwards
+var ErrChecksumMismatch = errors.New("migration checksum mismatch")
+
+// Migrator applies embedded migrations and records them in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a migrator for the embedded migrations
+func NewMigrator(db *sql.DB) (*Migrator, error) {
+	return NewMigratorFS(db, migrationFiles, "migrations")
+}
+
+// NewMigratorFS creates a migrator for migrations stored in dir of fsys
+// Useful for testing with fstest.MapFS
+func NewMigratorFS(db *sql.DB, fsys fs.FS, dir string) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// loadMigrations reads and pairs up/down files, sorted by version
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		file := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(file, ".sql") {
+			continue
+		}
+
+		base := strings.TrimSuffix(file, ".sql")
+		direction := path.Ext(base) // ".up" or ".down"
+		base = strings.TrimSuffix(base, direction)
+
+		versionStr, name, ok := strings.Cut(base, "_")
+		version, err := strconv.Atoi(versionStr)
+		if !ok || err != nil || version < 1 || (direction != ".up" && direction != ".down") {
+			return nil, fmt.Errorf("invalid migration filename %q", file)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, file))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %q: %w", file, err)
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration version %d used by %q and %q", version, m.Name, name)
+		}
+
+		if direction == ".up" {
+			m.UpSQL = string(data)
+		} else {
+			m.DownSQL = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.UpSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up file", m.Version, m.Name)
+		}
+		m.Checksum = checksum(m.UpSQL)
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// checksum returns the hex-encoded SHA-256 of a migration's up SQL
+func checksum(sqlText string) string {
+	sum := sha256.Sum256([]byte(sqlText))
+	return hex.EncodeToString(sum[:])
+}
+
+// ensureTable creates the schema_migrations bookkeeping table
+func (m *Migrator) ensureTable(ctx context.Context) error {
+	_, err := m.db.ExecContext(ctx, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INTEGER PRIMARY KEY,
+		name TEXT NOT NULL,
+		checksum TEXT NOT NULL,
+		applied_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+// appliedRecord is a row of schema_migrations
+type appliedRecord struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// applied loads schema_migrations keyed by version
+func (m *Migrator) applied(ctx context.Context) (map[int]appliedRecord, error) {
+	rows, err := m.db.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	records := make(map[int]appliedRecord)
+	for rows.Next() {
+		var version int
+		var rec appliedRecord
+		if err := rows.Scan(&version, &rec.name, &rec.checksum, &rec.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		records[version] = rec
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return records, nil
+}
+
+// Status returns every known migration with its applied state
+// Fails if an applied migration was modified or is missing from the binary
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	if err := m.ensureTable(ctx); err != nil {
+		return nil, err
+	}
+
+	records, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.verify(records); err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		rec, ok := records[mig.Version]
+		statuses = append(statuses, MigrationStatus{
+			Migration: mig,
+			Applied:   ok,
+			AppliedAt: rec.appliedAt,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Verify checks applied migrations against the embedded files without
+// modifying the database (safe on read-only connections)
+// Databases without schema_migrations are rejected
+func (m *Migrator) Verify(ctx context.Context) error {
+	records, err := m.applied(ctx)
+	if err != nil {
+		return err
+	}
+	return m.verify(records)
+}
+
+// verify checks applied migrations against the embedded files
+func (m *Migrator) verify(records map[int]appliedRecord) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = mig
+	}
+
+	for version, rec := range records {
+		mig, ok := known[version]
+		if !ok {
+			return fmt.Errorf("applied migration %04d_%s is unknown to this binary", version, rec.name)
+		}
+		if mig.Checksum != rec.checksum {
+			return fmt.Errorf("%w: %04d_%s was modified after being applied", ErrChecksumMismatch, version, mig.Name)
+		}
+	}
+
+	return nil
+}
+
+// Up applies all pending migrations in order, each in its own transaction
+// With dryRun, nothing is executed and the pending migrations are returned
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, s := range statuses {
+		if !s.Applied {
+			pending = append(pending, s.Migration)
+		}
+	}
+
+	if dryRun {
+		return pending, nil
+	}
+
+	for i, mig := range pending {
+		if err := m.apply(ctx, mig); err != nil {
+			return pending[:i], err
+		}
+	}
+
+	return pending, nil
+}
+
+// Down reverts the last steps applied migrations, newest first
+// With dryRun, nothing is executed and the migrations to revert are returned
+func (m *Migrator) Down(ctx context.Context, steps int, dryRun bool) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []Migration
+	for i := len(statuses) - 1; i >= 0 && len(targets) < steps; i-- {
+		if statuses[i].Applied {
+			targets = append(targets, statuses[i].Migration)
+		}
+	}
+
+	for _, mig := range targets {
+		if mig.DownSQL == "" {
+			return nil, fmt.Errorf("migration %04d_%s cannot be reverted (no down file)", mig.Version, mig.Name)
+		}
+	}
+
+	if dryRun {
+		return targets, nil
+	}
+
+	for i, mig := range targets {
+		if err := m.revert(ctx, mig); err != nil {
+			return targets[:i], err
+		}
+	}
+
+	return targets, nil
+}
+
+// apply runs one migration and records it atomically
+func (m *Migrator) apply(ctx context.Context, mig Migration) error {
+	return inTx(ctx, m.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.UpSQL); err != nil {
+			return fmt.Errorf("failed to apply migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)`,
+			mig.Version, mig.Name, mig.Checksum, time.Now(),
+		); err != nil {
+			return fmt.Errorf("failed to record migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		return nil
+	})
+}
+
+// revert runs one down migration and removes its record atomically
+func (m *Migrator) revert(ctx context.Context, mig Migration) error {
+	return inTx(ctx, m.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, mig.DownSQL); err != nil {
+			return fmt.Errorf("failed to revert migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = ?`, mig.Version); err != nil {
+			return fmt.Errorf("failed to unrecord migration %04d_%s: %w", mig.Version, mig.Name, err)
+		}
+		return nil
+	})
+}
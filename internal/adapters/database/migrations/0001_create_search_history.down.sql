@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_query;
+DROP INDEX IF EXISTS idx_created_at;
+DROP TABLE IF EXISTS search_history;
@@ -0,0 +1,11 @@
+-- Baseline schema. IF NOT EXISTS keeps this safe for databases created
+-- before versioned migrations existed.
+CREATE TABLE IF NOT EXISTS search_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	query TEXT NOT NULL CHECK(length(query) > 0),
+	results INTEGER NOT NULL CHECK(results >= 0),
+	created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_created_at ON search_history(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_query ON search_history(query);
@@ -0,0 +1,9 @@
+-- Daily rollup of pruned search_history rows, so retention doesn't lose
+-- long-term query frequencies.
+CREATE TABLE search_history_daily (
+	day TEXT NOT NULL,
+	query TEXT NOT NULL CHECK(length(query) > 0),
+	searches INTEGER NOT NULL CHECK(searches > 0),
+	total_results INTEGER NOT NULL CHECK(total_results >= 0),
+	PRIMARY KEY (day, query)
+);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_search_results_video_id;
+DROP TABLE IF EXISTS search_results;
+DROP INDEX IF EXISTS idx_videos_last_seen_at;
+DROP TABLE IF EXISTS videos;
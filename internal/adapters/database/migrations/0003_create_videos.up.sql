@@ -0,0 +1,23 @@
+-- Local catalog of every video seen in search results, and which videos
+-- each past search returned (in rank order).
+CREATE TABLE videos (
+	id TEXT PRIMARY KEY CHECK(length(id) > 0),
+	title TEXT NOT NULL,
+	channel TEXT NOT NULL,
+	published_at DATETIME,
+	thumbnail TEXT NOT NULL DEFAULT '',
+	first_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_videos_last_seen_at ON videos(last_seen_at DESC);
+
+-- Rows go away with their search_history row (retention pruning)
+CREATE TABLE search_results (
+	search_id INTEGER NOT NULL REFERENCES search_history(id) ON DELETE CASCADE,
+	rank INTEGER NOT NULL CHECK(rank > 0),
+	video_id TEXT NOT NULL REFERENCES videos(id),
+	PRIMARY KEY (search_id, rank)
+);
+
+CREATE INDEX idx_search_results_video_id ON search_results(video_id);
@@ -0,0 +1,3 @@
+ALTER TABLE search_history_daily DROP COLUMN cache_hits;
+ALTER TABLE search_history_daily DROP COLUMN zero_results;
+ALTER TABLE search_history DROP COLUMN cache_hit;
@@ -0,0 +1,6 @@
+-- Columns the insights page needs: whether a search was answered from the
+-- cache, and the same counts carried into the daily rollup.
+ALTER TABLE search_history ADD COLUMN cache_hit INTEGER NOT NULL DEFAULT 0 CHECK(cache_hit IN (0, 1));
+
+ALTER TABLE search_history_daily ADD COLUMN zero_results INTEGER NOT NULL DEFAULT 0 CHECK(zero_results >= 0);
+ALTER TABLE search_history_daily ADD COLUMN cache_hits INTEGER NOT NULL DEFAULT 0 CHECK(cache_hits >= 0);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_saved_videos_position;
+DROP TABLE IF EXISTS saved_videos;
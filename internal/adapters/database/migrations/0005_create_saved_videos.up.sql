@@ -0,0 +1,10 @@
+-- Watch-later list. Video metadata lives in the catalog (videos), which
+-- saving a video upserts into.
+CREATE TABLE saved_videos (
+	video_id TEXT PRIMARY KEY REFERENCES videos(id),
+	position INTEGER NOT NULL CHECK(position > 0),
+	saved_at DATETIME NOT NULL,
+	watched_at DATETIME
+);
+
+CREATE INDEX idx_saved_videos_position ON saved_videos(position);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_collection_items_position;
+DROP TABLE IF EXISTS collection_items;
+DROP TABLE IF EXISTS collections;
@@ -0,0 +1,19 @@
+-- User-defined collections: named, ordered lists of catalogued videos with notes.
+CREATE TABLE collections (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE COLLATE NOCASE CHECK(length(name) > 0),
+	description TEXT NOT NULL DEFAULT '',
+	created_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL
+);
+
+CREATE TABLE collection_items (
+	collection_id INTEGER NOT NULL REFERENCES collections(id) ON DELETE CASCADE,
+	video_id TEXT NOT NULL REFERENCES videos(id),
+	position INTEGER NOT NULL CHECK(position > 0),
+	note TEXT NOT NULL DEFAULT '',
+	added_at DATETIME NOT NULL,
+	PRIMARY KEY (collection_id, video_id)
+);
+
+CREATE INDEX idx_collection_items_position ON collection_items(collection_id, position);
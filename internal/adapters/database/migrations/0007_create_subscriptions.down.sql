@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_feed_items_channel;
+DROP INDEX IF EXISTS idx_feed_items_published;
+DROP TABLE IF EXISTS feed_items;
+DROP TABLE IF EXISTS subscriptions;
@@ -0,0 +1,20 @@
+-- Followed channels and the uploads polled from them. Video metadata lives
+-- in the catalog (videos); published_at is copied here to order the feed.
+CREATE TABLE subscriptions (
+	channel_id TEXT PRIMARY KEY,
+	title TEXT NOT NULL,
+	subscribed_at DATETIME NOT NULL,
+	last_polled_at DATETIME,
+	last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE feed_items (
+	video_id TEXT PRIMARY KEY REFERENCES videos(id),
+	channel_id TEXT NOT NULL REFERENCES subscriptions(channel_id) ON DELETE CASCADE,
+	published_at DATETIME NOT NULL,
+	fetched_at DATETIME NOT NULL,
+	seen_at DATETIME
+);
+
+CREATE INDEX idx_feed_items_published ON feed_items(published_at DESC, video_id DESC);
+CREATE INDEX idx_feed_items_channel ON feed_items(channel_id, published_at DESC);
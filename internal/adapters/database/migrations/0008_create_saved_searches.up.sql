@@ -0,0 +1,21 @@
+-- Searches re-run on a schedule. Every video a search has returned is kept in
+-- saved_search_results so later refreshes can tell new matches apart.
+CREATE TABLE saved_searches (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL UNIQUE COLLATE NOCASE CHECK(length(name) > 0),
+	query TEXT NOT NULL CHECK(length(query) > 0),
+	max_results INTEGER NOT NULL CHECK(max_results > 0),
+	created_at DATETIME NOT NULL,
+	last_checked_at DATETIME,
+	last_refreshed_at DATETIME,
+	last_error TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE saved_search_results (
+	saved_search_id INTEGER NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+	video_id TEXT NOT NULL REFERENCES videos(id),
+	first_seen_at DATETIME NOT NULL,
+	last_seen_at DATETIME NOT NULL,
+	is_new INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (saved_search_id, video_id)
+);
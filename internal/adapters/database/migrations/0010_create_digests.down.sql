@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_digests_sent_at;
+DROP TABLE IF EXISTS digests;
@@ -0,0 +1,9 @@
+-- Email digest runs. Only the latest sent_at is read, to decide when the
+-- next digest is due; the rest is a log of what went out.
+CREATE TABLE digests (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	sent_at DATETIME NOT NULL,
+	video_count INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_digests_sent_at ON digests(sent_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_watch_events_search_id;
+DROP INDEX IF EXISTS idx_watch_events_video_id;
+DROP INDEX IF EXISTS idx_watch_events_started_at;
+DROP TABLE IF EXISTS watch_events;
@@ -0,0 +1,17 @@
+-- Viewings in the modal player, reported by the player itself. search_id
+-- points at the search that surfaced the video and is cleared, not
+-- cascaded, when history retention prunes that search.
+CREATE TABLE watch_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	video_id TEXT NOT NULL CHECK(length(video_id) > 0),
+	title TEXT NOT NULL DEFAULT '',
+	started_at DATETIME NOT NULL,
+	updated_at DATETIME NOT NULL,
+	closed_at DATETIME,
+	watched_seconds INTEGER NOT NULL DEFAULT 0 CHECK(watched_seconds >= 0),
+	search_id INTEGER REFERENCES search_history(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_watch_events_started_at ON watch_events(started_at DESC);
+CREATE INDEX idx_watch_events_video_id ON watch_events(video_id);
+CREATE INDEX idx_watch_events_search_id ON watch_events(search_id);
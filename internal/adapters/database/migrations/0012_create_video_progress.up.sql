@@ -0,0 +1,8 @@
+-- Last playback position per video, so a video reopened in the player
+-- resumes where it stopped, whichever viewing that was.
+CREATE TABLE video_progress (
+	video_id TEXT PRIMARY KEY CHECK(length(video_id) > 0),
+	position_seconds INTEGER NOT NULL CHECK(position_seconds >= 0),
+	duration_seconds INTEGER NOT NULL CHECK(duration_seconds >= 0),
+	updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_focus_sessions_started_at;
+DROP TABLE IF EXISTS focus_sessions;
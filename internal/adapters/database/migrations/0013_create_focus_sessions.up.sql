@@ -0,0 +1,11 @@
+-- Focus sessions: a timer that ends with a reflection. ended_at stays NULL
+-- until the reflection is recorded or a newer session replaces this one.
+CREATE TABLE focus_sessions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	started_at DATETIME NOT NULL,
+	ends_at DATETIME NOT NULL,
+	ended_at DATETIME,
+	reflection TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX idx_focus_sessions_started_at ON focus_sessions(started_at DESC);
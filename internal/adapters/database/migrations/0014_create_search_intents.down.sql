@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_watch_events_intent_id;
+DROP INDEX IF EXISTS idx_search_history_intent_id;
+ALTER TABLE watch_events DROP COLUMN intent_id;
+ALTER TABLE search_history DROP COLUMN intent_id;
+DROP INDEX IF EXISTS idx_search_intents_created_at;
+DROP TABLE IF EXISTS search_intents;
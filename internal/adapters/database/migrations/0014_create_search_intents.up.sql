@@ -0,0 +1,19 @@
+-- Search intents: why a searching session started, and whether what was
+-- wanted was found. Searches made for an intent and the viewings they led
+-- to point back at it; the viewing keeps its intent when retention prunes
+-- the search.
+CREATE TABLE search_intents (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	intent TEXT NOT NULL CHECK(length(intent) > 0),
+	created_at DATETIME NOT NULL,
+	outcome TEXT NOT NULL DEFAULT '' CHECK(outcome IN ('', 'found', 'not_found')),
+	rated_at DATETIME
+);
+
+CREATE INDEX idx_search_intents_created_at ON search_intents(created_at DESC);
+
+ALTER TABLE search_history ADD COLUMN intent_id INTEGER REFERENCES search_intents(id) ON DELETE SET NULL;
+ALTER TABLE watch_events ADD COLUMN intent_id INTEGER REFERENCES search_intents(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_search_history_intent_id ON search_history(intent_id);
+CREATE INDEX idx_watch_events_intent_id ON watch_events(intent_id);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS search_history_fts_au;
+DROP TRIGGER IF EXISTS search_history_fts_ad;
+DROP TRIGGER IF EXISTS search_history_fts_ai;
+DROP TABLE IF EXISTS search_history_fts;
@@ -0,0 +1,27 @@
+-- Full-text index over past search queries (FTS5, bm25 ranking), kept in
+-- sync with search_history by triggers. Older binaries created this index at
+-- startup, possibly with FTS4, so whatever they left behind is replaced.
+DROP TRIGGER IF EXISTS search_history_fts_ai;
+DROP TRIGGER IF EXISTS search_history_fts_ad;
+DROP TRIGGER IF EXISTS search_history_fts_au;
+DROP TRIGGER IF EXISTS search_history_fts_bd;
+DROP TRIGGER IF EXISTS search_history_fts_bu;
+DROP TABLE IF EXISTS search_history_fts;
+
+CREATE VIRTUAL TABLE search_history_fts USING fts5(query, content='search_history', content_rowid='id');
+
+CREATE TRIGGER search_history_fts_ai AFTER INSERT ON search_history BEGIN
+	INSERT INTO search_history_fts(rowid, query) VALUES (new.id, new.query);
+END;
+
+CREATE TRIGGER search_history_fts_ad AFTER DELETE ON search_history BEGIN
+	INSERT INTO search_history_fts(search_history_fts, rowid, query) VALUES ('delete', old.id, old.query);
+END;
+
+CREATE TRIGGER search_history_fts_au AFTER UPDATE OF query ON search_history BEGIN
+	INSERT INTO search_history_fts(search_history_fts, rowid, query) VALUES ('delete', old.id, old.query);
+	INSERT INTO search_history_fts(rowid, query) VALUES (new.id, new.query);
+END;
+
+-- Index rows that existed before the virtual table
+INSERT INTO search_history_fts(search_history_fts) VALUES ('rebuild');
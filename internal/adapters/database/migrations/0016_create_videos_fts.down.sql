@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS videos_fts_au;
+DROP TRIGGER IF EXISTS videos_fts_ad;
+DROP TRIGGER IF EXISTS videos_fts_ai;
+DROP TABLE IF EXISTS videos_fts;
+ALTER TABLE videos DROP COLUMN description;
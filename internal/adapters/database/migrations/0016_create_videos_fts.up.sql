@@ -0,0 +1,25 @@
+-- Video descriptions, and a full-text index over catalog titles and
+-- descriptions so past searches can be found by the videos they returned.
+-- videos has a TEXT primary key and its implicit rowid may change on VACUUM,
+-- so the index keeps its own copy keyed by video_id rather than being an
+-- external-content table.
+ALTER TABLE videos ADD COLUMN description TEXT NOT NULL DEFAULT '';
+
+CREATE VIRTUAL TABLE videos_fts USING fts5(video_id UNINDEXED, title, description);
+
+CREATE TRIGGER videos_fts_ai AFTER INSERT ON videos BEGIN
+	INSERT INTO videos_fts(video_id, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+CREATE TRIGGER videos_fts_ad AFTER DELETE ON videos BEGIN
+	DELETE FROM videos_fts WHERE video_id = old.id;
+END;
+
+-- Catalog upserts rarely change the text, so only real changes touch the index
+CREATE TRIGGER videos_fts_au AFTER UPDATE OF title, description ON videos
+WHEN old.title IS NOT new.title OR old.description IS NOT new.description BEGIN
+	DELETE FROM videos_fts WHERE video_id = old.id;
+	INSERT INTO videos_fts(video_id, title, description) VALUES (new.id, new.title, new.description);
+END;
+
+INSERT INTO videos_fts(video_id, title, description) SELECT id, title, description FROM videos;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_focus_sessions_profile_started_at;
+DROP INDEX IF EXISTS idx_watch_events_profile_started_at;
+ALTER TABLE focus_sessions DROP COLUMN profile;
+ALTER TABLE watch_events DROP COLUMN profile;
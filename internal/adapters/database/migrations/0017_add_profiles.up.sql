@@ -0,0 +1,8 @@
+-- Profiles: viewings and focus sessions belong to the profile they were
+-- recorded under, so each profile's budgets count only its own watch time.
+-- Everything recorded before profiles existed goes to the default profile.
+ALTER TABLE watch_events ADD COLUMN profile TEXT NOT NULL DEFAULT 'default';
+ALTER TABLE focus_sessions ADD COLUMN profile TEXT NOT NULL DEFAULT 'default';
+
+CREATE INDEX idx_watch_events_profile_started_at ON watch_events(profile, started_at DESC);
+CREATE INDEX idx_focus_sessions_profile_started_at ON focus_sessions(profile, started_at DESC);
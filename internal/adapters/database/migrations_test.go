@@ -0,0 +1,122 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMigrationsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"m/0001_create_a.up.sql":   {Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY);")},
+		"m/0001_create_a.down.sql": {Data: []byte("DROP TABLE a;")},
+		"m/0002_create_b.up.sql":   {Data: []byte("CREATE TABLE b (id INTEGER PRIMARY KEY);")},
+		"m/0002_create_b.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+}
+
+func TestMigrator_UpDownStatus(t *testing.T) {
+	// Arrange
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigratorFS(db, testMigrationsFS(), "m")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	// Act - dry run applies nothing
+	pending, err := migrator.Up(ctx, true)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.False(t, statuses[0].Applied)
+
+	// Act - apply in version order
+	applied, err := migrator.Up(ctx, false)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2}, []int{applied[0].Version, applied[1].Version})
+
+	// Re-running is a no-op
+	applied, err = migrator.Up(ctx, false)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	// Act - revert newest first
+	reverted, err := migrator.Down(ctx, 1, false)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, 2, reverted[0].Version)
+
+	statuses, err = migrator.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'b'`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigrator_ChecksumMismatch(t *testing.T) {
+	// Arrange
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fsys := testMigrationsFS()
+	migrator, err := NewMigratorFS(db, fsys, "m")
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background(), false)
+	require.NoError(t, err)
+
+	// Act - an applied migration is edited afterwards
+	fsys["m/0001_create_a.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE a (id INTEGER PRIMARY KEY, x TEXT);")}
+	edited, err := NewMigratorFS(db, fsys, "m")
+	require.NoError(t, err)
+	_, err = edited.Up(context.Background(), false)
+
+	// Assert
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestMigrator_FailedMigrationRollsBack(t *testing.T) {
+	// Arrange
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fsys := testMigrationsFS()
+	fsys["m/0003_broken.up.sql"] = &fstest.MapFile{Data: []byte("CREATE TABLE c (id INTEGER); INSERT INTO missing VALUES (1);")}
+	migrator, err := NewMigratorFS(db, fsys, "m")
+	require.NoError(t, err)
+
+	// Act
+	applied, err := migrator.Up(context.Background(), false)
+
+	// Assert - earlier migrations stay applied, the broken one leaves no trace
+	assert.Error(t, err)
+	assert.Len(t, applied, 2)
+
+	var count int
+	require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE name = 'c'`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
+
+func TestMigrator_EmbeddedMigrationsLoad(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrator, err := NewMigrator(db)
+	require.NoError(t, err)
+
+	_, err = migrator.Up(context.Background(), false)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// dayLayout is the format of search_history_daily.day (SQLite's date())
+const dayLayout = "2006-01-02"
+
+// QueryStats returns totals per distinct query since the given time
+// Rolled up days count in full if they fall on or after since's UTC day
+func (r *SQLiteRepository) QueryStats(ctx context.Context, since time.Time) ([]entities.QueryStats, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT query, SUM(searches), SUM(zero_results), SUM(cache_hits) FROM (
+			SELECT query, COUNT(*) AS searches, SUM(results = 0) AS zero_results, SUM(cache_hit) AS cache_hits
+			FROM search_history
+			WHERE created_at >= ?
+			GROUP BY query
+			UNION ALL
+			SELECT query, searches, zero_results, cache_hits
+			FROM search_history_daily
+			WHERE day >= ?
+		)
+		GROUP BY query`,
+		since, since.UTC().Format(dayLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query query stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []entities.QueryStats
+	for rows.Next() {
+		var s entities.QueryStats
+		if err := rows.Scan(&s.Query, &s.Searches, &s.ZeroResults, &s.CacheHits); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return stats, nil
+}
+
+// DailyStats returns totals per UTC day since the given time, oldest first
+func (r *SQLiteRepository) DailyStats(ctx context.Context, since time.Time) ([]entities.DailySearchStats, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT day, SUM(searches), SUM(zero_results), SUM(cache_hits) FROM (
+			SELECT date(created_at) AS day, COUNT(*) AS searches, SUM(results = 0) AS zero_results, SUM(cache_hit) AS cache_hits
+			FROM search_history
+			WHERE created_at >= ?
+			GROUP BY day
+			UNION ALL
+			SELECT day, searches, zero_results, cache_hits
+			FROM search_history_daily
+			WHERE day >= ?
+		)
+		GROUP BY day
+		ORDER BY day`,
+		since, since.UTC().Format(dayLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []entities.DailySearchStats
+	for rows.Next() {
+		var s entities.DailySearchStats
+		var day string
+		if err := rows.Scan(&day, &s.Searches, &s.ZeroResults, &s.CacheHits); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if s.Day, err = time.Parse(dayLayout, day); err != nil {
+			return nil, fmt.Errorf("failed to parse day %q: %w", day, err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,46 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestSQLiteRepository_AnalyticsIncludeRollup(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	old := time.Now().UTC().Add(-72 * time.Hour)
+	for _, h := range []entities.SearchHistory{
+		{Query: "golang", Results: 0, CreatedAt: old},
+		{Query: "golang", Results: 5, CacheHit: true, CreatedAt: old},
+		{Query: "golang", Results: 5, CreatedAt: time.Now()},
+	} {
+		require.NoError(t, repo.Save(ctx, &h))
+	}
+
+	// Act - roll the old rows up, then aggregate across both tables
+	pruned, err := repo.PruneOlderThan(ctx, time.Now().Add(-24*time.Hour), 10, true)
+	require.NoError(t, err)
+	since := old.Add(-time.Hour)
+	queries, err1 := repo.QueryStats(ctx, since)
+	daily, err2 := repo.DailyStats(ctx, since)
+
+	// Assert
+	assert.Equal(t, int64(2), pruned)
+	require.NoError(t, err1)
+	require.NoError(t, err2)
+	assert.Equal(t, []entities.QueryStats{
+		{Query: "golang", Searches: 3, ZeroResults: 1, CacheHits: 1},
+	}, queries)
+	require.Len(t, daily, 2)
+	assert.Equal(t, old.Format(dayLayout), daily[0].Day.Format(dayLayout))
+	assert.Equal(t, 2, daily[0].Searches)
+	assert.Equal(t, 1, daily[0].ZeroResults)
+	assert.Equal(t, 1, daily[0].CacheHits)
+}
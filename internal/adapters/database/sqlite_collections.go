@@ -0,0 +1,344 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// selectCollectionItemsSQL reads a collection's items with their catalog metadata
+const selectCollectionItemsSQL = `
+SELECT v.id, v.title, v.channel, v.published_at, v.thumbnail, i.position, i.note, i.added_at
+FROM collection_items i
+JOIN videos v ON v.id = i.video_id
+WHERE i.collection_id = ?`
+
+// CreateCollection saves a collection and its items in one transaction
+// The items are replaced with what was stored, catalog metadata included
+func (r *SQLiteRepository) CreateCollection(ctx context.Context, collection *entities.Collection) error {
+	var id int64
+	var items []entities.CollectionItem
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkCollectionName(ctx, tx, collection.Name, 0); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO collections (name, description, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+			collection.Name, collection.Description, collection.CreatedAt, collection.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create collection: %w", err)
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		items = make([]entities.CollectionItem, 0, len(collection.Items))
+		for _, item := range collection.Items {
+			added, err := addCollectionItem(ctx, tx, id, item.Video, item.Note, item.AddedAt)
+			if err != nil {
+				return err
+			}
+			items = append(items, *added)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	collection.ID = id
+	collection.Items = items
+	return nil
+}
+
+// ListCollections returns every collection by name with its item count
+func (r *SQLiteRepository) ListCollections(ctx context.Context) ([]entities.Collection, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT c.id, c.name, c.description, c.created_at, c.updated_at, COUNT(i.video_id)
+		FROM collections c
+		LEFT JOIN collection_items i ON i.collection_id = c.id
+		GROUP BY c.id
+		ORDER BY c.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collections: %w", err)
+	}
+	defer rows.Close()
+
+	var collections []entities.Collection
+	for rows.Next() {
+		var c entities.Collection
+		if err := rows.Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt, &c.ItemCount); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		collections = append(collections, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return collections, nil
+}
+
+// GetCollection returns a collection with its items in position order
+func (r *SQLiteRepository) GetCollection(ctx context.Context, id int64) (*entities.Collection, error) {
+	var c entities.Collection
+	err := r.readDB.QueryRowContext(ctx,
+		`SELECT id, name, description, created_at, updated_at FROM collections WHERE id = ?`, id,
+	).Scan(&c.ID, &c.Name, &c.Description, &c.CreatedAt, &c.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErrors.NewNotFoundError("Collection")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	rows, err := r.readDB.QueryContext(ctx, selectCollectionItemsSQL+` ORDER BY i.position`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collection items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanCollectionItem(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		c.Items = append(c.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	c.ItemCount = len(c.Items)
+	return &c, nil
+}
+
+// UpdateCollection renames a collection and replaces its description
+func (r *SQLiteRepository) UpdateCollection(ctx context.Context, id int64, name, description string, updatedAt time.Time) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if err := checkCollectionName(ctx, tx, name, id); err != nil {
+			return err
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE collections SET name = ?, description = ?, updated_at = ? WHERE id = ?`,
+			name, description, updatedAt, id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update collection: %w", err)
+		}
+		return requireAffected(result, "Collection")
+	})
+}
+
+// DeleteCollection removes a collection (its items go with it)
+func (r *SQLiteRepository) DeleteCollection(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM collections WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	return requireAffected(result, "Collection")
+}
+
+// AddCollectionItem appends a video to a collection
+// The video's metadata is only stored if the catalog doesn't know it yet
+func (r *SQLiteRepository) AddCollectionItem(ctx context.Context, collectionID int64, video entities.Video, note string, addedAt time.Time) (*entities.CollectionItem, error) {
+	var item *entities.CollectionItem
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM collections WHERE id = ?)`, collectionID,
+		).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to get collection: %w", err)
+		}
+		if !exists {
+			return appErrors.NewNotFoundError("Collection")
+		}
+
+		var err error
+		item, err = addCollectionItem(ctx, tx, collectionID, video, note, addedAt)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// SetCollectionItemNote replaces an item's note
+func (r *SQLiteRepository) SetCollectionItemNote(ctx context.Context, collectionID int64, videoID, note string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE collection_items SET note = ? WHERE collection_id = ? AND video_id = ?`,
+		note, collectionID, videoID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update collection item: %w", err)
+	}
+	return requireAffected(result, "Collection item")
+}
+
+// MoveCollectionItem puts an item at position, shifting the items in between
+func (r *SQLiteRepository) MoveCollectionItem(ctx context.Context, collectionID int64, videoID string, position int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := collectionItemPosition(ctx, tx, collectionID, videoID)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx,
+			`SELECT COUNT(*) FROM collection_items WHERE collection_id = ?`, collectionID,
+		).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count collection items: %w", err)
+		}
+		target := max(min(position, count), 1)
+
+		switch {
+		case target < current:
+			_, err = tx.ExecContext(ctx, `UPDATE collection_items SET position = position + 1
+				WHERE collection_id = ? AND position >= ? AND position < ?`, collectionID, target, current)
+		case target > current:
+			_, err = tx.ExecContext(ctx, `UPDATE collection_items SET position = position - 1
+				WHERE collection_id = ? AND position > ? AND position <= ?`, collectionID, current, target)
+		default:
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to shift collection items: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE collection_items SET position = ? WHERE collection_id = ? AND video_id = ?`,
+			target, collectionID, videoID,
+		); err != nil {
+			return fmt.Errorf("failed to move collection item: %w", err)
+		}
+		return nil
+	})
+}
+
+// RemoveCollectionItem takes a video out of a collection and closes the gap
+func (r *SQLiteRepository) RemoveCollectionItem(ctx context.Context, collectionID int64, videoID string) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		position, err := collectionItemPosition(ctx, tx, collectionID, videoID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM collection_items WHERE collection_id = ? AND video_id = ?`, collectionID, videoID,
+		); err != nil {
+			return fmt.Errorf("failed to remove collection item: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE collection_items SET position = position - 1 WHERE collection_id = ? AND position > ?`,
+			collectionID, position,
+		); err != nil {
+			return fmt.Errorf("failed to renumber collection items: %w", err)
+		}
+		return nil
+	})
+}
+
+// addCollectionItem appends a video unless it's already in the collection
+func addCollectionItem(ctx context.Context, tx *sql.Tx, collectionID int64, video entities.Video, note string, addedAt time.Time) (*entities.CollectionItem, error) {
+	existing, err := scanCollectionItem(tx.QueryRowContext(ctx,
+		selectCollectionItemsSQL+` AND i.video_id = ?`, collectionID, video.ID,
+	))
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get collection item: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, insertVideoSQL,
+		video.ID, video.Title, video.Channel, nullTime(video.PublishedAt), video.Thumbnail, video.Description, addedAt, addedAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to save video %s: %w", video.ID, err)
+	}
+
+	var position int
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(MAX(position), 0) + 1 FROM collection_items WHERE collection_id = ?`, collectionID,
+	).Scan(&position); err != nil {
+		return nil, fmt.Errorf("failed to get next position: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO collection_items (collection_id, video_id, position, note, added_at) VALUES (?, ?, ?, ?, ?)`,
+		collectionID, video.ID, position, note, addedAt,
+	); err != nil {
+		return nil, fmt.Errorf("failed to add video %s: %w", video.ID, err)
+	}
+
+	added, err := scanCollectionItem(tx.QueryRowContext(ctx,
+		selectCollectionItemsSQL+` AND i.video_id = ?`, collectionID, video.ID,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get collection item: %w", err)
+	}
+	return &added, nil
+}
+
+// checkCollectionName fails if another collection (other than id) has the name
+// Names compare case-insensitively, like the UNIQUE constraint
+func checkCollectionName(ctx context.Context, tx *sql.Tx, name string, id int64) error {
+	var taken bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM collections WHERE name = ? AND id != ?)`, name, id,
+	).Scan(&taken); err != nil {
+		return fmt.Errorf("failed to check collection name: %w", err)
+	}
+	if taken {
+		return appErrors.NewValidationError(fmt.Sprintf("a collection named %q already exists", name), nil)
+	}
+	return nil
+}
+
+// collectionItemPosition returns an item's position (NotFound if it isn't in the collection)
+func collectionItemPosition(ctx context.Context, tx *sql.Tx, collectionID int64, videoID string) (int, error) {
+	var position int
+	err := tx.QueryRowContext(ctx,
+		`SELECT position FROM collection_items WHERE collection_id = ? AND video_id = ?`, collectionID, videoID,
+	).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, appErrors.NewNotFoundError("Collection item")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get collection item: %w", err)
+	}
+	return position, nil
+}
+
+// requireAffected returns a NotFound AppError for resource if no row changed
+func requireAffected(result sql.Result, resource string) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return appErrors.NewNotFoundError(resource)
+	}
+	return nil
+}
+
+// scanCollectionItem reads the columns of selectCollectionItemsSQL
+func scanCollectionItem(row rowScanner) (entities.CollectionItem, error) {
+	var item entities.CollectionItem
+	var published sql.NullTime
+	if err := row.Scan(&item.ID, &item.Title, &item.Channel, &published, &item.Thumbnail, &item.Position, &item.Note, &item.AddedAt); err != nil {
+		return item, err
+	}
+	item.PublishedAt = published.Time
+	return item, nil
+}
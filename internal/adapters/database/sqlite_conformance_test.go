@@ -0,0 +1,80 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/ports/porttest"
+)
+
+func TestSQLiteRepository_Conformance(t *testing.T) {
+	porttest.RunSearchHistoryRepositoryTests(t, func(t *testing.T) ports.SearchHistoryRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_AnalyticsConformance(t *testing.T) {
+	porttest.RunSearchAnalyticsTests(t, func(t *testing.T) porttest.AnalyticsRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_SavedVideosConformance(t *testing.T) {
+	porttest.RunSavedVideoRepositoryTests(t, func(t *testing.T) ports.SavedVideoRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_CollectionsConformance(t *testing.T) {
+	porttest.RunCollectionRepositoryTests(t, func(t *testing.T) ports.CollectionRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_SubscriptionsConformance(t *testing.T) {
+	porttest.RunSubscriptionRepositoryTests(t, func(t *testing.T) ports.SubscriptionRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_SavedSearchesConformance(t *testing.T) {
+	porttest.RunSavedSearchRepositoryTests(t, func(t *testing.T) ports.SavedSearchRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_WebhooksConformance(t *testing.T) {
+	porttest.RunWebhookDeliveryRepositoryTests(t, func(t *testing.T) ports.WebhookDeliveryRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_DigestsConformance(t *testing.T) {
+	porttest.RunDigestRepositoryTests(t, func(t *testing.T) ports.DigestRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_WatchEventsConformance(t *testing.T) {
+	porttest.RunWatchEventRepositoryTests(t, func(t *testing.T) ports.WatchEventRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_VideoProgressConformance(t *testing.T) {
+	porttest.RunVideoProgressRepositoryTests(t, func(t *testing.T) ports.VideoProgressRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_FocusSessionsConformance(t *testing.T) {
+	porttest.RunFocusSessionRepositoryTests(t, func(t *testing.T) ports.FocusSessionRepository {
+		return newTestRepository(t)
+	})
+}
+
+func TestSQLiteRepository_SearchIntentsConformance(t *testing.T) {
+	porttest.RunSearchIntentRepositoryTests(t, func(t *testing.T) ports.SearchIntentRepository {
+		return newTestRepository(t)
+	})
+}
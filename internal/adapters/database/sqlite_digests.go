@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// LastDigestAt returns when the latest digest was recorded, zero if none was
+func (r *SQLiteRepository) LastDigestAt(ctx context.Context) (time.Time, error) {
+	var sentAt time.Time
+	err := r.readDB.QueryRowContext(ctx,
+		`SELECT sent_at FROM digests ORDER BY sent_at DESC LIMIT 1`,
+	).Scan(&sentAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last digest: %w", err)
+	}
+	return sentAt, nil
+}
+
+// RecordDigest stores a digest run and how many videos it listed
+func (r *SQLiteRepository) RecordDigest(ctx context.Context, sentAt time.Time, videoCount int) error {
+	if _, err := r.db.ExecContext(ctx,
+		`INSERT INTO digests (sent_at, video_count) VALUES (?, ?)`, sentAt, videoCount,
+	); err != nil {
+		return fmt.Errorf("failed to record digest: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// selectFocusSessionsSQL reads focus sessions
+const selectFocusSessionsSQL = `
+SELECT id, profile, started_at, ends_at, ended_at, reflection
+FROM focus_sessions`
+
+// AddFocusSession stores a new session and sets its ID
+func (r *SQLiteRepository) AddFocusSession(ctx context.Context, session *entities.FocusSession) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO focus_sessions (profile, started_at, ends_at, ended_at, reflection) VALUES (?, ?, ?, ?, ?)`,
+		session.Profile, session.StartedAt, session.EndsAt, nullTime(session.EndedAt), session.Reflection,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add focus session: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	session.ID = id
+	return nil
+}
+
+// CurrentFocusSession returns the profile's latest session that has not ended
+func (r *SQLiteRepository) CurrentFocusSession(ctx context.Context, profile string) (*entities.FocusSession, error) {
+	row := r.readDB.QueryRowContext(ctx,
+		selectFocusSessionsSQL+` WHERE profile = ? AND ended_at IS NULL ORDER BY started_at DESC, id DESC LIMIT 1`, profile,
+	)
+
+	s, err := scanFocusSession(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErrors.NewNotFoundError("Focus session")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get focus session: %w", err)
+	}
+	return &s, nil
+}
+
+// EndFocusSession closes a session of the profile with its reflection
+func (r *SQLiteRepository) EndFocusSession(ctx context.Context, profile string, id int64, endedAt time.Time, reflection string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE focus_sessions SET ended_at = ?, reflection = ? WHERE id = ? AND profile = ?`, endedAt, reflection, id, profile,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to end focus session: %w", err)
+	}
+	return requireAffected(result, "Focus session")
+}
+
+// ListFocusSessions returns up to limit sessions of a profile, most recently started first
+func (r *SQLiteRepository) ListFocusSessions(ctx context.Context, profile string, limit int) ([]entities.FocusSession, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		selectFocusSessionsSQL+` WHERE profile = ? ORDER BY started_at DESC, id DESC LIMIT ?`, profile, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query focus sessions: %w", err)
+	}
+	defer rows.Close()
+
+	sessions := []entities.FocusSession{}
+	for rows.Next() {
+		s, err := scanFocusSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan focus session: %w", err)
+		}
+		sessions = append(sessions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read focus sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+func scanFocusSession(row rowScanner) (entities.FocusSession, error) {
+	var s entities.FocusSession
+	var endedAt sql.NullTime
+	if err := row.Scan(&s.ID, &s.Profile, &s.StartedAt, &s.EndsAt, &endedAt, &s.Reflection); err != nil {
+		return s, err
+	}
+	s.EndedAt = endedAt.Time
+	return s, nil
+}
@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+func TestSQLiteRepository_ListWithFiltersAndCursor(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	rows := []entities.SearchHistory{
+		{Query: "Golang generics", Results: 10, CreatedAt: base},
+		{Query: "golang channels", Results: 0, CreatedAt: base.Add(time.Minute)},
+		{Query: "rust", Results: 5, CreatedAt: base.Add(2 * time.Minute)},
+		{Query: "golang tutorial", Results: 7, CreatedAt: base.Add(2 * time.Minute)}, // same time, higher id
+	}
+	for i := range rows {
+		require.NoError(t, repo.Save(ctx, &rows[i]))
+	}
+
+	// Act
+	filter := entities.SearchHistoryFilter{Contains: "GOLANG", MinResults: 1}
+	first, err := repo.List(ctx, filter, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, first, 1)
+
+	cursor := &entities.SearchHistoryCursor{CreatedAt: first[0].CreatedAt, ID: first[0].ID}
+	second, err := repo.List(ctx, filter, cursor, 10)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, "golang tutorial", first[0].Query)
+	require.Len(t, second, 1)
+	assert.Equal(t, "Golang generics", second[0].Query)
+
+	ranged, err := repo.List(ctx, entities.SearchHistoryFilter{
+		From: base.Add(30 * time.Second),
+		To:   base.Add(90 * time.Second),
+	}, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, ranged, 1)
+	assert.Equal(t, "golang channels", ranged[0].Query)
+
+	// Ties on created_at are broken by id, so paging never skips a row
+	all, err := repo.List(ctx, entities.SearchHistoryFilter{}, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, []string{"golang tutorial", "rust"}, []string{all[0].Query, all[1].Query})
+}
+
+func TestSQLiteRepository_Delete(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	h := &entities.SearchHistory{Query: "golang", Results: 1, CreatedAt: time.Now()}
+	require.NoError(t, repo.Save(ctx, h))
+	require.NoError(t, repo.SaveSearchResults(ctx, h.ID, []entities.Video{{ID: "a", Title: "A"}}))
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "rust", Results: 1, CreatedAt: time.Now()}))
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "zig", Results: 1, CreatedAt: time.Now()}))
+
+	// Act & Assert
+	require.NoError(t, repo.Delete(ctx, h.ID))
+
+	err := repo.Delete(ctx, h.ID)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+
+	results, err := repo.GetSearchResults(ctx, h.ID)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	matches, err := repo.FullTextSearch(ctx, "golang", 10)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	deleted, err := repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+}
+
+func TestSQLiteRepository_SaveBatch(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	batch := []*entities.SearchHistory{
+		{Query: "golang", Results: 1, CreatedAt: time.Now()},
+		{Query: "rust", Results: 2, CreatedAt: time.Now()},
+	}
+
+	// Act
+	require.NoError(t, repo.SaveBatch(ctx, batch))
+
+	// Assert
+	assert.NotZero(t, batch[0].ID)
+	assert.Equal(t, batch[0].ID+1, batch[1].ID)
+
+	// A bad row rolls back the whole batch and leaves IDs unset
+	bad := []*entities.SearchHistory{
+		{Query: "zig", Results: 1, CreatedAt: time.Now()},
+		{Query: "", Results: 1, CreatedAt: time.Now()},
+	}
+	assert.Error(t, repo.SaveBatch(ctx, bad))
+	assert.Zero(t, bad[0].ID)
+
+	rows, err := repo.GetLast(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, rows, 2)
+}
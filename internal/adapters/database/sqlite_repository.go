@@ -4,42 +4,34 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
 )
 
+// SQLiteRepository routes writes to a single-connection pool (db) and
+// queries to a read-only pool (readDB)
 type SQLiteRepository struct {
-	db          *sql.DB
-	saveStmt    *sql.Stmt
-	getLastStmt *sql.Stmt
+	db                *sql.DB
+	readDB            *sql.DB
+	saveStmt          *sql.Stmt
+	getLastStmt       *sql.Stmt
+	getTopQueriesStmt *sql.Stmt
 }
 
 // NewSQLiteRepository creates a new SQLite repository with optimized settings
+// Pending schema migrations are applied on startup
 func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
-	// Open with SQLite-specific optimizations
-	// WAL mode enables concurrent reads and better performance
-	db, err := sql.Open("sqlite3", dbPath+"?_journal_mode=WAL&_timeout=5000&_fk=true")
+	db, err := OpenWriter(dbPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to open database: %w", err)
+		return nil, err
 	}
 
-	// Configure connection pool for optimal performance
-	// SQLite benefits from a single writer, but multiple readers
-	db.SetMaxOpenConns(25)           // Limit concurrent connections
-	db.SetMaxIdleConns(5)            // Keep some connections ready
-	db.SetConnMaxLifetime(time.Hour) // Recycle connections periodically
-
-	// Test connection
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	if err := db.PingContext(ctx); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to ping database: %w", err)
-	}
-
 	repo := &SQLiteRepository{db: db}
 
 	// Initialize schema
@@ -48,8 +40,16 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 		return nil, fmt.Errorf("failed to initialize schema: %w", err)
 	}
 
+	// Readers open after migrations so they never see a half-built schema
+	repo.readDB, err = OpenReader(dbPath, readerMaxOpenConns, readerMaxIdleConns)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	// Prepare frequently used statements for better performance
 	if err := repo.prepareStatements(); err != nil {
+		repo.readDB.Close()
 		db.Close()
 		return nil, fmt.Errorf("failed to prepare statements: %w", err)
 	}
@@ -57,35 +57,88 @@ func NewSQLiteRepository(dbPath string) (*SQLiteRepository, error) {
 	return repo, nil
 }
 
-func (r *SQLiteRepository) initSchema(ctx context.Context) error {
+// Pool sizes for the repository's read-only pool
+const (
+	readerMaxOpenConns = 25
+	readerMaxIdleConns = 5
+)
+
+// pragmas are applied to new pools (per connection settings only reach the
+// first connection, hence connOptions for anything that matters)
+const pragmas = `
+PRAGMA synchronous=NORMAL;
+PRAGMA cache_size=-64000;
+PRAGMA temp_store=MEMORY;
+PRAGMA busy_timeout=5000;
+`
+
+// Open opens and pings a SQLite database with the performance pragmas applied
+// It does not touch the schema (see Migrator)
+// Used by maintenance commands; the server uses OpenWriter and OpenReader
+func Open(dbPath string) (*sql.DB, error) {
+	// Configure connection pool for optimal performance
+	// SQLite benefits from a single writer, but multiple readers
+	return openPool(buildDSN(dbPath, connOptions{}), 25, 5)
+}
+
+// OpenWriter opens the single-connection pool every write goes through
+// SQLite allows one writer at a time, so writers queue on the pool instead of
+// contending for the file lock, and _txlock=immediate takes the write lock at
+// BEGIN so transactions never fail upgrading a read lock
+// Databases it creates use incremental auto-vacuum (see Optimize)
+func OpenWriter(dbPath string) (*sql.DB, error) {
+	return openPool(buildDSN(dbPath, connOptions{TxImmediate: true, IncrementalVacuum: true}), 1, 1)
+}
+
+// OpenReader opens a read-only pool for queries
+// WAL lets these run concurrently with the writer; query_only rejects any write
+func OpenReader(dbPath string, maxOpen, maxIdle int) (*sql.DB, error) {
+	return openPool(buildDSN(dbPath, connOptions{QueryOnly: true}), maxOpen, maxIdle)
+}
+
+// openPool opens, sizes and pings a connection pool and applies the pragmas
+func openPool(dsn string, maxOpen, maxIdle int) (*sql.DB, error) {
+	db, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db.SetMaxOpenConns(maxOpen)      // Limit concurrent connections
+	db.SetMaxIdleConns(maxIdle)      // Keep some connections ready
+	db.SetConnMaxLifetime(time.Hour) // Recycle connections periodically
+
+	// Test connection
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
 	// Apply performance pragmas
-	pragmas := `
-	PRAGMA journal_mode=WAL;
-	PRAGMA synchronous=NORMAL;
-	PRAGMA cache_size=-64000;
-	PRAGMA temp_store=MEMORY;
-	PRAGMA busy_timeout=5000;
-	`
-
-	if _, err := r.db.ExecContext(ctx, pragmas); err != nil {
-		return fmt.Errorf("failed to set pragmas: %w", err)
-	}
-
-	// Create schema with proper constraints
-	schema := `
-	CREATE TABLE IF NOT EXISTS search_history (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		query TEXT NOT NULL CHECK(length(query) > 0),
-		results INTEGER NOT NULL CHECK(results >= 0),
-		created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
-	);
-	
-	CREATE INDEX IF NOT EXISTS idx_created_at ON search_history(created_at DESC);
-	CREATE INDEX IF NOT EXISTS idx_query ON search_history(query);
-	`
-
-	if _, err := r.db.ExecContext(ctx, schema); err != nil {
-		return fmt.Errorf("failed to create schema: %w", err)
+	if _, err := db.ExecContext(ctx, pragmas); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to set pragmas: %w", err)
+	}
+
+	return db, nil
+}
+
+// initSchema brings the schema up to date using the embedded migrations
+// Migrations create FTS5 tables, so a SQLite without FTS5 is rejected first
+func (r *SQLiteRepository) initSchema(ctx context.Context) error {
+	if err := requireFTS5(ctx, r.db); err != nil {
+		return err
+	}
+
+	migrator, err := NewMigrator(r.db)
+	if err != nil {
+		return err
+	}
+
+	if _, err := migrator.Up(ctx, false); err != nil {
+		return fmt.Errorf("failed to apply migrations: %w", err)
 	}
 
 	return nil
@@ -96,25 +149,37 @@ func (r *SQLiteRepository) prepareStatements() error {
 
 	// Prepare INSERT statement
 	r.saveStmt, err = r.db.Prepare(
-		`INSERT INTO search_history (query, results, created_at) VALUES (?, ?, ?)`,
+		`INSERT INTO search_history (query, results, cache_hit, intent_id, created_at) VALUES (?, ?, ?, ?, ?)`,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to prepare save statement: %w", err)
 	}
 
 	// Prepare SELECT statement
-	r.getLastStmt, err = r.db.Prepare(
-		`SELECT id, query, results, created_at FROM search_history ORDER BY created_at DESC LIMIT ?`,
+	r.getLastStmt, err = r.readDB.Prepare(
+		`SELECT id, query, results, cache_hit, intent_id, created_at FROM search_history ORDER BY created_at DESC LIMIT ?`,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to prepare getLastStmt: %w", err)
 	}
 
+	// Prepare aggregate statement for popular queries
+	r.getTopQueriesStmt, err = r.readDB.Prepare(
+		`SELECT query, COUNT(*) AS count FROM search_history
+		WHERE created_at >= ?
+		GROUP BY query
+		ORDER BY count DESC, MAX(created_at) DESC
+		LIMIT ?`,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to prepare getTopQueriesStmt: %w", err)
+	}
+
 	return nil
 }
 
 func (r *SQLiteRepository) Save(ctx context.Context, history *entities.SearchHistory) error {
-	result, err := r.saveStmt.ExecContext(ctx, history.Query, history.Results, history.CreatedAt)
+	result, err := r.saveStmt.ExecContext(ctx, history.Query, history.Results, history.CacheHit, nullID(history.IntentID), history.CreatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to save search history: %w", err)
 	}
@@ -128,6 +193,39 @@ func (r *SQLiteRepository) Save(ctx context.Context, history *entities.SearchHis
 	return nil
 }
 
+// SaveBatch saves histories in a single transaction (all or nothing)
+func (r *SQLiteRepository) SaveBatch(ctx context.Context, histories []*entities.SearchHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(histories))
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		stmt := tx.StmtContext(ctx, r.saveStmt)
+		defer stmt.Close()
+
+		for i, h := range histories {
+			result, err := stmt.ExecContext(ctx, h.Query, h.Results, h.CacheHit, nullID(h.IntentID), h.CreatedAt)
+			if err != nil {
+				return fmt.Errorf("failed to save search history: %w", err)
+			}
+			if ids[i], err = result.LastInsertId(); err != nil {
+				return fmt.Errorf("failed to get last insert id: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Only hand out IDs once the transaction has committed
+	for i, h := range histories {
+		h.ID = ids[i]
+	}
+	return nil
+}
+
 func (r *SQLiteRepository) GetLast(ctx context.Context, limit int) ([]entities.SearchHistory, error) {
 	rows, err := r.getLastStmt.QueryContext(ctx, limit)
 	if err != nil {
@@ -137,8 +235,86 @@ func (r *SQLiteRepository) GetLast(ctx context.Context, limit int) ([]entities.S
 
 	var histories []entities.SearchHistory
 	for rows.Next() {
-		var h entities.SearchHistory
-		if err := rows.Scan(&h.ID, &h.Query, &h.Results, &h.CreatedAt); err != nil {
+		h, err := scanSearchHistory(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		histories = append(histories, h)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return histories, nil
+}
+
+func (r *SQLiteRepository) GetTopQueries(ctx context.Context, since time.Time, limit int) ([]entities.QueryFrequency, error) {
+	rows, err := r.getTopQueriesStmt.QueryContext(ctx, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top queries: %w", err)
+	}
+	defer rows.Close()
+
+	var queries []entities.QueryFrequency
+	for rows.Next() {
+		var q entities.QueryFrequency
+		if err := rows.Scan(&q.Query, &q.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		queries = append(queries, q)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return queries, nil
+}
+
+// List returns search history matching filter, newest first, after the cursor
+func (r *SQLiteRepository) List(ctx context.Context, filter entities.SearchHistoryFilter, after *entities.SearchHistoryCursor, limit int) ([]entities.SearchHistory, error) {
+	var where []string
+	var args []interface{}
+
+	if !filter.From.IsZero() {
+		where = append(where, "created_at >= ?")
+		args = append(args, filter.From)
+	}
+	if !filter.To.IsZero() {
+		where = append(where, "created_at < ?")
+		args = append(args, filter.To)
+	}
+	if filter.Contains != "" {
+		where = append(where, "instr(lower(query), lower(?)) > 0")
+		args = append(args, filter.Contains)
+	}
+	if filter.MinResults > 0 {
+		where = append(where, "results >= ?")
+		args = append(args, filter.MinResults)
+	}
+	if after != nil {
+		where = append(where, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, after.CreatedAt, after.CreatedAt, after.ID)
+	}
+
+	query := `SELECT id, query, results, cache_hit, intent_id, created_at FROM search_history`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY created_at DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list search history: %w", err)
+	}
+	defer rows.Close()
+
+	var histories []entities.SearchHistory
+	for rows.Next() {
+		h, err := scanSearchHistory(rows)
+		if err != nil {
 			return nil, fmt.Errorf("failed to scan row: %w", err)
 		}
 		histories = append(histories, h)
@@ -151,6 +327,166 @@ func (r *SQLiteRepository) GetLast(ctx context.Context, limit int) ([]entities.S
 	return histories, nil
 }
 
+// Delete removes one search history row (its search results go with it)
+func (r *SQLiteRepository) Delete(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM search_history WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete search history: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return appErrors.NewNotFoundError("Search history entry")
+	}
+	return nil
+}
+
+// DeleteAll removes every search history row
+// The daily rollup is kept, since it holds aggregates rather than individual searches
+func (r *SQLiteRepository) DeleteAll(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM search_history`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete search history: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return n, nil
+}
+
+// FullTextSearch finds past searches matching text, best matches first
+// Repeated searches for the same query are collapsed into the most relevant one
+// Searches are also found by the titles and descriptions of the videos they
+// returned; those follow the query matches, attached to the latest search
+// that returned them
+func (r *SQLiteRepository) FullTextSearch(ctx context.Context, text string, limit int) ([]entities.SearchHistoryMatch, error) {
+	match := matchExpression(text)
+	if match == "" {
+		return nil, nil
+	}
+
+	query := fmt.Sprintf(`
+		SELECT h.id, h.query, h.results, h.created_at, %s
+		FROM search_history_fts
+		JOIN search_history h ON h.id = search_history_fts.rowid
+		WHERE search_history_fts MATCH ?
+		ORDER BY bm25(search_history_fts), h.created_at DESC
+		LIMIT ?`, highlightColumn("search_history_fts", 0))
+
+	// Over-fetch so collapsing duplicates still fills the page
+	rows, err := r.readDB.QueryContext(ctx, query, match, limit*4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search history: %w", err)
+	}
+	defer rows.Close()
+
+	byQuery := make(map[string]int) // Index into matches
+	var matches []entities.SearchHistoryMatch
+	for rows.Next() && len(matches) < limit {
+		var m entities.SearchHistoryMatch
+		var highlighted string
+		if err := rows.Scan(&m.ID, &m.Query, &m.Results, &m.CreatedAt, &highlighted); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		if _, seen := byQuery[m.Query]; seen {
+			continue
+		}
+		byQuery[m.Query] = len(matches)
+		m.Highlighted = splitHighlight(highlighted)
+		matches = append(matches, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return r.addVideoMatches(ctx, match, matches, byQuery, limit)
+}
+
+// addVideoMatches attaches catalog videos matching the MATCH expression to the
+// latest search that returned them, adding that search if it isn't listed yet
+func (r *SQLiteRepository) addVideoMatches(ctx context.Context, match string, matches []entities.SearchHistoryMatch, byQuery map[string]int, limit int) ([]entities.SearchHistoryMatch, error) {
+	query := fmt.Sprintf(`
+		SELECT v.id, v.title, v.channel, v.published_at, v.thumbnail, v.description, %s,
+			h.id, h.query, h.results, h.created_at
+		FROM videos_fts
+		JOIN videos v ON v.id = videos_fts.video_id
+		JOIN search_history h ON h.id = (
+			SELECT sr.search_id FROM search_results sr
+			JOIN search_history latest ON latest.id = sr.search_id
+			WHERE sr.video_id = v.id
+			ORDER BY latest.created_at DESC, latest.id DESC
+			LIMIT 1
+		)
+		WHERE videos_fts MATCH ?
+		ORDER BY bm25(videos_fts)
+		LIMIT ?`, highlightColumn("videos_fts", 1))
+
+	rows, err := r.readDB.QueryContext(ctx, query, match, limit*4)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search videos: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var vm entities.VideoMatch
+		var h entities.SearchHistory
+		var published sql.NullTime
+		var highlighted string
+		if err := rows.Scan(
+			&vm.ID, &vm.Title, &vm.Channel, &published, &vm.Thumbnail, &vm.Description, &highlighted,
+			&h.ID, &h.Query, &h.Results, &h.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		vm.PublishedAt = published.Time
+		vm.HighlightedTitle = splitHighlight(highlighted)
+
+		i, seen := byQuery[h.Query]
+		if !seen {
+			if len(matches) >= limit {
+				continue
+			}
+			i = len(matches)
+			byQuery[h.Query] = i
+			matches = append(matches, entities.SearchHistoryMatch{
+				SearchHistory: h,
+				Highlighted:   []entities.TextSegment{{Text: h.Query}},
+			})
+		}
+		matches[i].Videos = append(matches[i].Videos, vm)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return matches, nil
+}
+
+// inTx runs fn in a transaction, rolling back on error
+func inTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
 // Close gracefully closes all prepared statements and the database connection
 func (r *SQLiteRepository) Close() error {
 	var errs []error
@@ -168,7 +504,19 @@ func (r *SQLiteRepository) Close() error {
 		}
 	}
 
-	// Close database connection
+	if r.getTopQueriesStmt != nil {
+		if err := r.getTopQueriesStmt.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close getTopQueriesStmt: %w", err))
+		}
+	}
+
+	// Close database connections
+	if r.readDB != nil {
+		if err := r.readDB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close read pool: %w", err))
+		}
+	}
+
 	if r.db != nil {
 		if err := r.db.Close(); err != nil {
 			errs = append(errs, fmt.Errorf("failed to close database: %w", err))
@@ -183,7 +531,19 @@ func (r *SQLiteRepository) Close() error {
 	return nil
 }
 
-// DB returns the underlying database connection for health checks
+// DB returns the read-only pool for health checks, backups and exports
+// None of them write to the database, so they never hold the single writer
+// connection that every write queues on
 func (r *SQLiteRepository) DB() *sql.DB {
-	return r.db
+	return r.readDB
+}
+
+func scanSearchHistory(row rowScanner) (entities.SearchHistory, error) {
+	var h entities.SearchHistory
+	var intent sql.NullInt64
+	if err := row.Scan(&h.ID, &h.Query, &h.Results, &h.CacheHit, &intent, &h.CreatedAt); err != nil {
+		return h, err
+	}
+	h.IntentID = intent.Int64
+	return h, nil
 }
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// benchmarkWriters is the number of goroutines per CPU saving history at once
+const benchmarkWriters = 16
+
+// BenchmarkConcurrentSaves_SharedPool saves history through one 25-connection
+// pool, where every connection competes for the SQLite write lock
+func BenchmarkConcurrentSaves_SharedPool(b *testing.B) {
+	path := filepath.Join(b.TempDir(), "bench.db")
+
+	// Create the schema, then reopen the way the repository used to
+	repo, err := NewSQLiteRepository(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	repo.Close()
+
+	db, err := Open(path)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	stmt, err := db.Prepare(`INSERT INTO search_history (query, results, created_at) VALUES (?, ?, ?)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer stmt.Close()
+
+	runConcurrentSaves(b, func(ctx context.Context) error {
+		_, err := stmt.ExecContext(ctx, "golang", 10, time.Now())
+		return err
+	})
+}
+
+// BenchmarkConcurrentSaves_SplitPools saves history through the repository's
+// single-connection writer pool
+func BenchmarkConcurrentSaves_SplitPools(b *testing.B) {
+	repo, err := NewSQLiteRepository(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer repo.Close()
+
+	runConcurrentSaves(b, func(ctx context.Context) error {
+		return repo.Save(ctx, &entities.SearchHistory{Query: "golang", Results: 10, CreatedAt: time.Now()})
+	})
+}
+
+// BenchmarkConcurrentReadsDuringSaves measures reads while writers are busy
+func BenchmarkConcurrentReadsDuringSaves(b *testing.B) {
+	repo, err := NewSQLiteRepository(filepath.Join(b.TempDir(), "bench.db"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer repo.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Background writer keeps the write lock busy
+	go func() {
+		for ctx.Err() == nil {
+			_ = repo.Save(ctx, &entities.SearchHistory{Query: "golang", Results: 10, CreatedAt: time.Now()})
+		}
+	}()
+
+	runConcurrentSaves(b, func(ctx context.Context) error {
+		_, err := repo.GetLast(ctx, 10)
+		return err
+	})
+}
+
+// runConcurrentSaves runs op from many goroutines and fails on the first error
+func runConcurrentSaves(b *testing.B, op func(ctx context.Context) error) {
+	b.Helper()
+	b.SetParallelism(benchmarkWriters)
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		ctx := context.Background()
+		for pb.Next() {
+			if err := op(ctx); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// newTestRepository opens a migrated repository in a temporary directory and
+// closes it when the test ends.
+func newTestRepository(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	repo, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+	return repo
+}
+
+func TestSQLiteRepository_ConcurrentSaves(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	const writers, perWriter = 20, 25
+
+	// Act - writers queue on the single writer connection instead of failing busy
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				h := &entities.SearchHistory{Query: "golang", Results: i, CreatedAt: time.Now()}
+				if err := repo.Save(ctx, h); err != nil {
+					errs <- err
+					continue
+				}
+				if err := repo.SaveSearchResults(ctx, h.ID, []entities.Video{{ID: "v", Title: "V"}}); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	// Assert
+	for err := range errs {
+		t.Error(err)
+	}
+
+	var count int
+	require.NoError(t, repo.readDB.QueryRow(`SELECT COUNT(*) FROM search_history`).Scan(&count))
+	assert.Equal(t, writers*perWriter, count)
+}
+
+func TestSQLiteRepository_ReadPoolIsReadOnly(t *testing.T) {
+	repo := newTestRepository(t)
+
+	_, err := repo.readDB.Exec(`INSERT INTO search_history (query, results) VALUES ('x', 1)`)
+	assert.Error(t, err)
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// rollupSQL aggregates the selected search_history rows into search_history_daily
+// %s is replaced by a subquery selecting the ids to prune
+const rollupSQL = `
+INSERT INTO search_history_daily (day, query, searches, total_results, zero_results, cache_hits)
+SELECT date(created_at), query, COUNT(*), SUM(results), SUM(results = 0), SUM(cache_hit) FROM search_history
+WHERE id IN (%s)
+GROUP BY date(created_at), query
+ON CONFLICT(day, query) DO UPDATE SET
+	searches = searches + excluded.searches,
+	total_results = total_results + excluded.total_results,
+	zero_results = zero_results + excluded.zero_results,
+	cache_hits = cache_hits + excluded.cache_hits`
+
+// PruneOlderThan deletes up to batchSize search_history rows created before cutoff
+// With rollup, the rows are aggregated into search_history_daily in the same transaction
+func (r *SQLiteRepository) PruneOlderThan(ctx context.Context, cutoff time.Time, batchSize int, rollup bool) (int64, error) {
+	var deleted int64
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var err error
+		deleted, err = pruneBatch(ctx, tx,
+			`SELECT id FROM search_history WHERE created_at < ? ORDER BY id LIMIT ?`,
+			[]interface{}{cutoff, batchSize}, rollup)
+		return err
+	})
+	return deleted, err
+}
+
+// PruneExcess deletes up to batchSize of the oldest rows beyond maxRows
+// With rollup, the rows are aggregated into search_history_daily in the same transaction
+func (r *SQLiteRepository) PruneExcess(ctx context.Context, maxRows, batchSize int, rollup bool) (int64, error) {
+	var deleted int64
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var total int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM search_history`).Scan(&total); err != nil {
+			return fmt.Errorf("failed to count search history: %w", err)
+		}
+
+		excess := total - maxRows
+		if excess <= 0 {
+			return nil
+		}
+
+		var err error
+		deleted, err = pruneBatch(ctx, tx,
+			`SELECT id FROM search_history ORDER BY created_at, id LIMIT ?`,
+			[]interface{}{min(excess, batchSize)}, rollup)
+		return err
+	})
+	return deleted, err
+}
+
+// pruneBatch optionally rolls up and then deletes the rows selected by idsQuery
+func pruneBatch(ctx context.Context, tx *sql.Tx, idsQuery string, args []interface{}, rollup bool) (int64, error) {
+	if rollup {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf(rollupSQL, idsQuery), args...); err != nil {
+			return 0, fmt.Errorf("failed to roll up search history: %w", err)
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM search_history WHERE id IN (`+idsQuery+`)`, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune search history: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// autoVacuumIncremental is PRAGMA auto_vacuum's value for INCREMENTAL
+const autoVacuumIncremental = 2
+
+// Optimize reclaims free pages and refreshes query planner statistics
+// It never runs a full VACUUM, so writers are only held up for as long as
+// incremental_vacuum takes. Databases created before incremental auto-vacuum
+// was the default only get the statistics refresh until
+// EnableIncrementalVacuum converts them (zentube db vacuum)
+func (r *SQLiteRepository) Optimize(ctx context.Context) error {
+	var autoVacuum int
+	if err := r.db.QueryRowContext(ctx, `PRAGMA auto_vacuum`).Scan(&autoVacuum); err != nil {
+		return fmt.Errorf("failed to read auto_vacuum: %w", err)
+	}
+
+	if autoVacuum == autoVacuumIncremental {
+		if _, err := r.db.ExecContext(ctx, `PRAGMA incremental_vacuum`); err != nil {
+			return fmt.Errorf("failed to run incremental_vacuum: %w", err)
+		}
+	}
+
+	if _, err := r.db.ExecContext(ctx, `PRAGMA optimize`); err != nil {
+		return fmt.Errorf("failed to optimize database: %w", err)
+	}
+
+	return nil
+}
+
+// EnableIncrementalVacuum switches an existing database to incremental
+// auto-vacuum, which takes a one-off full VACUUM
+// The VACUUM rewrites the whole file and blocks writers while it runs, so this
+// is meant for maintenance commands with the server stopped
+// Returns false if the database already used incremental auto-vacuum
+func EnableIncrementalVacuum(ctx context.Context, db *sql.DB) (bool, error) {
+	var autoVacuum int
+	if err := db.QueryRowContext(ctx, `PRAGMA auto_vacuum`).Scan(&autoVacuum); err != nil {
+		return false, fmt.Errorf("failed to read auto_vacuum: %w", err)
+	}
+	if autoVacuum == autoVacuumIncremental {
+		return false, nil
+	}
+
+	if _, err := db.ExecContext(ctx, `PRAGMA auto_vacuum = INCREMENTAL`); err != nil {
+		return false, fmt.Errorf("failed to enable incremental auto_vacuum: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, `VACUUM`); err != nil {
+		return false, fmt.Errorf("failed to vacuum database: %w", err)
+	}
+
+	return true, nil
+}
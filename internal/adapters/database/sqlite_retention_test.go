@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestSQLiteRepository_PruneWithRollup(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	old := time.Now().Add(-48 * time.Hour)
+	for i, q := range []string{"golang", "golang", "rust"} {
+		require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: q, Results: 10 + i, CreatedAt: old}))
+	}
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "fresh", Results: 1, CreatedAt: time.Now()}))
+
+	// Act - small batches must add up in the rollup
+	first, err := repo.PruneOlderThan(ctx, time.Now().Add(-24*time.Hour), 2, true)
+	require.NoError(t, err)
+	second, err := repo.PruneOlderThan(ctx, time.Now().Add(-24*time.Hour), 2, true)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Equal(t, int64(2), first)
+	assert.Equal(t, int64(1), second)
+
+	remaining, err := repo.GetLast(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, remaining, 1)
+	assert.Equal(t, "fresh", remaining[0].Query)
+
+	var searches, totalResults int
+	require.NoError(t, repo.DB().QueryRow(
+		`SELECT searches, total_results FROM search_history_daily WHERE query = 'golang'`,
+	).Scan(&searches, &totalResults))
+	assert.Equal(t, 2, searches)
+	assert.Equal(t, 21, totalResults)
+}
+
+func TestSQLiteRepository_PruneExcess(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.Save(ctx, &entities.SearchHistory{
+			Query: "q", Results: 1, CreatedAt: base.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	// Act
+	deleted, err := repo.PruneExcess(ctx, 3, 100, false)
+	require.NoError(t, err)
+
+	// Assert - the oldest rows go first
+	assert.Equal(t, int64(2), deleted)
+	remaining, err := repo.GetLast(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, remaining, 3)
+	assert.True(t, remaining[2].CreatedAt.After(base.Add(time.Minute)))
+
+	assert.NoError(t, repo.Optimize(ctx))
+}
+
+func TestSQLiteRepository_NewDatabaseUsesIncrementalVacuum(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	// Act
+	var autoVacuum int
+	require.NoError(t, repo.db.QueryRow(`PRAGMA auto_vacuum`).Scan(&autoVacuum))
+
+	// Assert
+	assert.Equal(t, autoVacuumIncremental, autoVacuum)
+}
+
+func TestEnableIncrementalVacuum_ConvertsExistingDatabase(t *testing.T) {
+	// Arrange - a database created without auto-vacuum
+	path := filepath.Join(t.TempDir(), "test.db")
+	db, err := Open(path)
+	require.NoError(t, err)
+	defer db.Close()
+
+	ctx := context.Background()
+	_, err = db.Exec(`CREATE TABLE t (x INTEGER)`)
+	require.NoError(t, err)
+
+	// Act
+	converted, err := EnableIncrementalVacuum(ctx, db)
+	require.NoError(t, err)
+	again, err := EnableIncrementalVacuum(ctx, db)
+	require.NoError(t, err)
+
+	// Assert
+	assert.True(t, converted)
+	assert.False(t, again)
+	var autoVacuum int
+	require.NoError(t, db.QueryRow(`PRAGMA auto_vacuum`).Scan(&autoVacuum))
+	assert.Equal(t, autoVacuumIncremental, autoVacuum)
+}
@@ -0,0 +1,254 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// selectSavedSearchesSQL reads saved searches with their result counts
+const selectSavedSearchesSQL = `
+SELECT s.id, s.name, s.query, s.max_results, s.created_at, s.last_checked_at, s.last_refreshed_at, s.last_error,
+	COUNT(r.video_id), COALESCE(SUM(r.is_new), 0)
+FROM saved_searches s
+LEFT JOIN saved_search_results r ON r.saved_search_id = s.id`
+
+// CreateSavedSearch stores a saved search and sets its ID
+func (r *SQLiteRepository) CreateSavedSearch(ctx context.Context, search *entities.SavedSearch) error {
+	var id int64
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var taken bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM saved_searches WHERE name = ?)`, search.Name,
+		).Scan(&taken); err != nil {
+			return fmt.Errorf("failed to check saved search name: %w", err)
+		}
+		if taken {
+			return appErrors.NewValidationError(fmt.Sprintf("a saved search named %q already exists", search.Name), nil)
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`INSERT INTO saved_searches (name, query, max_results, created_at) VALUES (?, ?, ?, ?)`,
+			search.Name, search.Options.Query, search.Options.MaxResults, search.CreatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to create saved search: %w", err)
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	search.ID = id
+	return nil
+}
+
+// ListSavedSearches returns every saved search by name with its result counts
+func (r *SQLiteRepository) ListSavedSearches(ctx context.Context) ([]entities.SavedSearch, error) {
+	rows, err := r.readDB.QueryContext(ctx, selectSavedSearchesSQL+` GROUP BY s.id ORDER BY s.name`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	var searches []entities.SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		searches = append(searches, search)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return searches, nil
+}
+
+// GetSavedSearch returns a saved search with its result counts
+func (r *SQLiteRepository) GetSavedSearch(ctx context.Context, id int64) (*entities.SavedSearch, error) {
+	search, err := scanSavedSearch(r.readDB.QueryRowContext(ctx,
+		selectSavedSearchesSQL+` WHERE s.id = ? GROUP BY s.id`, id,
+	))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErrors.NewNotFoundError("Saved search")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get saved search: %w", err)
+	}
+	return &search, nil
+}
+
+// DeleteSavedSearch removes a saved search (its results go with it)
+func (r *SQLiteRepository) DeleteSavedSearch(ctx context.Context, id int64) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved search: %w", err)
+	}
+	return requireAffected(result, "Saved search")
+}
+
+// RecordSavedSearchCheck stores the time and outcome of a search's last run
+func (r *SQLiteRepository) RecordSavedSearchCheck(ctx context.Context, id int64, checkedAt time.Time, checkErr string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE saved_searches SET last_checked_at = ?, last_error = ? WHERE id = ?`,
+		checkedAt, checkErr, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record saved search check: %w", err)
+	}
+	return requireAffected(result, "Saved search")
+}
+
+// AddSavedSearchResults merges a refresh into the results, upserting the videos into the catalog,
+// and returns the results it added
+func (r *SQLiteRepository) AddSavedSearchResults(ctx context.Context, id int64, videos []entities.Video, refreshedAt time.Time) ([]entities.SavedSearchResult, error) {
+	var added []entities.SavedSearchResult
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var lastRefreshed sql.NullTime
+		err := tx.QueryRowContext(ctx,
+			`SELECT last_refreshed_at FROM saved_searches WHERE id = ?`, id,
+		).Scan(&lastRefreshed)
+		if errors.Is(err, sql.ErrNoRows) {
+			return appErrors.NewNotFoundError("Saved search")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get saved search: %w", err)
+		}
+		// The first successful refresh is the baseline: nothing in it is new
+		isNew := lastRefreshed.Valid
+
+		for _, v := range videos {
+			if _, err := tx.ExecContext(ctx, upsertVideoSQL,
+				v.ID, v.Title, v.Channel, nullTime(v.PublishedAt), v.Thumbnail, v.Description, refreshedAt, refreshedAt,
+			); err != nil {
+				return fmt.Errorf("failed to save video %s: %w", v.ID, err)
+			}
+
+			result, err := tx.ExecContext(ctx, `
+				INSERT INTO saved_search_results (saved_search_id, video_id, first_seen_at, last_seen_at, is_new)
+				VALUES (?, ?, ?, ?, ?)
+				ON CONFLICT (saved_search_id, video_id) DO NOTHING`,
+				id, v.ID, refreshedAt, refreshedAt, isNew,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to add saved search result %s: %w", v.ID, err)
+			}
+			if n, _ := result.RowsAffected(); n > 0 {
+				added = append(added, entities.SavedSearchResult{Video: v, FirstSeenAt: refreshedAt, LastSeenAt: refreshedAt, New: isNew})
+				continue
+			}
+
+			if _, err := tx.ExecContext(ctx,
+				`UPDATE saved_search_results SET last_seen_at = ? WHERE saved_search_id = ? AND video_id = ?`,
+				refreshedAt, id, v.ID,
+			); err != nil {
+				return fmt.Errorf("failed to update saved search result %s: %w", v.ID, err)
+			}
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`UPDATE saved_searches SET last_refreshed_at = ? WHERE id = ?`, refreshedAt, id,
+		); err != nil {
+			return fmt.Errorf("failed to record saved search refresh: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// ListSavedSearchResults returns a search's results, new ones first, then most recently found
+func (r *SQLiteRepository) ListSavedSearchResults(ctx context.Context, id int64) ([]entities.SavedSearchResult, error) {
+	if _, err := r.GetSavedSearch(ctx, id); err != nil {
+		return nil, err
+	}
+
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT v.id, v.title, v.channel, v.published_at, v.thumbnail, s.first_seen_at, s.last_seen_at, s.is_new
+		FROM saved_search_results s
+		JOIN videos v ON v.id = s.video_id
+		WHERE s.saved_search_id = ?
+		ORDER BY s.is_new DESC, s.first_seen_at DESC, v.id`, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved search results: %w", err)
+	}
+	defer rows.Close()
+
+	var results []entities.SavedSearchResult
+	for rows.Next() {
+		var res entities.SavedSearchResult
+		var published sql.NullTime
+		if err := rows.Scan(&res.ID, &res.Title, &res.Channel, &published, &res.Thumbnail,
+			&res.FirstSeenAt, &res.LastSeenAt, &res.New); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		res.PublishedAt = published.Time
+		results = append(results, res)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// MarkSavedSearchSeen clears the New flag of every result of a search
+func (r *SQLiteRepository) MarkSavedSearchSeen(ctx context.Context, id int64) (int, error) {
+	marked := 0
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		var exists bool
+		if err := tx.QueryRowContext(ctx,
+			`SELECT EXISTS(SELECT 1 FROM saved_searches WHERE id = ?)`, id,
+		).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to get saved search: %w", err)
+		}
+		if !exists {
+			return appErrors.NewNotFoundError("Saved search")
+		}
+
+		result, err := tx.ExecContext(ctx,
+			`UPDATE saved_search_results SET is_new = 0 WHERE saved_search_id = ? AND is_new = 1`, id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to mark saved search seen: %w", err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		marked = int(n)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return marked, nil
+}
+
+// scanSavedSearch reads the columns of selectSavedSearchesSQL
+func scanSavedSearch(row rowScanner) (entities.SavedSearch, error) {
+	var s entities.SavedSearch
+	var checked, refreshed sql.NullTime
+	if err := row.Scan(&s.ID, &s.Name, &s.Options.Query, &s.Options.MaxResults, &s.CreatedAt,
+		&checked, &refreshed, &s.LastError, &s.ResultCount, &s.NewCount); err != nil {
+		return s, err
+	}
+	s.LastCheckedAt = checked.Time
+	s.LastRefreshedAt = refreshed.Time
+	return s, nil
+}
@@ -0,0 +1,185 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// selectSavedVideosSQL reads saved videos with their catalog metadata
+const selectSavedVideosSQL = `
+SELECT v.id, v.title, v.channel, v.published_at, v.thumbnail, s.position, s.saved_at, s.watched_at
+FROM saved_videos s
+JOIN videos v ON v.id = s.video_id`
+
+// AddSavedVideo appends a video to the watch-later list
+// The video's metadata is only stored if the catalog doesn't know it yet; the
+// saved video returned carries whatever the catalog holds
+func (r *SQLiteRepository) AddSavedVideo(ctx context.Context, video entities.Video, savedAt time.Time) (*entities.SavedVideo, error) {
+	var saved *entities.SavedVideo
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		existing, err := scanSavedVideo(tx.QueryRowContext(ctx, selectSavedVideosSQL+` WHERE s.video_id = ?`, video.ID))
+		if err == nil {
+			saved = &existing
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to get saved video: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, insertVideoSQL,
+			video.ID, video.Title, video.Channel, nullTime(video.PublishedAt), video.Thumbnail, video.Description, savedAt, savedAt,
+		); err != nil {
+			return fmt.Errorf("failed to save video %s: %w", video.ID, err)
+		}
+
+		var position int
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(position), 0) + 1 FROM saved_videos`).Scan(&position); err != nil {
+			return fmt.Errorf("failed to get next position: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO saved_videos (video_id, position, saved_at) VALUES (?, ?, ?)`,
+			video.ID, position, savedAt,
+		); err != nil {
+			return fmt.Errorf("failed to save video %s: %w", video.ID, err)
+		}
+
+		added, err := scanSavedVideo(tx.QueryRowContext(ctx, selectSavedVideosSQL+` WHERE s.video_id = ?`, video.ID))
+		if err != nil {
+			return fmt.Errorf("failed to get saved video: %w", err)
+		}
+		saved = &added
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// RemoveSavedVideo takes a video off the list and closes the gap it leaves
+func (r *SQLiteRepository) RemoveSavedVideo(ctx context.Context, videoID string) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		position, err := savedPosition(ctx, tx, videoID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM saved_videos WHERE video_id = ?`, videoID); err != nil {
+			return fmt.Errorf("failed to remove saved video: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, `UPDATE saved_videos SET position = position - 1 WHERE position > ?`, position); err != nil {
+			return fmt.Errorf("failed to renumber saved videos: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetSavedVideoWatched records when a saved video was watched (zero clears it)
+func (r *SQLiteRepository) SetSavedVideoWatched(ctx context.Context, videoID string, watchedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE saved_videos SET watched_at = ? WHERE video_id = ?`, nullTime(watchedAt), videoID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update saved video: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if n == 0 {
+		return appErrors.NewNotFoundError("Saved video")
+	}
+	return nil
+}
+
+// MoveSavedVideo puts a saved video at position, shifting the videos in between
+func (r *SQLiteRepository) MoveSavedVideo(ctx context.Context, videoID string, position int) error {
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		current, err := savedPosition(ctx, tx, videoID)
+		if err != nil {
+			return err
+		}
+
+		var count int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM saved_videos`).Scan(&count); err != nil {
+			return fmt.Errorf("failed to count saved videos: %w", err)
+		}
+		target := max(min(position, count), 1)
+
+		switch {
+		case target < current:
+			_, err = tx.ExecContext(ctx,
+				`UPDATE saved_videos SET position = position + 1 WHERE position >= ? AND position < ?`, target, current)
+		case target > current:
+			_, err = tx.ExecContext(ctx,
+				`UPDATE saved_videos SET position = position - 1 WHERE position > ? AND position <= ?`, current, target)
+		default:
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to shift saved videos: %w", err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `UPDATE saved_videos SET position = ? WHERE video_id = ?`, target, videoID); err != nil {
+			return fmt.Errorf("failed to move saved video: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListSavedVideos returns the watch-later list in position order
+func (r *SQLiteRepository) ListSavedVideos(ctx context.Context) ([]entities.SavedVideo, error) {
+	rows, err := r.readDB.QueryContext(ctx, selectSavedVideosSQL+` ORDER BY s.position`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved videos: %w", err)
+	}
+	defer rows.Close()
+
+	var videos []entities.SavedVideo
+	for rows.Next() {
+		v, err := scanSavedVideo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		videos = append(videos, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return videos, nil
+}
+
+// savedPosition returns a saved video's position (NotFound if it isn't saved)
+func savedPosition(ctx context.Context, tx *sql.Tx, videoID string) (int, error) {
+	var position int
+	err := tx.QueryRowContext(ctx, `SELECT position FROM saved_videos WHERE video_id = ?`, videoID).Scan(&position)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, appErrors.NewNotFoundError("Saved video")
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get saved video: %w", err)
+	}
+	return position, nil
+}
+
+// scanSavedVideo reads the columns of selectSavedVideosSQL
+func scanSavedVideo(row rowScanner) (entities.SavedVideo, error) {
+	var s entities.SavedVideo
+	var published, watched sql.NullTime
+	if err := row.Scan(&s.ID, &s.Title, &s.Channel, &published, &s.Thumbnail, &s.Position, &s.SavedAt, &watched); err != nil {
+		return s, err
+	}
+	s.PublishedAt = published.Time
+	s.WatchedAt = watched.Time
+	return s, nil
+}
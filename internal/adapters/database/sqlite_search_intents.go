@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// selectSearchIntentsSQL reads search intents
+const selectSearchIntentsSQL = `
+SELECT id, intent, created_at, outcome, rated_at
+FROM search_intents`
+
+// AddSearchIntent stores a new intent and sets its ID
+func (r *SQLiteRepository) AddSearchIntent(ctx context.Context, intent *entities.SearchIntent) error {
+	result, err := r.db.ExecContext(ctx,
+		`INSERT INTO search_intents (intent, created_at, outcome, rated_at) VALUES (?, ?, ?, ?)`,
+		intent.Intent, intent.CreatedAt, intent.Outcome, nullTime(intent.RatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to add search intent: %w", err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id: %w", err)
+	}
+	intent.ID = id
+	return nil
+}
+
+// GetSearchIntent returns an intent
+func (r *SQLiteRepository) GetSearchIntent(ctx context.Context, id int64) (*entities.SearchIntent, error) {
+	return r.getSearchIntent(ctx, selectSearchIntentsSQL+` WHERE id = ?`, id)
+}
+
+// LatestSearchIntent returns the most recently created intent
+func (r *SQLiteRepository) LatestSearchIntent(ctx context.Context) (*entities.SearchIntent, error) {
+	return r.getSearchIntent(ctx, selectSearchIntentsSQL+` ORDER BY created_at DESC, id DESC LIMIT 1`)
+}
+
+func (r *SQLiteRepository) getSearchIntent(ctx context.Context, query string, args ...any) (*entities.SearchIntent, error) {
+	i, err := scanSearchIntent(r.readDB.QueryRowContext(ctx, query, args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErrors.NewNotFoundError("Search intent")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search intent: %w", err)
+	}
+	return &i, nil
+}
+
+// RateSearchIntent records whether the intent was found
+func (r *SQLiteRepository) RateSearchIntent(ctx context.Context, id int64, outcome string, ratedAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE search_intents SET outcome = ?, rated_at = ? WHERE id = ?`, outcome, ratedAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to rate search intent: %w", err)
+	}
+	return requireAffected(result, "Search intent")
+}
+
+// ListSearchIntents returns up to limit intents with their searches and viewings,
+// most recently created first
+func (r *SQLiteRepository) ListSearchIntents(ctx context.Context, limit int) ([]entities.IntentActivity, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT i.id, i.intent, i.created_at, i.outcome, i.rated_at,
+			(SELECT COUNT(*) FROM search_history h WHERE h.intent_id = i.id),
+			(SELECT COUNT(*) FROM watch_events w WHERE w.intent_id = i.id),
+			(SELECT COALESCE(SUM(w.watched_seconds), 0) FROM watch_events w WHERE w.intent_id = i.id)
+		FROM search_intents i
+		ORDER BY i.created_at DESC, i.id DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search intents: %w", err)
+	}
+	defer rows.Close()
+
+	activity := []entities.IntentActivity{}
+	for rows.Next() {
+		var a entities.IntentActivity
+		var ratedAt sql.NullTime
+		if err := rows.Scan(&a.ID, &a.Intent, &a.CreatedAt, &a.Outcome, &ratedAt,
+			&a.Searches, &a.Videos, &a.WatchedSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan search intent: %w", err)
+		}
+		a.RatedAt = ratedAt.Time
+		activity = append(activity, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read search intents: %w", err)
+	}
+
+	if err := r.addIntentQueries(ctx, activity); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// addIntentQueries fills in the distinct queries searched for each intent, first searched first
+func (r *SQLiteRepository) addIntentQueries(ctx context.Context, activity []entities.IntentActivity) error {
+	if len(activity) == 0 {
+		return nil
+	}
+
+	args := make([]any, len(activity))
+	byID := make(map[int64]*entities.IntentActivity, len(activity))
+	for i := range activity {
+		args[i] = activity[i].ID
+		byID[activity[i].ID] = &activity[i]
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(activity)), ",")
+
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT intent_id, query
+		FROM search_history
+		WHERE intent_id IN (`+placeholders+`)
+		GROUP BY intent_id, query
+		ORDER BY MIN(created_at), MIN(id)`, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query intent searches: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var query string
+		if err := rows.Scan(&id, &query); err != nil {
+			return fmt.Errorf("failed to scan intent search: %w", err)
+		}
+		byID[id].Queries = append(byID[id].Queries, query)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read intent searches: %w", err)
+	}
+	return nil
+}
+
+func scanSearchIntent(row rowScanner) (entities.SearchIntent, error) {
+	var i entities.SearchIntent
+	var ratedAt sql.NullTime
+	if err := row.Scan(&i.ID, &i.Intent, &i.CreatedAt, &i.Outcome, &ratedAt); err != nil {
+		return i, err
+	}
+	i.RatedAt = ratedAt.Time
+	return i, nil
+}
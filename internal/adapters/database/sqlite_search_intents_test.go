@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestSQLiteRepository_SearchIntentActivity(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	now := time.Now()
+	intent := &entities.SearchIntent{Intent: "learn pgx pooling", CreatedAt: now.Add(-time.Hour)}
+	require.NoError(t, repo.AddSearchIntent(ctx, intent))
+
+	searches := []*entities.SearchHistory{
+		{Query: "pgx pool", Results: 1, IntentID: intent.ID, CreatedAt: now.Add(-50 * time.Minute)},
+		{Query: "pgxpool tutorial", Results: 1, IntentID: intent.ID, CreatedAt: now.Add(-40 * time.Minute)},
+		{Query: "pgx pool", Results: 1, IntentID: intent.ID, CreatedAt: now.Add(-30 * time.Minute)},
+		{Query: "cat videos", Results: 1, CreatedAt: now.Add(-20 * time.Minute)},
+	}
+	require.NoError(t, repo.SaveBatch(ctx, searches))
+	require.NoError(t, repo.SaveSearchResults(ctx, searches[1].ID, []entities.Video{{ID: "a", Title: "A"}}))
+	require.NoError(t, repo.SaveSearchResults(ctx, searches[3].ID, []entities.Video{{ID: "b", Title: "B"}}))
+
+	// Act - one viewing from the intent's search, one from the unrelated one
+	viewing := &entities.WatchEvent{VideoID: "a", Title: "A", StartedAt: now.Add(-35 * time.Minute)}
+	require.NoError(t, repo.AddWatchEvent(ctx, viewing))
+	require.NoError(t, repo.UpdateWatchEvent(ctx, viewing.ID, entities.WatchProgress{At: now, WatchedSeconds: 600}))
+	other := &entities.WatchEvent{VideoID: "b", Title: "B", StartedAt: now.Add(-10 * time.Minute)}
+	require.NoError(t, repo.AddWatchEvent(ctx, other))
+
+	// Assert
+	assert.Equal(t, intent.ID, viewing.IntentID, "the viewing takes its search's intent")
+	assert.Zero(t, other.IntentID)
+
+	history, err := repo.GetLast(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 4)
+	assert.Zero(t, history[0].IntentID)
+	assert.Equal(t, intent.ID, history[1].IntentID)
+
+	activity, err := repo.ListSearchIntents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, activity, 1)
+	assert.Equal(t, []string{"pgx pool", "pgxpool tutorial"}, activity[0].Queries, "distinct, first searched first")
+	assert.Equal(t, 3, activity[0].Searches)
+	assert.Equal(t, 1, activity[0].Videos)
+	assert.Equal(t, 600, activity[0].WatchedSeconds)
+
+	// Pruning the search keeps the viewing's intent
+	require.NoError(t, repo.Delete(ctx, searches[1].ID))
+	got, err := repo.GetWatchEvent(ctx, viewing.ID)
+	require.NoError(t, err)
+	assert.Zero(t, got.SearchID)
+	assert.Equal(t, intent.ID, got.IntentID)
+}
@@ -0,0 +1,263 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// AddSubscription follows a channel, or returns the existing subscription
+func (r *SQLiteRepository) AddSubscription(ctx context.Context, channel entities.Channel, subscribedAt time.Time) (*entities.Subscription, error) {
+	var sub *entities.Subscription
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO subscriptions (channel_id, title, subscribed_at) VALUES (?, ?, ?)
+			ON CONFLICT(channel_id) DO NOTHING`,
+			channel.ID, channel.Title, subscribedAt,
+		); err != nil {
+			return fmt.Errorf("failed to add subscription: %w", err)
+		}
+
+		existing, err := scanSubscription(tx.QueryRowContext(ctx,
+			`SELECT channel_id, title, subscribed_at, last_polled_at, last_error, 0
+			FROM subscriptions WHERE channel_id = ?`, channel.ID,
+		))
+		if err != nil {
+			return fmt.Errorf("failed to get subscription: %w", err)
+		}
+		sub = &existing
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// RemoveSubscription unfollows a channel (its feed items go with it)
+func (r *SQLiteRepository) RemoveSubscription(ctx context.Context, channelID string) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM subscriptions WHERE channel_id = ?`, channelID)
+	if err != nil {
+		return fmt.Errorf("failed to remove subscription: %w", err)
+	}
+	return requireAffected(result, "Subscription")
+}
+
+// ListSubscriptions returns every followed channel by title with its unseen count
+func (r *SQLiteRepository) ListSubscriptions(ctx context.Context) ([]entities.Subscription, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT s.channel_id, s.title, s.subscribed_at, s.last_polled_at, s.last_error,
+			(SELECT COUNT(*) FROM feed_items f WHERE f.channel_id = s.channel_id AND f.seen_at IS NULL)
+		FROM subscriptions s
+		ORDER BY s.title COLLATE NOCASE, s.channel_id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []entities.Subscription
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return subs, nil
+}
+
+// RecordSubscriptionPoll stores the time and outcome of a channel's last poll
+func (r *SQLiteRepository) RecordSubscriptionPoll(ctx context.Context, channelID string, polledAt time.Time, pollErr string) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE subscriptions SET last_polled_at = ?, last_error = ? WHERE channel_id = ?`,
+		polledAt, pollErr, channelID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record poll: %w", err)
+	}
+	return requireAffected(result, "Subscription")
+}
+
+// AddFeedItems upserts uploads into the catalog, adds the new ones to the feed and returns them
+// NotFound AppError if an item's channel isn't followed (e.g. unfollowed mid-poll)
+func (r *SQLiteRepository) AddFeedItems(ctx context.Context, items []entities.FeedItem) ([]entities.FeedItem, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	var added []entities.FeedItem
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		upsert, err := tx.PrepareContext(ctx, upsertVideoSQL)
+		if err != nil {
+			return fmt.Errorf("failed to prepare upsert: %w", err)
+		}
+		defer upsert.Close()
+
+		insert, err := tx.PrepareContext(ctx,
+			`INSERT INTO feed_items (video_id, channel_id, published_at, fetched_at, seen_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(video_id) DO NOTHING`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert: %w", err)
+		}
+		defer insert.Close()
+
+		checked := make(map[string]bool)
+		for _, item := range items {
+			if !checked[item.ChannelID] {
+				if err := requireSubscription(ctx, tx, item.ChannelID); err != nil {
+					return err
+				}
+				checked[item.ChannelID] = true
+			}
+
+			if _, err := upsert.ExecContext(ctx,
+				item.ID, item.Title, item.Channel, nullTime(item.PublishedAt), item.Thumbnail, item.Description, item.FetchedAt, item.FetchedAt,
+			); err != nil {
+				return fmt.Errorf("failed to save video %s: %w", item.ID, err)
+			}
+
+			result, err := insert.ExecContext(ctx,
+				item.ID, item.ChannelID, item.PublishedAt, item.FetchedAt, nullTime(item.SeenAt),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to add feed item %s: %w", item.ID, err)
+			}
+			n, err := result.RowsAffected()
+			if err != nil {
+				return fmt.Errorf("failed to get rows affected: %w", err)
+			}
+			if n > 0 {
+				added = append(added, item)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return added, nil
+}
+
+// ListFeed returns feed items matching filter, newest upload first, after the cursor
+func (r *SQLiteRepository) ListFeed(ctx context.Context, filter entities.FeedFilter, after *entities.FeedCursor, limit int) ([]entities.FeedItem, error) {
+	where, args := feedWhere(filter)
+	if after != nil {
+		where = append(where, "(f.published_at < ? OR (f.published_at = ? AND f.video_id < ?))")
+		args = append(args, after.PublishedAt, after.PublishedAt, after.VideoID)
+	}
+
+	query := `
+		SELECT v.id, v.title, v.channel, f.published_at, v.thumbnail, f.channel_id, f.fetched_at, f.seen_at
+		FROM feed_items f
+		JOIN videos v ON v.id = f.video_id`
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += " ORDER BY f.published_at DESC, f.video_id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := r.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed: %w", err)
+	}
+	defer rows.Close()
+
+	var items []entities.FeedItem
+	for rows.Next() {
+		var item entities.FeedItem
+		var seen sql.NullTime
+		if err := rows.Scan(&item.ID, &item.Title, &item.Channel, &item.PublishedAt, &item.Thumbnail,
+			&item.ChannelID, &item.FetchedAt, &seen); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		item.SeenAt = seen.Time
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return items, nil
+}
+
+// SetFeedItemSeen marks a feed item seen (zero seenAt marks it unseen)
+func (r *SQLiteRepository) SetFeedItemSeen(ctx context.Context, videoID string, seenAt time.Time) error {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE feed_items SET seen_at = ? WHERE video_id = ?`, nullTime(seenAt), videoID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update feed item: %w", err)
+	}
+	return requireAffected(result, "Feed item")
+}
+
+// MarkFeedSeen marks every unseen item matching filter as seen
+func (r *SQLiteRepository) MarkFeedSeen(ctx context.Context, filter entities.FeedFilter, seenAt time.Time) (int, error) {
+	filter.UnseenOnly = true
+	where, args := feedWhere(filter)
+
+	query := `UPDATE feed_items AS f SET seen_at = ? WHERE ` + strings.Join(where, " AND ")
+	result, err := r.db.ExecContext(ctx, query, append([]interface{}{seenAt}, args...)...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark feed seen: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+// feedWhere builds the WHERE clauses of a feed filter over feed_items aliased as f
+func feedWhere(filter entities.FeedFilter) ([]string, []interface{}) {
+	var where []string
+	var args []interface{}
+
+	if filter.UnseenOnly {
+		where = append(where, "f.seen_at IS NULL")
+	}
+	if filter.ChannelID != "" {
+		where = append(where, "f.channel_id = ?")
+		args = append(args, filter.ChannelID)
+	}
+	return where, args
+}
+
+// scanSubscription reads channel_id, title, subscribed_at, last_polled_at,
+// last_error and the unseen count
+func scanSubscription(row rowScanner) (entities.Subscription, error) {
+	var sub entities.Subscription
+	var polled sql.NullTime
+	if err := row.Scan(&sub.ID, &sub.Title, &sub.SubscribedAt, &polled, &sub.LastError, &sub.UnseenCount); err != nil {
+		return sub, err
+	}
+	sub.LastPolledAt = polled.Time
+	return sub, nil
+}
+
+// requireSubscription fails with a NotFound AppError unless the channel is followed
+func requireSubscription(ctx context.Context, tx *sql.Tx, channelID string) error {
+	var exists bool
+	if err := tx.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM subscriptions WHERE channel_id = ?)`, channelID,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to get subscription: %w", err)
+	}
+	if !exists {
+		return appErrors.NewNotFoundError("Subscription")
+	}
+	return nil
+}
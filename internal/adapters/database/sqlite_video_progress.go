@@ -0,0 +1,60 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// SaveVideoProgress replaces the stored position of a video
+func (r *SQLiteRepository) SaveVideoProgress(ctx context.Context, progress entities.VideoProgress) error {
+	if _, err := r.db.ExecContext(ctx, `
+		INSERT INTO video_progress (video_id, position_seconds, duration_seconds, updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(video_id) DO UPDATE SET
+			position_seconds = excluded.position_seconds,
+			duration_seconds = excluded.duration_seconds,
+			updated_at = excluded.updated_at`,
+		progress.VideoID, progress.Position, progress.Duration, progress.UpdatedAt,
+	); err != nil {
+		return fmt.Errorf("failed to save video progress: %w", err)
+	}
+	return nil
+}
+
+// GetVideoProgress returns the stored positions of the given videos, keyed by video ID
+func (r *SQLiteRepository) GetVideoProgress(ctx context.Context, videoIDs []string) (map[string]entities.VideoProgress, error) {
+	progress := make(map[string]entities.VideoProgress, len(videoIDs))
+	if len(videoIDs) == 0 {
+		return progress, nil
+	}
+
+	args := make([]any, len(videoIDs))
+	for i, id := range videoIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(videoIDs)), ",")
+
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT video_id, position_seconds, duration_seconds, updated_at
+		FROM video_progress
+		WHERE video_id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query video progress: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p entities.VideoProgress
+		if err := rows.Scan(&p.VideoID, &p.Position, &p.Duration, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan video progress: %w", err)
+		}
+		progress[p.VideoID] = p
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read video progress: %w", err)
+	}
+	return progress, nil
+}
@@ -0,0 +1,169 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// upsertVideoSQL refreshes a video's metadata but keeps when it was first seen
+// A source without a description doesn't erase one stored earlier
+const upsertVideoSQL = `
+INSERT INTO videos (id, title, channel, published_at, thumbnail, description, first_seen_at, last_seen_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO UPDATE SET
+	title = excluded.title,
+	channel = excluded.channel,
+	published_at = excluded.published_at,
+	thumbnail = excluded.thumbnail,
+	description = CASE WHEN excluded.description != '' THEN excluded.description ELSE description END,
+	last_seen_at = excluded.last_seen_at`
+
+// insertVideoSQL adds a video the catalog doesn't know yet and leaves known ones alone
+// Used for metadata that comes from clients rather than YouTube
+const insertVideoSQL = `
+INSERT INTO videos (id, title, channel, published_at, thumbnail, description, first_seen_at, last_seen_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(id) DO NOTHING`
+
+// SaveSearchResults upserts videos into the catalog and links them to a search
+// Everything happens in one transaction; saving the same search twice replaces its links
+// Viewings opened before the search reached the database are linked to it
+// here, as AddWatchEvent would have done had the search been written first
+func (r *SQLiteRepository) SaveSearchResults(ctx context.Context, searchID int64, videos []entities.Video) error {
+	if len(videos) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	return inTx(ctx, r.db, func(tx *sql.Tx) error {
+		upsert, err := tx.PrepareContext(ctx, upsertVideoSQL)
+		if err != nil {
+			return fmt.Errorf("failed to prepare video upsert: %w", err)
+		}
+		defer upsert.Close()
+
+		link, err := tx.PrepareContext(ctx,
+			`INSERT OR REPLACE INTO search_results (search_id, rank, video_id) VALUES (?, ?, ?)`,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to prepare search result insert: %w", err)
+		}
+		defer link.Close()
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM search_results WHERE search_id = ?`, searchID); err != nil {
+			return fmt.Errorf("failed to clear search results: %w", err)
+		}
+
+		for i, v := range videos {
+			if _, err := upsert.ExecContext(ctx, v.ID, v.Title, v.Channel, nullTime(v.PublishedAt), v.Thumbnail, v.Description, now, now); err != nil {
+				return fmt.Errorf("failed to save video %s: %w", v.ID, err)
+			}
+			if _, err := link.ExecContext(ctx, searchID, i+1, v.ID); err != nil {
+				return fmt.Errorf("failed to link video %s: %w", v.ID, err)
+			}
+		}
+		return linkWatchEvents(ctx, tx, searchID)
+	})
+}
+
+// linkWatchEvents points viewings of a search's videos at that search when it
+// is the latest search started no later than the viewing
+// Keeps the viewings consistent when the history writer saves a search after
+// the player already reported a viewing of one of its results
+func linkWatchEvents(ctx context.Context, tx *sql.Tx, searchID int64) error {
+	if _, err := tx.ExecContext(ctx, `
+		WITH s AS (SELECT id, created_at, intent_id FROM search_history WHERE id = ?)
+		UPDATE watch_events
+		SET search_id = s.id, intent_id = s.intent_id
+		FROM s
+		WHERE watch_events.started_at >= s.created_at
+			AND watch_events.video_id IN (SELECT video_id FROM search_results WHERE search_id = s.id)
+			AND (watch_events.search_id IS NULL OR watch_events.search_id IN (
+				SELECT h.id FROM search_history h
+				WHERE h.created_at < s.created_at OR (h.created_at = s.created_at AND h.id < s.id)
+			))`, searchID,
+	); err != nil {
+		return fmt.Errorf("failed to link watch events to search %d: %w", searchID, err)
+	}
+	return nil
+}
+
+// GetByID returns a video from the catalog
+func (r *SQLiteRepository) GetByID(ctx context.Context, id string) (*entities.Video, error) {
+	row := r.readDB.QueryRowContext(ctx,
+		`SELECT id, title, channel, published_at, thumbnail, description FROM videos WHERE id = ?`, id,
+	)
+
+	var v entities.Video
+	var published sql.NullTime
+	err := row.Scan(&v.ID, &v.Title, &v.Channel, &published, &v.Thumbnail, &v.Description)
+	v.PublishedAt = published.Time
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErrors.NewNotFoundError("video")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get video: %w", err)
+	}
+	return &v, nil
+}
+
+// GetSearchResults returns the videos a past search returned, in rank order
+func (r *SQLiteRepository) GetSearchResults(ctx context.Context, searchID int64) ([]entities.Video, error) {
+	rows, err := r.readDB.QueryContext(ctx, `
+		SELECT v.id, v.title, v.channel, v.published_at, v.thumbnail
+		FROM search_results sr
+		JOIN videos v ON v.id = sr.video_id
+		WHERE sr.search_id = ?
+		ORDER BY sr.rank`, searchID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query search results: %w", err)
+	}
+	defer rows.Close()
+
+	var videos []entities.Video
+	for rows.Next() {
+		v, err := scanVideo(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		videos = append(videos, v)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return videos, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanVideo reads the columns id, title, channel, published_at, thumbnail
+func scanVideo(row rowScanner) (entities.Video, error) {
+	var v entities.Video
+	var published sql.NullTime
+	if err := row.Scan(&v.ID, &v.Title, &v.Channel, &published, &v.Thumbnail); err != nil {
+		return v, err
+	}
+	v.PublishedAt = published.Time
+	return v, nil
+}
+
+// nullID stores a zero ID as NULL, for optional foreign keys
+func nullID(id int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: id, Valid: id != 0}
+}
+
+// nullTime stores the zero time as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+func TestSQLiteRepository_SaveSearchResults(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	published := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := &entities.SearchHistory{Query: "golang", Results: 2, CreatedAt: time.Now()}
+	second := &entities.SearchHistory{Query: "go tutorial", Results: 1, CreatedAt: time.Now()}
+	require.NoError(t, repo.Save(ctx, first))
+	require.NoError(t, repo.Save(ctx, second))
+
+	// Act - the same video seen twice is upserted with its newest metadata
+	require.NoError(t, repo.SaveSearchResults(ctx, first.ID, []entities.Video{
+		{ID: "b", Title: "Second", Channel: "chan"},
+		{ID: "a", Title: "Old title", Channel: "chan", PublishedAt: published},
+	}))
+	require.NoError(t, repo.SaveSearchResults(ctx, second.ID, []entities.Video{
+		{ID: "a", Title: "New title", Channel: "chan", PublishedAt: published},
+	}))
+
+	// Assert
+	results, err := repo.GetSearchResults(ctx, first.ID)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.Equal(t, "b", results[0].ID)
+	assert.Equal(t, "a", results[1].ID)
+	assert.True(t, results[0].PublishedAt.IsZero())
+
+	video, err := repo.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, "New title", video.Title)
+	assert.True(t, published.Equal(video.PublishedAt))
+
+	_, err = repo.GetByID(ctx, "missing")
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func TestSQLiteRepository_SearchResultsFollowHistoryPruning(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	old := &entities.SearchHistory{Query: "golang", Results: 1, CreatedAt: time.Now().Add(-48 * time.Hour)}
+	require.NoError(t, repo.Save(ctx, old))
+	require.NoError(t, repo.SaveSearchResults(ctx, old.ID, []entities.Video{{ID: "a", Title: "A"}}))
+
+	// Act
+	_, err := repo.PruneOlderThan(ctx, time.Now().Add(-24*time.Hour), 10, false)
+	require.NoError(t, err)
+
+	// Assert - links are gone, the catalog keeps the video
+	results, err := repo.GetSearchResults(ctx, old.ID)
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	_, err = repo.GetByID(ctx, "a")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,164 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// selectWatchEventsSQL reads viewings with the query of the search that surfaced them
+const selectWatchEventsSQL = `
+SELECT w.id, w.profile, w.video_id, w.title, w.started_at, w.updated_at, w.closed_at, w.watched_seconds, w.search_id, h.query, w.intent_id
+FROM watch_events w
+LEFT JOIN search_history h ON h.id = w.search_id`
+
+// AddWatchEvent stores a new viewing under its profile, linked to the latest earlier search
+// that returned the video and to that search's intent
+func (r *SQLiteRepository) AddWatchEvent(ctx context.Context, event *entities.WatchEvent) error {
+	var id int64
+	var search, intent sql.NullInt64
+	var query sql.NullString
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, `
+			SELECT h.id, h.query, h.intent_id
+			FROM search_results sr
+			JOIN search_history h ON h.id = sr.search_id
+			WHERE sr.video_id = ? AND h.created_at <= ?
+			ORDER BY h.created_at DESC, h.id DESC
+			LIMIT 1`, event.VideoID, event.StartedAt,
+		).Scan(&search, &query, &intent)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("failed to find search for video: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO watch_events (profile, video_id, title, started_at, updated_at, watched_seconds, search_id, intent_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			event.Profile, event.VideoID, event.Title, event.StartedAt, event.StartedAt, event.WatchedSeconds, search, intent,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to add watch event: %w", err)
+		}
+		if id, err = result.LastInsertId(); err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	event.ID = id
+	event.UpdatedAt = event.StartedAt
+	event.SearchID = search.Int64
+	event.SearchQuery = query.String
+	event.IntentID = intent.Int64
+	return nil
+}
+
+// GetWatchEvent returns a viewing
+func (r *SQLiteRepository) GetWatchEvent(ctx context.Context, id int64) (*entities.WatchEvent, error) {
+	row := r.readDB.QueryRowContext(ctx, selectWatchEventsSQL+` WHERE w.id = ?`, id)
+
+	e, err := scanWatchEvent(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, appErrors.NewNotFoundError("Watch event")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watch event: %w", err)
+	}
+	return &e, nil
+}
+
+// UpdateWatchEvent records progress on an open viewing
+// A closed viewing is left untouched and reported as a Validation AppError
+func (r *SQLiteRepository) UpdateWatchEvent(ctx context.Context, id int64, progress entities.WatchProgress) error {
+	var closedAt sql.NullTime
+	if progress.Closed {
+		closedAt = nullTime(progress.At)
+	}
+
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE watch_events
+		SET watched_seconds = MAX(watched_seconds, ?), updated_at = ?, closed_at = ?
+		WHERE id = ? AND closed_at IS NULL`,
+		progress.WatchedSeconds, progress.At, closedAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update watch event: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM watch_events WHERE id = ?)`, id,
+	).Scan(&exists); err != nil {
+		return fmt.Errorf("failed to get watch event: %w", err)
+	}
+	if !exists {
+		return appErrors.NewNotFoundError("Watch event")
+	}
+	return appErrors.NewValidationError("the viewing is already closed", nil)
+}
+
+// ListWatchEvents returns up to limit viewings of a profile, most recently started first
+func (r *SQLiteRepository) ListWatchEvents(ctx context.Context, profile string, limit int) ([]entities.WatchEvent, error) {
+	rows, err := r.readDB.QueryContext(ctx,
+		selectWatchEventsSQL+` WHERE w.profile = ? ORDER BY w.started_at DESC, w.id DESC LIMIT ?`, profile, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query watch events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []entities.WatchEvent{}
+	for rows.Next() {
+		e, err := scanWatchEvent(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan watch event: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read watch events: %w", err)
+	}
+	return events, nil
+}
+
+// WatchedSecondsSince totals the watched seconds of a profile's viewings started at or after since
+func (r *SQLiteRepository) WatchedSecondsSince(ctx context.Context, profile string, since time.Time) (int, error) {
+	var total int
+	if err := r.readDB.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(watched_seconds), 0) FROM watch_events WHERE profile = ? AND started_at >= ?`, profile, since,
+	).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to total watched time: %w", err)
+	}
+	return total, nil
+}
+
+func scanWatchEvent(row rowScanner) (entities.WatchEvent, error) {
+	var e entities.WatchEvent
+	var closedAt sql.NullTime
+	var search, intent sql.NullInt64
+	var query sql.NullString
+	if err := row.Scan(&e.ID, &e.Profile, &e.VideoID, &e.Title, &e.StartedAt, &e.UpdatedAt, &closedAt,
+		&e.WatchedSeconds, &search, &query, &intent); err != nil {
+		return e, err
+	}
+	e.ClosedAt = closedAt.Time
+	e.SearchID = search.Int64
+	e.SearchQuery = query.String
+	e.IntentID = intent.Int64
+	return e, nil
+}
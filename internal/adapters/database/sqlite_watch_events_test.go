@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestSQLiteRepository_WatchEventLinksLatestSearch(t *testing.T) {
+	// Arrange
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	now := time.Now()
+	older := &entities.SearchHistory{Query: "go tutorial", Results: 1, CreatedAt: now.Add(-time.Hour)}
+	latest := &entities.SearchHistory{Query: "golang", Results: 1, CreatedAt: now.Add(-time.Minute)}
+	later := &entities.SearchHistory{Query: "after the fact", Results: 1, CreatedAt: now.Add(time.Hour)}
+	for _, h := range []*entities.SearchHistory{older, latest, later} {
+		require.NoError(t, repo.Save(ctx, h))
+		require.NoError(t, repo.SaveSearchResults(ctx, h.ID, []entities.Video{{ID: "a", Title: "A", Channel: "chan"}}))
+	}
+
+	// Act
+	linked := &entities.WatchEvent{VideoID: "a", Title: "A", StartedAt: now}
+	require.NoError(t, repo.AddWatchEvent(ctx, linked))
+	unlinked := &entities.WatchEvent{VideoID: "b", Title: "B", StartedAt: now}
+	require.NoError(t, repo.AddWatchEvent(ctx, unlinked))
+
+	// Assert - the latest search before the viewing wins
+	assert.Equal(t, latest.ID, linked.SearchID)
+	assert.Equal(t, "golang", linked.SearchQuery)
+	assert.Zero(t, unlinked.SearchID)
+
+	got, err := repo.GetWatchEvent(ctx, linked.ID)
+	require.NoError(t, err)
+	assert.Equal(t, latest.ID, got.SearchID)
+	assert.Equal(t, "golang", got.SearchQuery)
+
+	// Deleting the search keeps the viewing but drops the link
+	require.NoError(t, repo.Delete(ctx, latest.ID))
+	got, err = repo.GetWatchEvent(ctx, linked.ID)
+	require.NoError(t, err)
+	assert.Zero(t, got.SearchID)
+	assert.Empty(t, got.SearchQuery)
+}
+
+func TestSQLiteRepository_WatchEventLinkedWhenSearchIsWrittenLater(t *testing.T) {
+	// Arrange - the player opens a result before the history writer flushes
+	repo := newTestRepository(t)
+
+	ctx := context.Background()
+	now := time.Now()
+	older := &entities.SearchHistory{Query: "go tutorial", Results: 1, CreatedAt: now.Add(-time.Hour)}
+	require.NoError(t, repo.Save(ctx, older))
+	require.NoError(t, repo.SaveSearchResults(ctx, older.ID, []entities.Video{{ID: "a", Title: "A"}}))
+
+	viewing := &entities.WatchEvent{VideoID: "a", Title: "A", StartedAt: now}
+	require.NoError(t, repo.AddWatchEvent(ctx, viewing))
+	require.Equal(t, older.ID, viewing.SearchID)
+	before := &entities.WatchEvent{VideoID: "a", Title: "A", StartedAt: now.Add(-2 * time.Minute)}
+	require.NoError(t, repo.AddWatchEvent(ctx, before))
+
+	// Act
+	flushed := &entities.SearchHistory{Query: "golang", Results: 1, CreatedAt: now.Add(-time.Second)}
+	require.NoError(t, repo.Save(ctx, flushed))
+	require.NoError(t, repo.SaveSearchResults(ctx, flushed.ID, []entities.Video{{ID: "a", Title: "A"}}))
+
+	// Assert - only viewings started after the search move to it
+	got, err := repo.GetWatchEvent(ctx, viewing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, flushed.ID, got.SearchID)
+	assert.Equal(t, "golang", got.SearchQuery)
+
+	got, err = repo.GetWatchEvent(ctx, before.ID)
+	require.NoError(t, err)
+	assert.Equal(t, older.ID, got.SearchID)
+
+	// Writing an older search afterwards doesn't steal the link back
+	require.NoError(t, repo.SaveSearchResults(ctx, older.ID, []entities.Video{{ID: "a", Title: "A"}}))
+	got, err = repo.GetWatchEvent(ctx, viewing.ID)
+	require.NoError(t, err)
+	assert.Equal(t, flushed.ID, got.SearchID)
+}
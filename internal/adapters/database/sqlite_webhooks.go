@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// selectWebhookDeliveriesSQL reads webhook deliveries
+const selectWebhookDeliveriesSQL = `
+SELECT id, endpoint, event, payload, status, attempts, next_attempt_at, last_attempt_at, last_error, created_at
+FROM webhook_deliveries`
+
+// EnqueueWebhookDeliveries stores pending deliveries in one transaction and sets their IDs
+func (r *SQLiteRepository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(deliveries))
+	err := inTx(ctx, r.db, func(tx *sql.Tx) error {
+		insert, err := tx.PrepareContext(ctx, `
+			INSERT INTO webhook_deliveries (endpoint, event, payload, status, next_attempt_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare insert: %w", err)
+		}
+		defer insert.Close()
+
+		for i, d := range deliveries {
+			result, err := insert.ExecContext(ctx,
+				d.Endpoint, d.Event, string(d.Payload), entities.WebhookPending, d.NextAttemptAt, d.CreatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+			}
+			if ids[i], err = result.LastInsertId(); err != nil {
+				return fmt.Errorf("failed to get last insert id: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, d := range deliveries {
+		d.ID = ids[i]
+		d.Status = entities.WebhookPending
+		d.Attempts = 0
+	}
+	return nil
+}
+
+// DueWebhookDeliveries returns up to limit pending deliveries due at or before now, oldest due first
+func (r *SQLiteRepository) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]entities.WebhookDelivery, error) {
+	return r.queryWebhookDeliveries(ctx,
+		selectWebhookDeliveriesSQL+` WHERE status = ? AND next_attempt_at <= ? ORDER BY next_attempt_at, id LIMIT ?`,
+		entities.WebhookPending, now, limit,
+	)
+}
+
+// RecordWebhookAttempt counts an attempt and stores its outcome
+func (r *SQLiteRepository) RecordWebhookAttempt(ctx context.Context, id int64, attempt entities.WebhookAttempt) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries
+		SET attempts = attempts + 1, status = ?, next_attempt_at = ?, last_attempt_at = ?, last_error = ?
+		WHERE id = ?`,
+		attempt.Status, attempt.NextAttemptAt, attempt.AttemptedAt, attempt.Error, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return requireAffected(result, "Webhook delivery")
+}
+
+// ListWebhookDeliveries returns up to limit deliveries, most recent first
+func (r *SQLiteRepository) ListWebhookDeliveries(ctx context.Context, limit int) ([]entities.WebhookDelivery, error) {
+	return r.queryWebhookDeliveries(ctx,
+		selectWebhookDeliveriesSQL+` ORDER BY created_at DESC, id DESC LIMIT ?`, limit,
+	)
+}
+
+// RetryWebhookDelivery makes a delivery pending again with its attempts reset
+func (r *SQLiteRepository) RetryWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE webhook_deliveries SET status = ?, attempts = 0, next_attempt_at = ? WHERE id = ?`,
+		entities.WebhookPending, dueAt, id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+	return requireAffected(result, "Webhook delivery")
+}
+
+// PruneWebhookDeliveries deletes finished deliveries created before cutoff
+func (r *SQLiteRepository) PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int, error) {
+	result, err := r.db.ExecContext(ctx,
+		`DELETE FROM webhook_deliveries WHERE status != ? AND created_at < ?`,
+		entities.WebhookPending, cutoff,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
+func (r *SQLiteRepository) queryWebhookDeliveries(ctx context.Context, query string, args ...any) ([]entities.WebhookDelivery, error) {
+	rows, err := r.readDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []entities.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func scanWebhookDelivery(row rowScanner) (entities.WebhookDelivery, error) {
+	var d entities.WebhookDelivery
+	var payload string
+	var lastAttempt sql.NullTime
+	if err := row.Scan(&d.ID, &d.Endpoint, &d.Event, &payload, &d.Status, &d.Attempts,
+		&d.NextAttemptAt, &lastAttempt, &d.LastError, &d.CreatedAt); err != nil {
+		return d, err
+	}
+	d.Payload = []byte(payload)
+	d.LastAttemptAt = lastAttempt.Time
+	return d, nil
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/cache"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// AdminHandler handles cache inspection and purge endpoints
+// Routes are expected to be protected by middleware.AdminAuth
+type AdminHandler struct {
+	cache         *cache.Cache
+	historyWriter *usecases.HistoryWriter
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(c *cache.Cache, historyWriter *usecases.HistoryWriter) *AdminHandler {
+	return &AdminHandler{
+		cache:         c,
+		historyWriter: historyWriter,
+	}
+}
+
+// CacheStatsResponse represents cache statistics in API responses
+type CacheStatsResponse struct {
+	TotalItems    int            `json:"total_items"`
+	MaxEntries    int            `json:"max_entries"`
+	DefaultTTL    string         `json:"default_ttl"`
+	OldestItemAge string         `json:"oldest_item_age"`
+	NewestItemAge string         `json:"newest_item_age"`
+	BySource      map[string]int `json:"by_source"`
+}
+
+// CacheEntryResponse represents a single cache entry in API responses
+type CacheEntryResponse struct {
+	Key        string  `json:"key"`
+	Namespace  string  `json:"namespace"`
+	Label      string  `json:"label"`
+	Source     string  `json:"source"`
+	AgeSeconds float64 `json:"age_seconds"`
+	TTLSeconds float64 `json:"ttl_seconds"`
+	SizeBytes  int     `json:"size_bytes"`
+}
+
+// HistoryWriterStatsResponse represents history writer counters in API responses
+type HistoryWriterStatsResponse struct {
+	Queued   int    `json:"queued"`
+	Enqueued uint64 `json:"enqueued"`
+	Written  uint64 `json:"written"`
+	Dropped  uint64 `json:"dropped"`
+	Failed   uint64 `json:"failed"`
+	Batches  uint64 `json:"batches"`
+}
+
+// CachePage renders the cache admin page
+func (h *AdminHandler) CachePage(c *gin.Context) {
+	entries := h.cache.Entries("")
+	respondComponent(c, pages.AdminCachePage(h.cache.GetStats(), namespaces(entries), entries))
+}
+
+// CacheStats returns cache statistics
+func (h *AdminHandler) CacheStats(c *gin.Context) {
+	stats := h.cache.GetStats()
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.CacheStats(stats))
+		return
+	}
+
+	respondSuccess(c, CacheStatsResponse{
+		TotalItems:    stats.TotalItems,
+		MaxEntries:    stats.MaxEntries,
+		DefaultTTL:    stats.DefaultTTL.String(),
+		OldestItemAge: stats.OldestItemAge.String(),
+		NewestItemAge: stats.NewestItemAge.String(),
+		BySource:      stats.BySource,
+	})
+}
+
+// CacheEntries lists cache entries, optionally filtered by ?namespace=
+func (h *AdminHandler) CacheEntries(c *gin.Context) {
+	h.respondEntries(c, h.cache.Entries(c.Query("namespace")))
+}
+
+// DeleteCacheEntry removes a single entry by key
+func (h *AdminHandler) DeleteCacheEntry(c *gin.Context) {
+	key := c.Param("key")
+	if _, found := h.cache.TTL(key); !found {
+		respondAppError(c, appErrors.NewNotFoundError("Cache entry"))
+		return
+	}
+
+	h.cache.Delete(key)
+
+	// HTMX swaps the deleted row with an empty response
+	if middleware.IsHTMXRequest(c) {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": 1})
+}
+
+// DeleteCacheByQuery removes every entry labeled with ?query= (all maxResults variants)
+func (h *AdminHandler) DeleteCacheByQuery(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("query"))
+	if query == "" {
+		respondAppError(c, appErrors.NewValidationError("query cannot be empty", nil))
+		return
+	}
+
+	deleted := h.cache.DeleteByLabel(query)
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.CacheEntries(h.cache.Entries("")))
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": deleted})
+}
+
+// ClearCacheNamespace removes every entry in a namespace
+func (h *AdminHandler) ClearCacheNamespace(c *gin.Context) {
+	deleted := h.cache.ClearNamespace(c.Param("namespace"))
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.CacheEntries(h.cache.Entries("")))
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": deleted})
+}
+
+// respondEntries renders entries as an HTMX fragment or JSON
+func (h *AdminHandler) respondEntries(c *gin.Context, entries []cache.EntryInfo) {
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.CacheEntries(entries))
+		return
+	}
+
+	resp := make([]CacheEntryResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, CacheEntryResponse{
+			Key:        e.Key,
+			Namespace:  e.Namespace,
+			Label:      e.Label,
+			Source:     e.Source,
+			AgeSeconds: e.Age.Seconds(),
+			TTLSeconds: e.TTL.Seconds(),
+			SizeBytes:  e.Size,
+		})
+	}
+	respondSuccess(c, resp)
+}
+
+// HistoryWriterStats returns the background history writer's counters
+func (h *AdminHandler) HistoryWriterStats(c *gin.Context) {
+	stats := h.historyWriter.Stats()
+	respondSuccess(c, HistoryWriterStatsResponse{
+		Queued:   stats.Queued,
+		Enqueued: stats.Enqueued,
+		Written:  stats.Written,
+		Dropped:  stats.Dropped,
+		Failed:   stats.Failed,
+		Batches:  stats.Batches,
+	})
+}
+
+// namespaces returns the distinct, sorted namespaces of the given entries
+func namespaces(entries []cache.EntryInfo) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, e := range entries {
+		if e.Namespace != "" && !seen[e.Namespace] {
+			seen[e.Namespace] = true
+			result = append(result, e.Namespace)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
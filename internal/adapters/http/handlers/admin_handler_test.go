@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/cache"
+)
+
+const testAdminToken = "test-admin-token"
+
+// newAdminTestRouter wires the cache admin endpoints the way RegisterAdminRoutes does
+func newAdminTestRouter(c *cache.Cache) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	admin := NewAdminHandler(c, nil)
+
+	group := r.Group("/admin", middleware.AdminAuth(testAdminToken))
+	group.GET("/cache/stats", admin.CacheStats)
+	group.GET("/cache/entries", admin.CacheEntries)
+	group.DELETE("/cache/entries", admin.DeleteCacheByQuery)
+	group.DELETE("/cache/entries/:key", admin.DeleteCacheEntry)
+	group.DELETE("/cache/namespaces/:namespace", admin.ClearCacheNamespace)
+	return r
+}
+
+// seededCache returns a cache with two search queries and one channel entry
+func seededCache() *cache.Cache {
+	c := cache.NewCache(100, time.Minute)
+	c.SetWithMeta(cache.NamespacedKey("search", "golang", 10), "a", time.Minute, cache.EntryMeta{Label: "golang", Source: "results"})
+	c.SetWithMeta(cache.NamespacedKey("search", "golang", 25), "b", time.Minute, cache.EntryMeta{Label: "golang", Source: "results"})
+	c.SetWithMeta(cache.NamespacedKey("search", "rust", 10), "c", time.Minute, cache.EntryMeta{Label: "rust", Source: "empty"})
+	c.SetWithMeta(cache.NamespacedKey("channel", "UC123"), "d", time.Minute, cache.EntryMeta{Label: "UC123"})
+	return c
+}
+
+// doAdmin sends an authorized admin request and decodes the data field into out
+func doAdmin(t *testing.T, r *gin.Engine, method, target string, out interface{}) int {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, nil)
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if out != nil && w.Code == http.StatusOK {
+		var body struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		require.NoError(t, json.Unmarshal(body.Data, out))
+	}
+	return w.Code
+}
+
+func TestAdminHandler_RequiresToken(t *testing.T) {
+	r := newAdminTestRouter(seededCache())
+
+	for _, target := range []string{"/admin/cache/stats", "/admin/cache/entries"} {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code, target)
+	}
+
+	c := seededCache()
+	r = newAdminTestRouter(c)
+	req := httptest.NewRequest(http.MethodDelete, "/admin/cache/namespaces/search", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	assert.Equal(t, 4, c.Len(), "rejected requests must not purge anything")
+}
+
+func TestAdminHandler_CacheStats(t *testing.T) {
+	r := newAdminTestRouter(seededCache())
+
+	var stats CacheStatsResponse
+	code := doAdmin(t, r, http.MethodGet, "/admin/cache/stats", &stats)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 4, stats.TotalItems)
+	assert.Equal(t, map[string]int{"results": 2, "empty": 1, "": 1}, stats.BySource)
+}
+
+func TestAdminHandler_CacheEntries(t *testing.T) {
+	r := newAdminTestRouter(seededCache())
+
+	var all, search []CacheEntryResponse
+	assert.Equal(t, http.StatusOK, doAdmin(t, r, http.MethodGet, "/admin/cache/entries", &all))
+	assert.Equal(t, http.StatusOK, doAdmin(t, r, http.MethodGet, "/admin/cache/entries?namespace=search", &search))
+
+	assert.Len(t, all, 4)
+	assert.Len(t, search, 3)
+	for _, e := range search {
+		assert.Equal(t, "search", e.Namespace)
+	}
+}
+
+func TestAdminHandler_DeleteCacheEntry(t *testing.T) {
+	c := seededCache()
+	r := newAdminTestRouter(c)
+	key := cache.NamespacedKey("search", "rust", 10)
+
+	assert.Equal(t, http.StatusOK, doAdmin(t, r, http.MethodDelete, "/admin/cache/entries/"+url.PathEscape(key), nil))
+	_, found := c.Get(key)
+	assert.False(t, found)
+
+	assert.Equal(t, http.StatusNotFound, doAdmin(t, r, http.MethodDelete, "/admin/cache/entries/"+url.PathEscape(key), nil))
+}
+
+func TestAdminHandler_DeleteCacheByQuery(t *testing.T) {
+	c := seededCache()
+	r := newAdminTestRouter(c)
+
+	var result map[string]int
+	code := doAdmin(t, r, http.MethodDelete, "/admin/cache/entries?query=golang", &result)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 2, result["deleted"])
+	assert.Equal(t, 2, c.Len())
+
+	assert.Equal(t, http.StatusBadRequest, doAdmin(t, r, http.MethodDelete, "/admin/cache/entries?query=%20", nil))
+}
+
+func TestAdminHandler_ClearCacheNamespace(t *testing.T) {
+	c := seededCache()
+	r := newAdminTestRouter(c)
+
+	var result map[string]int
+	code := doAdmin(t, r, http.MethodDelete, "/admin/cache/namespaces/search", &result)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 3, result["deleted"])
+	assert.Equal(t, 1, c.Len())
+}
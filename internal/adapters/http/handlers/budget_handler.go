@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+const (
+	// recentFocusSessions is how many past focus sessions the budget page shows
+	recentFocusSessions = 20
+	// profileCookieMaxAge keeps the chosen viewing profile for a year (seconds)
+	profileCookieMaxAge = 365 * 24 * 60 * 60
+)
+
+// BudgetHandler handles the viewing budget page, the layout budget bar and focus sessions
+type BudgetHandler struct {
+	budgetsUC *usecases.Budgets
+	focusUC   *usecases.FocusSessions
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(budgetsUC *usecases.Budgets, focusUC *usecases.FocusSessions) *BudgetHandler {
+	return &BudgetHandler{budgetsUC: budgetsUC, focusUC: focusUC}
+}
+
+// FocusSessionResponse represents a focus session in API responses
+type FocusSessionResponse struct {
+	ID         int64      `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	EndsAt     time.Time  `json:"ends_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	Reflection string     `json:"reflection,omitempty"`
+}
+
+// Page renders the budgets and recent focus sessions
+func (h *BudgetHandler) Page(c *gin.Context) {
+	h.renderPage(c, http.StatusOK)
+}
+
+// Bar renders the budget bar shown in the layout
+// It renders nothing when neither budgets nor focus sessions are configured
+func (h *BudgetHandler) Bar(c *gin.Context) {
+	if !h.budgetsUC.Enabled() && !h.focusUC.Enabled() {
+		c.Status(http.StatusOK)
+		return
+	}
+	h.renderBar(c)
+}
+
+// StartFocus starts a focus session, ending any that is running
+func (h *BudgetHandler) StartFocus(c *gin.Context) {
+	session, err := h.focusUC.Start(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to start focus session")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		h.renderBar(c)
+		return
+	}
+	respondSuccess(c, focusSessionResponse(*session))
+}
+
+// EndFocus ends a focus session with a reflection (form field reflection)
+func (h *BudgetHandler) EndFocus(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid focus session id", err))
+		return
+	}
+
+	if err := h.focusUC.End(c.Request.Context(), id, c.PostForm("reflection")); err != nil {
+		respondError(c, err, "Failed to end focus session")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		h.renderBar(c)
+		return
+	}
+	respondSuccess(c, gin.H{"ended": id})
+}
+
+// SwitchProfile selects the viewing profile (form field profile) for this browser
+// The choice is kept in a cookie that the Profile middleware reads
+func (h *BudgetHandler) SwitchProfile(c *gin.Context) {
+	profile := c.PostForm("profile")
+	if !h.budgetsUC.HasProfile(profile) {
+		respondAppError(c, appErrors.NewValidationError("unknown viewing profile", nil))
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middleware.ProfileCookie, profile, profileCookieMaxAge, "/", "", false, true)
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/budget")
+		c.Status(http.StatusNoContent)
+		return
+	}
+	c.Redirect(http.StatusSeeOther, "/budget")
+}
+
+// redirectToBudget sends HTMX requests refused for an exhausted budget to the budget page
+// HTMX does not swap error responses, so the error alone would leave the page unchanged
+func redirectToBudget(c *gin.Context, err error) {
+	if appErrors.GetErrorCode(err) == appErrors.ErrCodeBudgetExhausted && middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/budget")
+	}
+}
+
+// renderPage renders the budget page with the given status code
+// Searches refused for an exhausted budget are answered with this page and 429
+func (h *BudgetHandler) renderPage(c *gin.Context, status int) {
+	ctx := c.Request.Context()
+
+	budgets, err := h.budgetsUC.Status(ctx)
+	if err != nil {
+		respondError(c, err, "Failed to get viewing budget")
+		return
+	}
+	sessions, err := h.focusUC.Recent(ctx, recentFocusSessions)
+	if err != nil {
+		respondError(c, err, "Failed to list focus sessions")
+		return
+	}
+
+	c.Status(status)
+	respondComponent(c, pages.BudgetPage(budgets, h.budgetsUC.Profiles(), sessions, h.focusUC.Length()))
+}
+
+func (h *BudgetHandler) renderBar(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	budgets, err := h.budgetsUC.Status(ctx)
+	if err != nil {
+		respondError(c, err, "Failed to get viewing budget")
+		return
+	}
+	current, err := h.focusUC.Current(ctx)
+	if err != nil {
+		respondError(c, err, "Failed to get focus session")
+		return
+	}
+
+	respondComponent(c, components.BudgetBar(budgets, current, h.focusUC.Length(), time.Now()))
+}
+
+func focusSessionResponse(s entities.FocusSession) FocusSessionResponse {
+	resp := FocusSessionResponse{
+		ID:         s.ID,
+		StartedAt:  s.StartedAt,
+		EndsAt:     s.EndsAt,
+		Reflection: s.Reflection,
+	}
+	if s.Ended() {
+		resp.EndedAt = &s.EndedAt
+	}
+	return resp
+}
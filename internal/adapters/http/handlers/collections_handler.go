@@ -0,0 +1,388 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// collectionContentTypes maps export formats to their MIME types
+var collectionContentTypes = map[usecases.CollectionFormat]string{
+	usecases.CollectionFormatJSON: "application/json; charset=utf-8",
+	usecases.CollectionFormatM3U:  "audio/x-mpegurl; charset=utf-8",
+	usecases.CollectionFormatText: "text/plain; charset=utf-8",
+}
+
+// CollectionsHandler handles the collection pages, API, import and export
+type CollectionsHandler struct {
+	collectionsUC *usecases.Collections
+	feeds         bool
+}
+
+// NewCollectionsHandler creates a new collections handler
+// feeds shows links to each collection's Atom and RSS feeds (syndication.enabled)
+func NewCollectionsHandler(collectionsUC *usecases.Collections, feeds bool) *CollectionsHandler {
+	return &CollectionsHandler{collectionsUC: collectionsUC, feeds: feeds}
+}
+
+// CollectionResponse represents a collection in API responses
+// Items is omitted in listings
+type CollectionResponse struct {
+	ID          int64                    `json:"id"`
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	ItemCount   int                      `json:"item_count"`
+	CreatedAt   time.Time                `json:"created_at"`
+	UpdatedAt   time.Time                `json:"updated_at"`
+	Items       []CollectionItemResponse `json:"items,omitempty"`
+}
+
+// CollectionItemResponse represents a collection item in API responses
+type CollectionItemResponse struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Channel     string     `json:"channel"`
+	Thumbnail   string     `json:"thumbnail"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Position    int        `json:"position"`
+	Note        string     `json:"note"`
+	AddedAt     time.Time  `json:"added_at"`
+}
+
+// Index renders the collections page
+func (h *CollectionsHandler) Index(c *gin.Context) {
+	collections, err := h.collectionsUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list collections")
+		return
+	}
+
+	respondComponent(c, pages.CollectionsPage(collections))
+}
+
+// List returns every collection, without items
+func (h *CollectionsHandler) List(c *gin.Context) {
+	collections, err := h.collectionsUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list collections")
+		return
+	}
+
+	resp := make([]CollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		resp = append(resp, collectionResponse(collection))
+	}
+	respondSuccess(c, resp)
+}
+
+// Create creates an empty collection (form fields name, description)
+// HTMX requests are redirected to the new collection's page
+func (h *CollectionsHandler) Create(c *gin.Context) {
+	collection, err := h.collectionsUC.Create(c.Request.Context(), c.PostForm("name"), c.PostForm("description"))
+	if err != nil {
+		respondError(c, err, "Failed to create collection")
+		return
+	}
+	h.respondCreated(c, collection)
+}
+
+// Import creates a collection from an uploaded JSON, M3U or URL list file
+// (multipart field file, optional field name overriding the file's name)
+func (h *CollectionsHandler) Import(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		respondAppError(c, appErrors.NewValidationError("choose a file to import", err))
+		return
+	}
+	if header.Size > usecases.MaxCollectionImportSize {
+		respondAppError(c, appErrors.NewValidationError(
+			fmt.Sprintf("import file must be at most %d bytes", usecases.MaxCollectionImportSize), nil))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondError(c, err, "Failed to read import file")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, usecases.MaxCollectionImportSize+1))
+	if err != nil {
+		respondError(c, err, "Failed to read import file")
+		return
+	}
+
+	collection, err := h.collectionsUC.Import(c.Request.Context(), data, c.PostForm("name"))
+	if err != nil {
+		respondError(c, err, "Failed to import collection")
+		return
+	}
+	h.respondCreated(c, collection)
+}
+
+// Page renders a collection's page
+func (h *CollectionsHandler) Page(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionsUC.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get collection")
+		return
+	}
+
+	respondComponent(c, pages.CollectionPage(collection, h.feeds))
+}
+
+// Get returns a collection with its items
+func (h *CollectionsHandler) Get(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	collection, err := h.collectionsUC.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get collection")
+		return
+	}
+
+	respondSuccess(c, collectionResponse(*collection))
+}
+
+// Update renames a collection and replaces its description (form fields name, description)
+// HTMX requests get the refreshed collection header
+func (h *CollectionsHandler) Update(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionsUC.Update(c.Request.Context(), id, c.PostForm("name"), c.PostForm("description")); err != nil {
+		respondError(c, err, "Failed to update collection")
+		return
+	}
+
+	collection, err := h.collectionsUC.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get collection")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.CollectionHeader(collection, h.feeds))
+		return
+	}
+	respondSuccess(c, collectionResponse(*collection))
+}
+
+// Delete removes a collection; HTMX requests go back to the collections page
+func (h *CollectionsHandler) Delete(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionsUC.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete collection")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/collections")
+		c.Status(http.StatusOK)
+		return
+	}
+	respondSuccess(c, nil)
+}
+
+// Export downloads a collection (?format=json, m3u or txt; JSON by default)
+func (h *CollectionsHandler) Export(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	format, err := usecases.ParseCollectionFormat(c.Query("format"))
+	if err != nil {
+		respondError(c, err, "Invalid format")
+		return
+	}
+
+	export, err := h.collectionsUC.Export(c.Request.Context(), id, format)
+	if err != nil {
+		respondError(c, err, "Failed to export collection")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, export.Filename))
+	c.Data(http.StatusOK, collectionContentTypes[export.Format], export.Data)
+}
+
+// AddItem appends a video to a collection
+// Form field link (a YouTube URL or video ID) or the video fields id, title,
+// channel, thumbnail, published_at; plus an optional note
+func (h *CollectionsHandler) AddItem(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	var err error
+	if link := c.PostForm("link"); link != "" {
+		_, err = h.collectionsUC.AddLink(c.Request.Context(), id, link, c.PostForm("note"))
+	} else {
+		video := entities.Video{
+			ID:        c.PostForm("id"),
+			Title:     c.PostForm("title"),
+			Channel:   c.PostForm("channel"),
+			Thumbnail: c.PostForm("thumbnail"),
+		}
+		if v := c.PostForm("published_at"); v != "" {
+			published, parseErr := time.Parse(time.RFC3339, v)
+			if parseErr != nil {
+				respondAppError(c, appErrors.NewValidationError("published_at must be an RFC 3339 time", parseErr))
+				return
+			}
+			video.PublishedAt = published
+		}
+		_, err = h.collectionsUC.AddItem(c.Request.Context(), id, video, c.PostForm("note"))
+	}
+	if err != nil {
+		respondError(c, err, "Failed to add video to collection")
+		return
+	}
+	h.respondItems(c, id)
+}
+
+// SetNote replaces an item's note (form field note)
+func (h *CollectionsHandler) SetNote(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionsUC.SetNote(c.Request.Context(), id, c.Param("videoID"), c.PostForm("note")); err != nil {
+		respondError(c, err, "Failed to update note")
+		return
+	}
+	h.respondItems(c, id)
+}
+
+// MoveItem puts an item at a new position (form field position, 1-based)
+func (h *CollectionsHandler) MoveItem(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	position, err := strconv.Atoi(c.PostForm("position"))
+	if err != nil {
+		respondAppError(c, appErrors.NewValidationError("position must be a number", err))
+		return
+	}
+
+	if err := h.collectionsUC.MoveItem(c.Request.Context(), id, c.Param("videoID"), position); err != nil {
+		respondError(c, err, "Failed to move video")
+		return
+	}
+	h.respondItems(c, id)
+}
+
+// RemoveItem takes a video out of a collection
+func (h *CollectionsHandler) RemoveItem(c *gin.Context) {
+	id, ok := collectionID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.collectionsUC.RemoveItem(c.Request.Context(), id, c.Param("videoID")); err != nil {
+		respondError(c, err, "Failed to remove video from collection")
+		return
+	}
+	h.respondItems(c, id)
+}
+
+// respondCreated sends HTMX requests to a new collection's page and returns it as JSON otherwise
+func (h *CollectionsHandler) respondCreated(c *gin.Context, created *entities.Collection) {
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", components.CollectionURL(created.ID))
+		c.Status(http.StatusCreated)
+		return
+	}
+
+	collection, err := h.collectionsUC.Get(c.Request.Context(), created.ID)
+	if err != nil {
+		respondError(c, err, "Failed to get collection")
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    collectionResponse(*collection),
+	})
+}
+
+// respondItems responds with a collection: the item list fragment for HTMX, JSON otherwise
+func (h *CollectionsHandler) respondItems(c *gin.Context, id int64) {
+	collection, err := h.collectionsUC.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get collection")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.CollectionItems(collection))
+		return
+	}
+	respondSuccess(c, collectionResponse(*collection))
+}
+
+// collectionID parses the :id path parameter, responding with an error if it's invalid
+func collectionID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid collection id", err))
+		return 0, false
+	}
+	return id, true
+}
+
+func collectionResponse(collection entities.Collection) CollectionResponse {
+	resp := CollectionResponse{
+		ID:          collection.ID,
+		Name:        collection.Name,
+		Description: collection.Description,
+		ItemCount:   collection.ItemCount,
+		CreatedAt:   collection.CreatedAt,
+		UpdatedAt:   collection.UpdatedAt,
+	}
+	for _, item := range collection.Items {
+		itemResp := CollectionItemResponse{
+			ID:        item.ID,
+			Title:     item.Title,
+			Channel:   item.Channel,
+			Thumbnail: item.Thumbnail,
+			Position:  item.Position,
+			Note:      item.Note,
+			AddedAt:   item.AddedAt,
+		}
+		if !item.PublishedAt.IsZero() {
+			itemResp.PublishedAt = &item.PublishedAt
+		}
+		resp.Items = append(resp.Items, itemResp)
+	}
+	return resp
+}
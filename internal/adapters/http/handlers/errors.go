@@ -1,9 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"log/slog"
 	"net/http"
 
+	"github.com/a-h/templ"
 	"github.com/gin-gonic/gin"
 	"github.com/uiansol/zentube/internal/adapters/http/middleware"
 	appErrors "github.com/uiansol/zentube/internal/errors"
@@ -32,7 +34,8 @@ func respondError(c *gin.Context, err error, fallbackMessage string) {
 	var errorCode string
 	var message string
 
-	if appErr, ok := err.(*appErrors.AppError); ok {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
 		// Use AppError details
 		statusCode = appErr.StatusCode
 		errorCode = appErr.Code
@@ -82,3 +85,10 @@ func respondSuccess(c *gin.Context, data interface{}) {
 		"data":    data,
 	})
 }
+
+// respondComponent renders a templ page or fragment
+func respondComponent(c *gin.Context, component templ.Component) {
+	if err := component.Render(c.Request.Context(), c.Writer); err != nil {
+		respondError(c, appErrors.NewInternalError("Failed to render page", err), "Failed to render page")
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// FeedHandler handles the subscriptions feed page and API
+type FeedHandler struct {
+	subsUC *usecases.Subscriptions
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(subsUC *usecases.Subscriptions) *FeedHandler {
+	return &FeedHandler{subsUC: subsUC}
+}
+
+// FeedItemResponse represents an upload in the feed in API responses
+type FeedItemResponse struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Channel     string     `json:"channel"`
+	ChannelID   string     `json:"channel_id"`
+	Thumbnail   string     `json:"thumbnail"`
+	PublishedAt time.Time  `json:"published_at"`
+	FetchedAt   time.Time  `json:"fetched_at"`
+	SeenAt      *time.Time `json:"seen_at,omitempty"`
+}
+
+// FeedPageResponse represents a page of the feed in API responses
+type FeedPageResponse struct {
+	Items      []FeedItemResponse `json:"items"`
+	NextCursor string             `json:"next_cursor,omitempty"`
+}
+
+// SubscriptionResponse represents a followed channel in API responses
+type SubscriptionResponse struct {
+	ChannelID    string     `json:"channel_id"`
+	Title        string     `json:"title"`
+	SubscribedAt time.Time  `json:"subscribed_at"`
+	LastPolledAt *time.Time `json:"last_polled_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	Unseen       int        `json:"unseen"`
+}
+
+// Page renders the feed with the first page of uploads (?unseen=&channel=)
+func (h *FeedHandler) Page(c *gin.Context) {
+	filter, err := parseFeedFilter(c)
+	if err != nil {
+		respondError(c, err, "Invalid feed filter")
+		return
+	}
+
+	page, err := h.subsUC.Feed(c.Request.Context(), filter, "", 0)
+	if err != nil {
+		respondError(c, err, "Failed to load feed")
+		return
+	}
+
+	subs, err := h.subsUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list subscriptions")
+		return
+	}
+
+	respondComponent(c, pages.FeedPage(filter, subs, page, nextFeedPageURL(c, page)))
+}
+
+// List returns a page of the feed (?unseen=&channel=&cursor=&page_size=)
+// HTMX requests get the rows and a "load more" button
+func (h *FeedHandler) List(c *gin.Context) {
+	filter, err := parseFeedFilter(c)
+	if err != nil {
+		respondError(c, err, "Invalid feed filter")
+		return
+	}
+
+	pageSize := 0
+	if v := c.Query("page_size"); v != "" {
+		if pageSize, err = strconv.Atoi(v); err != nil {
+			respondAppError(c, appErrors.NewValidationError("page_size must be a number", err))
+			return
+		}
+	}
+
+	page, err := h.subsUC.Feed(c.Request.Context(), filter, c.Query("cursor"), pageSize)
+	if err != nil {
+		respondError(c, err, "Failed to load feed")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.FeedRows(page, nextFeedPageURL(c, page)))
+		return
+	}
+
+	resp := FeedPageResponse{
+		Items:      make([]FeedItemResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, item := range page.Items {
+		resp.Items = append(resp.Items, feedItemResponse(item))
+	}
+	respondSuccess(c, resp)
+}
+
+// SetSeen marks an upload seen or unseen (form field seen)
+// HTMX requests get the updated seen toggle
+func (h *FeedHandler) SetSeen(c *gin.Context) {
+	seen, err := strconv.ParseBool(c.PostForm("seen"))
+	if err != nil {
+		respondAppError(c, appErrors.NewValidationError("seen must be true or false", err))
+		return
+	}
+
+	videoID := c.Param("id")
+	if err := h.subsUC.SetSeen(c.Request.Context(), videoID, seen); err != nil {
+		respondError(c, err, "Failed to update feed item")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.FeedSeenToggle(videoID, seen))
+		return
+	}
+
+	respondSuccess(c, gin.H{"id": videoID, "seen": seen})
+}
+
+// MarkAllSeen marks every unseen upload as seen (optional form field channel)
+func (h *FeedHandler) MarkAllSeen(c *gin.Context) {
+	channelID := c.PostForm("channel")
+
+	marked, err := h.subsUC.MarkAllSeen(c.Request.Context(), channelID)
+	if err != nil {
+		respondError(c, err, "Failed to mark feed seen")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", components.FeedURL(entities.FeedFilter{ChannelID: channelID}))
+		c.Status(http.StatusOK)
+		return
+	}
+
+	respondSuccess(c, gin.H{"marked": marked})
+}
+
+// Refresh polls every followed channel now instead of waiting for the scheduler
+func (h *FeedHandler) Refresh(c *gin.Context) {
+	result, err := h.subsUC.PollAll(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to refresh feed")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/feed")
+		c.Status(http.StatusOK)
+		return
+	}
+
+	respondSuccess(c, gin.H{
+		"channels":   result.Channels,
+		"new_videos": result.NewVideos,
+		"failed":     result.Failed,
+	})
+}
+
+// ListSubscriptions returns every followed channel
+func (h *FeedHandler) ListSubscriptions(c *gin.Context) {
+	subs, err := h.subsUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list subscriptions")
+		return
+	}
+
+	resp := make([]SubscriptionResponse, 0, len(subs))
+	for _, sub := range subs {
+		resp = append(resp, subscriptionResponse(sub))
+	}
+	respondSuccess(c, resp)
+}
+
+// Subscribe follows a channel (form field channel: ID, @handle or channel link)
+func (h *FeedHandler) Subscribe(c *gin.Context) {
+	sub, err := h.subsUC.Subscribe(c.Request.Context(), c.PostForm("channel"))
+	if err != nil {
+		respondError(c, err, "Failed to subscribe")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/feed")
+		c.Status(http.StatusCreated)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    subscriptionResponse(*sub),
+	})
+}
+
+// Unsubscribe unfollows a channel and drops its uploads from the feed
+func (h *FeedHandler) Unsubscribe(c *gin.Context) {
+	if err := h.subsUC.Unsubscribe(c.Request.Context(), c.Param("channelID")); err != nil {
+		respondError(c, err, "Failed to unsubscribe")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/feed")
+		c.Status(http.StatusOK)
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": 1})
+}
+
+// parseFeedFilter reads the feed filters from the query string
+// Channel IDs are validated by the use case
+func parseFeedFilter(c *gin.Context) (entities.FeedFilter, error) {
+	filter := entities.FeedFilter{ChannelID: c.Query("channel")}
+
+	if v := c.Query("unseen"); v != "" {
+		unseen, err := strconv.ParseBool(v)
+		if err != nil {
+			return filter, appErrors.NewValidationError("unseen must be true or false", err)
+		}
+		filter.UnseenOnly = unseen
+	}
+	return filter, nil
+}
+
+// nextFeedPageURL is the URL of the page after page, keeping the current filters
+func nextFeedPageURL(c *gin.Context, page *entities.FeedPage) string {
+	if page.NextCursor == "" {
+		return ""
+	}
+
+	values := url.Values{}
+	for _, key := range []string{"unseen", "channel", "page_size"} {
+		if v := c.Query(key); v != "" {
+			values.Set(key, v)
+		}
+	}
+	values.Set("cursor", page.NextCursor)
+	return "/feed/items?" + values.Encode()
+}
+
+func feedItemResponse(item entities.FeedItem) FeedItemResponse {
+	resp := FeedItemResponse{
+		ID:          item.ID,
+		Title:       item.Title,
+		Channel:     item.Channel,
+		ChannelID:   item.ChannelID,
+		Thumbnail:   item.Thumbnail,
+		PublishedAt: item.PublishedAt,
+		FetchedAt:   item.FetchedAt,
+	}
+	if item.Seen() {
+		resp.SeenAt = &item.SeenAt
+	}
+	return resp
+}
+
+func subscriptionResponse(sub entities.Subscription) SubscriptionResponse {
+	resp := SubscriptionResponse{
+		ChannelID:    sub.ID,
+		Title:        sub.Title,
+		SubscribedAt: sub.SubscribedAt,
+		LastError:    sub.LastError,
+		Unseen:       sub.UnseenCount,
+	}
+	if !sub.LastPolledAt.IsZero() {
+		resp.LastPolledAt = &sub.LastPolledAt
+	}
+	return resp
+}
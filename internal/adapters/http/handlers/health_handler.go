@@ -2,7 +2,6 @@ package handlers
 
 import (
 	"context"
-	"database/sql"
 	"log/slog"
 	"net/http"
 	"time"
@@ -10,14 +9,19 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Pinger checks that a dependency is reachable (*sql.DB implements it)
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
 // HealthHandler handles health check endpoints
 type HealthHandler struct {
-	db     *sql.DB
+	db     Pinger
 	logger *slog.Logger
 }
 
 // NewHealthHandler creates a new health check handler
-func NewHealthHandler(db *sql.DB, logger *slog.Logger) *HealthHandler {
+func NewHealthHandler(db Pinger, logger *slog.Logger) *HealthHandler {
 	return &HealthHandler{
 		db:     db,
 		logger: logger,
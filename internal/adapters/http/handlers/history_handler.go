@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/internal/validation"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// historySearchLimit is the number of full-text matches shown per lookup
+const historySearchLimit = 20
+
+// historyDateLayout is the format of the from/to filters (as sent by <input type="date">)
+const historyDateLayout = "2006-01-02"
+
+// HistoryHandler handles search history pages and the history API
+type HistoryHandler struct {
+	findUC   *usecases.FindSearchHistory
+	listUC   *usecases.ListSearchHistory
+	deleteUC *usecases.DeleteSearchHistory
+}
+
+// NewHistoryHandler creates a new history handler
+func NewHistoryHandler(findUC *usecases.FindSearchHistory, listUC *usecases.ListSearchHistory, deleteUC *usecases.DeleteSearchHistory) *HistoryHandler {
+	return &HistoryHandler{
+		findUC:   findUC,
+		listUC:   listUC,
+		deleteUC: deleteUC,
+	}
+}
+
+// SearchHistoryResponse represents a past search in API responses
+type SearchHistoryResponse struct {
+	ID        int64     `json:"id"`
+	Query     string    `json:"query"`
+	Results   int       `json:"results"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SearchHistoryPageResponse represents a page of past searches in API responses
+type SearchHistoryPageResponse struct {
+	Items      []SearchHistoryResponse `json:"items"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
+
+// Page renders the history page with the first page of results
+func (h *HistoryHandler) Page(c *gin.Context) {
+	filter, err := parseHistoryFilter(c)
+	if err != nil {
+		respondError(c, err, "Invalid history filter")
+		return
+	}
+
+	page, err := h.listUC.Execute(c.Request.Context(), filter, "", 0)
+	if err != nil {
+		respondError(c, err, "Failed to list search history")
+		return
+	}
+
+	respondComponent(c, pages.HistoryPage(historyFilterValues(c), page, nextPageURL(c, page)))
+}
+
+// List returns a page of past searches (?from=&to=&contains=&min_results=&cursor=&page_size=)
+// HTMX requests get the rows and a "load more" button
+func (h *HistoryHandler) List(c *gin.Context) {
+	filter, err := parseHistoryFilter(c)
+	if err != nil {
+		respondError(c, err, "Invalid history filter")
+		return
+	}
+
+	pageSize := 0
+	if v := c.Query("page_size"); v != "" {
+		if pageSize, err = strconv.Atoi(v); err != nil {
+			respondAppError(c, appErrors.NewValidationError("page_size must be a number", err))
+			return
+		}
+	}
+
+	page, err := h.listUC.Execute(c.Request.Context(), filter, c.Query("cursor"), pageSize)
+	if err != nil {
+		respondError(c, err, "Failed to list search history")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.HistoryRows(page, nextPageURL(c, page)))
+		return
+	}
+
+	resp := SearchHistoryPageResponse{
+		Items:      make([]SearchHistoryResponse, 0, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for _, item := range page.Items {
+		resp.Items = append(resp.Items, SearchHistoryResponse{
+			ID:        item.ID,
+			Query:     item.Query,
+			Results:   item.Results,
+			CreatedAt: item.CreatedAt,
+		})
+	}
+	respondSuccess(c, resp)
+}
+
+// Delete removes a single past search
+func (h *HistoryHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		respondAppError(c, appErrors.NewValidationError("id must be a number", err))
+		return
+	}
+
+	if err := h.deleteUC.Execute(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete search history")
+		return
+	}
+
+	// HTMX swaps the deleted row with an empty response
+	if middleware.IsHTMXRequest(c) {
+		c.Status(http.StatusOK)
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": 1})
+}
+
+// DeleteAll removes every past search
+func (h *HistoryHandler) DeleteAll(c *gin.Context) {
+	deleted, err := h.deleteUC.ExecuteAll(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to delete search history")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.HistoryRows(&entities.SearchHistoryPage{}, ""))
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": deleted})
+}
+
+// Search does a full-text lookup over past searches (?q=)
+func (h *HistoryHandler) Search(c *gin.Context) {
+	query := c.Query("q")
+
+	var matches []entities.SearchHistoryMatch
+	if query != "" {
+		input, err := validation.ValidateSearchQuery(query, historySearchLimit)
+		if err != nil {
+			respondError(c, err, "Invalid search query")
+			return
+		}
+		query = input.Query
+
+		matches, err = h.findUC.Execute(c.Request.Context(), input.Query, int(input.MaxResults))
+		if err != nil {
+			respondError(c, appErrors.NewInternalError("Failed to search history", err), "Failed to search history")
+			return
+		}
+	}
+
+	// HTMX live search only swaps the result list
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.HistoryMatches(query, matches))
+		return
+	}
+
+	respondComponent(c, pages.HistorySearchPage(query, matches))
+}
+
+// parseHistoryFilter reads the listing filters from the query string
+// Dates are whole days in server local time (the zone history is stored in); to is inclusive
+func parseHistoryFilter(c *gin.Context) (entities.SearchHistoryFilter, error) {
+	var filter entities.SearchHistoryFilter
+
+	if v := c.Query("from"); v != "" {
+		from, err := time.ParseInLocation(historyDateLayout, v, time.Local)
+		if err != nil {
+			return filter, appErrors.NewValidationError("from must be a date (YYYY-MM-DD)", err)
+		}
+		filter.From = from
+	}
+
+	if v := c.Query("to"); v != "" {
+		to, err := time.ParseInLocation(historyDateLayout, v, time.Local)
+		if err != nil {
+			return filter, appErrors.NewValidationError("to must be a date (YYYY-MM-DD)", err)
+		}
+		filter.To = to.AddDate(0, 0, 1)
+	}
+
+	if v := strings.TrimSpace(c.Query("contains")); v != "" {
+		input, err := validation.ValidateSearchQuery(v, 1)
+		if err != nil {
+			return filter, err
+		}
+		filter.Contains = input.Query
+	}
+
+	if v := c.Query("min_results"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return filter, appErrors.NewValidationError("min_results must be a non-negative number", err)
+		}
+		filter.MinResults = n
+	}
+
+	return filter, nil
+}
+
+// historyFilterValues returns the raw filter values to refill the filter form
+func historyFilterValues(c *gin.Context) url.Values {
+	values := url.Values{}
+	for _, key := range []string{"from", "to", "contains", "min_results"} {
+		if v := c.Query(key); v != "" {
+			values.Set(key, v)
+		}
+	}
+	return values
+}
+
+// nextPageURL links to the page after this one with the same filters
+func nextPageURL(c *gin.Context, page *entities.SearchHistoryPage) string {
+	if page.NextCursor == "" {
+		return ""
+	}
+
+	values := historyFilterValues(c)
+	if v := c.Query("page_size"); v != "" {
+		values.Set("page_size", v)
+	}
+	values.Set("cursor", page.NextCursor)
+	return "/history/entries?" + values.Encode()
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// InsightsHandler handles the search analytics page and API
+type InsightsHandler struct {
+	insightsUC *usecases.GetSearchInsights
+}
+
+// NewInsightsHandler creates a new insights handler
+func NewInsightsHandler(insightsUC *usecases.GetSearchInsights) *InsightsHandler {
+	return &InsightsHandler{insightsUC: insightsUC}
+}
+
+// QueryStatsResponse represents a grouped query in API responses
+type QueryStatsResponse struct {
+	Query       string `json:"query"`
+	Searches    int    `json:"searches"`
+	ZeroResults int    `json:"zero_results"`
+	CacheHits   int    `json:"cache_hits"`
+}
+
+// PeriodStatsResponse represents the totals of a day or week in API responses
+type PeriodStatsResponse struct {
+	Start         string  `json:"start"`
+	Searches      int     `json:"searches"`
+	ZeroResults   int     `json:"zero_results"`
+	CacheHits     int     `json:"cache_hits"`
+	CacheHitRatio float64 `json:"cache_hit_ratio"`
+}
+
+// InsightsResponse represents search insights in API responses
+type InsightsResponse struct {
+	Since             string                `json:"since"`
+	Searches          int                   `json:"searches"`
+	CacheHits         int                   `json:"cache_hits"`
+	CacheHitRatio     float64               `json:"cache_hit_ratio"`
+	TopQueries        []QueryStatsResponse  `json:"top_queries"`
+	ZeroResultQueries []QueryStatsResponse  `json:"zero_result_queries"`
+	Daily             []PeriodStatsResponse `json:"daily"`
+	Weekly            []PeriodStatsResponse `json:"weekly"`
+}
+
+// Page renders the insights page (?days=)
+func (h *InsightsHandler) Page(c *gin.Context) {
+	days, insights, ok := h.execute(c)
+	if !ok {
+		return
+	}
+
+	respondComponent(c, pages.InsightsPage(insights, days))
+}
+
+// Stats returns search insights as JSON (?days=)
+func (h *InsightsHandler) Stats(c *gin.Context) {
+	_, insights, ok := h.execute(c)
+	if !ok {
+		return
+	}
+
+	respondSuccess(c, InsightsResponse{
+		Since:             insights.Since.Format(historyDateLayout),
+		Searches:          insights.Searches,
+		CacheHits:         insights.CacheHits,
+		CacheHitRatio:     insights.CacheHitRatio(),
+		TopQueries:        queryStatsResponses(insights.TopQueries),
+		ZeroResultQueries: queryStatsResponses(insights.ZeroResultQueries),
+		Daily:             periodStatsResponses(insights.Daily),
+		Weekly:            periodStatsResponses(insights.Weekly),
+	})
+}
+
+// execute parses ?days= and runs the use case, responding with the error if any
+func (h *InsightsHandler) execute(c *gin.Context) (int, *entities.SearchInsights, bool) {
+	days := usecases.DefaultInsightsDays
+	if v := c.Query("days"); v != "" {
+		var err error
+		if days, err = strconv.Atoi(v); err != nil {
+			respondAppError(c, appErrors.NewValidationError("days must be a number", err))
+			return 0, nil, false
+		}
+	}
+
+	insights, err := h.insightsUC.Execute(c.Request.Context(), days)
+	if err != nil {
+		respondError(c, err, "Failed to load insights")
+		return 0, nil, false
+	}
+	return days, insights, true
+}
+
+func queryStatsResponses(queries []entities.QueryStats) []QueryStatsResponse {
+	resp := make([]QueryStatsResponse, 0, len(queries))
+	for _, q := range queries {
+		resp = append(resp, QueryStatsResponse{
+			Query:       q.Query,
+			Searches:    q.Searches,
+			ZeroResults: q.ZeroResults,
+			CacheHits:   q.CacheHits,
+		})
+	}
+	return resp
+}
+
+func periodStatsResponses(periods []entities.DailySearchStats) []PeriodStatsResponse {
+	resp := make([]PeriodStatsResponse, 0, len(periods))
+	for _, p := range periods {
+		resp = append(resp, PeriodStatsResponse{
+			Start:         p.Day.Format(historyDateLayout),
+			Searches:      p.Searches,
+			ZeroResults:   p.ZeroResults,
+			CacheHits:     p.CacheHits,
+			CacheHitRatio: p.CacheHitRatio(),
+		})
+	}
+	return resp
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// IntentsHandler handles "did you find it?" ratings and the intent review
+type IntentsHandler struct {
+	intentsUC *usecases.SearchIntents
+}
+
+// NewIntentsHandler creates a new intents handler
+func NewIntentsHandler(intentsUC *usecases.SearchIntents) *IntentsHandler {
+	return &IntentsHandler{intentsUC: intentsUC}
+}
+
+// SearchIntentResponse represents a search intent in API responses
+type SearchIntentResponse struct {
+	ID             int64      `json:"id"`
+	Intent         string     `json:"intent"`
+	CreatedAt      time.Time  `json:"created_at"`
+	Outcome        string     `json:"outcome,omitempty"`
+	RatedAt        *time.Time `json:"rated_at,omitempty"`
+	Queries        []string   `json:"queries,omitempty"`
+	Searches       int        `json:"searches"`
+	Videos         int        `json:"videos"`
+	WatchedSeconds int        `json:"watched_seconds"`
+}
+
+// IntentOutcomeResponse totals the intents with one outcome in API responses
+type IntentOutcomeResponse struct {
+	Outcome               string `json:"outcome"` // found, not_found or unrated
+	Intents               int    `json:"intents"`
+	Videos                int    `json:"videos"`
+	WatchedSeconds        int    `json:"watched_seconds"`
+	AverageWatchedSeconds int    `json:"average_watched_seconds"`
+}
+
+// IntentReviewResponse is the intent review in API responses
+type IntentReviewResponse struct {
+	Intents  []SearchIntentResponse  `json:"intents"`
+	Outcomes []IntentOutcomeResponse `json:"outcomes"`
+}
+
+// Page renders the review of recent intents
+func (h *IntentsHandler) Page(c *gin.Context) {
+	review, err := h.intentsUC.Review(c.Request.Context(), 0)
+	if err != nil {
+		respondError(c, err, "Failed to review search intents")
+		return
+	}
+
+	respondComponent(c, pages.IntentsPage(review, h.intentsUC.Enabled()))
+}
+
+// Review returns recent intents with the time spent on them (?limit=, default 50)
+func (h *IntentsHandler) Review(c *gin.Context) {
+	var err error
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			respondAppError(c, appErrors.NewValidationError("limit must be a number", err))
+			return
+		}
+	}
+
+	review, err := h.intentsUC.Review(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err, "Failed to review search intents")
+		return
+	}
+
+	resp := IntentReviewResponse{
+		Intents:  make([]SearchIntentResponse, 0, len(review.Intents)),
+		Outcomes: make([]IntentOutcomeResponse, 0, len(review.Outcomes)),
+	}
+	for _, a := range review.Intents {
+		r := searchIntentResponse(a.SearchIntent)
+		r.Queries = a.Queries
+		r.Searches = a.Searches
+		r.Videos = a.Videos
+		r.WatchedSeconds = a.WatchedSeconds
+		resp.Intents = append(resp.Intents, r)
+	}
+	for _, o := range review.Outcomes {
+		outcome := o.Outcome
+		if outcome == "" {
+			outcome = "unrated"
+		}
+		resp.Outcomes = append(resp.Outcomes, IntentOutcomeResponse{
+			Outcome:               outcome,
+			Intents:               o.Intents,
+			Videos:                o.Videos,
+			WatchedSeconds:        o.WatchedSeconds,
+			AverageWatchedSeconds: o.AverageSeconds(),
+		})
+	}
+	respondSuccess(c, resp)
+}
+
+// Rate records whether an intent was found (form field outcome: found or not_found)
+func (h *IntentsHandler) Rate(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid search intent id", err))
+		return
+	}
+
+	intent, err := h.intentsUC.Rate(c.Request.Context(), id, c.PostForm("outcome"))
+	if err != nil {
+		respondError(c, err, "Failed to rate search intent")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.IntentOutcome(*intent))
+		return
+	}
+	respondSuccess(c, searchIntentResponse(*intent))
+}
+
+func searchIntentResponse(i entities.SearchIntent) SearchIntentResponse {
+	resp := SearchIntentResponse{
+		ID:        i.ID,
+		Intent:    i.Intent,
+		CreatedAt: i.CreatedAt,
+		Outcome:   i.Outcome,
+	}
+	if i.Rated() {
+		resp.RatedAt = &i.RatedAt
+	}
+	return resp
+}
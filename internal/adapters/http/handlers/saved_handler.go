@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// SavedHandler handles the watch-later page and API
+type SavedHandler struct {
+	savedUC *usecases.SavedVideos
+}
+
+// NewSavedHandler creates a new watch-later handler
+func NewSavedHandler(savedUC *usecases.SavedVideos) *SavedHandler {
+	return &SavedHandler{savedUC: savedUC}
+}
+
+// SavedVideoResponse represents a watch-later entry in API responses
+type SavedVideoResponse struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Channel     string     `json:"channel"`
+	Thumbnail   string     `json:"thumbnail"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Position    int        `json:"position"`
+	SavedAt     time.Time  `json:"saved_at"`
+	WatchedAt   *time.Time `json:"watched_at,omitempty"`
+}
+
+// Page renders the watch-later list
+func (h *SavedHandler) Page(c *gin.Context) {
+	videos, err := h.savedUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list saved videos")
+		return
+	}
+
+	respondComponent(c, pages.SavedPage(videos))
+}
+
+// List returns the watch-later list
+func (h *SavedHandler) List(c *gin.Context) {
+	h.respondList(c)
+}
+
+// Save adds a video to the list (form fields id, title, channel, thumbnail, published_at)
+// The form metadata is only stored for videos the catalog doesn't know yet
+// HTMX requests get the "saved" badge that replaces the button
+func (h *SavedHandler) Save(c *gin.Context) {
+	video := entities.Video{
+		ID:        c.PostForm("id"),
+		Title:     c.PostForm("title"),
+		Channel:   c.PostForm("channel"),
+		Thumbnail: c.PostForm("thumbnail"),
+	}
+	if v := c.PostForm("published_at"); v != "" {
+		published, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			respondAppError(c, appErrors.NewValidationError("published_at must be an RFC 3339 time", err))
+			return
+		}
+		video.PublishedAt = published
+	}
+
+	saved, err := h.savedUC.Save(c.Request.Context(), video)
+	if err != nil {
+		respondError(c, err, "Failed to save video")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.SavedBadge())
+		return
+	}
+
+	respondSuccess(c, savedVideoResponse(*saved))
+}
+
+// Delete removes a video from the list
+func (h *SavedHandler) Delete(c *gin.Context) {
+	if err := h.savedUC.Unsave(c.Request.Context(), c.Param("id")); err != nil {
+		respondError(c, err, "Failed to remove saved video")
+		return
+	}
+	h.respondList(c)
+}
+
+// SetWatched marks a saved video watched or not (form field watched)
+func (h *SavedHandler) SetWatched(c *gin.Context) {
+	watched, err := strconv.ParseBool(c.PostForm("watched"))
+	if err != nil {
+		respondAppError(c, appErrors.NewValidationError("watched must be true or false", err))
+		return
+	}
+
+	if err := h.savedUC.MarkWatched(c.Request.Context(), c.Param("id"), watched); err != nil {
+		respondError(c, err, "Failed to update saved video")
+		return
+	}
+	h.respondList(c)
+}
+
+// Move puts a saved video at a new position (form field position, 1-based)
+func (h *SavedHandler) Move(c *gin.Context) {
+	position, err := strconv.Atoi(c.PostForm("position"))
+	if err != nil {
+		respondAppError(c, appErrors.NewValidationError("position must be a number", err))
+		return
+	}
+
+	if err := h.savedUC.Move(c.Request.Context(), c.Param("id"), position); err != nil {
+		respondError(c, err, "Failed to move saved video")
+		return
+	}
+	h.respondList(c)
+}
+
+// respondList responds with the current list: the list fragment for HTMX, JSON otherwise
+func (h *SavedHandler) respondList(c *gin.Context) {
+	videos, err := h.savedUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list saved videos")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.SavedList(videos))
+		return
+	}
+
+	resp := make([]SavedVideoResponse, 0, len(videos))
+	for _, v := range videos {
+		resp = append(resp, savedVideoResponse(v))
+	}
+	respondSuccess(c, resp)
+}
+
+func savedVideoResponse(v entities.SavedVideo) SavedVideoResponse {
+	resp := SavedVideoResponse{
+		ID:        v.ID,
+		Title:     v.Title,
+		Channel:   v.Channel,
+		Thumbnail: v.Thumbnail,
+		Position:  v.Position,
+		SavedAt:   v.SavedAt,
+	}
+	if !v.PublishedAt.IsZero() {
+		resp.PublishedAt = &v.PublishedAt
+	}
+	if v.Watched() {
+		resp.WatchedAt = &v.WatchedAt
+	}
+	return resp
+}
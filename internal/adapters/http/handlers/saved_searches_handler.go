@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/components"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// SavedSearchesHandler handles the saved search pages and API
+type SavedSearchesHandler struct {
+	searchesUC *usecases.SavedSearches
+	feeds      bool
+}
+
+// NewSavedSearchesHandler creates a new saved searches handler
+// feeds shows links to each search's Atom and RSS feeds (syndication.enabled)
+func NewSavedSearchesHandler(searchesUC *usecases.SavedSearches, feeds bool) *SavedSearchesHandler {
+	return &SavedSearchesHandler{searchesUC: searchesUC, feeds: feeds}
+}
+
+// SavedSearchResponse represents a saved search in API responses
+type SavedSearchResponse struct {
+	ID              int64                       `json:"id"`
+	Name            string                      `json:"name"`
+	Query           string                      `json:"query"`
+	MaxResults      int64                       `json:"max_results"`
+	CreatedAt       time.Time                   `json:"created_at"`
+	LastCheckedAt   *time.Time                  `json:"last_checked_at,omitempty"`
+	LastRefreshedAt *time.Time                  `json:"last_refreshed_at,omitempty"`
+	LastError       string                      `json:"last_error,omitempty"`
+	ResultCount     int                         `json:"result_count"`
+	NewCount        int                         `json:"new_count"`
+	Results         []SavedSearchResultResponse `json:"results,omitempty"`
+}
+
+// SavedSearchResultResponse represents a video matched by a saved search in API responses
+type SavedSearchResultResponse struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Channel     string     `json:"channel"`
+	Thumbnail   string     `json:"thumbnail"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	FirstSeenAt time.Time  `json:"first_seen_at"`
+	LastSeenAt  time.Time  `json:"last_seen_at"`
+	New         bool       `json:"new"`
+}
+
+// Index renders the saved searches with their new-result counts
+func (h *SavedSearchesHandler) Index(c *gin.Context) {
+	searches, err := h.searchesUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list saved searches")
+		return
+	}
+
+	respondComponent(c, pages.SavedSearchesPage(searches))
+}
+
+// Page renders a saved search with its results, new ones highlighted
+func (h *SavedSearchesHandler) Page(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	search, results, err := h.searchesUC.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get saved search")
+		return
+	}
+
+	respondComponent(c, pages.SavedSearchPage(search, results, h.feeds))
+}
+
+// List returns every saved search
+func (h *SavedSearchesHandler) List(c *gin.Context) {
+	searches, err := h.searchesUC.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "Failed to list saved searches")
+		return
+	}
+
+	resp := make([]SavedSearchResponse, 0, len(searches))
+	for _, s := range searches {
+		resp = append(resp, savedSearchResponse(s, nil))
+	}
+	respondSuccess(c, resp)
+}
+
+// Create saves a search (form fields query, name and max_results, all but query optional)
+// and runs it once to record the baseline results
+func (h *SavedSearchesHandler) Create(c *gin.Context) {
+	var maxResults int64
+	if v := c.PostForm("max_results"); v != "" {
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			respondAppError(c, appErrors.NewValidationError("max_results must be a number", err))
+			return
+		}
+		maxResults = n
+	}
+
+	search, err := h.searchesUC.Create(c.Request.Context(), c.PostForm("name"), c.PostForm("query"), maxResults)
+	if err != nil {
+		redirectToBudget(c, err)
+		respondError(c, err, "Failed to save search")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", components.SavedSearchURL(search.ID))
+		c.Status(http.StatusCreated)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    savedSearchResponse(*search, nil),
+	})
+}
+
+// Get returns a saved search with its results
+func (h *SavedSearchesHandler) Get(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	h.respondSearch(c, id)
+}
+
+// Delete removes a saved search
+func (h *SavedSearchesHandler) Delete(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.searchesUC.Delete(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to delete saved search")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		c.Header("HX-Redirect", "/saved-searches")
+		c.Status(http.StatusOK)
+		return
+	}
+
+	respondSuccess(c, gin.H{"deleted": 1})
+}
+
+// Refresh runs a saved search now (one API call) instead of waiting for the scheduler
+// A failed run is recorded on the search and reported as an error
+func (h *SavedSearchesHandler) Refresh(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.searchesUC.RefreshByID(c.Request.Context(), id); err != nil {
+		redirectToBudget(c, err)
+		respondError(c, err, "Failed to refresh saved search")
+		return
+	}
+	h.respondSearch(c, id)
+}
+
+// MarkSeen clears the "new" highlight of a search's results
+func (h *SavedSearchesHandler) MarkSeen(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.searchesUC.MarkSeen(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to mark saved search seen")
+		return
+	}
+	h.respondSearch(c, id)
+}
+
+// respondSearch responds with a saved search and its results:
+// the results fragment for HTMX, JSON otherwise
+func (h *SavedSearchesHandler) respondSearch(c *gin.Context, id int64) {
+	search, results, err := h.searchesUC.Get(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to get saved search")
+		return
+	}
+
+	if middleware.IsHTMXRequest(c) {
+		respondComponent(c, components.SavedSearchDetail(search, results, h.feeds))
+		return
+	}
+
+	respondSuccess(c, savedSearchResponse(*search, results))
+}
+
+// savedSearchID reads the :id parameter, responding with a validation error if it's invalid
+func savedSearchID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid saved search id", err))
+		return 0, false
+	}
+	return id, true
+}
+
+func savedSearchResponse(s entities.SavedSearch, results []entities.SavedSearchResult) SavedSearchResponse {
+	resp := SavedSearchResponse{
+		ID:          s.ID,
+		Name:        s.Name,
+		Query:       s.Options.Query,
+		MaxResults:  s.Options.MaxResults,
+		CreatedAt:   s.CreatedAt,
+		LastError:   s.LastError,
+		ResultCount: s.ResultCount,
+		NewCount:    s.NewCount,
+	}
+	if !s.LastCheckedAt.IsZero() {
+		resp.LastCheckedAt = &s.LastCheckedAt
+	}
+	if !s.LastRefreshedAt.IsZero() {
+		resp.LastRefreshedAt = &s.LastRefreshedAt
+	}
+	for _, r := range results {
+		res := SavedSearchResultResponse{
+			ID:          r.ID,
+			Title:       r.Title,
+			Channel:     r.Channel,
+			Thumbnail:   r.Thumbnail,
+			FirstSeenAt: r.FirstSeenAt,
+			LastSeenAt:  r.LastSeenAt,
+			New:         r.New,
+		}
+		if !r.PublishedAt.IsZero() {
+			res.PublishedAt = &r.PublishedAt
+		}
+		resp.Results = append(resp.Results, res)
+	}
+	return resp
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+)
+
+// syndicationContentTypes maps feed formats to their MIME types
+var syndicationContentTypes = map[usecases.SyndicationFormat]string{
+	usecases.SyndicationAtom: "application/atom+xml; charset=utf-8",
+	usecases.SyndicationRSS:  "application/rss+xml; charset=utf-8",
+}
+
+// SyndicationHandler serves saved searches and collections as Atom and RSS feeds
+type SyndicationHandler struct {
+	syndicationUC *usecases.Syndication
+	baseURL       string
+}
+
+// NewSyndicationHandler creates a new syndication handler
+// baseURL is the public origin used in feed links (app.base_url, required for feeds)
+// It is never taken from the request, whose Host and X-Forwarded-Proto any client can set
+func NewSyndicationHandler(syndicationUC *usecases.Syndication, baseURL string) *SyndicationHandler {
+	return &SyndicationHandler{syndicationUC: syndicationUC, baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// SavedSearch serves a saved search's results (:file is <id>.atom or <id>.rss)
+func (h *SyndicationHandler) SavedSearch(c *gin.Context) {
+	id, format, ok := feedFile(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.syndicationUC.SavedSearchFeed(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to build saved search feed")
+		return
+	}
+	h.respondFeed(c, feed, format)
+}
+
+// Collection serves a collection's videos (:file is <id>.atom or <id>.rss)
+func (h *SyndicationHandler) Collection(c *gin.Context) {
+	id, format, ok := feedFile(c)
+	if !ok {
+		return
+	}
+
+	feed, err := h.syndicationUC.CollectionFeed(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err, "Failed to build collection feed")
+		return
+	}
+	h.respondFeed(c, feed, format)
+}
+
+// respondFeed renders a feed with its validators, answering 304 Not Modified
+// when the reader already has the current version
+func (h *SyndicationHandler) respondFeed(c *gin.Context, feed *usecases.SyndicatedFeed, format usecases.SyndicationFormat) {
+	doc, err := h.syndicationUC.Render(feed, format, h.baseURL, c.Request.URL.Path)
+	if err != nil {
+		respondError(c, err, "Failed to render feed")
+		return
+	}
+
+	c.Header("ETag", doc.ETag)
+	c.Header("Last-Modified", doc.Updated.Format(http.TimeFormat))
+	c.Header("Cache-Control", "no-cache")
+
+	if etagMatches(c.GetHeader("If-None-Match"), doc.ETag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.Data(http.StatusOK, syndicationContentTypes[doc.Format], doc.Data)
+}
+
+// feedFile reads the :file parameter (<id>.<format>), responding with a validation error if it's invalid
+func feedFile(c *gin.Context) (int64, usecases.SyndicationFormat, bool) {
+	name, ext, found := strings.Cut(c.Param("file"), ".")
+	if !found {
+		respondAppError(c, appErrors.NewValidationError("feed URL must end in .atom or .rss", nil))
+		return 0, "", false
+	}
+
+	format, err := usecases.ParseSyndicationFormat(ext)
+	if err != nil {
+		respondError(c, err, "Invalid feed format")
+		return 0, "", false
+	}
+
+	id, err := strconv.ParseInt(name, 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid feed id", err))
+		return 0, "", false
+	}
+	return id, format, true
+}
+
+// etagMatches reports whether an If-None-Match header lists etag (or is *)
+// Weak comparison, as RFC 9110 requires for If-None-Match
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
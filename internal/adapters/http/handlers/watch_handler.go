@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+	"github.com/uiansol/zentube/web/templates/pages"
+)
+
+// Player events accepted by POST /events/watch
+const (
+	watchEventOpen     = "open"
+	watchEventProgress = "progress"
+	watchEventClose    = "close"
+)
+
+// WatchHandler handles player events and the watched history page
+type WatchHandler struct {
+	watchUC *usecases.WatchHistory
+}
+
+// NewWatchHandler creates a new watch handler
+func NewWatchHandler(watchUC *usecases.WatchHistory) *WatchHandler {
+	return &WatchHandler{watchUC: watchUC}
+}
+
+// WatchEventResponse represents a viewing in API responses
+type WatchEventResponse struct {
+	ID             int64      `json:"id"`
+	VideoID        string     `json:"video_id"`
+	Title          string     `json:"title"`
+	StartedAt      time.Time  `json:"started_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	ClosedAt       *time.Time `json:"closed_at,omitempty"`
+	WatchedSeconds int        `json:"watched_seconds"`
+	SearchID       int64      `json:"search_id,omitempty"`
+	SearchQuery    string     `json:"search_query,omitempty"`
+}
+
+// WatchOpenResponse is the response to an open event
+type WatchOpenResponse struct {
+	WatchEventResponse
+	ResumeAt int `json:"resume_at"` // Seconds into the video to start playback at
+}
+
+// Page renders the most recent viewings
+func (h *WatchHandler) Page(c *gin.Context) {
+	events, err := h.watchUC.List(c.Request.Context(), 0)
+	if err != nil {
+		respondError(c, err, "Failed to list watched videos")
+		return
+	}
+
+	respondComponent(c, pages.WatchedPage(events))
+}
+
+// List returns the most recent viewings (?limit=, default 50)
+func (h *WatchHandler) List(c *gin.Context) {
+	var err error
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			respondAppError(c, appErrors.NewValidationError("limit must be a number", err))
+			return
+		}
+	}
+
+	events, err := h.watchUC.List(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err, "Failed to list watched videos")
+		return
+	}
+
+	resp := make([]WatchEventResponse, 0, len(events))
+	for _, e := range events {
+		resp = append(resp, watchEventResponse(e))
+	}
+	respondSuccess(c, resp)
+}
+
+// Event records a player event (form fields event, video_id and title on open;
+// event, id, watched_seconds and optionally position and duration on progress and close)
+// Open responds with the new viewing, whose id the player sends back afterwards,
+// and the position to resume the video at
+func (h *WatchHandler) Event(c *gin.Context) {
+	event := c.PostForm("event")
+	if event == watchEventOpen {
+		h.open(c)
+		return
+	}
+	if event != watchEventProgress && event != watchEventClose {
+		respondAppError(c, appErrors.NewValidationError("event must be open, progress or close", nil))
+		return
+	}
+
+	id, err := strconv.ParseInt(c.PostForm("id"), 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid watch event id", err))
+		return
+	}
+	report, appErr := parsePlaybackReport(c)
+	if appErr != nil {
+		respondAppError(c, appErr)
+		return
+	}
+
+	if event == watchEventClose {
+		err = h.watchUC.Close(c.Request.Context(), id, report)
+	} else {
+		err = h.watchUC.Progress(c.Request.Context(), id, report)
+	}
+	if err != nil {
+		respondError(c, err, "Failed to record watch event")
+		return
+	}
+
+	respondSuccess(c, gin.H{"id": id})
+}
+
+// open starts a viewing and looks up where the video last stopped
+func (h *WatchHandler) open(c *gin.Context) {
+	opened, err := h.watchUC.Open(c.Request.Context(), c.PostForm("video_id"), c.PostForm("title"))
+	if err != nil {
+		respondError(c, err, "Failed to record watch event")
+		return
+	}
+
+	progress, err := h.watchUC.Positions(c.Request.Context(), []string{opened.VideoID})
+	if err != nil {
+		respondError(c, err, "Failed to get playback position")
+		return
+	}
+
+	respondSuccess(c, WatchOpenResponse{
+		WatchEventResponse: watchEventResponse(*opened),
+		ResumeAt:           progress[opened.VideoID].ResumeAt(),
+	})
+}
+
+// parsePlaybackReport reads watched_seconds and the optional position and duration
+// (whole seconds; players report fractions, which are truncated)
+func parsePlaybackReport(c *gin.Context) (usecases.PlaybackReport, *appErrors.AppError) {
+	var report usecases.PlaybackReport
+	var err error
+
+	if report.WatchedSeconds, err = strconv.Atoi(c.PostForm("watched_seconds")); err != nil {
+		return report, appErrors.NewValidationError("watched_seconds must be a number", err)
+	}
+	if v := c.PostForm("duration"); v != "" {
+		duration, err := parseSeconds(v)
+		if err != nil {
+			return report, appErrors.NewValidationError("duration must be a number", err)
+		}
+		position, err := parseSeconds(c.PostForm("position"))
+		if err != nil {
+			return report, appErrors.NewValidationError("position must be a number", err)
+		}
+		report.Duration = duration
+		report.Position = min(position, duration)
+	}
+	return report, nil
+}
+
+// parseSeconds parses a finite number of seconds, dropping the fraction
+func parseSeconds(v string) (int, error) {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) || math.Abs(f) > math.MaxInt32 {
+		return 0, fmt.Errorf("%q is out of range", v)
+	}
+	return int(f), nil
+}
+
+func watchEventResponse(e entities.WatchEvent) WatchEventResponse {
+	resp := WatchEventResponse{
+		ID:             e.ID,
+		VideoID:        e.VideoID,
+		Title:          e.Title,
+		StartedAt:      e.StartedAt,
+		UpdatedAt:      e.UpdatedAt,
+		WatchedSeconds: e.WatchedSeconds,
+		SearchID:       e.SearchID,
+		SearchQuery:    e.SearchQuery,
+	}
+	if e.Closed() {
+		resp.ClosedAt = &e.ClosedAt
+	}
+	return resp
+}
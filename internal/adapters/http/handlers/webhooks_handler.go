@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+)
+
+// WebhooksHandler handles the webhook delivery log endpoints
+// Routes are expected to be protected by middleware.AdminAuth
+type WebhooksHandler struct {
+	webhooksUC *usecases.Webhooks
+}
+
+// NewWebhooksHandler creates a new webhooks handler
+func NewWebhooksHandler(webhooksUC *usecases.Webhooks) *WebhooksHandler {
+	return &WebhooksHandler{webhooksUC: webhooksUC}
+}
+
+// WebhookDeliveryResponse represents a webhook delivery in API responses
+type WebhookDeliveryResponse struct {
+	ID            int64           `json:"id"`
+	Endpoint      string          `json:"endpoint"`
+	Event         string          `json:"event"`
+	Status        string          `json:"status"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt *time.Time      `json:"next_attempt_at,omitempty"`
+	LastAttemptAt *time.Time      `json:"last_attempt_at,omitempty"`
+	LastError     string          `json:"last_error,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// Deliveries returns the delivery log, most recent first (?limit=, default 50)
+func (h *WebhooksHandler) Deliveries(c *gin.Context) {
+	var err error
+
+	limit := 0
+	if v := c.Query("limit"); v != "" {
+		if limit, err = strconv.Atoi(v); err != nil {
+			respondAppError(c, appErrors.NewValidationError("limit must be a number", err))
+			return
+		}
+	}
+
+	deliveries, err := h.webhooksUC.Deliveries(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err, "Failed to list webhook deliveries")
+		return
+	}
+
+	resp := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		resp = append(resp, webhookDeliveryResponse(d))
+	}
+	respondSuccess(c, resp)
+}
+
+// Retry queues a delivery again with a fresh set of attempts
+func (h *WebhooksHandler) Retry(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		respondAppError(c, appErrors.NewValidationError("invalid webhook delivery id", err))
+		return
+	}
+
+	if err := h.webhooksUC.Retry(c.Request.Context(), id); err != nil {
+		respondError(c, err, "Failed to retry webhook delivery")
+		return
+	}
+
+	respondSuccess(c, gin.H{"queued": 1})
+}
+
+func webhookDeliveryResponse(d entities.WebhookDelivery) WebhookDeliveryResponse {
+	resp := WebhookDeliveryResponse{
+		ID:        d.ID,
+		Endpoint:  d.Endpoint,
+		Event:     d.Event,
+		Status:    string(d.Status),
+		Attempts:  d.Attempts,
+		LastError: d.LastError,
+		CreatedAt: d.CreatedAt,
+		Payload:   json.RawMessage(d.Payload),
+	}
+	if d.Status == entities.WebhookPending {
+		resp.NextAttemptAt = &d.NextAttemptAt
+	}
+	if !d.LastAttemptAt.IsZero() {
+		resp.LastAttemptAt = &d.LastAttemptAt
+	}
+	return resp
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"log/slog"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
 	appErrors "github.com/uiansol/zentube/internal/errors"
 	"github.com/uiansol/zentube/internal/usecases"
 	"github.com/uiansol/zentube/internal/validation"
@@ -12,15 +16,22 @@ import (
 
 type YouTubeHandler struct {
 	searchUC   *usecases.SearchVideos
+	watchUC    *usecases.WatchHistory
+	budget     *BudgetHandler
+	intentsUC  *usecases.SearchIntents
 	maxResults int64
 }
 
-func NewYouTubeHandler(searchUC *usecases.SearchVideos, maxResults int64) *YouTubeHandler {
-	return &YouTubeHandler{searchUC: searchUC, maxResults: maxResults}
+// NewYouTubeHandler creates the search handler
+// watchUC supplies playback positions for the progress bars on results,
+// budget renders its page for full-page searches refused by the viewing budget,
+// and intentsUC tells whether the search form asks for an intent
+func NewYouTubeHandler(searchUC *usecases.SearchVideos, watchUC *usecases.WatchHistory, budget *BudgetHandler, intentsUC *usecases.SearchIntents, maxResults int64) *YouTubeHandler {
+	return &YouTubeHandler{searchUC: searchUC, watchUC: watchUC, budget: budget, intentsUC: intentsUC, maxResults: maxResults}
 }
 
 func (h *YouTubeHandler) Home(c *gin.Context) {
-	if err := pages.HomePage("", nil).Render(c.Request.Context(), c.Writer); err != nil {
+	if err := pages.HomePage("", nil, h.intentsUC.Enabled(), nil, nil).Render(c.Request.Context(), c.Writer); err != nil {
 		respondError(c, appErrors.NewInternalError("Failed to render page", err), "Failed to render page")
 		return
 	}
@@ -37,22 +48,59 @@ func (h *YouTubeHandler) Search(c *gin.Context) {
 		return
 	}
 
-	// Execute search with validated input
-	videos, err := h.searchUC.Execute(c.Request.Context(), input.Query, input.MaxResults)
+	// Execute search with validated input and the optional intent (form field intent),
+	// which is only stored once the search succeeds
+	videos, intent, err := h.searchUC.ExecuteWithIntent(c.Request.Context(), input.Query, input.MaxResults, c.PostForm("intent"))
 	if err != nil {
-		respondError(c, appErrors.NewInternalError("Failed to search videos", err), "Failed to search videos")
+		// Classified upstream errors (bad request, quota) keep their status code
+		if !appErrors.IsAppError(err) {
+			err = appErrors.NewInternalError("Failed to search videos", err)
+		}
+		h.respondSearchError(c, err, "Failed to search videos")
 		return
 	}
 
+	progress := h.positions(c, videos)
+
 	// Check if it's an HTMX request - return only results fragment
 	if middleware.IsHTMXRequest(c) {
-		if err := components.SearchResults(videos).Render(c.Request.Context(), c.Writer); err != nil {
+		if err := components.SearchResults(videos, progress, intent).Render(c.Request.Context(), c.Writer); err != nil {
 			respondError(c, appErrors.NewInternalError("Failed to render search results", err), "Failed to render search results")
 		}
 	} else {
 		// Regular request - return full page
-		if err := pages.HomePage(input.Query, videos).Render(c.Request.Context(), c.Writer); err != nil {
+		if err := pages.HomePage(input.Query, intent, h.intentsUC.Enabled(), videos, progress).Render(c.Request.Context(), c.Writer); err != nil {
 			respondError(c, appErrors.NewInternalError("Failed to render page", err), "Failed to render page")
 		}
 	}
 }
+
+// respondSearchError answers a failed search
+// Full-page searches refused by the viewing budget get the budget page with 429
+func (h *YouTubeHandler) respondSearchError(c *gin.Context, err error, message string) {
+	if appErrors.GetErrorCode(err) == appErrors.ErrCodeBudgetExhausted && !middleware.IsHTMXRequest(c) {
+		h.budget.renderPage(c, http.StatusTooManyRequests)
+		return
+	}
+	redirectToBudget(c, err)
+	respondError(c, err, message)
+}
+
+// positions returns where playback of each result last stopped
+// Results are still worth showing without them, so a failed lookup is only logged
+func (h *YouTubeHandler) positions(c *gin.Context, videos []entities.Video) map[string]entities.VideoProgress {
+	ids := make([]string, 0, len(videos))
+	for _, v := range videos {
+		ids = append(ids, v.ID)
+	}
+
+	progress, err := h.watchUC.Positions(c.Request.Context(), ids)
+	if err != nil {
+		slog.Warn("failed to get playback positions",
+			slog.String("path", c.Request.URL.Path),
+			slog.Any("error", err),
+		)
+		return nil
+	}
+	return progress
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/adapters/database"
+	"github.com/uiansol/zentube/internal/adapters/http/middleware"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/usecases"
+)
+
+// stubYouTubeClient answers every search with the same videos or error
+type stubYouTubeClient struct {
+	videos []entities.Video
+	err    error
+}
+
+func (s stubYouTubeClient) Search(query string, maxResults int64) ([]entities.Video, error) {
+	return s.videos, s.err
+}
+
+// stubBudget refuses or allows every search
+type stubBudget struct {
+	err error
+}
+
+func (s stubBudget) CheckSearch(ctx context.Context) error {
+	return s.err
+}
+
+// newSearchTestRouter wires POST /search against a fresh SQLite store with intents enabled
+func newSearchTestRouter(t *testing.T, client stubYouTubeClient, budget stubBudget) (*gin.Engine, *database.SQLiteRepository) {
+	t.Helper()
+
+	repo, err := database.NewSQLiteRepository(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { repo.Close() })
+
+	intents := usecases.NewSearchIntents(repo, true)
+	search := usecases.NewSearchVideos(client, repo).WithBudget(budget).WithIntents(intents)
+	handler := NewYouTubeHandler(search, usecases.NewWatchHistory(repo, repo), nil, intents, 10)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.HTMX())
+	r.POST("/search", handler.Search)
+	return r, repo
+}
+
+// postSearch sends an HTMX search with an intent
+func postSearch(r *gin.Engine) *httptest.ResponseRecorder {
+	form := url.Values{"q": {"pgx pool"}, "intent": {"learn pgx pooling"}}
+	req := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestYouTubeHandler_Search_RefusedSearchStoresNoIntent(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   stubYouTubeClient
+		budget   stubBudget
+		status   int
+		redirect string
+	}{
+		{
+			name:     "budget exhausted",
+			budget:   stubBudget{err: appErrors.NewBudgetExhaustedError("Daily viewing budget used up")},
+			status:   http.StatusTooManyRequests,
+			redirect: "/budget",
+		},
+		{
+			name:   "youtube unavailable",
+			client: stubYouTubeClient{err: appErrors.NewServiceUnavailableError("YouTube", nil)},
+			status: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			r, repo := newSearchTestRouter(t, tt.client, tt.budget)
+
+			// Act
+			w := postSearch(r)
+
+			// Assert
+			assert.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.redirect, w.Header().Get("HX-Redirect"))
+			stored, err := repo.ListSearchIntents(context.Background(), 10)
+			require.NoError(t, err)
+			assert.Empty(t, stored)
+		})
+	}
+}
+
+func TestYouTubeHandler_Search_StoresIntentOnSuccess(t *testing.T) {
+	// Arrange
+	r, repo := newSearchTestRouter(t, stubYouTubeClient{videos: []entities.Video{{ID: "dQw4w9WgXcQ", Title: "pgx pools"}}}, stubBudget{})
+
+	// Act
+	w := postSearch(r)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	stored, err := repo.ListSearchIntents(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "learn pgx pooling", stored[0].Intent)
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAuth protects admin routes with a shared token
+// Accepts either:
+// - "Authorization: Bearer <token>" (API clients, curl)
+// - HTTP Basic auth with any username and the token as password (browsers)
+func AdminAuth(token string) gin.HandlerFunc {
+	expected := []byte(token)
+
+	return func(c *gin.Context) {
+		if provided, ok := adminCredential(c); ok && tokensEqual(provided, expected) {
+			c.Next()
+			return
+		}
+
+		// Ask browsers to prompt for credentials
+		c.Header("WWW-Authenticate", `Basic realm="zentube admin"`)
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error":   "unauthorized",
+			"message": "Admin credentials required.",
+		})
+	}
+}
+
+// adminCredential extracts the token from a Bearer or Basic Authorization header
+func adminCredential(c *gin.Context) (string, bool) {
+	if _, password, ok := c.Request.BasicAuth(); ok {
+		return password, true
+	}
+
+	header := c.GetHeader("Authorization")
+	if token, ok := strings.CutPrefix(header, "Bearer "); ok {
+		return token, true
+	}
+
+	return "", false
+}
+
+// tokensEqual compares a provided token with the expected one in constant time
+// An empty expected token never matches, so a missing setting can't open the routes
+func tokensEqual(provided string, expected []byte) bool {
+	if len(expected) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(provided), expected) == 1
+}
@@ -0,0 +1,115 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// newAdminRouter returns a router with one AdminAuth-protected route
+func newAdminRouter(token string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/admin", AdminAuth(token), func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func TestAdminAuth(t *testing.T) {
+	const token = "s3cret-admin-token"
+
+	tests := []struct {
+		name      string
+		authorize func(req *http.Request)
+		wantCode  int
+	}{
+		{
+			name:      "missing credentials",
+			authorize: func(req *http.Request) {},
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "valid bearer token",
+			authorize: func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "valid basic auth password",
+			authorize: func(req *http.Request) { req.SetBasicAuth("anyone", token) },
+			wantCode:  http.StatusOK,
+		},
+		{
+			name:      "wrong token of the same length",
+			authorize: func(req *http.Request) { req.Header.Set("Authorization", "Bearer s3cret-admin-tokeN") },
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "prefix of the token",
+			authorize: func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token[:6]) },
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "token with a suffix",
+			authorize: func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token+"x") },
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "token as basic auth username",
+			authorize: func(req *http.Request) { req.SetBasicAuth(token, "") },
+			wantCode:  http.StatusUnauthorized,
+		},
+		{
+			name:      "unknown scheme",
+			authorize: func(req *http.Request) { req.Header.Set("Authorization", "Token "+token) },
+			wantCode:  http.StatusUnauthorized,
+		},
+	}
+
+	r := newAdminRouter(token)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			tt.authorize(req)
+			w := httptest.NewRecorder()
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="zentube admin"`, w.Header().Get("WWW-Authenticate"))
+				assert.NotContains(t, w.Body.String(), "ok")
+			}
+		})
+	}
+}
+
+func TestAdminAuth_EmptyTokenRejectsEverything(t *testing.T) {
+	// Arrange
+	r := newAdminRouter("")
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	w := httptest.NewRecorder()
+
+	// Act
+	r.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTokensEqual_ComparesWholeToken(t *testing.T) {
+	expected := []byte("abcdef")
+
+	assert.True(t, tokensEqual("abcdef", expected))
+	assert.False(t, tokensEqual("abcdeX", expected))
+	assert.False(t, tokensEqual("abc", expected))
+	assert.False(t, tokensEqual("abcdefg", expected))
+	assert.False(t, tokensEqual("", []byte{}))
+}
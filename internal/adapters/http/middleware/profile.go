@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+const (
+	// ProfileHeader picks the viewing profile for one request (API clients, curl)
+	ProfileHeader = "X-Zentube-Profile"
+	// ProfileCookie remembers the viewing profile chosen in a browser
+	ProfileCookie = "zentube_profile"
+)
+
+// Profile puts the viewing profile into the request context for budgets,
+// focus sessions and watch history (see entities.ProfileFromContext)
+// The header wins over the cookie; without either the default profile is used
+// An unknown header is rejected, while an unknown cookie (e.g. a profile removed
+// from the config) falls back to the default profile
+func Profile(known func(name string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profile := entities.DefaultProfile
+
+		if header := c.GetHeader(ProfileHeader); header != "" {
+			if !known(header) {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+					"error":   "unknown_profile",
+					"message": "Unknown viewing profile.",
+				})
+				return
+			}
+			profile = header
+		} else if cookie, err := c.Cookie(ProfileCookie); err == nil && known(cookie) {
+			profile = cookie
+		}
+
+		c.Request = c.Request.WithContext(entities.WithProfile(c.Request.Context(), profile))
+		c.Next()
+	}
+}
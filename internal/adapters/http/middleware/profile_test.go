@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// newProfileRouter returns a router that echoes the profile Profile puts in the context
+func newProfileRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	known := func(name string) bool { return name == entities.DefaultProfile || name == "kids" }
+	r.GET("/", Profile(known), func(c *gin.Context) {
+		c.String(http.StatusOK, entities.ProfileFromContext(c.Request.Context()))
+	})
+	return r
+}
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		header      string
+		cookie      string
+		wantCode    int
+		wantProfile string
+	}{
+		{name: "nothing set", wantCode: http.StatusOK, wantProfile: entities.DefaultProfile},
+		{name: "cookie", cookie: "kids", wantCode: http.StatusOK, wantProfile: "kids"},
+		{name: "header", header: "kids", wantCode: http.StatusOK, wantProfile: "kids"},
+		{name: "header wins over cookie", header: "default", cookie: "kids", wantCode: http.StatusOK, wantProfile: entities.DefaultProfile},
+		{name: "unknown cookie", cookie: "removed", wantCode: http.StatusOK, wantProfile: entities.DefaultProfile},
+		{name: "unknown header", header: "removed", wantCode: http.StatusBadRequest},
+	}
+
+	r := newProfileRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(ProfileHeader, tt.header)
+			}
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: ProfileCookie, Value: tt.cookie})
+			}
+			w := httptest.NewRecorder()
+
+			// Act
+			r.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusOK {
+				assert.Equal(t, tt.wantProfile, w.Body.String())
+			}
+		})
+	}
+}
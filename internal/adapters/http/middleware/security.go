@@ -15,8 +15,8 @@ func SecurityHeaders() gin.HandlerFunc {
 		c.Header("X-XSS-Protection", "1; mode=block")
 
 		// Content Security Policy - restrict resource loading
-		// Adjust based on your needs (e.g., allow YouTube embeds)
-		c.Header("Content-Security-Policy", "default-src 'self'; img-src 'self' https://i.ytimg.com; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'")
+		// Frames are limited to the YouTube embed used by the video player
+		c.Header("Content-Security-Policy", "default-src 'self'; img-src 'self' https://i.ytimg.com; frame-src https://www.youtube.com; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'")
 
 		// Referrer Policy - control referrer information
 		c.Header("Referrer-Policy", "strict-origin-when-cross-origin")
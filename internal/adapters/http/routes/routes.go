@@ -21,3 +21,122 @@ func RegisterRoutes(r *gin.Engine, h *handlers.YouTubeHandler, health *handlers.
 	r.GET("/", h.Home)
 	r.POST("/search", h.Search)
 }
+
+// RegisterHistoryRoutes registers search history pages and the history API
+func RegisterHistoryRoutes(r *gin.Engine, history *handlers.HistoryHandler) {
+	r.GET("/history", history.Page)
+	r.GET("/history/search", history.Search)
+	r.GET("/history/entries", history.List)
+	r.DELETE("/history/entries", history.DeleteAll)
+	r.DELETE("/history/entries/:id", history.Delete)
+}
+
+// RegisterSavedRoutes registers the watch-later page and API
+func RegisterSavedRoutes(r *gin.Engine, saved *handlers.SavedHandler) {
+	r.GET("/saved", saved.Page)
+	r.GET("/saved/videos", saved.List)
+	r.POST("/saved/videos", saved.Save)
+	r.DELETE("/saved/videos/:id", saved.Delete)
+	r.PUT("/saved/videos/:id/watched", saved.SetWatched)
+	r.PUT("/saved/videos/:id/position", saved.Move)
+}
+
+// RegisterWatchRoutes registers the player event endpoint and the watched history page
+func RegisterWatchRoutes(r *gin.Engine, watch *handlers.WatchHandler) {
+	r.POST("/events/watch", watch.Event)
+	r.GET("/watched", watch.Page)
+	r.GET("/watched/events", watch.List)
+}
+
+// RegisterIntentRoutes registers the intent review page and API and "did you find it?" ratings
+func RegisterIntentRoutes(r *gin.Engine, intents *handlers.IntentsHandler) {
+	r.GET("/intents", intents.Page)
+	r.GET("/intents/review", intents.Review)
+	r.POST("/intents/:id/rating", intents.Rate)
+}
+
+// RegisterBudgetRoutes registers the viewing budget page, the layout budget bar, profile switching and focus sessions
+func RegisterBudgetRoutes(r *gin.Engine, budget *handlers.BudgetHandler) {
+	r.GET("/budget", budget.Page)
+	r.GET("/budget/bar", budget.Bar)
+	r.POST("/profile", budget.SwitchProfile)
+	r.POST("/focus/sessions", budget.StartFocus)
+	r.POST("/focus/sessions/:id/end", budget.EndFocus)
+}
+
+// RegisterFeedRoutes registers the subscriptions feed page and API
+func RegisterFeedRoutes(r *gin.Engine, feed *handlers.FeedHandler) {
+	r.GET("/feed", feed.Page)
+	r.GET("/feed/items", feed.List)
+	r.PUT("/feed/items/:id/seen", feed.SetSeen)
+	r.POST("/feed/seen", feed.MarkAllSeen)
+	r.POST("/feed/refresh", feed.Refresh)
+	r.GET("/feed/subscriptions", feed.ListSubscriptions)
+	r.POST("/feed/subscriptions", feed.Subscribe)
+	r.DELETE("/feed/subscriptions/:channelID", feed.Unsubscribe)
+}
+
+// RegisterSavedSearchRoutes registers the saved search pages and API
+func RegisterSavedSearchRoutes(r *gin.Engine, searches *handlers.SavedSearchesHandler) {
+	r.GET("/saved-searches", searches.Index)
+	r.GET("/saved-searches/:id", searches.Page)
+	r.GET("/saved-searches/entries", searches.List)
+	r.POST("/saved-searches/entries", searches.Create)
+	r.GET("/saved-searches/entries/:id", searches.Get)
+	r.DELETE("/saved-searches/entries/:id", searches.Delete)
+	r.POST("/saved-searches/entries/:id/refresh", searches.Refresh)
+	r.POST("/saved-searches/entries/:id/seen", searches.MarkSeen)
+}
+
+// RegisterSyndicationRoutes registers the Atom and RSS feeds of saved searches and collections
+// :file is <id>.atom or <id>.rss
+func RegisterSyndicationRoutes(r *gin.Engine, syndication *handlers.SyndicationHandler) {
+	r.GET("/feeds/search/:file", syndication.SavedSearch)
+	r.GET("/feeds/collection/:file", syndication.Collection)
+}
+
+// RegisterInsightsRoutes registers the search analytics page and API
+func RegisterInsightsRoutes(r *gin.Engine, insights *handlers.InsightsHandler) {
+	r.GET("/insights", insights.Page)
+	r.GET("/insights/stats", insights.Stats)
+}
+
+// RegisterAdminRoutes registers token-protected admin endpoints
+// Endpoints return JSON, or HTML fragments for HTMX requests
+func RegisterAdminRoutes(r *gin.Engine, admin *handlers.AdminHandler, token string) {
+	group := r.Group("/admin", middleware.AdminAuth(token))
+
+	group.GET("/cache", admin.CachePage)
+	group.GET("/cache/stats", admin.CacheStats)
+	group.GET("/cache/entries", admin.CacheEntries)
+	group.DELETE("/cache/entries", admin.DeleteCacheByQuery)
+	group.DELETE("/cache/entries/:key", admin.DeleteCacheEntry)
+	group.DELETE("/cache/namespaces/:namespace", admin.ClearCacheNamespace)
+
+	group.GET("/history-writer/stats", admin.HistoryWriterStats)
+}
+
+// RegisterAdminWebhookRoutes registers the token-protected webhook delivery log
+func RegisterAdminWebhookRoutes(r *gin.Engine, webhooks *handlers.WebhooksHandler, token string) {
+	group := r.Group("/admin/webhooks", middleware.AdminAuth(token))
+
+	group.GET("/deliveries", webhooks.Deliveries)
+	group.POST("/deliveries/:id/retry", webhooks.Retry)
+}
+
+// RegisterCollectionRoutes registers the collection pages and API
+func RegisterCollectionRoutes(r *gin.Engine, collections *handlers.CollectionsHandler) {
+	r.GET("/collections", collections.Index)
+	r.GET("/collections/:id", collections.Page)
+	r.POST("/collections/import", collections.Import)
+	r.GET("/collections/entries", collections.List)
+	r.POST("/collections/entries", collections.Create)
+	r.GET("/collections/entries/:id", collections.Get)
+	r.PUT("/collections/entries/:id", collections.Update)
+	r.DELETE("/collections/entries/:id", collections.Delete)
+	r.GET("/collections/entries/:id/export", collections.Export)
+	r.POST("/collections/entries/:id/items", collections.AddItem)
+	r.PUT("/collections/entries/:id/items/:videoID/note", collections.SetNote)
+	r.PUT("/collections/entries/:id/items/:videoID/position", collections.MoveItem)
+	r.DELETE("/collections/entries/:id/items/:videoID", collections.RemoveItem)
+}
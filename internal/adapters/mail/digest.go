@@ -0,0 +1,103 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/web/templates/email"
+)
+
+// DigestMailer formats digests as email and hands them to a transport
+// It implements ports.DigestSender
+type DigestMailer struct {
+	transport Transport
+	from      string
+	to        []string
+	appName   string
+	baseURL   string
+}
+
+// NewDigestMailer creates a mailer that sends digests from one address to the recipients
+// baseURL is the app's public origin, used for links back to the app; when
+// empty, the email carries no such links
+func NewDigestMailer(transport Transport, from string, to []string, appName, baseURL string) *DigestMailer {
+	return &DigestMailer{
+		transport: transport,
+		from:      from,
+		to:        to,
+		appName:   appName,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// SendDigest renders a digest (HTML and plaintext) and delivers it
+func (m *DigestMailer) SendDigest(ctx context.Context, digest entities.Digest) error {
+	var html bytes.Buffer
+	if err := email.Digest(digest, m.appName, m.baseURL).Render(ctx, &html); err != nil {
+		return fmt.Errorf("failed to render digest: %w", err)
+	}
+
+	msg, err := Message{
+		From:    m.from,
+		To:      m.to,
+		Subject: email.Subject(digest, m.appName),
+		Text:    m.digestText(digest),
+		HTML:    html.String(),
+		Date:    time.Now(),
+	}.Bytes()
+	if err != nil {
+		return err
+	}
+	return m.transport.Deliver(ctx, m.from, m.to, msg)
+}
+
+// digestText renders the plaintext part of a digest
+func (m *DigestMailer) digestText(d entities.Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s digest\n%s\n", m.appName, email.Period(d))
+	if d.VideoCount() == 0 {
+		b.WriteString("\nNothing new this time.\n")
+	}
+
+	if len(d.Channels) > 0 {
+		b.WriteString("\n== From your subscriptions ==\n")
+		if m.baseURL != "" {
+			b.WriteString(m.baseURL + "/feed\n")
+		}
+		for _, c := range d.Channels {
+			b.WriteString("\n" + c.Title + "\n")
+			writeTextVideos(&b, c.Videos, c.More, false)
+		}
+	}
+
+	if len(d.Searches) > 0 {
+		b.WriteString("\n== New saved search matches ==\n")
+		for _, s := range d.Searches {
+			b.WriteString("\n" + s.Name + "\n")
+			if m.baseURL != "" {
+				b.WriteString(m.baseURL + "/saved-searches/" + strconv.FormatInt(s.ID, 10) + "\n")
+			}
+			writeTextVideos(&b, s.Videos, s.More, true)
+		}
+	}
+	return b.String()
+}
+
+// writeTextVideos writes one "- title (channel)" line and its link per video
+func writeTextVideos(b *strings.Builder, videos []entities.Video, more int, showChannel bool) {
+	for _, v := range videos {
+		b.WriteString("- " + email.VideoTitle(v))
+		if showChannel && v.Channel != "" {
+			b.WriteString(" (" + v.Channel + ")")
+		}
+		b.WriteString("\n  " + email.WatchURL(v.ID) + "\n")
+	}
+	if more > 0 {
+		fmt.Fprintf(b, "- …and %d more\n", more)
+	}
+}
@@ -0,0 +1,85 @@
+package mail
+
+import (
+	"context"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestDigestMailer_DryRunWritesEML(t *testing.T) {
+	// Arrange
+	dir := filepath.Join(t.TempDir(), "digests")
+	mailer := NewDigestMailer(NewEMLWriter(dir), "Zentube <digest@example.com>", []string{"me@example.com"},
+		"zentube", "https://tube.example.com/")
+	until := time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)
+	digest := entities.Digest{
+		Since: until.Add(-7 * 24 * time.Hour),
+		Until: until,
+		Channels: []entities.DigestChannel{{
+			Channel: entities.Channel{ID: "UC_x5XG1OV2P6uZZ5FSM9Ttw", Title: "Google for Developers"},
+			Videos:  []entities.Video{{ID: "dQw4w9WgXcQ", Title: "Go & <generics>"}},
+			More:    2,
+		}},
+		Searches: []entities.DigestSearch{{
+			ID: 3, Name: "kubecon storage",
+			Videos: []entities.Video{{ID: "abcdefghijk", Title: "Storage talk", Channel: "CNCF"}},
+		}},
+	}
+
+	// Act
+	require.NoError(t, mailer.SendDigest(context.Background(), digest))
+
+	// Assert
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.True(t, strings.HasSuffix(files[0].Name(), ".eml"))
+
+	raw, err := os.Open(filepath.Join(dir, files[0].Name()))
+	require.NoError(t, err)
+	defer raw.Close()
+	msg, err := mail.ReadMessage(raw)
+	require.NoError(t, err)
+	assert.Equal(t, "zentube digest: 4 new videos", msg.Header.Get("Subject"))
+	assert.Equal(t, `"Zentube" <digest@example.com>`, msg.Header.Get("From"))
+	assert.Equal(t, "<me@example.com>", msg.Header.Get("To"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	bodies := map[string]string{}
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := parts.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		// NextPart decodes quoted-printable parts
+		body, err := io.ReadAll(part)
+		require.NoError(t, err)
+		contentType, _, _ := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		bodies[contentType] = strings.ReplaceAll(string(body), "\r\n", "\n")
+	}
+
+	text := bodies["text/plain"]
+	assert.Contains(t, text, "Google for Developers\n- Go & <generics>\n  https://www.youtube.com/watch?v=dQw4w9WgXcQ\n- …and 2 more")
+	assert.Contains(t, text, "kubecon storage\nhttps://tube.example.com/saved-searches/3\n- Storage talk (CNCF)")
+
+	html := bodies["text/html"]
+	assert.Contains(t, html, "Go &amp; &lt;generics&gt;")
+	assert.Contains(t, html, `href="https://tube.example.com/saved-searches/3"`)
+	assert.Contains(t, html, `href="https://tube.example.com/feed"`)
+}
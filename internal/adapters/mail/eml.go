@@ -0,0 +1,44 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EMLWriter writes messages to .eml files instead of sending them (dry run)
+// The files open in any mail client
+// It implements Transport
+type EMLWriter struct {
+	dir string
+}
+
+// NewEMLWriter creates a writer that stores messages in dir
+func NewEMLWriter(dir string) *EMLWriter {
+	return &EMLWriter{dir: dir}
+}
+
+// Deliver writes msg to <dir>/<UTC timestamp>.eml
+// The file is written under a temporary name and renamed, so a partial
+// message is never left behind
+func (w *EMLWriter) Deliver(ctx context.Context, _ string, _ []string, msg []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(w.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create dry-run directory: %w", err)
+	}
+
+	name := filepath.Join(w.dir, time.Now().UTC().Format("20060102T150405.000000000Z")+".eml")
+	tmp := name + ".tmp"
+	if err := os.WriteFile(tmp, msg, 0o600); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := os.Rename(tmp, name); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Package mail formats emails and delivers them over SMTP or to .eml files
+package mail
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Transport delivers a formatted message
+type Transport interface {
+	Deliver(ctx context.Context, from string, to []string, msg []byte) error
+}
+
+// Message is an email with a plaintext and an HTML body
+type Message struct {
+	From    string   // Address, optionally with a display name
+	To      []string // Addresses, optionally with display names
+	Subject string
+	Text    string
+	HTML    string
+	Date    time.Time
+}
+
+// Bytes formats the message as multipart/alternative with CRLF line endings,
+// plaintext first so clients that can show HTML prefer it
+func (m Message) Bytes() ([]byte, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	to := make([]string, 0, len(m.To))
+	for _, addr := range m.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to address: %w", err)
+		}
+		to = append(to, a.String())
+	}
+
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=utf-8", m.Text},
+		{"text/html; charset=utf-8", m.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	header := func(name, value string) { msg.WriteString(name + ": " + value + "\r\n") }
+	header("From", from.String())
+	header("To", strings.Join(to, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	header("Date", m.Date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from.Address))
+	header("MIME-Version", "1.0")
+	header("Content-Type", `multipart/alternative; boundary="`+parts.Boundary()+`"`)
+	msg.WriteString("\r\n")
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if i := strings.LastIndexByte(from, '@'); i >= 0 {
+		domain = from[i+1:]
+	}
+	var b [12]byte
+	_, _ = rand.Read(b[:])
+	return "<" + hex.EncodeToString(b[:]) + "@" + domain + ">"
+}
+
+// envelope returns the bare addresses of a message's sender and recipients
+func envelope(from string, to []string) (string, []string, error) {
+	sender, err := mail.ParseAddress(from)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	recipients := make([]string, 0, len(to))
+	for _, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid to address: %w", err)
+		}
+		recipients = append(recipients, a.Address)
+	}
+	return sender.Address, recipients, nil
+}
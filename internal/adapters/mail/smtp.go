@@ -0,0 +1,122 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+// SMTP connection security modes
+const (
+	SecurityStartTLS = "starttls" // Plain connection upgraded with STARTTLS, which is required (port 587)
+	SecurityTLS      = "tls"      // TLS from the start (port 465)
+	SecurityNone     = "none"     // No encryption; only for a relay on localhost
+)
+
+// smtpTimeout bounds a whole delivery when ctx has no earlier deadline
+const smtpTimeout = 30 * time.Second
+
+// SMTPConfig describes an SMTP server
+type SMTPConfig struct {
+	Host      string
+	Port      int
+	Username  string // No authentication when empty
+	Password  string
+	Security  string      // SecurityStartTLS, SecurityTLS or SecurityNone
+	TLSConfig *tls.Config // nil verifies the server against the system roots
+}
+
+// SMTPTransport delivers messages to an SMTP server
+// It implements Transport
+type SMTPTransport struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPTransport creates a transport for the server in cfg
+func NewSMTPTransport(cfg SMTPConfig) *SMTPTransport {
+	return &SMTPTransport{cfg: cfg}
+}
+
+// Deliver sends msg to the recipients in a single SMTP session
+// With SecurityStartTLS, a server that doesn't offer STARTTLS is an error
+// rather than a reason to send credentials and mail in the clear
+func (t *SMTPTransport) Deliver(ctx context.Context, from string, to []string, msg []byte) error {
+	sender, recipients, err := envelope(from, to)
+	if err != nil {
+		return err
+	}
+
+	conn, err := t.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(smtpTimeout)
+	}
+	_ = conn.SetDeadline(deadline)
+
+	c, err := smtp.NewClient(conn, t.cfg.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer c.Close()
+
+	if t.cfg.Security == SecurityStartTLS {
+		if ok, _ := c.Extension("STARTTLS"); !ok {
+			return errors.New("SMTP server does not offer STARTTLS")
+		}
+		if err := c.StartTLS(t.tlsConfig()); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if t.cfg.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", t.cfg.Username, t.cfg.Password, t.cfg.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := c.Mail(sender); err != nil {
+		return fmt.Errorf("SMTP server rejected the sender: %w", err)
+	}
+	for _, rcpt := range recipients {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP server rejected a recipient: %w", err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP server refused the message: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server refused the message: %w", err)
+	}
+	return c.Quit()
+}
+
+// dial opens the connection, with TLS from the start for SecurityTLS
+func (t *SMTPTransport) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(t.cfg.Host, strconv.Itoa(t.cfg.Port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if t.cfg.Security == SecurityTLS {
+		return (&tls.Dialer{NetDialer: dialer, Config: t.tlsConfig()}).DialContext(ctx, "tcp", addr)
+	}
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+func (t *SMTPTransport) tlsConfig() *tls.Config {
+	if t.cfg.TLSConfig != nil {
+		return t.cfg.TLSConfig.Clone()
+	}
+	return &tls.Config{ServerName: t.cfg.Host, MinVersion: tls.VersionTLS12}
+}
@@ -0,0 +1,185 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"net"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpStandIn is a minimal SMTP server that records what it receives
+type smtpStandIn struct {
+	listener net.Listener
+	tls      *tls.Config // nil: STARTTLS isn't offered
+
+	mu       sync.Mutex
+	usedTLS  bool
+	auth     string // Decoded AUTH PLAIN credentials
+	from     string
+	rcpts    []string
+	messages []string
+}
+
+// newSMTPStandIn starts a server on localhost; with offerTLS it offers
+// STARTTLS with a self-signed certificate and returns a client config trusting it
+func newSMTPStandIn(t *testing.T, offerTLS bool) (*smtpStandIn, *tls.Config) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := &smtpStandIn{listener: l}
+	t.Cleanup(func() { l.Close() })
+
+	var clientTLS *tls.Config
+	if offerTLS {
+		// Borrow httptest's self-signed certificate for 127.0.0.1
+		certServer := httptest.NewUnstartedServer(nil)
+		certServer.StartTLS()
+		s.tls = &tls.Config{Certificates: certServer.TLS.Certificates}
+		pool := x509.NewCertPool()
+		pool.AddCert(certServer.Certificate())
+		certServer.Close()
+		clientTLS = &tls.Config{RootCAs: pool, ServerName: "127.0.0.1"}
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, clientTLS
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	_ = tp.PrintfLine("220 stand-in ESMTP")
+	secure := false
+
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			exts := []string{"250-stand-in"}
+			if s.tls != nil && !secure {
+				exts = append(exts, "250-STARTTLS")
+			}
+			exts = append(exts, "250 AUTH PLAIN")
+			_ = tp.PrintfLine("%s", strings.Join(exts, "\r\n"))
+		case "STARTTLS":
+			_ = tp.PrintfLine("220 go ahead")
+			tlsConn := tls.Server(conn, s.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, secure = tlsConn, true
+			tp = textproto.NewConn(conn)
+			s.mu.Lock()
+			s.usedTLS = true
+			s.mu.Unlock()
+		case "AUTH":
+			_, encoded, _ := strings.Cut(arg, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(encoded)
+			s.mu.Lock()
+			s.auth = string(decoded)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("235 ok")
+		case "MAIL":
+			s.mu.Lock()
+			s.from = arg
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 ok")
+		case "RCPT":
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, arg)
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 ok")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			_ = tp.PrintfLine("250 queued")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("250 ok")
+		}
+	}
+}
+
+func TestSMTPTransport_DeliversOverSTARTTLS(t *testing.T) {
+	// Arrange
+	server, clientTLS := newSMTPStandIn(t, true)
+	transport := NewSMTPTransport(SMTPConfig{
+		Host:      "127.0.0.1",
+		Port:      server.port(),
+		Username:  "zentube",
+		Password:  "hunter2",
+		Security:  SecurityStartTLS,
+		TLSConfig: clientTLS,
+	})
+	msg, err := Message{
+		From:    "Zentube <digest@example.com>",
+		To:      []string{"me@example.com", "Partner <you@example.com>"},
+		Subject: "zentube digest: 2 new videos",
+		Text:    "plain",
+		HTML:    "<p>html</p>",
+	}.Bytes()
+	require.NoError(t, err)
+
+	// Act
+	err = transport.Deliver(context.Background(), "Zentube <digest@example.com>",
+		[]string{"me@example.com", "Partner <you@example.com>"}, msg)
+
+	// Assert
+	require.NoError(t, err)
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.True(t, server.usedTLS)
+	assert.Equal(t, "\x00zentube\x00hunter2", server.auth)
+	assert.Equal(t, "FROM:<digest@example.com>", server.from)
+	assert.Equal(t, []string{"TO:<me@example.com>", "TO:<you@example.com>"}, server.rcpts)
+	require.Len(t, server.messages, 1)
+	assert.Contains(t, server.messages[0], "Subject: zentube digest: 2 new videos")
+}
+
+func TestSMTPTransport_RequiresSTARTTLS(t *testing.T) {
+	server, _ := newSMTPStandIn(t, false)
+	transport := NewSMTPTransport(SMTPConfig{
+		Host: "127.0.0.1", Port: server.port(), Username: "zentube", Password: "hunter2", Security: SecurityStartTLS,
+	})
+
+	err := transport.Deliver(context.Background(), "digest@example.com", []string{"me@example.com"}, []byte("Subject: x\r\n\r\nbody"))
+
+	assert.ErrorContains(t, err, "STARTTLS")
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	assert.Empty(t, server.auth, "credentials must not be sent in the clear")
+	assert.Empty(t, server.messages)
+}
@@ -0,0 +1,337 @@
+// Package memory provides in-memory adapters for development and tests.
+// Nothing survives a restart.
+package memory
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// SearchHistoryRepository keeps search history in a slice guarded by a RWMutex
+// It implements ports.SearchHistoryRepository, ports.SearchHistoryFullText and
+// ports.SearchAnalytics
+type SearchHistoryRepository struct {
+	mu     sync.RWMutex
+	rows   []entities.SearchHistory // In insertion (ID) order
+	nextID int64
+}
+
+// NewSearchHistoryRepository creates an empty repository
+func NewSearchHistoryRepository() *SearchHistoryRepository {
+	return &SearchHistoryRepository{nextID: 1}
+}
+
+func (r *SearchHistoryRepository) Save(ctx context.Context, history *entities.SearchHistory) error {
+	return r.SaveBatch(ctx, []*entities.SearchHistory{history})
+}
+
+// SaveBatch saves all histories and sets their IDs
+func (r *SearchHistoryRepository) SaveBatch(ctx context.Context, histories []*entities.SearchHistory) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Same constraint as the SQLite schema
+	for _, h := range histories {
+		if h.Query == "" || h.Results < 0 {
+			return appErrors.NewValidationError("invalid search history", nil)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, h := range histories {
+		h.ID = r.nextID
+		r.nextID++
+		r.rows = append(r.rows, *h)
+	}
+	return nil
+}
+
+func (r *SearchHistoryRepository) GetLast(ctx context.Context, limit int) ([]entities.SearchHistory, error) {
+	return r.List(ctx, entities.SearchHistoryFilter{}, nil, limit)
+}
+
+func (r *SearchHistoryRepository) GetTopQueries(ctx context.Context, since time.Time, limit int) ([]entities.QueryFrequency, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	counts := make(map[string]int)
+	lastSeen := make(map[string]time.Time)
+	for _, h := range r.rows {
+		if h.CreatedAt.Before(since) {
+			continue
+		}
+		counts[h.Query]++
+		if h.CreatedAt.After(lastSeen[h.Query]) {
+			lastSeen[h.Query] = h.CreatedAt
+		}
+	}
+	r.mu.RUnlock()
+
+	queries := make([]entities.QueryFrequency, 0, len(counts))
+	for q, n := range counts {
+		queries = append(queries, entities.QueryFrequency{Query: q, Count: n})
+	}
+
+	// Most frequent first, ties go to the most recently searched query
+	sort.Slice(queries, func(i, j int) bool {
+		if queries[i].Count != queries[j].Count {
+			return queries[i].Count > queries[j].Count
+		}
+		return lastSeen[queries[i].Query].After(lastSeen[queries[j].Query])
+	})
+
+	if len(queries) > limit {
+		queries = queries[:limit]
+	}
+	return queries, nil
+}
+
+// List returns rows matching filter, newest first, after the cursor
+func (r *SearchHistoryRepository) List(ctx context.Context, filter entities.SearchHistoryFilter, after *entities.SearchHistoryCursor, limit int) ([]entities.SearchHistory, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	contains := strings.ToLower(filter.Contains)
+
+	r.mu.RLock()
+	var matches []entities.SearchHistory
+	for _, h := range r.rows {
+		if !filter.From.IsZero() && h.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && !h.CreatedAt.Before(filter.To) {
+			continue
+		}
+		if contains != "" && !strings.Contains(strings.ToLower(h.Query), contains) {
+			continue
+		}
+		if h.Results < filter.MinResults {
+			continue
+		}
+		if after != nil && !olderThan(h, after) {
+			continue
+		}
+		matches = append(matches, h)
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		return olderThan(matches[j], &entities.SearchHistoryCursor{CreatedAt: matches[i].CreatedAt, ID: matches[i].ID})
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches, nil
+}
+
+// olderThan reports whether h sorts after the cursor (created_at DESC, id DESC)
+func olderThan(h entities.SearchHistory, c *entities.SearchHistoryCursor) bool {
+	if h.CreatedAt.Equal(c.CreatedAt) {
+		return h.ID < c.ID
+	}
+	return h.CreatedAt.Before(c.CreatedAt)
+}
+
+// Delete removes a single row
+func (r *SearchHistoryRepository) Delete(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, h := range r.rows {
+		if h.ID == id {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			return nil
+		}
+	}
+	return appErrors.NewNotFoundError("Search history entry")
+}
+
+// DeleteAll removes every row
+func (r *SearchHistoryRepository) DeleteAll(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := int64(len(r.rows))
+	r.rows = nil
+	return n, nil
+}
+
+// PingContext always succeeds; it lets health checks treat the repository as a database
+func (r *SearchHistoryRepository) PingContext(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// FullTextSearch returns past queries containing every word of text as a word
+// prefix, newest first, one row per distinct query
+// There's no relevance ranking beyond that; it's meant for development
+func (r *SearchHistoryRepository) FullTextSearch(ctx context.Context, text string, limit int) ([]entities.SearchHistoryMatch, error) {
+	terms := uniqueFields(strings.ToLower(text))
+	if len(terms) == 0 {
+		return nil, nil
+	}
+
+	rows, err := r.List(ctx, entities.SearchHistoryFilter{}, nil, math.MaxInt)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var matches []entities.SearchHistoryMatch
+	for _, h := range rows {
+		if len(matches) >= limit {
+			break
+		}
+		if seen[h.Query] {
+			continue
+		}
+		segments, ok := highlightPrefixes(h.Query, terms)
+		if !ok {
+			continue
+		}
+		seen[h.Query] = true
+		matches = append(matches, entities.SearchHistoryMatch{SearchHistory: h, Highlighted: segments})
+	}
+	return matches, nil
+}
+
+// uniqueFields splits s on whitespace, dropping repeated words
+func uniqueFields(s string) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, f := range strings.Fields(s) {
+		if !seen[f] {
+			seen[f] = true
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// highlightPrefixes marks words of query that start with one of terms
+// ok is false unless every term matched at least one word
+func highlightPrefixes(query string, terms []string) (segments []entities.TextSegment, ok bool) {
+	matched := make(map[string]bool)
+	words := strings.Fields(query)
+
+	for i, word := range words {
+		if i > 0 {
+			segments = appendSegment(segments, " ", false)
+		}
+
+		lower := strings.ToLower(word)
+		hit := false
+		for _, term := range terms {
+			if strings.HasPrefix(lower, term) {
+				matched[term] = true
+				hit = true
+			}
+		}
+		segments = appendSegment(segments, word, hit)
+	}
+
+	return segments, len(matched) == len(terms)
+}
+
+// appendSegment adds text, merging it into the previous segment when both
+// are unmatched
+func appendSegment(segments []entities.TextSegment, text string, match bool) []entities.TextSegment {
+	if n := len(segments); n > 0 && !match && !segments[n-1].Match {
+		segments[n-1].Text += text
+		return segments
+	}
+	return append(segments, entities.TextSegment{Text: text, Match: match})
+}
+
+// QueryStats returns totals per distinct query since the given time
+func (r *SearchHistoryRepository) QueryStats(ctx context.Context, since time.Time) ([]entities.QueryStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	byQuery := make(map[string]*entities.QueryStats)
+	var stats []*entities.QueryStats
+	for _, h := range r.rows {
+		if h.CreatedAt.Before(since) {
+			continue
+		}
+		s, ok := byQuery[h.Query]
+		if !ok {
+			s = &entities.QueryStats{Query: h.Query}
+			byQuery[h.Query] = s
+			stats = append(stats, s)
+		}
+		s.Searches++
+		s.ZeroResults += boolToInt(h.Results == 0)
+		s.CacheHits += boolToInt(h.CacheHit)
+	}
+	r.mu.RUnlock()
+
+	result := make([]entities.QueryStats, len(stats))
+	for i, s := range stats {
+		result[i] = *s
+	}
+	return result, nil
+}
+
+// DailyStats returns totals per UTC day since the given time, oldest first
+func (r *SearchHistoryRepository) DailyStats(ctx context.Context, since time.Time) ([]entities.DailySearchStats, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	byDay := make(map[time.Time]*entities.DailySearchStats)
+	for _, h := range r.rows {
+		if h.CreatedAt.Before(since) {
+			continue
+		}
+		day := h.CreatedAt.UTC().Truncate(24 * time.Hour)
+		s, ok := byDay[day]
+		if !ok {
+			s = &entities.DailySearchStats{Day: day}
+			byDay[day] = s
+		}
+		s.Searches++
+		s.ZeroResults += boolToInt(h.Results == 0)
+		s.CacheHits += boolToInt(h.CacheHit)
+	}
+	r.mu.RUnlock()
+
+	result := make([]entities.DailySearchStats, 0, len(byDay))
+	for _, s := range byDay {
+		result = append(result, *s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day.Before(result[j].Day) })
+	return result, nil
+}
+
+// boolToInt returns 1 for true, like SQLite's boolean expressions
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
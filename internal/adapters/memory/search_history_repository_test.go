@@ -0,0 +1,46 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/ports/porttest"
+)
+
+func TestSearchHistoryRepository_Conformance(t *testing.T) {
+	porttest.RunSearchHistoryRepositoryTests(t, func(t *testing.T) ports.SearchHistoryRepository {
+		return NewSearchHistoryRepository()
+	})
+}
+
+func TestSearchHistoryRepository_AnalyticsConformance(t *testing.T) {
+	porttest.RunSearchAnalyticsTests(t, func(t *testing.T) porttest.AnalyticsRepository {
+		return NewSearchHistoryRepository()
+	})
+}
+
+func TestSearchHistoryRepository_FullTextSearch(t *testing.T) {
+	// Arrange
+	repo := NewSearchHistoryRepository()
+	ctx := context.Background()
+	for _, q := range []string{"golang generics", "rust generics", "golang generics"} {
+		require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: q, Results: 1, CreatedAt: time.Now()}))
+	}
+
+	// Act
+	matches, err := repo.FullTextSearch(ctx, "GO gen", 10)
+
+	// Assert - every term must match, duplicates collapse
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, []entities.TextSegment{
+		{Text: "golang", Match: true},
+		{Text: " ", Match: false},
+		{Text: "generics", Match: true},
+	}, matches[0].Highlighted)
+}
@@ -0,0 +1,64 @@
+// Package webhook POSTs webhook payloads over HTTP
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// maxResponseSize caps how much of a receiver's response is read before the
+// connection is reused; the body itself is ignored
+const maxResponseSize = 64 << 10
+
+// HTTPSender POSTs webhook payloads
+// It implements ports.WebhookSender
+type HTTPSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender creates a sender that uses a copy of client
+// Redirects are not followed: a receiver that moved must be reconfigured,
+// rather than having signed payloads forwarded somewhere else
+func NewHTTPSender(client *http.Client) *HTTPSender {
+	c := *client
+	c.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &HTTPSender{client: &c}
+}
+
+// SendWebhook POSTs body as JSON to endpoint with the extra headers
+// Errors never include the URL, which may carry a token
+func (s *HTTPSender) SendWebhook(ctx context.Context, endpoint string, body []byte, headers map[string]string) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.New("failed to build webhook request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "zentube-webhooks")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// *url.Error quotes the URL; keep only the cause
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseSize))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPSender_PostsJSONWithHeaders(t *testing.T) {
+	// Arrange
+	var gotBody []byte
+	var gotHeader http.Header
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		gotHeader = r.Header.Clone()
+		gotBody, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(receiver.Close)
+
+	sender := NewHTTPSender(receiver.Client())
+
+	// Act
+	status, err := sender.SendWebhook(context.Background(), receiver.URL, []byte(`{"event":"test"}`),
+		map[string]string{"X-Zentube-Event": "test"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, `{"event":"test"}`, string(gotBody))
+	assert.Equal(t, "application/json", gotHeader.Get("Content-Type"))
+	assert.Equal(t, "test", gotHeader.Get("X-Zentube-Event"))
+}
+
+func TestHTTPSender_ReportsFailures(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/moved":
+			http.Redirect(w, r, "/elsewhere", http.StatusFound)
+		default:
+			http.Error(w, "boom", http.StatusInternalServerError)
+		}
+	}))
+	t.Cleanup(receiver.Close)
+
+	sender := NewHTTPSender(&http.Client{Timeout: time.Second})
+
+	t.Run("error status", func(t *testing.T) {
+		status, err := sender.SendWebhook(context.Background(), receiver.URL+"/hook", []byte(`{}`), nil)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusInternalServerError, status)
+	})
+
+	t.Run("redirects are not followed", func(t *testing.T) {
+		status, err := sender.SendWebhook(context.Background(), receiver.URL+"/moved", []byte(`{}`), nil)
+		assert.Error(t, err)
+		assert.Equal(t, http.StatusFound, status)
+	})
+
+	t.Run("unreachable receiver hides the URL", func(t *testing.T) {
+		closed := httptest.NewServer(http.NotFoundHandler())
+		closed.Close()
+
+		status, err := sender.SendWebhook(context.Background(), closed.URL+"/hook?token=s3cret", []byte(`{}`), nil)
+		require.Error(t, err)
+		assert.Zero(t, status)
+		assert.NotContains(t, err.Error(), "s3cret")
+	})
+}
@@ -0,0 +1,77 @@
+package youtube
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// uploadsPerPoll is how many recent uploads RecentUploads asks for
+const uploadsPerPoll = 15
+
+// LookupChannel resolves a channel ID or @handle with channels.list (1 quota unit)
+func (c *YouTubeClient) LookupChannel(ctx context.Context, ref string) (*entities.Channel, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	call := c.service.Channels.List([]string{"snippet"}).Context(ctx)
+	if strings.HasPrefix(ref, "@") {
+		call = call.ForHandle(ref)
+	} else {
+		call = call.Id(ref)
+	}
+
+	resp, err := call.Do()
+	if err != nil {
+		return nil, classifyError(err)
+	}
+	if len(resp.Items) == 0 || resp.Items[0].Snippet == nil {
+		return nil, appErrors.NewNotFoundError("Channel")
+	}
+
+	item := resp.Items[0]
+	return &entities.Channel{ID: item.Id, Title: item.Snippet.Title}, nil
+}
+
+// RecentUploads lists a channel's uploads playlist with playlistItems.list (1 quota unit)
+// The uploads playlist ID is the channel ID with "UC" replaced by "UU"
+func (c *YouTubeClient) RecentUploads(ctx context.Context, channelID string) ([]entities.Video, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	resp, err := c.service.PlaylistItems.List([]string{"snippet", "contentDetails"}).
+		PlaylistId("UU" + strings.TrimPrefix(channelID, "UC")).
+		MaxResults(uploadsPerPoll).
+		Context(ctx).
+		Do()
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	videos := make([]entities.Video, 0, len(resp.Items))
+	for _, item := range resp.Items {
+		if item.Snippet == nil || item.ContentDetails == nil {
+			continue
+		}
+		published, _ := time.Parse(time.RFC3339, item.ContentDetails.VideoPublishedAt)
+		if published.IsZero() {
+			published, _ = time.Parse(time.RFC3339, item.Snippet.PublishedAt)
+		}
+		v := entities.Video{
+			ID:          item.ContentDetails.VideoId,
+			Title:       item.Snippet.Title,
+			Channel:     item.Snippet.ChannelTitle,
+			PublishedAt: published.UTC(),
+			Description: item.Snippet.Description,
+		}
+		if item.Snippet.Thumbnails != nil && item.Snippet.Thumbnails.Default != nil {
+			v.Thumbnail = item.Snippet.Thumbnails.Default.Url
+		}
+		videos = append(videos, v)
+	}
+
+	return videos, nil
+}
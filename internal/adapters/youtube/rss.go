@@ -0,0 +1,118 @@
+package youtube
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// ChannelFeedURL is YouTube's public Atom feed of a channel's latest uploads
+const ChannelFeedURL = "https://www.youtube.com/feeds/videos.xml"
+
+// maxFeedSize caps how much of a feed response is read
+const maxFeedSize = 2 << 20
+
+// RSSChannelSource reads channels from their public upload feeds
+// It costs no API quota but can't resolve @handles
+// It implements ports.ChannelSource
+type RSSChannelSource struct {
+	client  *http.Client
+	feedURL string
+}
+
+// NewRSSChannelSource creates a channel source that reads feedURL
+// (ChannelFeedURL outside tests)
+func NewRSSChannelSource(client *http.Client, feedURL string) *RSSChannelSource {
+	return &RSSChannelSource{client: client, feedURL: feedURL}
+}
+
+// atomFeed is the subset of a channel feed we read
+type atomFeed struct {
+	Title   string      `xml:"title"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	VideoID   string `xml:"http://www.youtube.com/xml/schemas/2015 videoId"`
+	Title     string `xml:"title"`
+	Published string `xml:"published"`
+	Author    string `xml:"author>name"`
+	// Description lives in the Media RSS group
+	Description string `xml:"http://search.yahoo.com/mrss/ group>description"`
+}
+
+// LookupChannel reads a channel's title from its feed
+// Handles need the Data API; they get a Validation AppError
+func (s *RSSChannelSource) LookupChannel(ctx context.Context, ref string) (*entities.Channel, error) {
+	if strings.HasPrefix(ref, "@") {
+		return nil, appErrors.NewValidationError("@handles need the Data API source, use the channel ID or a youtube.com/channel/ link", nil)
+	}
+
+	feed, err := s.fetch(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return &entities.Channel{ID: ref, Title: feed.Title}, nil
+}
+
+// RecentUploads returns the uploads listed in a channel's feed, newest first
+// Thumbnails point at i.ytimg.com (the feed uses numbered mirrors the CSP doesn't allow)
+func (s *RSSChannelSource) RecentUploads(ctx context.Context, channelID string) ([]entities.Video, error) {
+	feed, err := s.fetch(ctx, channelID)
+	if err != nil {
+		return nil, err
+	}
+
+	videos := make([]entities.Video, 0, len(feed.Entries))
+	for _, e := range feed.Entries {
+		if e.VideoID == "" {
+			continue
+		}
+		published, _ := time.Parse(time.RFC3339, e.Published)
+		videos = append(videos, entities.Video{
+			ID:          e.VideoID,
+			Title:       e.Title,
+			Channel:     e.Author,
+			PublishedAt: published.UTC(),
+			Thumbnail:   "https://i.ytimg.com/vi/" + e.VideoID + "/mqdefault.jpg",
+			Description: e.Description,
+		})
+	}
+	return videos, nil
+}
+
+// fetch downloads and parses a channel's feed
+func (s *RSSChannelSource) fetch(ctx context.Context, channelID string) (*atomFeed, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.feedURL+"?channel_id="+url.QueryEscape(channelID), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build feed request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, appErrors.NewServiceUnavailableError("YouTube channel feed", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, appErrors.NewNotFoundError("Channel")
+	case resp.StatusCode != http.StatusOK:
+		return nil, appErrors.NewServiceUnavailableError("YouTube channel feed",
+			fmt.Errorf("unexpected status %d", resp.StatusCode))
+	}
+
+	var feed atomFeed
+	if err := xml.NewDecoder(io.LimitReader(resp.Body, maxFeedSize)).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("failed to parse channel feed: %w", err)
+	}
+	return &feed, nil
+}
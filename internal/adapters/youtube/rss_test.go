@@ -0,0 +1,134 @@
+package youtube
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+const testChannelID = "UC_x5XG1OV2P6uZZ5FSM9Ttw"
+
+// testFeed is a trimmed channel feed in the shape YouTube serves
+const testFeed = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns:yt="http://www.youtube.com/xml/schemas/2015" xmlns:media="http://search.yahoo.com/mrss/" xmlns="http://www.w3.org/2005/Atom">
+ <title>Google for Developers</title>
+ <yt:channelId>UC_x5XG1OV2P6uZZ5FSM9Ttw</yt:channelId>
+ <entry>
+  <id>yt:video:f6kdp27TYZs</id>
+  <yt:videoId>f6kdp27TYZs</yt:videoId>
+  <title>Go Concurrency Patterns</title>
+  <author><name>Google for Developers</name></author>
+  <published>2024-03-01T12:00:00+01:00</published>
+  <media:group>
+   <media:title>Go Concurrency Patterns</media:title>
+   <media:thumbnail url="https://i4.ytimg.com/vi/f6kdp27TYZs/hqdefault.jpg" width="480" height="360"/>
+   <media:description>Rob Pike on goroutines and channels</media:description>
+  </media:group>
+ </entry>
+ <entry>
+  <id>yt:video:missing</id>
+  <title>Entry without a video ID</title>
+ </entry>
+</feed>`
+
+// feedServer serves body with status for every request and records the channel asked for
+func feedServer(t *testing.T, status int, body string) (*RSSChannelSource, *string) {
+	t.Helper()
+	var channelID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		channelID = r.URL.Query().Get("channel_id")
+		w.Header().Set("Content-Type", "application/atom+xml")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+
+	return NewRSSChannelSource(server.Client(), server.URL+"/feeds/videos.xml"), &channelID
+}
+
+func TestRSSChannelSource_RecentUploads(t *testing.T) {
+	// Arrange
+	source, channelID := feedServer(t, http.StatusOK, testFeed)
+
+	// Act
+	videos, err := source.RecentUploads(context.Background(), testChannelID)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, testChannelID, *channelID)
+	require.Len(t, videos, 1, "entries without a yt:videoId are skipped")
+	v := videos[0]
+	assert.Equal(t, "f6kdp27TYZs", v.ID)
+	assert.Equal(t, "Go Concurrency Patterns", v.Title)
+	assert.Equal(t, "Google for Developers", v.Channel)
+	assert.Equal(t, "Rob Pike on goroutines and channels", v.Description)
+	assert.Equal(t, "https://i.ytimg.com/vi/f6kdp27TYZs/mqdefault.jpg", v.Thumbnail)
+	assert.Equal(t, time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC), v.PublishedAt)
+}
+
+func TestRSSChannelSource_EmptyFeed(t *testing.T) {
+	source, _ := feedServer(t, http.StatusOK,
+		`<feed xmlns="http://www.w3.org/2005/Atom"><title>Quiet channel</title></feed>`)
+
+	videos, err := source.RecentUploads(context.Background(), testChannelID)
+
+	require.NoError(t, err)
+	assert.Empty(t, videos)
+}
+
+func TestRSSChannelSource_LookupChannel(t *testing.T) {
+	t.Run("reads the feed title", func(t *testing.T) {
+		source, _ := feedServer(t, http.StatusOK, testFeed)
+
+		channel, err := source.LookupChannel(context.Background(), testChannelID)
+
+		require.NoError(t, err)
+		assert.Equal(t, testChannelID, channel.ID)
+		assert.Equal(t, "Google for Developers", channel.Title)
+	})
+
+	t.Run("rejects handles", func(t *testing.T) {
+		source, channelID := feedServer(t, http.StatusOK, testFeed)
+
+		_, err := source.LookupChannel(context.Background(), "@GoogleDevelopers")
+
+		assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+		assert.Empty(t, *channelID, "no request is made for a handle")
+	})
+}
+
+func TestRSSChannelSource_ReportsFailures(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		code   string
+	}{
+		{"unknown channel", http.StatusNotFound, appErrors.ErrCodeNotFound},
+		{"server error", http.StatusInternalServerError, appErrors.ErrCodeServiceUnavail},
+		{"rate limited", http.StatusTooManyRequests, appErrors.ErrCodeServiceUnavail},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source, _ := feedServer(t, tt.status, "")
+
+			_, err := source.RecentUploads(context.Background(), testChannelID)
+
+			assert.Equal(t, tt.code, appErrors.GetErrorCode(err))
+		})
+	}
+
+	t.Run("malformed feed", func(t *testing.T) {
+		source, _ := feedServer(t, http.StatusOK, "<feed><entry>")
+
+		_, err := source.RecentUploads(context.Background(), testChannelID)
+
+		assert.Error(t, err)
+	})
+}
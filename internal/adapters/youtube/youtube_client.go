@@ -2,9 +2,13 @@ package youtube
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"time"
 
 	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 	"google.golang.org/api/youtube/v3"
 )
@@ -35,7 +39,7 @@ func (c *YouTubeClient) Search(query string, maxResults int64) ([]entities.Video
 
 	resp, err := call.Do()
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	videos := make([]entities.Video, 0, len(resp.Items))
@@ -50,9 +54,32 @@ func (c *YouTubeClient) Search(query string, maxResults int64) ([]entities.Video
 			Channel:     item.Snippet.ChannelTitle,
 			PublishedAt: pubTime,
 			Thumbnail:   item.Snippet.Thumbnails.Default.Url,
+			Description: item.Snippet.Description,
 		}
 		videos = append(videos, v)
 	}
 
 	return videos, nil
 }
+
+// classifyError maps YouTube API errors to AppErrors so callers can tell
+// permanent request errors apart from transient upstream failures
+func classifyError(err error) error {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch apiErr.Code {
+	case http.StatusBadRequest:
+		return appErrors.NewBadRequestError("YouTube rejected the search parameters", err)
+	case http.StatusForbidden:
+		for _, item := range apiErr.Errors {
+			if item.Reason == "quotaExceeded" || item.Reason == "dailyLimitExceeded" {
+				return appErrors.NewServiceUnavailableError("YouTube search", err)
+			}
+		}
+	}
+
+	return err
+}
@@ -3,6 +3,9 @@ package cache
 import (
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -28,10 +31,17 @@ type Cache struct {
 // cacheItem represents a single cache entry
 type cacheItem struct {
 	value      interface{}
+	meta       EntryMeta
 	expiration time.Time
 	createdAt  time.Time
 }
 
+// EntryMeta describes a cached value for inspection
+type EntryMeta struct {
+	Label  string // Human-readable description (e.g. the search query)
+	Source string // What produced the value (e.g. "results", "empty", "error")
+}
+
 // NewCache creates a new cache with specified max entries and default TTL
 // Parameters:
 //   - maxEntries: Maximum number of items to store (0 = unlimited, not recommended)
@@ -61,16 +71,23 @@ func (c *Cache) Set(key string, value interface{}) {
 
 // SetWithTTL stores a value in the cache with custom TTL
 func (c *Cache) SetWithTTL(key string, value interface{}, ttl time.Duration) {
+	c.SetWithMeta(key, value, ttl, EntryMeta{})
+}
+
+// SetWithMeta stores a value with custom TTL and descriptive metadata
+// Metadata shows up in Entries and GetStats, and labels can be used with DeleteByLabel
+func (c *Cache) SetWithMeta(key string, value interface{}, ttl time.Duration, meta EntryMeta) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// If at capacity, remove oldest entry
-	if c.maxEntries > 0 && len(c.items) >= c.maxEntries {
+	// If at capacity, remove oldest entry (overwriting a key doesn't grow the cache)
+	if _, exists := c.items[key]; !exists && c.maxEntries > 0 && len(c.items) >= c.maxEntries {
 		c.evictOldest()
 	}
 
 	c.items[key] = &cacheItem{
 		value:      value,
+		meta:       meta,
 		expiration: time.Now().Add(ttl),
 		createdAt:  time.Now(),
 	}
@@ -96,6 +113,25 @@ func (c *Cache) Get(key string) (interface{}, bool) {
 	return item.value, true
 }
 
+// TTL returns the remaining time-to-live of a key
+// Returns (0, false) if not found or expired
+func (c *Cache) TTL(key string) (time.Duration, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	item, exists := c.items[key]
+	if !exists {
+		return 0, false
+	}
+
+	remaining := time.Until(item.expiration)
+	if remaining <= 0 {
+		return 0, false
+	}
+
+	return remaining, true
+}
+
 // Delete removes a key from the cache
 func (c *Cache) Delete(key string) {
 	c.mu.Lock()
@@ -112,6 +148,43 @@ func (c *Cache) Clear() {
 	c.items = make(map[string]*cacheItem)
 }
 
+// DeleteByLabel removes all entries with the given label
+// Returns the number of removed entries
+func (c *Cache) DeleteByLabel(label string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key, item := range c.items {
+		if item.meta.Label == label {
+			delete(c.items, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// ClearNamespace removes all entries whose key belongs to the namespace
+// (see NamespacedKey). Returns the number of removed entries
+func (c *Cache) ClearNamespace(namespace string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key := range c.items {
+		if Namespace(key) == namespace {
+			delete(c.items, key)
+			removed++
+		}
+	}
+	return removed
+}
+
+// DefaultTTL returns the TTL used by Set
+func (c *Cache) DefaultTTL() time.Duration {
+	return c.defaultTTL
+}
+
 // Len returns the current number of items in the cache
 func (c *Cache) Len() int {
 	c.mu.RLock()
@@ -181,6 +254,26 @@ func GenerateKey(parts ...interface{}) string {
 	return hex.EncodeToString(h.Sum(nil))
 }
 
+// NamespacedKey creates a deterministic cache key prefixed with a namespace
+// Namespaced keys can be listed and purged together (see ClearNamespace)
+//
+// Example:
+//
+//	key := NamespacedKey("search", "golang tutorial", 10)
+//	// Returns: "search:abc123..."
+func NamespacedKey(namespace string, parts ...interface{}) string {
+	return namespace + ":" + GenerateKey(append([]interface{}{namespace}, parts...)...)
+}
+
+// Namespace returns the namespace of a key created with NamespacedKey
+// Returns "" for keys without a namespace
+func Namespace(key string) string {
+	if i := strings.IndexByte(key, ':'); i > 0 {
+		return key[:i]
+	}
+	return ""
+}
+
 // toString converts various types to string for hashing
 func toString(v interface{}) string {
 	switch val := v.(type) {
@@ -202,6 +295,7 @@ type Stats struct {
 	DefaultTTL    time.Duration
 	OldestItemAge time.Duration
 	NewestItemAge time.Duration
+	BySource      map[string]int // Entry count per EntryMeta.Source ("" for entries without one)
 }
 
 // GetStats returns cache statistics for monitoring
@@ -213,6 +307,11 @@ func (c *Cache) GetStats() Stats {
 		TotalItems: len(c.items),
 		MaxEntries: c.maxEntries,
 		DefaultTTL: c.defaultTTL,
+		BySource:   make(map[string]int),
+	}
+
+	for _, item := range c.items {
+		stats.BySource[item.meta.Source]++
 	}
 
 	if len(c.items) > 0 {
@@ -234,3 +333,62 @@ func (c *Cache) GetStats() Stats {
 
 	return stats
 }
+
+// EntryInfo describes a single cache entry for inspection
+type EntryInfo struct {
+	Key       string
+	Namespace string
+	Label     string
+	Source    string
+	Age       time.Duration
+	TTL       time.Duration // Remaining time-to-live (negative if expired but not yet cleaned up)
+	Size      int           // Approximate size in bytes (JSON-encoded value)
+}
+
+// Entries returns information about cached entries, newest first
+// Pass an empty namespace to list every entry
+func (c *Cache) Entries(namespace string) []EntryInfo {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	entries := make([]EntryInfo, 0, len(c.items))
+	for key, item := range c.items {
+		ns := Namespace(key)
+		if namespace != "" && ns != namespace {
+			continue
+		}
+		entries = append(entries, EntryInfo{
+			Key:       key,
+			Namespace: ns,
+			Label:     item.meta.Label,
+			Source:    item.meta.Source,
+			Age:       now.Sub(item.createdAt),
+			TTL:       item.expiration.Sub(now),
+			Size:      approximateSize(item.value),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Age < entries[j].Age
+	})
+
+	return entries
+}
+
+// approximateSize estimates the memory footprint of a value
+// JSON encoding is not exact but good enough to spot unusually large entries
+func approximateSize(v interface{}) int {
+	switch val := v.(type) {
+	case string:
+		return len(val)
+	case []byte:
+		return len(val)
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return 0
+	}
+	return len(data)
+}
@@ -0,0 +1,86 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_DeleteByLabel(t *testing.T) {
+	// Arrange - one query cached for two maxResults values, plus another query
+	c := NewCache(100, time.Minute)
+	c.SetWithMeta(NamespacedKey("search", "golang", 10), "a", time.Minute, EntryMeta{Label: "golang", Source: "results"})
+	c.SetWithMeta(NamespacedKey("search", "golang", 25), "b", time.Minute, EntryMeta{Label: "golang", Source: "empty"})
+	c.SetWithMeta(NamespacedKey("search", "rust", 10), "c", time.Minute, EntryMeta{Label: "rust", Source: "results"})
+
+	// Act
+	removed := c.DeleteByLabel("golang")
+
+	// Assert
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, 1, c.Len())
+	_, found := c.Get(NamespacedKey("search", "rust", 10))
+	assert.True(t, found)
+	assert.Zero(t, c.DeleteByLabel("golang"))
+}
+
+func TestCache_ClearNamespace(t *testing.T) {
+	// Arrange
+	c := NewCache(100, time.Minute)
+	c.Set(NamespacedKey("search", "golang", 10), "a")
+	c.Set(NamespacedKey("search", "rust", 10), "b")
+	c.Set(NamespacedKey("channel", "UC123"), "c")
+	c.Set("plain-key", "d")
+
+	// Act
+	removed := c.ClearNamespace("search")
+
+	// Assert
+	assert.Equal(t, 2, removed)
+	assert.Equal(t, 2, c.Len())
+	_, found := c.Get(NamespacedKey("channel", "UC123"))
+	assert.True(t, found)
+	_, found = c.Get("plain-key")
+	assert.True(t, found)
+}
+
+func TestCache_Entries(t *testing.T) {
+	// Arrange
+	c := NewCache(100, time.Minute)
+	oldKey := NamespacedKey("search", "golang", 10)
+	c.SetWithMeta(oldKey, "abcd", time.Minute, EntryMeta{Label: "golang", Source: "results"})
+	time.Sleep(5 * time.Millisecond)
+	newKey := NamespacedKey("search", "rust", 10)
+	c.SetWithMeta(newKey, "ab", 30*time.Second, EntryMeta{Label: "rust", Source: "error"})
+	c.Set(NamespacedKey("channel", "UC123"), "x")
+
+	// Act
+	all := c.Entries("")
+	search := c.Entries("search")
+
+	// Assert
+	assert.Len(t, all, 3)
+	require.Len(t, search, 2)
+
+	// Newest first
+	assert.Equal(t, newKey, search[0].Key)
+	assert.Equal(t, "search", search[0].Namespace)
+	assert.Equal(t, "rust", search[0].Label)
+	assert.Equal(t, "error", search[0].Source)
+	assert.Equal(t, 2, search[0].Size)
+	assert.LessOrEqual(t, search[0].TTL, 30*time.Second)
+	assert.Greater(t, search[0].TTL, 25*time.Second)
+
+	assert.Equal(t, oldKey, search[1].Key)
+	assert.Equal(t, 4, search[1].Size)
+
+	assert.Empty(t, c.Entries("unknown"))
+}
+
+func TestNamespace(t *testing.T) {
+	assert.Equal(t, "search", Namespace(NamespacedKey("search", "golang", 10)))
+	assert.Equal(t, "", Namespace(GenerateKey("golang", 10)))
+	assert.Equal(t, "", Namespace(":leading-colon"))
+}
@@ -3,8 +3,12 @@ package config
 import (
 	"errors"
 	"fmt"
+	"net/mail"
+	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
+	"time"
 
 	"github.com/joho/godotenv"
 	"gopkg.in/yaml.v3"
@@ -19,11 +23,24 @@ const (
 	Production  Environment = "production"
 )
 
+// Database backends
+const (
+	BackendSQLite = "sqlite" // Persistent SQLite database (default)
+	BackendMemory = "memory" // In-memory search history for development and tests
+)
+
+// Channel sources for subscriptions
+const (
+	SourceRSS = "rss" // Public upload feeds: no quota, channel IDs only
+	SourceAPI = "api" // YouTube Data API: 1 quota unit per poll, resolves @handles
+)
+
 // Public, tiny struct that contains app configs
 type App struct {
 	Name        string      `yaml:"name"`
 	Port        int         `yaml:"port"`
 	Environment Environment `yaml:"environment"`
+	BaseURL     string      `yaml:"base_url"` // Public origin for absolute links in feeds and webhooks; required for syndication
 }
 
 // Public, tiny struct that contains YouTube client configs
@@ -34,14 +51,219 @@ type YouTube struct {
 
 // Public, tiny struct that contains database configs
 type Database struct {
-	Path string `yaml:"path"`
+	Backend       string        `yaml:"backend"` // "sqlite" or "memory"
+	Path          string        `yaml:"path"`
+	Retention     Retention     `yaml:"retention"`
+	Backup        Backup        `yaml:"backup"`
+	HistoryWriter HistoryWriter `yaml:"history_writer"`
+}
+
+// Public, tiny struct that contains background history writer configs
+type HistoryWriter struct {
+	QueueSize     int           `yaml:"queue_size"`     // Searches buffered before overflow applies
+	BatchSize     int           `yaml:"batch_size"`     // Searches written per transaction
+	FlushInterval time.Duration `yaml:"flush_interval"` // Max time a search waits to be written
+	Overflow      string        `yaml:"overflow"`       // "drop" or "block" when the queue is full
+	BlockTimeout  time.Duration `yaml:"block_timeout"`  // How long "block" waits before dropping
+}
+
+// Public, tiny struct that contains scheduled backup configs
+// Scheduled backups are disabled when Interval is zero
+type Backup struct {
+	Dir      string        `yaml:"dir"`      // Defaults to <database dir>/backups
+	Interval time.Duration `yaml:"interval"` // How often a backup is taken
+	Keep     int           `yaml:"keep"`     // Number of backups to keep
+}
+
+// Public, tiny struct that contains search history retention configs
+// Pruning is disabled when both MaxAge and MaxRows are zero
+type Retention struct {
+	MaxAge           time.Duration `yaml:"max_age"`           // Delete history older than this
+	MaxRows          int           `yaml:"max_rows"`          // Keep at most this many rows
+	BatchSize        int           `yaml:"batch_size"`        // Rows deleted per transaction
+	BatchPause       time.Duration `yaml:"batch_pause"`       // Pause between batches
+	Rollup           bool          `yaml:"rollup"`            // Aggregate into daily totals before deleting
+	Interval         time.Duration `yaml:"interval"`          // How often pruning runs
+	OptimizeInterval time.Duration `yaml:"optimize_interval"` // How often vacuum/optimize runs
+}
+
+// Public, tiny struct that contains search cache configs
+type Cache struct {
+	MaxEntries int           `yaml:"max_entries"`
+	TTL        time.Duration `yaml:"ttl"`         // TTL for non-empty search results
+	EmptyTTL   time.Duration `yaml:"empty_ttl"`   // TTL for searches with zero results
+	ErrorTTL   time.Duration `yaml:"error_ttl"`   // TTL for negative cache entries (0 disables)
+	ErrorCodes []string      `yaml:"error_codes"` // AppError codes that get negative cached
+	Warmer     CacheWarmer   `yaml:"warmer"`
+}
+
+// Public, tiny struct that contains cache warmer configs
+// The warmer pre-fetches the most frequent queries from search history
+type CacheWarmer struct {
+	Enabled      bool          `yaml:"enabled"`
+	TopN         int           `yaml:"top_n"`         // How many popular queries to keep warm
+	Window       time.Duration `yaml:"window"`        // How far back to look in search history
+	Interval     time.Duration `yaml:"interval"`      // How often the warmer runs
+	RefreshAhead time.Duration `yaml:"refresh_ahead"` // Refresh entries expiring within this window
+	QuotaPerRun  int           `yaml:"quota_per_run"` // Max upstream API calls per run
+}
+
+// Public, tiny struct that contains subscriptions feed configs
+type Subscriptions struct {
+	Source       string        `yaml:"source"`        // "rss" or "api"
+	PollInterval time.Duration `yaml:"poll_interval"` // How often followed channels are polled
+}
+
+// Public, tiny struct that contains saved search refresh configs
+type SavedSearches struct {
+	Interval     time.Duration `yaml:"interval"`      // How often the refresher looks for due searches
+	RefreshEvery time.Duration `yaml:"refresh_every"` // Re-run a search once its last run is this old
+	CallsPerRun  int           `yaml:"calls_per_run"` // Max search calls per run (100 quota units each)
+}
+
+// Public, tiny struct that contains outbound webhook configs
+// Webhooks are disabled when Endpoints is empty
+type Webhooks struct {
+	Endpoints   []WebhookEndpoint `yaml:"endpoints"`
+	Interval    time.Duration     `yaml:"interval"`     // How often queued deliveries are sent
+	MaxAttempts int               `yaml:"max_attempts"` // Attempts before a delivery is given up
+	Backoff     time.Duration     `yaml:"backoff"`      // Wait before the first retry, doubled after each failure
+	MaxBackoff  time.Duration     `yaml:"max_backoff"`  // Cap on the wait between attempts
+	Keep        time.Duration     `yaml:"keep"`         // How long finished deliveries stay in the log
+}
+
+// Public, tiny struct that describes a webhook receiver
+// URL and Secret may reference env vars as ${VAR}
+type WebhookEndpoint struct {
+	Name   string   `yaml:"name"` // Identifies the endpoint in the delivery log
+	URL    string   `yaml:"url"`
+	Secret string   `yaml:"secret"` // Signs payloads with HMAC-SHA256 when set
+	Format string   `yaml:"format"` // "json" or "slack"
+	Events []string `yaml:"events"` // Event types to send; empty for all
+}
+
+// Webhook payload formats
+const (
+	WebhookFormatJSON  = "json"
+	WebhookFormatSlack = "slack"
+)
+
+// webhookEvents are the event types an endpoint can subscribe to
+var webhookEvents = []string{"saved_search.new_videos", "subscription.new_videos"}
+
+// Public, tiny struct that contains email digest configs
+// Digests are disabled unless Enabled is set
+type Digest struct {
+	Enabled   bool          `yaml:"enabled"`
+	Schedule  string        `yaml:"schedule"`    // "daily" or "weekly"
+	Interval  time.Duration `yaml:"interval"`    // How often the job checks whether a digest is due
+	SendEmpty bool          `yaml:"send_empty"`  // Send even when nothing new was found
+	From      string        `yaml:"from"`        // Sender address, e.g. "zentube <me@example.com>"
+	To        []string      `yaml:"to"`          // Recipient addresses
+	DryRun    bool          `yaml:"dry_run"`     // Write .eml files instead of sending
+	DryRunDir string        `yaml:"dry_run_dir"` // Defaults to <database dir>/digests
+	SMTP      SMTP          `yaml:"smtp"`
+}
+
+// Public, tiny struct that contains SMTP server configs
+type SMTP struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"` // No authentication when empty
+	Password string `yaml:"password"` // Prefer the SMTP_PASSWORD env var
+	Security string `yaml:"security"` // "starttls" (required), "tls" or "none" (localhost relays only)
+}
+
+// Digest schedules
+const (
+	DigestDaily  = "daily"
+	DigestWeekly = "weekly"
+)
+
+// Every returns the time between digests
+func (d Digest) Every() time.Duration {
+	if d.Schedule == DigestWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Public, tiny struct that contains viewing budget configs
+// Daily and Weekly apply to the default profile; Profiles adds named profiles with their own limits,
+// picked per browser with the zentube_profile cookie or per request with the X-Zentube-Profile header
+// Each budget is disabled when zero
+type Budget struct {
+	Daily        time.Duration           `yaml:"daily"`         // Watch time allowed per day
+	Weekly       time.Duration           `yaml:"weekly"`        // Watch time allowed per week, starting Monday
+	FocusSession time.Duration           `yaml:"focus_session"` // Length of a focus session; 0 hides the timer
+	Profiles     map[string]BudgetLimits `yaml:"profiles"`      // Named profiles and their budgets
+}
+
+// Public, tiny struct that contains the budgets of one profile
+type BudgetLimits struct {
+	Daily  time.Duration `yaml:"daily"`
+	Weekly time.Duration `yaml:"weekly"`
+}
+
+// Enabled returns true if any profile has a daily or weekly budget configured
+func (b Budget) Enabled() bool {
+	if b.Daily > 0 || b.Weekly > 0 {
+		return true
+	}
+	for _, limits := range b.Profiles {
+		if limits.Daily > 0 || limits.Weekly > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// validProfileName reports whether name is 1 to 32 lowercase letters, digits, '-' or '_'
+func validProfileName(name string) bool {
+	if name == "" || len(name) > 32 {
+		return false
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return false
+		}
+	}
+	return true
+}
+
+// Public, tiny struct that contains syndication configs
+// Atom and RSS feeds of saved searches and collections need app.base_url for their links,
+// since request headers can't be trusted for a body that readers cache
+type Syndication struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Public, tiny struct that contains search intent configs
+// When enabled the search form asks why you are searching, and results ask whether you found it
+type Intents struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// Public, tiny struct that contains admin configs
+// Admin routes are disabled when Token is empty
+type Admin struct {
+	Token string `yaml:"token"`
 }
 
 // Full app config
 type Config struct {
-	App      App      `yaml:"app"`
-	YouTube  YouTube  `yaml:"youtube"`
-	Database Database `yaml:"database"`
+	App           App           `yaml:"app"`
+	YouTube       YouTube       `yaml:"youtube"`
+	Database      Database      `yaml:"database"`
+	Cache         Cache         `yaml:"cache"`
+	Subscriptions Subscriptions `yaml:"subscriptions"`
+	SavedSearches SavedSearches `yaml:"saved_searches"`
+	Webhooks      Webhooks      `yaml:"webhooks"`
+	Digest        Digest        `yaml:"digest"`
+	Budget        Budget        `yaml:"budget"`
+	Syndication   Syndication   `yaml:"syndication"`
+	Intents       Intents       `yaml:"intents"`
+	Admin         Admin         `yaml:"admin"`
 }
 
 // GetEnvironment returns the current environment from APP_ENV or defaults to development
@@ -111,9 +333,143 @@ func LoadConfig(baseFile string) (*Config, error) {
 	// Set environment from APP_ENV
 	config.App.Environment = env
 
+	config.applyDefaults()
+
 	return &config, nil
 }
 
+// applyDefaults fills optional settings that were omitted from the YAML file
+func (c *Config) applyDefaults() {
+	if c.Cache.MaxEntries == 0 {
+		c.Cache.MaxEntries = 1000
+	}
+	if c.Cache.TTL == 0 {
+		c.Cache.TTL = 5 * time.Minute
+	}
+	if c.Cache.EmptyTTL == 0 {
+		c.Cache.EmptyTTL = c.Cache.TTL
+	}
+	if c.Cache.ErrorCodes == nil {
+		c.Cache.ErrorCodes = []string{"BAD_REQUEST"}
+	}
+
+	if c.Database.Backend == "" {
+		c.Database.Backend = BackendSQLite
+	}
+
+	ret := &c.Database.Retention
+	if ret.BatchSize == 0 {
+		ret.BatchSize = 500
+	}
+	if ret.BatchPause == 0 {
+		ret.BatchPause = 50 * time.Millisecond
+	}
+	if ret.Interval == 0 {
+		ret.Interval = time.Hour
+	}
+	if ret.OptimizeInterval == 0 {
+		ret.OptimizeInterval = 24 * time.Hour
+	}
+
+	if c.Database.Backup.Dir == "" && c.Database.Path != "" {
+		c.Database.Backup.Dir = filepath.Join(filepath.Dir(c.Database.Path), "backups")
+	}
+	if c.Database.Backup.Keep == 0 {
+		c.Database.Backup.Keep = 7
+	}
+
+	hw := &c.Database.HistoryWriter
+	if hw.QueueSize == 0 {
+		hw.QueueSize = 1024
+	}
+	if hw.BatchSize == 0 {
+		hw.BatchSize = 100
+	}
+	if hw.FlushInterval == 0 {
+		hw.FlushInterval = time.Second
+	}
+	if hw.Overflow == "" {
+		hw.Overflow = "drop"
+	}
+	if hw.BlockTimeout == 0 {
+		hw.BlockTimeout = 100 * time.Millisecond
+	}
+
+	w := &c.Cache.Warmer
+	if w.TopN == 0 {
+		w.TopN = 10
+	}
+	if w.Window == 0 {
+		w.Window = 7 * 24 * time.Hour
+	}
+	if w.Interval == 0 {
+		w.Interval = c.Cache.TTL / 2
+	}
+	if w.RefreshAhead == 0 {
+		w.RefreshAhead = c.Cache.TTL / 2
+	}
+	if w.QuotaPerRun == 0 {
+		w.QuotaPerRun = w.TopN
+	}
+
+	if c.Subscriptions.Source == "" {
+		c.Subscriptions.Source = SourceRSS
+	}
+	if c.Subscriptions.PollInterval == 0 {
+		c.Subscriptions.PollInterval = 30 * time.Minute
+	}
+
+	ss := &c.SavedSearches
+	if ss.Interval == 0 {
+		ss.Interval = 15 * time.Minute
+	}
+	if ss.RefreshEvery == 0 {
+		ss.RefreshEvery = 24 * time.Hour
+	}
+	if ss.CallsPerRun == 0 {
+		ss.CallsPerRun = 5
+	}
+
+	wh := &c.Webhooks
+	if wh.Interval == 0 {
+		wh.Interval = 30 * time.Second
+	}
+	if wh.MaxAttempts == 0 {
+		wh.MaxAttempts = 5
+	}
+	if wh.Backoff == 0 {
+		wh.Backoff = time.Minute
+	}
+	if wh.MaxBackoff == 0 {
+		wh.MaxBackoff = time.Hour
+	}
+	if wh.Keep == 0 {
+		wh.Keep = 30 * 24 * time.Hour
+	}
+	for i := range wh.Endpoints {
+		if wh.Endpoints[i].Format == "" {
+			wh.Endpoints[i].Format = WebhookFormatJSON
+		}
+	}
+
+	dg := &c.Digest
+	if dg.Schedule == "" {
+		dg.Schedule = DigestDaily
+	}
+	if dg.Interval == 0 {
+		dg.Interval = time.Hour
+	}
+	if dg.DryRunDir == "" && c.Database.Path != "" {
+		dg.DryRunDir = filepath.Join(filepath.Dir(c.Database.Path), "digests")
+	}
+	if dg.SMTP.Port == 0 {
+		dg.SMTP.Port = 587
+	}
+	if dg.SMTP.Security == "" {
+		dg.SMTP.Security = "starttls"
+	}
+}
+
 // Inject secret from env var into the struct
 func InjectEnvVariables(config *Config) error {
 	apiKey := os.Getenv("YOUTUBE_API_KEY")
@@ -121,6 +477,24 @@ func InjectEnvVariables(config *Config) error {
 		return errors.New("YOUTUBE_API_KEY environment variable not set")
 	}
 	config.YouTube.APIKey = apiKey
+
+	// Optional: admin routes stay disabled without a token
+	if adminToken := os.Getenv("ADMIN_TOKEN"); adminToken != "" {
+		config.Admin.Token = adminToken
+	}
+
+	// Optional: only needed when the SMTP server requires authentication
+	if smtpPassword := os.Getenv("SMTP_PASSWORD"); smtpPassword != "" {
+		config.Digest.SMTP.Password = smtpPassword
+	}
+
+	// Webhook URLs and secrets often embed tokens, so they can stay out of the YAML file
+	for i := range config.Webhooks.Endpoints {
+		e := &config.Webhooks.Endpoints[i]
+		e.URL = os.ExpandEnv(e.URL)
+		e.Secret = os.ExpandEnv(e.Secret)
+	}
+
 	return nil
 }
 
@@ -135,6 +509,14 @@ func (c *Config) Validate() error {
 	if c.App.Port < 1 || c.App.Port > 65535 {
 		errs = append(errs, fmt.Errorf("app.port must be between 1 and 65535, got %d", c.App.Port))
 	}
+	if c.App.BaseURL != "" {
+		if u, err := url.Parse(c.App.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("app.base_url must be an absolute http(s) URL, got %q", c.App.BaseURL))
+		}
+	}
+	if c.Syndication.Enabled && c.App.BaseURL == "" {
+		errs = append(errs, errors.New("app.base_url is required when syndication.enabled is set"))
+	}
 
 	// Validate YouTube config
 	if c.YouTube.APIKey == "" {
@@ -145,8 +527,188 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate Database config
-	if c.Database.Path == "" {
-		errs = append(errs, errors.New("database.path cannot be empty"))
+	switch c.Database.Backend {
+	case BackendSQLite:
+		if c.Database.Path == "" {
+			errs = append(errs, errors.New("database.path cannot be empty"))
+		}
+	case BackendMemory:
+	default:
+		errs = append(errs, fmt.Errorf("database.backend must be sqlite or memory, got %q", c.Database.Backend))
+	}
+
+	if ret := c.Database.Retention; ret.Enabled() {
+		if ret.MaxAge < 0 || ret.MaxRows < 0 {
+			errs = append(errs, errors.New("database.retention.max_age and max_rows cannot be negative"))
+		}
+		if ret.BatchSize < 1 {
+			errs = append(errs, fmt.Errorf("database.retention.batch_size must be at least 1, got %d", ret.BatchSize))
+		}
+		if ret.Interval <= 0 {
+			errs = append(errs, fmt.Errorf("database.retention.interval must be positive, got %s", ret.Interval))
+		}
+	}
+
+	if c.Database.Backup.Interval < 0 {
+		errs = append(errs, fmt.Errorf("database.backup.interval cannot be negative, got %s", c.Database.Backup.Interval))
+	}
+	if c.Database.Backup.Keep < 1 {
+		errs = append(errs, fmt.Errorf("database.backup.keep must be at least 1, got %d", c.Database.Backup.Keep))
+	}
+
+	if hw := c.Database.HistoryWriter; hw.QueueSize < 1 || hw.BatchSize < 1 || hw.FlushInterval <= 0 {
+		errs = append(errs, errors.New("database.history_writer.queue_size, batch_size and flush_interval must be positive"))
+	}
+	if o := c.Database.HistoryWriter.Overflow; o != "drop" && o != "block" {
+		errs = append(errs, fmt.Errorf("database.history_writer.overflow must be drop or block, got %q", o))
+	}
+
+	// Validate Cache config
+	if c.Cache.MaxEntries < 0 {
+		errs = append(errs, fmt.Errorf("cache.max_entries cannot be negative, got %d", c.Cache.MaxEntries))
+	}
+	if c.Cache.TTL <= 0 {
+		errs = append(errs, fmt.Errorf("cache.ttl must be positive, got %s", c.Cache.TTL))
+	}
+	if c.Cache.EmptyTTL <= 0 {
+		errs = append(errs, fmt.Errorf("cache.empty_ttl must be positive, got %s", c.Cache.EmptyTTL))
+	}
+	if c.Cache.ErrorTTL < 0 {
+		errs = append(errs, fmt.Errorf("cache.error_ttl cannot be negative, got %s", c.Cache.ErrorTTL))
+	}
+	if w := c.Cache.Warmer; w.Enabled {
+		if w.TopN < 1 {
+			errs = append(errs, fmt.Errorf("cache.warmer.top_n must be at least 1, got %d", w.TopN))
+		}
+		if w.Interval <= 0 || w.Interval >= c.Cache.TTL {
+			errs = append(errs, fmt.Errorf("cache.warmer.interval must be positive and shorter than cache.ttl, got %s", w.Interval))
+		}
+		if w.RefreshAhead < w.Interval {
+			errs = append(errs, fmt.Errorf("cache.warmer.refresh_ahead must be at least cache.warmer.interval, got %s", w.RefreshAhead))
+		}
+		if w.QuotaPerRun < 0 {
+			errs = append(errs, fmt.Errorf("cache.warmer.quota_per_run cannot be negative, got %d", w.QuotaPerRun))
+		}
+	}
+
+	// Validate Subscriptions config
+	switch c.Subscriptions.Source {
+	case SourceRSS, SourceAPI:
+	default:
+		errs = append(errs, fmt.Errorf("subscriptions.source must be rss or api, got %q", c.Subscriptions.Source))
+	}
+	if c.Subscriptions.PollInterval < time.Minute {
+		errs = append(errs, fmt.Errorf("subscriptions.poll_interval must be at least 1m, got %s", c.Subscriptions.PollInterval))
+	}
+
+	// Validate SavedSearches config
+	ss := c.SavedSearches
+	if ss.Interval < time.Minute {
+		errs = append(errs, fmt.Errorf("saved_searches.interval must be at least 1m, got %s", ss.Interval))
+	}
+	if ss.RefreshEvery < ss.Interval {
+		errs = append(errs, fmt.Errorf("saved_searches.refresh_every must be at least saved_searches.interval, got %s", ss.RefreshEvery))
+	}
+	if ss.CallsPerRun < 0 {
+		errs = append(errs, fmt.Errorf("saved_searches.calls_per_run cannot be negative, got %d", ss.CallsPerRun))
+	}
+
+	// Validate Webhooks config
+	wh := c.Webhooks
+	names := make(map[string]bool, len(wh.Endpoints))
+	for i, e := range wh.Endpoints {
+		if e.Name == "" {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].name cannot be empty", i))
+		} else if names[e.Name] {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].name %q is used twice", i, e.Name))
+		}
+		names[e.Name] = true
+		// The URL may carry a token, so it is never echoed back
+		if u, err := url.Parse(e.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].url must be an absolute http(s) URL", i))
+		}
+		if e.Format != WebhookFormatJSON && e.Format != WebhookFormatSlack {
+			errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].format must be json or slack, got %q", i, e.Format))
+		}
+		for _, event := range e.Events {
+			if !slices.Contains(webhookEvents, event) {
+				errs = append(errs, fmt.Errorf("webhooks.endpoints[%d].events: unknown event %q", i, event))
+			}
+		}
+	}
+	if wh.Interval < time.Second {
+		errs = append(errs, fmt.Errorf("webhooks.interval must be at least 1s, got %s", wh.Interval))
+	}
+	if wh.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("webhooks.max_attempts must be at least 1, got %d", wh.MaxAttempts))
+	}
+	if wh.Backoff <= 0 || wh.MaxBackoff < wh.Backoff {
+		errs = append(errs, fmt.Errorf("webhooks.backoff must be positive and at most webhooks.max_backoff, got %s", wh.Backoff))
+	}
+	if wh.Keep < 0 {
+		errs = append(errs, fmt.Errorf("webhooks.keep cannot be negative, got %s", wh.Keep))
+	}
+
+	// Validate Digest config
+	if dg := c.Digest; dg.Enabled {
+		if dg.Schedule != DigestDaily && dg.Schedule != DigestWeekly {
+			errs = append(errs, fmt.Errorf("digest.schedule must be daily or weekly, got %q", dg.Schedule))
+		}
+		if dg.Interval < time.Minute || dg.Interval > dg.Every() {
+			errs = append(errs, fmt.Errorf("digest.interval must be between 1m and the schedule's period, got %s", dg.Interval))
+		}
+		if _, err := mail.ParseAddress(dg.From); err != nil {
+			errs = append(errs, fmt.Errorf("digest.from must be an email address, got %q", dg.From))
+		}
+		if len(dg.To) == 0 {
+			errs = append(errs, errors.New("digest.to needs at least one recipient"))
+		}
+		for i, to := range dg.To {
+			if _, err := mail.ParseAddress(to); err != nil {
+				errs = append(errs, fmt.Errorf("digest.to[%d] must be an email address, got %q", i, to))
+			}
+		}
+		if dg.DryRun {
+			if dg.DryRunDir == "" {
+				errs = append(errs, errors.New("digest.dry_run_dir cannot be empty with dry_run"))
+			}
+		} else {
+			if dg.SMTP.Host == "" {
+				errs = append(errs, errors.New("digest.smtp.host cannot be empty unless dry_run is set"))
+			}
+			if dg.SMTP.Port < 1 || dg.SMTP.Port > 65535 {
+				errs = append(errs, fmt.Errorf("digest.smtp.port must be between 1 and 65535, got %d", dg.SMTP.Port))
+			}
+			switch dg.SMTP.Security {
+			case "starttls", "tls", "none":
+			default:
+				errs = append(errs, fmt.Errorf("digest.smtp.security must be starttls, tls or none, got %q", dg.SMTP.Security))
+			}
+		}
+	}
+
+	// Validate Budget config
+	if c.Budget.Daily < 0 || c.Budget.Weekly < 0 {
+		errs = append(errs, errors.New("budget.daily and budget.weekly cannot be negative"))
+	}
+	if c.Budget.FocusSession != 0 && (c.Budget.FocusSession < time.Minute || c.Budget.FocusSession > 8*time.Hour) {
+		errs = append(errs, fmt.Errorf("budget.focus_session must be between 1m and 8h, got %s", c.Budget.FocusSession))
+	}
+	for name, limits := range c.Budget.Profiles {
+		switch {
+		case name == "default":
+			errs = append(errs, errors.New("budget.profiles cannot redefine the default profile, use budget.daily and budget.weekly"))
+		case !validProfileName(name):
+			errs = append(errs, fmt.Errorf("budget.profiles name %q must be 1 to 32 lowercase letters, digits, '-' or '_'", name))
+		}
+		if limits.Daily < 0 || limits.Weekly < 0 {
+			errs = append(errs, fmt.Errorf("budget.profiles.%s daily and weekly cannot be negative", name))
+		}
+	}
+
+	// Validate Admin config
+	if c.Admin.Token != "" && len(c.Admin.Token) < 16 {
+		errs = append(errs, errors.New("admin.token must be at least 16 characters"))
 	}
 
 	if len(errs) > 0 {
@@ -156,6 +718,21 @@ func (c *Config) Validate() error {
 	return nil
 }
 
+// Enabled returns true if any retention limit is configured
+func (r Retention) Enabled() bool {
+	return r.MaxAge != 0 || r.MaxRows != 0
+}
+
+// WebhooksEnabled returns true if any webhook endpoint is configured
+func (c *Config) WebhooksEnabled() bool {
+	return len(c.Webhooks.Endpoints) > 0
+}
+
+// AdminEnabled returns true if admin routes should be registered
+func (c *Config) AdminEnabled() bool {
+	return c.Admin.Token != ""
+}
+
 // IsDevelopment returns true if running in development environment
 func (c *Config) IsDevelopment() bool {
 	return c.App.Environment == Development
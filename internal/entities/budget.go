@@ -0,0 +1,44 @@
+package entities
+
+import "time"
+
+// Budget periods
+const (
+	BudgetDaily  = "daily"
+	BudgetWeekly = "weekly"
+)
+
+// Budget is a limit on watch time over a period, and how much of it is used
+type Budget struct {
+	Period   string // BudgetDaily or BudgetWeekly
+	Limit    time.Duration
+	Used     time.Duration // Watched in the player since the period started
+	ResetsAt time.Time     // When the next period starts
+}
+
+// Remaining returns the watch time left in the period
+func (b Budget) Remaining() time.Duration {
+	return max(b.Limit-b.Used, 0)
+}
+
+// Exhausted reports whether the budget is used up
+func (b Budget) Exhausted() bool {
+	return b.Used >= b.Limit
+}
+
+// BudgetStatus is the state of every configured budget
+type BudgetStatus struct {
+	Profile string   // Whose watch time is counted
+	Budgets []Budget // Daily first; empty when no budget is configured
+}
+
+// Exhausted returns the used-up budget that resets last, nil if none is used up
+func (s BudgetStatus) Exhausted() *Budget {
+	var exhausted *Budget
+	for i, b := range s.Budgets {
+		if b.Exhausted() && (exhausted == nil || b.ResetsAt.After(exhausted.ResetsAt)) {
+			exhausted = &s.Budgets[i]
+		}
+	}
+	return exhausted
+}
@@ -0,0 +1,22 @@
+package entities
+
+import "time"
+
+// Collection is a named, ordered list of videos with notes (e.g. a learning path)
+type Collection struct {
+	ID          int64
+	Name        string
+	Description string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ItemCount   int              // Set by listings, which leave Items empty
+	Items       []CollectionItem // In position order
+}
+
+// CollectionItem is a video in a collection
+type CollectionItem struct {
+	Video
+	Position int // 1-based place in the collection
+	Note     string
+	AddedAt  time.Time
+}
@@ -0,0 +1,39 @@
+package entities
+
+import "time"
+
+// Digest collects the videos found over a period for an email summary
+type Digest struct {
+	Since    time.Time // Start of the period (exclusive)
+	Until    time.Time // End of the period (inclusive)
+	Channels []DigestChannel
+	Searches []DigestSearch
+}
+
+// DigestChannel is a followed channel's uploads in a digest
+type DigestChannel struct {
+	Channel
+	Videos []Video
+	More   int // Uploads left out to keep the email short
+}
+
+// DigestSearch is a saved search's new matches in a digest
+type DigestSearch struct {
+	ID     int64
+	Name   string
+	Videos []Video
+	More   int // Matches left out to keep the email short
+}
+
+// VideoCount returns how many videos the digest covers, including those left out
+// A video found by both a channel and a search counts twice
+func (d Digest) VideoCount() int {
+	n := 0
+	for _, c := range d.Channels {
+		n += len(c.Videos) + c.More
+	}
+	for _, s := range d.Searches {
+		n += len(s.Videos) + s.More
+	}
+	return n
+}
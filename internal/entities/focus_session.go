@@ -0,0 +1,28 @@
+package entities
+
+import "time"
+
+// FocusSession is a timed stretch of viewing that ends with a short reflection
+type FocusSession struct {
+	ID         int64
+	Profile    string
+	StartedAt  time.Time
+	EndsAt     time.Time
+	EndedAt    time.Time // Zero until the reflection is recorded (or the session is abandoned)
+	Reflection string
+}
+
+// Ended reports whether the session was closed
+func (s FocusSession) Ended() bool {
+	return !s.EndedAt.IsZero()
+}
+
+// Remaining returns the time left on the timer at now
+func (s FocusSession) Remaining(now time.Time) time.Duration {
+	return max(s.EndsAt.Sub(now), 0)
+}
+
+// Due reports whether the timer ran out and the reflection is still missing
+func (s FocusSession) Due(now time.Time) bool {
+	return !s.Ended() && !now.Before(s.EndsAt)
+}
@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// QueryStats aggregates every search for one query
+type QueryStats struct {
+	Query       string `db:"query"`
+	Searches    int    `db:"searches"`
+	ZeroResults int    `db:"zero_results"` // Searches that returned nothing
+	CacheHits   int    `db:"cache_hits"`   // Searches answered from the cache
+}
+
+// DailySearchStats totals the searches of one (UTC) day
+type DailySearchStats struct {
+	Day         time.Time `db:"day"`
+	Searches    int       `db:"searches"`
+	ZeroResults int       `db:"zero_results"`
+	CacheHits   int       `db:"cache_hits"`
+}
+
+// CacheHitRatio is the share of searches answered from the cache (0 without searches)
+func (d DailySearchStats) CacheHitRatio() float64 {
+	if d.Searches == 0 {
+		return 0
+	}
+	return float64(d.CacheHits) / float64(d.Searches)
+}
+
+// SearchInsights summarizes search activity over a window
+type SearchInsights struct {
+	Since             time.Time
+	Searches          int
+	CacheHits         int
+	TopQueries        []QueryStats       // Most searched first, grouped by normalized query
+	ZeroResultQueries []QueryStats       // Queries that returned nothing at least once
+	Daily             []DailySearchStats // One entry per day, oldest first, no gaps
+	Weekly            []DailySearchStats // One entry per week starting Monday, oldest first
+}
+
+// CacheHitRatio is the share of searches in the window answered from the cache
+func (s SearchInsights) CacheHitRatio() float64 {
+	return DailySearchStats{Searches: s.Searches, CacheHits: s.CacheHits}.CacheHitRatio()
+}
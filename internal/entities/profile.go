@@ -0,0 +1,23 @@
+package entities
+
+import "context"
+
+// DefaultProfile is the profile used when a request names none
+const DefaultProfile = "default"
+
+// profileKey is the context key of the request's profile
+type profileKey struct{}
+
+// WithProfile returns a context carrying the profile budgets, focus sessions
+// and viewings are recorded under
+func WithProfile(ctx context.Context, profile string) context.Context {
+	return context.WithValue(ctx, profileKey{}, profile)
+}
+
+// ProfileFromContext returns the profile set by WithProfile, or DefaultProfile
+func ProfileFromContext(ctx context.Context) string {
+	if profile, ok := ctx.Value(profileKey{}).(string); ok && profile != "" {
+		return profile
+	}
+	return DefaultProfile
+}
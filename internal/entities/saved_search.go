@@ -0,0 +1,30 @@
+package entities
+
+import "time"
+
+// SearchOptions are the parameters of a YouTube search
+type SearchOptions struct {
+	Query      string
+	MaxResults int64
+}
+
+// SavedSearch is a search that is re-run on a schedule to find new matches
+type SavedSearch struct {
+	ID              int64
+	Name            string
+	Options         SearchOptions
+	CreatedAt       time.Time
+	LastCheckedAt   time.Time // Last refresh attempt, zero until the first one
+	LastRefreshedAt time.Time // Last successful refresh, zero until the first one
+	LastError       string    // Error of the last attempt, empty if it succeeded
+	ResultCount     int       // Set by listings
+	NewCount        int       // Set by listings
+}
+
+// SavedSearchResult is a video a saved search has matched
+type SavedSearchResult struct {
+	Video
+	FirstSeenAt time.Time // Refresh that first returned it
+	LastSeenAt  time.Time // Latest refresh that returned it
+	New         bool      // Found after the first refresh and not marked seen yet
+}
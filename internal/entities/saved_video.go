@@ -0,0 +1,16 @@
+package entities
+
+import "time"
+
+// SavedVideo is a video on the watch-later list
+type SavedVideo struct {
+	Video
+	Position  int // 1-based place in the list
+	SavedAt   time.Time
+	WatchedAt time.Time // Zero until the video is marked watched
+}
+
+// Watched reports whether the video has been marked watched
+func (s SavedVideo) Watched() bool {
+	return !s.WatchedAt.IsZero()
+}
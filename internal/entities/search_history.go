@@ -6,5 +6,54 @@ type SearchHistory struct {
 	ID        int64     `db:"id"`
 	Query     string    `db:"query"`
 	Results   int       `db:"results"`
+	CacheHit  bool      `db:"cache_hit"` // Answered from the cache, not the YouTube API
+	IntentID  int64     `db:"intent_id"` // Search intent stated before searching, 0 if none
 	CreatedAt time.Time `db:"created_at"`
 }
+
+// SearchHistoryFilter narrows a search history listing
+// Zero values leave the corresponding bound open
+type SearchHistoryFilter struct {
+	From       time.Time // Created at or after
+	To         time.Time // Created before
+	Contains   string    // Case-insensitive substring of the query
+	MinResults int       // Minimum number of results
+}
+
+// SearchHistoryCursor marks the last row of a page (keyset pagination)
+// Listings are ordered newest first, ties broken by ID
+type SearchHistoryCursor struct {
+	CreatedAt time.Time
+	ID        int64
+}
+
+// SearchHistoryPage is one page of a search history listing
+type SearchHistoryPage struct {
+	Items      []SearchHistory
+	NextCursor string // Opaque cursor for the next page, empty on the last one
+}
+
+// QueryFrequency represents how often a query was searched
+type QueryFrequency struct {
+	Query string `db:"query"`
+	Count int    `db:"count"`
+}
+
+// SearchHistoryMatch is a full-text search hit on a past query, or on videos it returned
+type SearchHistoryMatch struct {
+	SearchHistory
+	Highlighted []TextSegment // Query text split into matched/unmatched parts
+	Videos      []VideoMatch  // Returned videos whose title or description matched
+}
+
+// VideoMatch is a catalog video matched by title or description
+type VideoMatch struct {
+	Video
+	HighlightedTitle []TextSegment
+}
+
+// TextSegment is a piece of highlighted text
+type TextSegment struct {
+	Text  string
+	Match bool
+}
@@ -0,0 +1,55 @@
+package entities
+
+import "time"
+
+// Search intent outcomes, answered with "did you find it?"
+const (
+	IntentFound    = "found"
+	IntentNotFound = "not_found"
+)
+
+// SearchIntent is why a searching session started, stated before the first search
+// Searches made for it and the videos opened from them carry its ID
+type SearchIntent struct {
+	ID        int64
+	Intent    string
+	CreatedAt time.Time
+	Outcome   string    // IntentFound, IntentNotFound, or empty until rated
+	RatedAt   time.Time // Zero until rated
+}
+
+// Rated reports whether "did you find it?" was answered
+func (i SearchIntent) Rated() bool {
+	return i.Outcome != ""
+}
+
+// IntentActivity is an intent with what searching for it led to
+type IntentActivity struct {
+	SearchIntent
+	Queries        []string // Distinct queries searched for it, first searched first
+	Searches       int
+	Videos         int // Viewings of videos those searches returned
+	WatchedSeconds int
+}
+
+// IntentOutcomeStats totals the intents that ended with one outcome
+type IntentOutcomeStats struct {
+	Outcome        string // IntentFound, IntentNotFound, or empty for unrated intents
+	Intents        int
+	Videos         int
+	WatchedSeconds int
+}
+
+// AverageSeconds returns the watch time per intent
+func (s IntentOutcomeStats) AverageSeconds() int {
+	if s.Intents == 0 {
+		return 0
+	}
+	return s.WatchedSeconds / s.Intents
+}
+
+// IntentReview correlates recent intents with the time spent on them
+type IntentReview struct {
+	Intents  []IntentActivity     // Most recent first
+	Outcomes []IntentOutcomeStats // Found, not found, then unrated
+}
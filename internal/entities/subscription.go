@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+// Channel identifies a YouTube channel
+type Channel struct {
+	ID    string // "UC" followed by 22 characters
+	Title string
+}
+
+// Subscription is a followed channel whose uploads show up in the feed
+type Subscription struct {
+	Channel
+	SubscribedAt time.Time
+	LastPolledAt time.Time // Zero until the first poll
+	LastError    string    // Error of the last poll, empty if it succeeded
+	UnseenCount  int       // Set by listings
+}
+
+// FeedItem is an upload from a followed channel
+type FeedItem struct {
+	Video
+	ChannelID string
+	FetchedAt time.Time // When a poll first found it
+	SeenAt    time.Time // Zero while unseen
+}
+
+// Seen reports whether the item has been marked as seen
+func (f FeedItem) Seen() bool {
+	return !f.SeenAt.IsZero()
+}
+
+// FeedFilter narrows a feed listing
+type FeedFilter struct {
+	UnseenOnly bool
+	ChannelID  string // Empty for every channel
+}
+
+// FeedCursor marks the last item of a page (keyset pagination)
+type FeedCursor struct {
+	PublishedAt time.Time
+	VideoID     string
+}
+
+// FeedPage is one page of the feed, newest uploads first
+type FeedPage struct {
+	Items      []FeedItem
+	NextCursor string // Opaque cursor for the next page, empty on the last one
+}
@@ -8,4 +8,5 @@ type Video struct {
 	Channel     string
 	PublishedAt time.Time
 	Thumbnail   string
+	Description string // May be truncated (search results) or empty (sources without one)
 }
@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+// Playback positions too close to either end of a video are not worth resuming
+const (
+	MinResumePosition = 10 // Seconds watched before a video counts as started
+	FinishedPercent   = 95 // Percent watched after which a video counts as finished
+)
+
+// VideoProgress is where playback of a video last stopped, across viewings
+type VideoProgress struct {
+	VideoID   string
+	Position  int // Seconds into the video
+	Duration  int // Length of the video in seconds
+	UpdatedAt time.Time
+}
+
+// Finished reports whether playback got to the end, or close enough to it
+func (p VideoProgress) Finished() bool {
+	return p.Duration > 0 && p.Position*100 >= p.Duration*FinishedPercent
+}
+
+// Partial reports whether the video was started but not finished
+func (p VideoProgress) Partial() bool {
+	return p.Position >= MinResumePosition && !p.Finished()
+}
+
+// ResumeAt returns the position to reopen the video at, 0 to start over
+func (p VideoProgress) ResumeAt() int {
+	if !p.Partial() {
+		return 0
+	}
+	return p.Position
+}
+
+// Percent returns how much of the video was watched, from 0 to 100
+func (p VideoProgress) Percent() int {
+	if p.Duration <= 0 {
+		return 0
+	}
+	return min(p.Position*100/p.Duration, 100)
+}
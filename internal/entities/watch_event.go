@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+// WatchEvent is one viewing of a video in the player, from open to close
+// The player reports progress while it is open; watched seconds only count
+// time spent playing, not time the modal sat paused or in a background tab
+type WatchEvent struct {
+	ID             int64
+	Profile        string // Whose watch time the viewing counts against
+	VideoID        string
+	Title          string
+	StartedAt      time.Time
+	UpdatedAt      time.Time // Last open, progress or close event
+	ClosedAt       time.Time // Zero while open, or if the page went away without a close
+	WatchedSeconds int
+	SearchID       int64  // Latest past search that returned the video, 0 if none
+	SearchQuery    string // Query of SearchID
+	IntentID       int64  // Search intent of SearchID when it was opened, 0 if none
+}
+
+// Closed reports whether the player sent a close event
+func (e WatchEvent) Closed() bool {
+	return !e.ClosedAt.IsZero()
+}
+
+// WatchProgress is a progress or close event for an open viewing
+type WatchProgress struct {
+	At             time.Time
+	WatchedSeconds int  // Total for the viewing so far, not a delta
+	Closed         bool // The player was closed
+}
@@ -0,0 +1,51 @@
+package entities
+
+import "time"
+
+// Webhook event types
+const (
+	EventSavedSearchNewVideos  = "saved_search.new_videos"
+	EventSubscriptionNewVideos = "subscription.new_videos"
+)
+
+// NewVideosEvent reports videos a saved search or followed channel has just found
+type NewVideosEvent struct {
+	Type       string // EventSavedSearchNewVideos or EventSubscriptionNewVideos
+	SourceID   string // Saved search ID or channel ID
+	SourceName string // Saved search name or channel title
+	SourcePath string // App page of the source, e.g. /saved-searches/3 (empty if it has none)
+	Videos     []Video
+	OccurredAt time.Time
+}
+
+// WebhookStatus is the state of a webhook delivery
+type WebhookStatus string
+
+// Webhook delivery states
+const (
+	WebhookPending   WebhookStatus = "pending"   // Waiting for its first or next attempt
+	WebhookDelivered WebhookStatus = "delivered" // The receiver answered 2xx
+	WebhookFailed    WebhookStatus = "failed"    // Out of attempts, or the endpoint is gone
+)
+
+// WebhookDelivery is an event queued for POSTing to a configured endpoint
+type WebhookDelivery struct {
+	ID            int64
+	Endpoint      string // Name of the configured endpoint (its URL may hold a token, so it isn't stored)
+	Event         string
+	Payload       []byte // Request body, already in the endpoint's format
+	Status        WebhookStatus
+	Attempts      int
+	NextAttemptAt time.Time // When a pending delivery is due
+	LastAttemptAt time.Time // Zero until the first attempt
+	LastError     string    // Error of the last attempt, empty if it succeeded
+	CreatedAt     time.Time
+}
+
+// WebhookAttempt is the outcome of one delivery attempt
+type WebhookAttempt struct {
+	AttemptedAt   time.Time
+	Status        WebhookStatus
+	NextAttemptAt time.Time // Next try when Status is still pending
+	Error         string
+}
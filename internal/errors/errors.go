@@ -34,14 +34,15 @@ func (e *AppError) Unwrap() error {
 
 // Common error codes for consistent error handling across the application
 const (
-	ErrCodeValidation     = "VALIDATION_ERROR"
-	ErrCodeNotFound       = "NOT_FOUND"
-	ErrCodeUnauthorized   = "UNAUTHORIZED"
-	ErrCodeForbidden      = "FORBIDDEN"
-	ErrCodeRateLimited    = "RATE_LIMITED"
-	ErrCodeInternal       = "INTERNAL_ERROR"
-	ErrCodeServiceUnavail = "SERVICE_UNAVAILABLE"
-	ErrCodeBadRequest     = "BAD_REQUEST"
+	ErrCodeValidation      = "VALIDATION_ERROR"
+	ErrCodeNotFound        = "NOT_FOUND"
+	ErrCodeUnauthorized    = "UNAUTHORIZED"
+	ErrCodeForbidden       = "FORBIDDEN"
+	ErrCodeRateLimited     = "RATE_LIMITED"
+	ErrCodeInternal        = "INTERNAL_ERROR"
+	ErrCodeServiceUnavail  = "SERVICE_UNAVAILABLE"
+	ErrCodeBadRequest      = "BAD_REQUEST"
+	ErrCodeBudgetExhausted = "BUDGET_EXHAUSTED"
 )
 
 // Pre-defined error constructors for common scenarios
@@ -57,6 +58,17 @@ func NewValidationError(message string, err error) *AppError {
 	}
 }
 
+// NewBadRequestError creates a bad request error (400)
+// Use when a request is well-formed but rejected (e.g. by an upstream API)
+func NewBadRequestError(message string, err error) *AppError {
+	return &AppError{
+		Code:       ErrCodeBadRequest,
+		Message:    message,
+		StatusCode: http.StatusBadRequest,
+		Err:        err,
+	}
+}
+
 // NewNotFoundError creates a not found error (404)
 // Use when a requested resource doesn't exist
 func NewNotFoundError(resource string) *AppError {
@@ -101,6 +113,17 @@ func NewRateLimitError(retryAfter int) *AppError {
 	}
 }
 
+// NewBudgetExhaustedError creates a viewing budget error (429)
+// Use when the configured watch time for the day or week is used up
+func NewBudgetExhaustedError(message string) *AppError {
+	return &AppError{
+		Code:       ErrCodeBudgetExhausted,
+		Message:    message,
+		StatusCode: http.StatusTooManyRequests,
+		Err:        nil,
+	}
+}
+
 // NewInternalError creates an internal server error (500)
 // Use when an unexpected error occurs
 func NewInternalError(message string, err error) *AppError {
@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// BackupStore creates and rotates database backups
+type BackupStore interface {
+	// CreateBackup writes a new consistent backup and returns its location
+	CreateBackup(ctx context.Context) (string, error)
+	// PruneBackups deletes all but the newest keep backups and returns what was removed
+	PruneBackups(keep int) ([]string, error)
+}
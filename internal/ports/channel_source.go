@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// ChannelSource reads channels and their uploads from YouTube
+type ChannelSource interface {
+	// LookupChannel resolves a channel ID or @handle
+	// NotFound AppError if there is no such channel
+	LookupChannel(ctx context.Context, ref string) (*entities.Channel, error)
+	// RecentUploads returns a channel's latest uploads, newest first
+	RecentUploads(ctx context.Context, channelID string) ([]entities.Video, error)
+}
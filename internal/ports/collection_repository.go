@@ -0,0 +1,36 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// CollectionRepository stores named collections of videos
+// Names are unique; item positions are 1-based and kept contiguous
+type CollectionRepository interface {
+	// CreateCollection saves a collection and its items in one transaction and sets its ID
+	// Validation AppError if the name is taken
+	CreateCollection(ctx context.Context, collection *entities.Collection) error
+	// ListCollections returns every collection by name, with ItemCount set and no items
+	ListCollections(ctx context.Context) ([]entities.Collection, error)
+	// GetCollection returns a collection with its items (NotFound AppError if it doesn't exist)
+	GetCollection(ctx context.Context, id int64) (*entities.Collection, error)
+	// UpdateCollection renames a collection and replaces its description
+	// NotFound AppError if it doesn't exist, Validation AppError if the name is taken
+	UpdateCollection(ctx context.Context, id int64, name, description string, updatedAt time.Time) error
+	// DeleteCollection removes a collection and its items (NotFound AppError if it doesn't exist)
+	DeleteCollection(ctx context.Context, id int64) error
+
+	// AddCollectionItem appends a video and returns it; a video already in the
+	// collection is returned unchanged (NotFound AppError if the collection doesn't exist)
+	AddCollectionItem(ctx context.Context, collectionID int64, video entities.Video, note string, addedAt time.Time) (*entities.CollectionItem, error)
+	// SetCollectionItemNote replaces an item's note (NotFound AppError if it isn't in the collection)
+	SetCollectionItemNote(ctx context.Context, collectionID int64, videoID, note string) error
+	// MoveCollectionItem puts an item at position, shifting the items in between
+	// Positions past the end move it to the end (NotFound AppError if it isn't in the collection)
+	MoveCollectionItem(ctx context.Context, collectionID int64, videoID string, position int) error
+	// RemoveCollectionItem takes a video out of a collection (NotFound AppError if it isn't in it)
+	RemoveCollectionItem(ctx context.Context, collectionID int64, videoID string) error
+}
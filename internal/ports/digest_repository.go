@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// DigestRepository remembers when email digests went out, so the schedule
+// survives restarts
+type DigestRepository interface {
+	// LastDigestAt returns when the latest digest was recorded, zero if none was
+	LastDigestAt(ctx context.Context) (time.Time, error)
+	// RecordDigest stores a digest run and how many videos it listed
+	RecordDigest(ctx context.Context, sentAt time.Time, videoCount int) error
+}
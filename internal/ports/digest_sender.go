@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// DigestSender formats a digest and delivers it to the configured recipients
+type DigestSender interface {
+	SendDigest(ctx context.Context, digest entities.Digest) error
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// FocusSessionRepository stores focus sessions and their reflections
+// Sessions belong to a profile and are only visible to it
+type FocusSessionRepository interface {
+	// AddFocusSession stores a new session under its profile and sets its ID
+	AddFocusSession(ctx context.Context, session *entities.FocusSession) error
+	// CurrentFocusSession returns the profile's latest session that has not ended
+	// (NotFound AppError if every session has ended)
+	CurrentFocusSession(ctx context.Context, profile string) (*entities.FocusSession, error)
+	// EndFocusSession closes a session of the profile with its reflection
+	// (NotFound AppError if the profile has no such session)
+	EndFocusSession(ctx context.Context, profile string, id int64, endedAt time.Time, reflection string) error
+	// ListFocusSessions returns up to limit sessions of a profile, most recently started first
+	ListFocusSessions(ctx context.Context, profile string, limit int) ([]entities.FocusSession, error)
+}
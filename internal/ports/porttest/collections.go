@@ -0,0 +1,300 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// CollectionRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type CollectionRepositoryFactory func(t *testing.T) ports.CollectionRepository
+
+// RunCollectionRepositoryTests checks that an adapter behaves like
+// ports.CollectionRepository expects
+func RunCollectionRepositoryTests(t *testing.T, factory CollectionRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.CollectionRepository)
+	}{
+		{"CreateWithItems", testCreateCollectionWithItems},
+		{"CreateRejectsTakenName", testCreateCollectionRejectsTakenName},
+		{"ListByName", testListCollectionsByName},
+		{"Update", testUpdateCollection},
+		{"Delete", testDeleteCollection},
+		{"AddItemIsIdempotent", testAddCollectionItemIsIdempotent},
+		{"ItemsKeepKnownMetadata", testCollectionItemsKeepKnownMetadata},
+		{"SetItemNote", testSetCollectionItemNote},
+		{"MoveItem", testMoveCollectionItem},
+		{"RemoveItemClosesGap", testRemoveCollectionItemClosesGap},
+		{"CollectionsCancelledContext", testCollectionsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// createCollection creates a collection holding the named videos in order
+func createCollection(t *testing.T, repo ports.CollectionRepository, name string, videos ...string) int64 {
+	t.Helper()
+	c := &entities.Collection{Name: name, CreatedAt: baseTime, UpdatedAt: baseTime}
+	for _, v := range videos {
+		c.Items = append(c.Items, entities.CollectionItem{Video: testVideo(v), Note: "note " + v, AddedAt: baseTime})
+	}
+	require.NoError(t, repo.CreateCollection(context.Background(), c))
+	require.NotZero(t, c.ID)
+	return c.ID
+}
+
+// itemOrder returns the titles of a collection's items with their positions checked
+func itemOrder(t *testing.T, repo ports.CollectionRepository, id int64) []string {
+	t.Helper()
+	c, err := repo.GetCollection(context.Background(), id)
+	require.NoError(t, err)
+
+	titles := make([]string, 0, len(c.Items))
+	for i, item := range c.Items {
+		assert.Equal(t, i+1, item.Position, "positions must be contiguous")
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func testCreateCollectionWithItems(t *testing.T, repo ports.CollectionRepository) {
+	c := &entities.Collection{
+		Name:        "Go basics",
+		Description: "Start here",
+		CreatedAt:   baseTime,
+		UpdatedAt:   baseTime,
+		Items: []entities.CollectionItem{
+			{Video: testVideo("a"), Note: "first", AddedAt: baseTime},
+			{Video: testVideo("b"), AddedAt: baseTime},
+			{Video: testVideo("a"), Note: "duplicate", AddedAt: baseTime},
+		},
+	}
+	require.NoError(t, repo.CreateCollection(context.Background(), c))
+
+	got, err := repo.GetCollection(context.Background(), c.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Go basics", got.Name)
+	assert.Equal(t, "Start here", got.Description)
+	assert.True(t, baseTime.Equal(got.CreatedAt))
+	assert.Equal(t, 2, got.ItemCount)
+	require.Len(t, got.Items, 2)
+
+	want := testVideo("a")
+	first := got.Items[0]
+	assert.Equal(t, want.ID, first.ID)
+	assert.Equal(t, want.Title, first.Title)
+	assert.Equal(t, want.Channel, first.Channel)
+	assert.Equal(t, want.Thumbnail, first.Thumbnail)
+	assert.True(t, want.PublishedAt.Equal(first.PublishedAt))
+	assert.Equal(t, "first", first.Note)
+	assert.True(t, baseTime.Equal(first.AddedAt))
+
+	_, err = repo.GetCollection(context.Background(), c.ID+100)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testCreateCollectionRejectsTakenName(t *testing.T, repo ports.CollectionRepository) {
+	createCollection(t, repo, "Go basics", "a")
+
+	err := repo.CreateCollection(context.Background(), &entities.Collection{
+		Name:      "GO BASICS",
+		CreatedAt: baseTime,
+		UpdatedAt: baseTime,
+		Items:     []entities.CollectionItem{{Video: testVideo("b"), AddedAt: baseTime}},
+	})
+
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+	collections, err := repo.ListCollections(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, collections, 1)
+}
+
+func testListCollectionsByName(t *testing.T, repo ports.CollectionRepository) {
+	createCollection(t, repo, "Rust", "a")
+	createCollection(t, repo, "Go", "a", "b", "c")
+	createCollection(t, repo, "Empty")
+
+	collections, err := repo.ListCollections(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, collections, 3)
+	assert.Equal(t, "Empty", collections[0].Name)
+	assert.Equal(t, 0, collections[0].ItemCount)
+	assert.Equal(t, "Go", collections[1].Name)
+	assert.Equal(t, 3, collections[1].ItemCount)
+	assert.Empty(t, collections[1].Items)
+	assert.Equal(t, "Rust", collections[2].Name)
+}
+
+func testUpdateCollection(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a")
+	createCollection(t, repo, "Rust")
+	ctx := context.Background()
+	later := baseTime.Add(time.Hour)
+
+	// Changing only the case of its own name is fine
+	require.NoError(t, repo.UpdateCollection(ctx, id, "GO", "Gophers", later))
+	got, err := repo.GetCollection(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "GO", got.Name)
+	assert.Equal(t, "Gophers", got.Description)
+	assert.True(t, later.Equal(got.UpdatedAt))
+	assert.Len(t, got.Items, 1)
+
+	err = repo.UpdateCollection(ctx, id, "rust", "", later)
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+
+	err = repo.UpdateCollection(ctx, id+100, "Other", "", later)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testDeleteCollection(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a", "b")
+	ctx := context.Background()
+
+	require.NoError(t, repo.DeleteCollection(ctx, id))
+
+	_, err := repo.GetCollection(ctx, id)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	err = repo.DeleteCollection(ctx, id)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+
+	// The name is free again
+	createCollection(t, repo, "Go")
+}
+
+func testAddCollectionItemIsIdempotent(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a")
+	ctx := context.Background()
+
+	added, err := repo.AddCollectionItem(ctx, id, testVideo("b"), "second", baseTime.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, added.Position)
+	assert.Equal(t, "second", added.Note)
+
+	again, err := repo.AddCollectionItem(ctx, id, testVideo("a"), "changed", baseTime.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, again.Position)
+	assert.Equal(t, "note a", again.Note)
+	assert.True(t, baseTime.Equal(again.AddedAt))
+	assert.Equal(t, []string{"Video a", "Video b"}, itemOrder(t, repo, id))
+
+	_, err = repo.AddCollectionItem(ctx, id+100, testVideo("c"), "", baseTime)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testCollectionItemsKeepKnownMetadata(t *testing.T, repo ports.CollectionRepository) {
+	createCollection(t, repo, "Go", "a")
+	ctx := context.Background()
+
+	imported := testVideo("a")
+	imported.Title = "Imported title"
+	imported.Thumbnail = ""
+	imported.PublishedAt = time.Time{}
+	c := &entities.Collection{
+		Name:      "Imported",
+		CreatedAt: baseTime,
+		UpdatedAt: baseTime,
+		Items:     []entities.CollectionItem{{Video: imported, AddedAt: baseTime}},
+	}
+	require.NoError(t, repo.CreateCollection(ctx, c))
+	added, err := repo.AddCollectionItem(ctx, c.ID, imported, "", baseTime)
+	require.NoError(t, err)
+
+	want := testVideo("a")
+	require.Len(t, c.Items, 1)
+	assert.Equal(t, want.Title, c.Items[0].Title)
+	assert.Equal(t, want.Title, added.Title)
+
+	got, err := repo.GetCollection(ctx, c.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Items, 1)
+	assert.Equal(t, want.Title, got.Items[0].Title)
+	assert.Equal(t, want.Thumbnail, got.Items[0].Thumbnail)
+	assert.True(t, want.PublishedAt.Equal(got.Items[0].PublishedAt))
+}
+
+func testSetCollectionItemNote(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a")
+	ctx := context.Background()
+
+	require.NoError(t, repo.SetCollectionItemNote(ctx, id, testVideo("a").ID, "watch at 2x"))
+	got, err := repo.GetCollection(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "watch at 2x", got.Items[0].Note)
+
+	err = repo.SetCollectionItemNote(ctx, id, testVideo("missing").ID, "")
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testMoveCollectionItem(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a", "b", "c", "d")
+	other := createCollection(t, repo, "Rust", "a", "b")
+	ctx := context.Background()
+
+	require.NoError(t, repo.MoveCollectionItem(ctx, id, testVideo("d").ID, 2))
+	assert.Equal(t, []string{"Video a", "Video d", "Video b", "Video c"}, itemOrder(t, repo, id))
+
+	require.NoError(t, repo.MoveCollectionItem(ctx, id, testVideo("a").ID, 3))
+	assert.Equal(t, []string{"Video d", "Video b", "Video a", "Video c"}, itemOrder(t, repo, id))
+
+	// Past the end means last
+	require.NoError(t, repo.MoveCollectionItem(ctx, id, testVideo("d").ID, 99))
+	assert.Equal(t, []string{"Video b", "Video a", "Video c", "Video d"}, itemOrder(t, repo, id))
+
+	// Other collections are untouched
+	assert.Equal(t, []string{"Video a", "Video b"}, itemOrder(t, repo, other))
+
+	err := repo.MoveCollectionItem(ctx, id, testVideo("missing").ID, 1)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testRemoveCollectionItemClosesGap(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a", "b", "c")
+	ctx := context.Background()
+
+	require.NoError(t, repo.RemoveCollectionItem(ctx, id, testVideo("b").ID))
+
+	err := repo.RemoveCollectionItem(ctx, id, testVideo("b").ID)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	assert.Equal(t, []string{"Video a", "Video c"}, itemOrder(t, repo, id))
+}
+
+func testCollectionsCancelledContext(t *testing.T, repo ports.CollectionRepository) {
+	id := createCollection(t, repo, "Go", "a", "b")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Error(t, repo.CreateCollection(ctx, &entities.Collection{Name: "Rust", CreatedAt: baseTime, UpdatedAt: baseTime}))
+	_, err := repo.ListCollections(ctx)
+	assert.Error(t, err)
+	_, err = repo.GetCollection(ctx, id)
+	assert.Error(t, err)
+	assert.Error(t, repo.UpdateCollection(ctx, id, "Other", "", baseTime))
+	_, err = repo.AddCollectionItem(ctx, id, testVideo("c"), "", baseTime)
+	assert.Error(t, err)
+	assert.Error(t, repo.SetCollectionItemNote(ctx, id, testVideo("a").ID, ""))
+	assert.Error(t, repo.MoveCollectionItem(ctx, id, testVideo("b").ID, 1))
+	assert.Error(t, repo.RemoveCollectionItem(ctx, id, testVideo("a").ID))
+	assert.Error(t, repo.DeleteCollection(ctx, id))
+
+	// Nothing changed
+	assert.Equal(t, []string{"Video a", "Video b"}, itemOrder(t, repo, id))
+	collections, err := repo.ListCollections(context.Background())
+	require.NoError(t, err)
+	require.Len(t, collections, 1)
+	assert.Equal(t, "Go", collections[0].Name)
+}
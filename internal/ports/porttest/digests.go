@@ -0,0 +1,57 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// DigestRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type DigestRepositoryFactory func(t *testing.T) ports.DigestRepository
+
+// RunDigestRepositoryTests checks that an adapter behaves like
+// ports.DigestRepository expects
+func RunDigestRepositoryTests(t *testing.T, factory DigestRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.DigestRepository)
+	}{
+		{"LastDigestAt", testLastDigestAt},
+		{"DigestsCancelledContext", testDigestsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+func testLastDigestAt(t *testing.T, repo ports.DigestRepository) {
+	ctx := context.Background()
+
+	last, err := repo.LastDigestAt(ctx)
+	require.NoError(t, err)
+	assert.True(t, last.IsZero(), "no digest yet")
+
+	require.NoError(t, repo.RecordDigest(ctx, baseTime.Add(24*time.Hour), 3))
+	require.NoError(t, repo.RecordDigest(ctx, baseTime, 0))
+
+	last, err = repo.LastDigestAt(ctx)
+	require.NoError(t, err)
+	assert.True(t, baseTime.Add(24*time.Hour).Equal(last), "latest by time, not by insertion, got %s", last)
+}
+
+func testDigestsCancelledContext(t *testing.T, repo ports.DigestRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.LastDigestAt(ctx)
+	assert.Error(t, err)
+	assert.Error(t, repo.RecordDigest(ctx, baseTime, 1))
+}
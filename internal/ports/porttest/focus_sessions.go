@@ -0,0 +1,140 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// FocusSessionRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type FocusSessionRepositoryFactory func(t *testing.T) ports.FocusSessionRepository
+
+// RunFocusSessionRepositoryTests checks that an adapter behaves like
+// ports.FocusSessionRepository expects
+func RunFocusSessionRepositoryTests(t *testing.T, factory FocusSessionRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.FocusSessionRepository)
+	}{
+		{"AddAndCurrent", testAddFocusSession},
+		{"EndWithReflection", testEndFocusSession},
+		{"ListNewestFirst", testListFocusSessions},
+		{"ProfilesAreSeparate", testFocusSessionProfiles},
+		{"FocusSessionsCancelledContext", testFocusSessionsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// startFocus stores a 25 minute session of profile started at startedAt and returns its ID
+func startFocus(t *testing.T, repo ports.FocusSessionRepository, profile string, startedAt time.Time) int64 {
+	t.Helper()
+	s := &entities.FocusSession{Profile: profile, StartedAt: startedAt, EndsAt: startedAt.Add(25 * time.Minute)}
+	require.NoError(t, repo.AddFocusSession(context.Background(), s))
+	return s.ID
+}
+
+func testAddFocusSession(t *testing.T, repo ports.FocusSessionRepository) {
+	ctx := context.Background()
+	_, err := repo.CurrentFocusSession(ctx, entities.DefaultProfile)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+
+	first := startFocus(t, repo, entities.DefaultProfile, baseTime)
+	second := startFocus(t, repo, entities.DefaultProfile, baseTime.Add(time.Minute))
+	assert.NotZero(t, first)
+	assert.NotEqual(t, first, second)
+
+	current, err := repo.CurrentFocusSession(ctx, entities.DefaultProfile)
+	require.NoError(t, err)
+	assert.Equal(t, second, current.ID, "the latest open session")
+	assert.True(t, baseTime.Add(time.Minute).Equal(current.StartedAt))
+	assert.True(t, baseTime.Add(26*time.Minute).Equal(current.EndsAt))
+	assert.False(t, current.Ended())
+	assert.Empty(t, current.Reflection)
+}
+
+func testEndFocusSession(t *testing.T, repo ports.FocusSessionRepository) {
+	ctx := context.Background()
+	id := startFocus(t, repo, entities.DefaultProfile, baseTime)
+
+	endedAt := baseTime.Add(30 * time.Minute)
+	require.NoError(t, repo.EndFocusSession(ctx, entities.DefaultProfile, id, endedAt, "Found the pgx pooling talk"))
+
+	_, err := repo.CurrentFocusSession(ctx, entities.DefaultProfile)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err), "ended sessions are not current")
+
+	sessions, err := repo.ListFocusSessions(ctx, entities.DefaultProfile, 10)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.True(t, endedAt.Equal(sessions[0].EndedAt))
+	assert.Equal(t, "Found the pgx pooling talk", sessions[0].Reflection)
+
+	err = repo.EndFocusSession(ctx, entities.DefaultProfile, id+100, endedAt, "")
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testListFocusSessions(t *testing.T, repo ports.FocusSessionRepository) {
+	ctx := context.Background()
+	middle := startFocus(t, repo, entities.DefaultProfile, baseTime.Add(time.Hour))
+	oldest := startFocus(t, repo, entities.DefaultProfile, baseTime)
+	newest := startFocus(t, repo, entities.DefaultProfile, baseTime.Add(2*time.Hour))
+
+	sessions, err := repo.ListFocusSessions(ctx, entities.DefaultProfile, 10)
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(sessions))
+	for _, s := range sessions {
+		ids = append(ids, s.ID)
+	}
+	assert.Equal(t, []int64{newest, middle, oldest}, ids)
+
+	sessions, err = repo.ListFocusSessions(ctx, entities.DefaultProfile, 1)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, newest, sessions[0].ID)
+}
+
+func testFocusSessionProfiles(t *testing.T, repo ports.FocusSessionRepository) {
+	ctx := context.Background()
+	own := startFocus(t, repo, entities.DefaultProfile, baseTime)
+	other := startFocus(t, repo, "kids", baseTime.Add(time.Minute))
+
+	current, err := repo.CurrentFocusSession(ctx, entities.DefaultProfile)
+	require.NoError(t, err)
+	assert.Equal(t, own, current.ID, "another profile's newer session is not current")
+	assert.Equal(t, entities.DefaultProfile, current.Profile)
+
+	err = repo.EndFocusSession(ctx, entities.DefaultProfile, other, baseTime.Add(time.Hour), "")
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err), "sessions of another profile cannot be ended")
+
+	sessions, err := repo.ListFocusSessions(ctx, "kids", 10)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, other, sessions[0].ID)
+	assert.Equal(t, "kids", sessions[0].Profile)
+	assert.False(t, sessions[0].Ended())
+}
+
+func testFocusSessionsCancelledContext(t *testing.T, repo ports.FocusSessionRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.AddFocusSession(ctx, &entities.FocusSession{StartedAt: baseTime, EndsAt: baseTime})
+	assert.Error(t, err)
+	_, err = repo.CurrentFocusSession(ctx, entities.DefaultProfile)
+	assert.Error(t, err)
+	err = repo.EndFocusSession(ctx, entities.DefaultProfile, 1, baseTime, "")
+	assert.Error(t, err)
+	_, err = repo.ListFocusSessions(ctx, entities.DefaultProfile, 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,275 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// SavedSearchRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type SavedSearchRepositoryFactory func(t *testing.T) ports.SavedSearchRepository
+
+// RunSavedSearchRepositoryTests checks that an adapter behaves like
+// ports.SavedSearchRepository expects
+func RunSavedSearchRepositoryTests(t *testing.T, factory SavedSearchRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.SavedSearchRepository)
+	}{
+		{"CreateAndGet", testCreateSavedSearch},
+		{"CreateRejectsTakenName", testCreateSavedSearchRejectsTakenName},
+		{"ListByName", testListSavedSearchesByName},
+		{"Delete", testDeleteSavedSearch},
+		{"RecordCheck", testRecordSavedSearchCheck},
+		{"FirstRefreshIsBaseline", testSavedSearchFirstRefreshIsBaseline},
+		{"LaterRefreshFlagsNew", testSavedSearchLaterRefreshFlagsNew},
+		{"MarkSeen", testMarkSavedSearchSeen},
+		{"SavedSearchesCancelledContext", testSavedSearchesCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// createSavedSearch stores a search named name for query and returns its ID
+func createSavedSearch(t *testing.T, repo ports.SavedSearchRepository, name, query string) int64 {
+	t.Helper()
+	search := &entities.SavedSearch{
+		Name:      name,
+		Options:   entities.SearchOptions{Query: query, MaxResults: 10},
+		CreatedAt: baseTime,
+	}
+	require.NoError(t, repo.CreateSavedSearch(context.Background(), search))
+	return search.ID
+}
+
+// refresh adds the named videos as the results of a refresh at refreshedAt
+// and returns the titles of the results it added, new ones marked "*"
+func refresh(t *testing.T, repo ports.SavedSearchRepository, id int64, refreshedAt time.Time, names ...string) []string {
+	t.Helper()
+	videos := make([]entities.Video, 0, len(names))
+	for _, name := range names {
+		videos = append(videos, testVideo(name))
+	}
+	added, err := repo.AddSavedSearchResults(context.Background(), id, videos, refreshedAt)
+	require.NoError(t, err)
+	return markedTitles(added)
+}
+
+// resultTitles returns a search's result titles in order, with new ones marked "*"
+func resultTitles(t *testing.T, repo ports.SavedSearchRepository, id int64) []string {
+	t.Helper()
+	results, err := repo.ListSavedSearchResults(context.Background(), id)
+	require.NoError(t, err)
+	return markedTitles(results)
+}
+
+// markedTitles returns the titles of results, with new ones marked "*"
+func markedTitles(results []entities.SavedSearchResult) []string {
+	titles := make([]string, 0, len(results))
+	for _, res := range results {
+		if res.New {
+			titles = append(titles, res.Title+"*")
+		} else {
+			titles = append(titles, res.Title)
+		}
+	}
+	return titles
+}
+
+func testCreateSavedSearch(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "KubeCon storage", "kubecon 2026 storage")
+	assert.NotZero(t, id)
+
+	search, err := repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, "KubeCon storage", search.Name)
+	assert.Equal(t, entities.SearchOptions{Query: "kubecon 2026 storage", MaxResults: 10}, search.Options)
+	assert.True(t, baseTime.Equal(search.CreatedAt))
+	assert.True(t, search.LastCheckedAt.IsZero())
+	assert.True(t, search.LastRefreshedAt.IsZero())
+	assert.Zero(t, search.ResultCount)
+
+	_, err = repo.GetSavedSearch(context.Background(), id+100)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testCreateSavedSearchRejectsTakenName(t *testing.T, repo ports.SavedSearchRepository) {
+	createSavedSearch(t, repo, "KubeCon", "kubecon")
+
+	err := repo.CreateSavedSearch(context.Background(), &entities.SavedSearch{
+		Name:      "KUBECON",
+		Options:   entities.SearchOptions{Query: "other", MaxResults: 5},
+		CreatedAt: baseTime,
+	})
+
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+	searches, err := repo.ListSavedSearches(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, searches, 1)
+}
+
+func testListSavedSearchesByName(t *testing.T, repo ports.SavedSearchRepository) {
+	rust := createSavedSearch(t, repo, "rust", "rust")
+	goID := createSavedSearch(t, repo, "Go", "golang")
+	refresh(t, repo, goID, baseTime, "a", "b")
+	refresh(t, repo, goID, baseTime.Add(time.Hour), "b", "c")
+
+	searches, err := repo.ListSavedSearches(context.Background())
+	require.NoError(t, err)
+	require.Len(t, searches, 2)
+	assert.Equal(t, goID, searches[0].ID)
+	assert.Equal(t, 3, searches[0].ResultCount)
+	assert.Equal(t, 1, searches[0].NewCount)
+	assert.Equal(t, rust, searches[1].ID)
+	assert.Zero(t, searches[1].ResultCount)
+	assert.Zero(t, searches[1].NewCount)
+}
+
+func testDeleteSavedSearch(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "Go", "golang")
+	refresh(t, repo, id, baseTime, "a")
+
+	require.NoError(t, repo.DeleteSavedSearch(context.Background(), id))
+
+	_, err := repo.GetSavedSearch(context.Background(), id)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	_, err = repo.ListSavedSearchResults(context.Background(), id)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	err = repo.DeleteSavedSearch(context.Background(), id)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testRecordSavedSearchCheck(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "Go", "golang")
+	checked := baseTime.Add(time.Hour)
+
+	require.NoError(t, repo.RecordSavedSearchCheck(context.Background(), id, checked, "quota exceeded"))
+
+	search, err := repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.True(t, checked.Equal(search.LastCheckedAt))
+	assert.Equal(t, "quota exceeded", search.LastError)
+	assert.True(t, search.LastRefreshedAt.IsZero(), "a failed check is not a refresh")
+
+	require.NoError(t, repo.RecordSavedSearchCheck(context.Background(), id, checked.Add(time.Hour), ""))
+	search, err = repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.Empty(t, search.LastError)
+
+	err = repo.RecordSavedSearchCheck(context.Background(), id+100, checked, "")
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testSavedSearchFirstRefreshIsBaseline(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "Go", "golang")
+
+	added := refresh(t, repo, id, baseTime, "a", "b")
+
+	assert.ElementsMatch(t, []string{"Video a", "Video b"}, added)
+	assert.ElementsMatch(t, []string{"Video a", "Video b"}, resultTitles(t, repo, id))
+
+	search, err := repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.True(t, baseTime.Equal(search.LastRefreshedAt))
+	assert.Zero(t, search.NewCount)
+
+	_, err = repo.AddSavedSearchResults(context.Background(), id+100, []entities.Video{testVideo("a")}, baseTime)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testSavedSearchLaterRefreshFlagsNew(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "Go", "golang")
+	refresh(t, repo, id, baseTime, "a", "b")
+
+	later := baseTime.Add(24 * time.Hour)
+	added := refresh(t, repo, id, later, "b", "c", "d")
+
+	// Only videos never matched before are new; they sort first, most recent first
+	assert.ElementsMatch(t, []string{"Video c*", "Video d*"}, added)
+	titles := resultTitles(t, repo, id)
+	require.Len(t, titles, 4)
+	assert.ElementsMatch(t, []string{"Video c*", "Video d*"}, titles[:2])
+	assert.ElementsMatch(t, []string{"Video a", "Video b"}, titles[2:])
+
+	results, err := repo.ListSavedSearchResults(context.Background(), id)
+	require.NoError(t, err)
+	for _, res := range results {
+		switch res.Title {
+		case "Video b":
+			assert.True(t, baseTime.Equal(res.FirstSeenAt))
+			assert.True(t, later.Equal(res.LastSeenAt))
+		case "Video a":
+			assert.True(t, baseTime.Equal(res.LastSeenAt))
+		}
+	}
+}
+
+func testMarkSavedSearchSeen(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "Go", "golang")
+	other := createSavedSearch(t, repo, "Rust", "rust")
+	refresh(t, repo, id, baseTime, "a")
+	refresh(t, repo, id, baseTime.Add(time.Hour), "b", "c")
+	refresh(t, repo, other, baseTime, "a")
+	refresh(t, repo, other, baseTime.Add(time.Hour), "d")
+
+	marked, err := repo.MarkSavedSearchSeen(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, 2, marked)
+
+	search, err := repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.Zero(t, search.NewCount)
+	search, err = repo.GetSavedSearch(context.Background(), other)
+	require.NoError(t, err)
+	assert.Equal(t, 1, search.NewCount, "other searches keep their new results")
+
+	// Results seen once aren't new again when a refresh returns them
+	refresh(t, repo, id, baseTime.Add(2*time.Hour), "a", "b", "c")
+	search, err = repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.Zero(t, search.NewCount)
+
+	_, err = repo.MarkSavedSearchSeen(context.Background(), id+100)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testSavedSearchesCancelledContext(t *testing.T, repo ports.SavedSearchRepository) {
+	id := createSavedSearch(t, repo, "Go", "golang")
+	refresh(t, repo, id, baseTime, "a")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Error(t, repo.CreateSavedSearch(ctx, &entities.SavedSearch{
+		Name: "Rust", Options: entities.SearchOptions{Query: "rust", MaxResults: 5}, CreatedAt: baseTime,
+	}))
+	_, err := repo.ListSavedSearches(ctx)
+	assert.Error(t, err)
+	_, err = repo.GetSavedSearch(ctx, id)
+	assert.Error(t, err)
+	assert.Error(t, repo.RecordSavedSearchCheck(ctx, id, baseTime, "boom"))
+	_, err = repo.AddSavedSearchResults(ctx, id, []entities.Video{testVideo("b")}, baseTime.Add(time.Hour))
+	assert.Error(t, err)
+	_, err = repo.ListSavedSearchResults(ctx, id)
+	assert.Error(t, err)
+	_, err = repo.MarkSavedSearchSeen(ctx, id)
+	assert.Error(t, err)
+	assert.Error(t, repo.DeleteSavedSearch(ctx, id))
+
+	// Nothing changed
+	assert.Equal(t, []string{"Video a"}, resultTitles(t, repo, id))
+	search, err := repo.GetSavedSearch(context.Background(), id)
+	require.NoError(t, err)
+	assert.Empty(t, search.LastError)
+}
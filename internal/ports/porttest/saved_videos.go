@@ -0,0 +1,191 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// SavedVideoRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type SavedVideoRepositoryFactory func(t *testing.T) ports.SavedVideoRepository
+
+// RunSavedVideoRepositoryTests checks that an adapter behaves like
+// ports.SavedVideoRepository expects
+func RunSavedVideoRepositoryTests(t *testing.T, factory SavedVideoRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.SavedVideoRepository)
+	}{
+		{"AddAppends", testAddSavedVideoAppends},
+		{"AddIsIdempotent", testAddSavedVideoIsIdempotent},
+		{"AddKeepsKnownMetadata", testAddSavedVideoKeepsKnownMetadata},
+		{"RemoveClosesGap", testRemoveSavedVideoClosesGap},
+		{"SetWatched", testSetSavedVideoWatched},
+		{"Move", testMoveSavedVideo},
+		{"SavedVideosCancelledContext", testSavedVideosCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// testVideo returns a video with a valid-looking ID derived from name
+func testVideo(name string) entities.Video {
+	return entities.Video{
+		ID:          (name + "___________")[:11],
+		Title:       "Video " + name,
+		Channel:     "Channel",
+		PublishedAt: baseTime.Add(-24 * time.Hour),
+		Thumbnail:   "https://i.ytimg.com/vi/" + name + "/mqdefault.jpg",
+	}
+}
+
+// addSaved adds videos in order
+func addSaved(t *testing.T, repo ports.SavedVideoRepository, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		_, err := repo.AddSavedVideo(context.Background(), testVideo(name), baseTime)
+		require.NoError(t, err)
+	}
+}
+
+// savedOrder returns the titles of the list with their positions checked
+func savedOrder(t *testing.T, repo ports.SavedVideoRepository) []string {
+	t.Helper()
+	videos, err := repo.ListSavedVideos(context.Background())
+	require.NoError(t, err)
+
+	titles := make([]string, 0, len(videos))
+	for i, v := range videos {
+		assert.Equal(t, i+1, v.Position, "positions must be contiguous")
+		titles = append(titles, v.Title)
+	}
+	return titles
+}
+
+func testAddSavedVideoAppends(t *testing.T, repo ports.SavedVideoRepository) {
+	first, err := repo.AddSavedVideo(context.Background(), testVideo("a"), baseTime)
+	require.NoError(t, err)
+	second, err := repo.AddSavedVideo(context.Background(), testVideo("b"), baseTime.Add(time.Minute))
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, first.Position)
+	assert.Equal(t, 2, second.Position)
+	assert.False(t, second.Watched())
+
+	videos, err := repo.ListSavedVideos(context.Background())
+	require.NoError(t, err)
+	require.Len(t, videos, 2)
+	want := testVideo("a")
+	assert.Equal(t, want.ID, videos[0].ID)
+	assert.Equal(t, want.Title, videos[0].Title)
+	assert.Equal(t, want.Channel, videos[0].Channel)
+	assert.Equal(t, want.Thumbnail, videos[0].Thumbnail)
+	assert.True(t, want.PublishedAt.Equal(videos[0].PublishedAt))
+	assert.True(t, baseTime.Equal(videos[0].SavedAt))
+}
+
+func testAddSavedVideoIsIdempotent(t *testing.T, repo ports.SavedVideoRepository) {
+	addSaved(t, repo, "a", "b")
+
+	again, err := repo.AddSavedVideo(context.Background(), testVideo("a"), baseTime.Add(time.Hour))
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, again.Position)
+	assert.True(t, baseTime.Equal(again.SavedAt))
+	assert.Equal(t, []string{"Video a", "Video b"}, savedOrder(t, repo))
+}
+
+func testAddSavedVideoKeepsKnownMetadata(t *testing.T, repo ports.SavedVideoRepository) {
+	addSaved(t, repo, "a")
+	ctx := context.Background()
+	require.NoError(t, repo.RemoveSavedVideo(ctx, testVideo("a").ID))
+
+	forged := testVideo("a")
+	forged.Title = "Forged title"
+	forged.Thumbnail = "https://example.com/forged.jpg"
+	saved, err := repo.AddSavedVideo(ctx, forged, baseTime.Add(time.Hour))
+
+	require.NoError(t, err)
+	want := testVideo("a")
+	assert.Equal(t, want.Title, saved.Title)
+	assert.Equal(t, want.Thumbnail, saved.Thumbnail)
+	assert.Equal(t, []string{"Video a"}, savedOrder(t, repo))
+}
+
+func testRemoveSavedVideoClosesGap(t *testing.T, repo ports.SavedVideoRepository) {
+	addSaved(t, repo, "a", "b", "c")
+	ctx := context.Background()
+
+	require.NoError(t, repo.RemoveSavedVideo(ctx, testVideo("b").ID))
+
+	err := repo.RemoveSavedVideo(ctx, testVideo("b").ID)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	assert.Equal(t, []string{"Video a", "Video c"}, savedOrder(t, repo))
+}
+
+func testSetSavedVideoWatched(t *testing.T, repo ports.SavedVideoRepository) {
+	addSaved(t, repo, "a")
+	ctx := context.Background()
+	id := testVideo("a").ID
+
+	require.NoError(t, repo.SetSavedVideoWatched(ctx, id, baseTime.Add(time.Hour)))
+	videos, err := repo.ListSavedVideos(ctx)
+	require.NoError(t, err)
+	assert.True(t, videos[0].Watched())
+	assert.True(t, baseTime.Add(time.Hour).Equal(videos[0].WatchedAt))
+
+	require.NoError(t, repo.SetSavedVideoWatched(ctx, id, time.Time{}))
+	videos, err = repo.ListSavedVideos(ctx)
+	require.NoError(t, err)
+	assert.False(t, videos[0].Watched())
+
+	err = repo.SetSavedVideoWatched(ctx, testVideo("missing").ID, baseTime)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testMoveSavedVideo(t *testing.T, repo ports.SavedVideoRepository) {
+	addSaved(t, repo, "a", "b", "c", "d")
+	ctx := context.Background()
+
+	require.NoError(t, repo.MoveSavedVideo(ctx, testVideo("d").ID, 2))
+	assert.Equal(t, []string{"Video a", "Video d", "Video b", "Video c"}, savedOrder(t, repo))
+
+	require.NoError(t, repo.MoveSavedVideo(ctx, testVideo("a").ID, 3))
+	assert.Equal(t, []string{"Video d", "Video b", "Video a", "Video c"}, savedOrder(t, repo))
+
+	// Past the end means last
+	require.NoError(t, repo.MoveSavedVideo(ctx, testVideo("d").ID, 99))
+	assert.Equal(t, []string{"Video b", "Video a", "Video c", "Video d"}, savedOrder(t, repo))
+
+	err := repo.MoveSavedVideo(ctx, testVideo("missing").ID, 1)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testSavedVideosCancelledContext(t *testing.T, repo ports.SavedVideoRepository) {
+	addSaved(t, repo, "a", "b")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.AddSavedVideo(ctx, testVideo("c"), baseTime)
+	assert.Error(t, err)
+	assert.Error(t, repo.RemoveSavedVideo(ctx, testVideo("a").ID))
+	assert.Error(t, repo.SetSavedVideoWatched(ctx, testVideo("a").ID, baseTime))
+	assert.Error(t, repo.MoveSavedVideo(ctx, testVideo("b").ID, 1))
+	_, err = repo.ListSavedVideos(ctx)
+	assert.Error(t, err)
+
+	// Nothing changed
+	assert.Equal(t, []string{"Video a", "Video b"}, savedOrder(t, repo))
+}
@@ -0,0 +1,99 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// AnalyticsRepository is a history repository that also aggregates its rows
+type AnalyticsRepository interface {
+	ports.SearchHistoryRepository
+	ports.SearchAnalytics
+}
+
+// SearchAnalyticsFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type SearchAnalyticsFactory func(t *testing.T) AnalyticsRepository
+
+// RunSearchAnalyticsTests checks that an adapter behaves like
+// ports.SearchAnalytics expects
+func RunSearchAnalyticsTests(t *testing.T, factory SearchAnalyticsFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo AnalyticsRepository)
+	}{
+		{"QueryStats", testQueryStats},
+		{"DailyStatsUTCDays", testDailyStatsUTCDays},
+		{"AnalyticsCancelledContext", testAnalyticsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// saveSearch stores a history row with an explicit cache hit flag
+func saveSearch(t *testing.T, repo ports.SearchHistoryRepository, query string, results int, cacheHit bool, createdAt time.Time) {
+	t.Helper()
+	require.NoError(t, repo.Save(context.Background(), &entities.SearchHistory{
+		Query: query, Results: results, CacheHit: cacheHit, CreatedAt: createdAt,
+	}))
+}
+
+func testQueryStats(t *testing.T, repo AnalyticsRepository) {
+	saveSearch(t, repo, "golang", 0, false, baseTime.Add(-48*time.Hour)) // Before the window
+	saveSearch(t, repo, "golang", 10, false, baseTime)
+	saveSearch(t, repo, "golang", 0, true, baseTime.Add(time.Minute))
+	saveSearch(t, repo, "Golang", 5, true, baseTime.Add(2*time.Minute))
+
+	stats, err := repo.QueryStats(context.Background(), baseTime.Add(-time.Hour))
+
+	// Adapters don't normalize queries; that's the use case's job
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []entities.QueryStats{
+		{Query: "golang", Searches: 2, ZeroResults: 1, CacheHits: 1},
+		{Query: "Golang", Searches: 1, ZeroResults: 0, CacheHits: 1},
+	}, stats)
+}
+
+func testDailyStatsUTCDays(t *testing.T, repo AnalyticsRepository) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	eastOfUTC := time.FixedZone("UTC+5", 5*60*60)
+
+	// 01:00 in UTC+5 is still the previous UTC day
+	saveSearch(t, repo, "a", 1, false, yesterday.Add(21*time.Hour).In(eastOfUTC))
+	saveSearch(t, repo, "b", 0, true, yesterday.Add(22*time.Hour))
+	saveSearch(t, repo, "c", 3, true, today.Add(time.Minute))
+	saveSearch(t, repo, "d", 3, false, yesterday.Add(-time.Hour)) // Before the window
+
+	stats, err := repo.DailyStats(context.Background(), yesterday)
+
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.True(t, yesterday.Equal(stats[0].Day), "got %v", stats[0].Day)
+	assert.Equal(t, 2, stats[0].Searches)
+	assert.Equal(t, 1, stats[0].ZeroResults)
+	assert.Equal(t, 1, stats[0].CacheHits)
+	assert.True(t, today.Equal(stats[1].Day), "got %v", stats[1].Day)
+	assert.Equal(t, 1, stats[1].Searches)
+	assert.Equal(t, 1, stats[1].CacheHits)
+}
+
+func testAnalyticsCancelledContext(t *testing.T, repo AnalyticsRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.QueryStats(ctx, baseTime)
+	assert.Error(t, err)
+	_, err = repo.DailyStats(ctx, baseTime)
+	assert.Error(t, err)
+}
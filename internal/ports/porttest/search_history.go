@@ -0,0 +1,294 @@
+// Package porttest holds conformance suites that every adapter of a port must pass.
+//
+// Call a suite from the adapter's own tests with a factory that returns a
+// fresh, empty repository:
+//
+//	func TestMemoryRepository(t *testing.T) {
+//		porttest.RunSearchHistoryRepositoryTests(t, func(t *testing.T) ports.SearchHistoryRepository {
+//			return memory.NewSearchHistoryRepository()
+//		})
+//	}
+package porttest
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// SearchHistoryRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type SearchHistoryRepositoryFactory func(t *testing.T) ports.SearchHistoryRepository
+
+// RunSearchHistoryRepositoryTests checks that an adapter behaves like
+// ports.SearchHistoryRepository expects
+func RunSearchHistoryRepositoryTests(t *testing.T, factory SearchHistoryRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.SearchHistoryRepository)
+	}{
+		{"SaveAssignsIDs", testSaveAssignsIDs},
+		{"SaveKeepsCacheHit", testSaveKeepsCacheHit},
+		{"GetLastNewestFirst", testGetLastNewestFirst},
+		{"GetTopQueries", testGetTopQueries},
+		{"SaveBatch", testSaveBatch},
+		{"ListFilters", testListFilters},
+		{"ListCursor", testListCursor},
+		{"Delete", testDelete},
+		{"DeleteAll", testDeleteAll},
+		{"ConcurrentSaves", testConcurrentSaves},
+		{"CancelledContext", testCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// baseTime is a fixed, second-aligned time so adapters that round-trip
+// timestamps through text compare equal
+var baseTime = time.Now().Add(-time.Hour).Truncate(time.Second)
+
+// save stores a history row and returns it with its ID
+func save(t *testing.T, repo ports.SearchHistoryRepository, query string, results int, createdAt time.Time) entities.SearchHistory {
+	t.Helper()
+	h := entities.SearchHistory{Query: query, Results: results, CreatedAt: createdAt}
+	require.NoError(t, repo.Save(context.Background(), &h))
+	return h
+}
+
+// queries returns the queries of rows, in order
+func queries(rows []entities.SearchHistory) []string {
+	result := make([]string, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, r.Query)
+	}
+	return result
+}
+
+func testSaveAssignsIDs(t *testing.T, repo ports.SearchHistoryRepository) {
+	first := save(t, repo, "golang", 10, baseTime)
+	second := save(t, repo, "rust", 5, baseTime)
+
+	assert.NotZero(t, first.ID)
+	assert.NotEqual(t, first.ID, second.ID)
+}
+
+func testSaveKeepsCacheHit(t *testing.T, repo ports.SearchHistoryRepository) {
+	ctx := context.Background()
+	require.NoError(t, repo.Save(ctx, &entities.SearchHistory{Query: "cached", Results: 1, CacheHit: true, CreatedAt: baseTime.Add(time.Minute)}))
+	save(t, repo, "fetched", 1, baseTime)
+
+	rows, err := repo.GetLast(ctx, 10)
+
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.True(t, rows[0].CacheHit)
+	assert.False(t, rows[1].CacheHit)
+}
+
+func testGetLastNewestFirst(t *testing.T, repo ports.SearchHistoryRepository) {
+	save(t, repo, "old", 1, baseTime)
+	save(t, repo, "newest", 1, baseTime.Add(2*time.Minute))
+	save(t, repo, "middle", 1, baseTime.Add(time.Minute))
+
+	rows, err := repo.GetLast(context.Background(), 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"newest", "middle"}, queries(rows))
+	assert.Equal(t, 1, rows[0].Results)
+	assert.True(t, baseTime.Add(2*time.Minute).Equal(rows[0].CreatedAt))
+}
+
+func testGetTopQueries(t *testing.T, repo ports.SearchHistoryRepository) {
+	save(t, repo, "ancient", 1, baseTime.Add(-48*time.Hour))
+	save(t, repo, "ancient", 1, baseTime.Add(-48*time.Hour))
+	save(t, repo, "ancient", 1, baseTime.Add(-48*time.Hour))
+	save(t, repo, "rust", 1, baseTime)
+	save(t, repo, "golang", 1, baseTime)
+	save(t, repo, "golang", 1, baseTime.Add(time.Minute))
+	save(t, repo, "zig", 1, baseTime.Add(2*time.Minute))
+
+	top, err := repo.GetTopQueries(context.Background(), baseTime.Add(-time.Hour), 2)
+
+	// Ties on count go to the most recently searched query
+	require.NoError(t, err)
+	assert.Equal(t, []entities.QueryFrequency{
+		{Query: "golang", Count: 2},
+		{Query: "zig", Count: 1},
+	}, top)
+}
+
+func testSaveBatch(t *testing.T, repo ports.SearchHistoryRepository) {
+	batch := []*entities.SearchHistory{
+		{Query: "a", Results: 1, CreatedAt: baseTime},
+		{Query: "b", Results: 2, CreatedAt: baseTime.Add(time.Second)},
+	}
+
+	require.NoError(t, repo.SaveBatch(context.Background(), batch))
+	require.NoError(t, repo.SaveBatch(context.Background(), nil))
+
+	assert.NotZero(t, batch[0].ID)
+	assert.NotEqual(t, batch[0].ID, batch[1].ID)
+
+	rows, err := repo.GetLast(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b", "a"}, queries(rows))
+}
+
+func testListFilters(t *testing.T, repo ports.SearchHistoryRepository) {
+	save(t, repo, "Golang generics", 10, baseTime)
+	save(t, repo, "golang channels", 0, baseTime.Add(time.Minute))
+	save(t, repo, "rust", 5, baseTime.Add(2*time.Minute))
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter entities.SearchHistoryFilter
+		want   []string
+	}{
+		{"no filter", entities.SearchHistoryFilter{}, []string{"rust", "golang channels", "Golang generics"}},
+		{"contains ignores case", entities.SearchHistoryFilter{Contains: "GOLANG"}, []string{"golang channels", "Golang generics"}},
+		{"min results", entities.SearchHistoryFilter{MinResults: 5}, []string{"rust", "Golang generics"}},
+		{"from inclusive", entities.SearchHistoryFilter{From: baseTime.Add(time.Minute)}, []string{"rust", "golang channels"}},
+		{"to exclusive", entities.SearchHistoryFilter{To: baseTime.Add(time.Minute)}, []string{"Golang generics"}},
+		{"combined", entities.SearchHistoryFilter{Contains: "golang", MinResults: 1, To: baseTime.Add(time.Hour)}, []string{"Golang generics"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := repo.List(ctx, tt.filter, nil, 10)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, queries(rows))
+		})
+	}
+}
+
+func testListCursor(t *testing.T, repo ports.SearchHistoryRepository) {
+	// Two rows share a timestamp; the cursor must not skip or repeat either
+	save(t, repo, "a", 1, baseTime)
+	save(t, repo, "b", 1, baseTime.Add(time.Minute))
+	save(t, repo, "c", 1, baseTime.Add(time.Minute))
+	save(t, repo, "d", 1, baseTime.Add(2*time.Minute))
+	ctx := context.Background()
+
+	var seen []string
+	var after *entities.SearchHistoryCursor
+	for page := 0; page < 10; page++ {
+		rows, err := repo.List(ctx, entities.SearchHistoryFilter{}, after, 2)
+		require.NoError(t, err)
+		if len(rows) == 0 {
+			break
+		}
+		seen = append(seen, queries(rows)...)
+		last := rows[len(rows)-1]
+		after = &entities.SearchHistoryCursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	assert.Equal(t, []string{"d", "c", "b", "a"}, seen)
+}
+
+func testDelete(t *testing.T, repo ports.SearchHistoryRepository) {
+	kept := save(t, repo, "kept", 1, baseTime)
+	deleted := save(t, repo, "deleted", 1, baseTime)
+	ctx := context.Background()
+
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	err := repo.Delete(ctx, deleted.ID)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+
+	rows, err := repo.GetLast(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	assert.Equal(t, kept.ID, rows[0].ID)
+}
+
+func testDeleteAll(t *testing.T, repo ports.SearchHistoryRepository) {
+	save(t, repo, "a", 1, baseTime)
+	save(t, repo, "b", 1, baseTime)
+	ctx := context.Background()
+
+	deleted, err := repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	deleted, err = repo.DeleteAll(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	rows, err := repo.GetLast(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func testConcurrentSaves(t *testing.T, repo ports.SearchHistoryRepository) {
+	const writers, perWriter = 10, 20
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	ids := make(chan int64, writers*perWriter)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				h := entities.SearchHistory{Query: "golang", Results: i, CreatedAt: time.Now()}
+				if err := repo.Save(ctx, &h); err != nil {
+					t.Error(err)
+					return
+				}
+				ids <- h.ID
+				if _, err := repo.GetLast(ctx, 5); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	unique := make(map[int64]bool)
+	for id := range ids {
+		unique[id] = true
+	}
+	assert.Len(t, unique, writers*perWriter)
+
+	rows, err := repo.GetLast(ctx, writers*perWriter+1)
+	require.NoError(t, err)
+	assert.Len(t, rows, writers*perWriter)
+}
+
+func testCancelledContext(t *testing.T, repo ports.SearchHistoryRepository) {
+	existing := save(t, repo, "golang", 1, baseTime)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.Error(t, repo.Save(ctx, &entities.SearchHistory{Query: "rust", Results: 1, CreatedAt: baseTime}))
+	assert.Error(t, repo.SaveBatch(ctx, []*entities.SearchHistory{{Query: "zig", Results: 1, CreatedAt: baseTime}}))
+	_, err := repo.GetLast(ctx, 10)
+	assert.Error(t, err)
+	_, err = repo.GetTopQueries(ctx, baseTime, 10)
+	assert.Error(t, err)
+	_, err = repo.List(ctx, entities.SearchHistoryFilter{}, nil, 10)
+	assert.Error(t, err)
+	assert.Error(t, repo.Delete(ctx, existing.ID))
+	_, err = repo.DeleteAll(ctx)
+	assert.Error(t, err)
+
+	// Nothing was written or removed
+	rows, err := repo.GetLast(context.Background(), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"golang"}, queries(rows))
+}
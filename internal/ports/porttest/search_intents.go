@@ -0,0 +1,133 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// SearchIntentRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type SearchIntentRepositoryFactory func(t *testing.T) ports.SearchIntentRepository
+
+// RunSearchIntentRepositoryTests checks that an adapter behaves like
+// ports.SearchIntentRepository expects
+func RunSearchIntentRepositoryTests(t *testing.T, factory SearchIntentRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.SearchIntentRepository)
+	}{
+		{"AddAndGet", testAddSearchIntent},
+		{"Latest", testLatestSearchIntent},
+		{"Rate", testRateSearchIntent},
+		{"ListNewestFirst", testListSearchIntents},
+		{"SearchIntentsCancelledContext", testSearchIntentsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// addIntent stores an unrated intent created at createdAt and returns its ID
+func addIntent(t *testing.T, repo ports.SearchIntentRepository, intent string, createdAt time.Time) int64 {
+	t.Helper()
+	i := &entities.SearchIntent{Intent: intent, CreatedAt: createdAt}
+	require.NoError(t, repo.AddSearchIntent(context.Background(), i))
+	return i.ID
+}
+
+func testAddSearchIntent(t *testing.T, repo ports.SearchIntentRepository) {
+	ctx := context.Background()
+	first := addIntent(t, repo, "learn pgx pooling", baseTime)
+	second := addIntent(t, repo, "fix the bike chain", baseTime)
+	assert.NotZero(t, first)
+	assert.NotEqual(t, first, second)
+
+	got, err := repo.GetSearchIntent(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, "learn pgx pooling", got.Intent)
+	assert.True(t, baseTime.Equal(got.CreatedAt))
+	assert.False(t, got.Rated())
+	assert.True(t, got.RatedAt.IsZero())
+
+	_, err = repo.GetSearchIntent(ctx, second+100)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testLatestSearchIntent(t *testing.T, repo ports.SearchIntentRepository) {
+	ctx := context.Background()
+	_, err := repo.LatestSearchIntent(ctx)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+
+	addIntent(t, repo, "later", baseTime.Add(time.Hour))
+	addIntent(t, repo, "earlier", baseTime)
+
+	latest, err := repo.LatestSearchIntent(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "later", latest.Intent, "by creation time, not insertion order")
+}
+
+func testRateSearchIntent(t *testing.T, repo ports.SearchIntentRepository) {
+	ctx := context.Background()
+	id := addIntent(t, repo, "learn pgx pooling", baseTime)
+
+	require.NoError(t, repo.RateSearchIntent(ctx, id, entities.IntentNotFound, baseTime.Add(time.Minute)))
+	require.NoError(t, repo.RateSearchIntent(ctx, id, entities.IntentFound, baseTime.Add(time.Hour)))
+
+	got, err := repo.GetSearchIntent(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, entities.IntentFound, got.Outcome, "a later answer replaces the earlier one")
+	assert.True(t, baseTime.Add(time.Hour).Equal(got.RatedAt))
+
+	err = repo.RateSearchIntent(ctx, id+100, entities.IntentFound, baseTime)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testListSearchIntents(t *testing.T, repo ports.SearchIntentRepository) {
+	ctx := context.Background()
+	addIntent(t, repo, "second", baseTime.Add(time.Minute))
+	rated := addIntent(t, repo, "first", baseTime)
+	addIntent(t, repo, "third", baseTime.Add(2*time.Minute))
+	require.NoError(t, repo.RateSearchIntent(ctx, rated, entities.IntentFound, baseTime.Add(time.Hour)))
+
+	intents, err := repo.ListSearchIntents(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, intents, 3)
+	assert.Equal(t, []string{"third", "second", "first"}, intentTexts(intents))
+	assert.Equal(t, entities.IntentFound, intents[2].Outcome)
+	assert.Zero(t, intents[0].Searches, "no searches were made for it")
+
+	intents, err = repo.ListSearchIntents(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"third", "second"}, intentTexts(intents))
+}
+
+func testSearchIntentsCancelledContext(t *testing.T, repo ports.SearchIntentRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.AddSearchIntent(ctx, &entities.SearchIntent{Intent: "a", CreatedAt: baseTime})
+	assert.Error(t, err)
+	_, err = repo.LatestSearchIntent(ctx)
+	assert.Error(t, err)
+	_, err = repo.ListSearchIntents(ctx, 10)
+	assert.Error(t, err)
+}
+
+// intentTexts returns the intent of each listed intent in order
+func intentTexts(intents []entities.IntentActivity) []string {
+	texts := make([]string, 0, len(intents))
+	for _, i := range intents {
+		texts = append(texts, i.Intent)
+	}
+	return texts
+}
@@ -0,0 +1,341 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// SubscriptionRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type SubscriptionRepositoryFactory func(t *testing.T) ports.SubscriptionRepository
+
+// RunSubscriptionRepositoryTests checks that an adapter behaves like
+// ports.SubscriptionRepository expects
+func RunSubscriptionRepositoryTests(t *testing.T, factory SubscriptionRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.SubscriptionRepository)
+	}{
+		{"AddIsIdempotent", testAddSubscriptionIsIdempotent},
+		{"ListByTitleWithUnseen", testListSubscriptionsByTitle},
+		{"RecordPoll", testRecordSubscriptionPoll},
+		{"RemoveDropsFeed", testRemoveSubscriptionDropsFeed},
+		{"AddFeedItemsKeepsExisting", testAddFeedItemsKeepsExisting},
+		{"AddFeedItemsNeedsSubscription", testAddFeedItemsNeedsSubscription},
+		{"FeedIsChronological", testFeedIsChronological},
+		{"FeedFilters", testFeedFilters},
+		{"FeedCursor", testFeedCursor},
+		{"SetSeen", testSetFeedItemSeen},
+		{"MarkFeedSeen", testMarkFeedSeen},
+		{"SubscriptionsCancelledContext", testSubscriptionsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// testChannel returns a channel with a valid-looking ID derived from name
+func testChannel(name string) entities.Channel {
+	return entities.Channel{
+		ID:    ("UC" + name + "______________________")[:24],
+		Title: "Channel " + name,
+	}
+}
+
+// subscribe follows the named channels
+func subscribe(t *testing.T, repo ports.SubscriptionRepository, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		_, err := repo.AddSubscription(context.Background(), testChannel(name), baseTime)
+		require.NoError(t, err)
+	}
+}
+
+// feedItem returns an upload of the named channel published hoursAgo before baseTime
+func feedItem(channel, video string, hoursAgo int) entities.FeedItem {
+	v := testVideo(video)
+	v.Channel = testChannel(channel).Title
+	v.PublishedAt = baseTime.Add(-time.Duration(hoursAgo) * time.Hour)
+	return entities.FeedItem{Video: v, ChannelID: testChannel(channel).ID, FetchedAt: baseTime}
+}
+
+// feedTitles returns the titles of a feed listing
+func feedTitles(t *testing.T, repo ports.SubscriptionRepository, filter entities.FeedFilter) []string {
+	t.Helper()
+	items, err := repo.ListFeed(context.Background(), filter, nil, 100)
+	require.NoError(t, err)
+
+	titles := make([]string, 0, len(items))
+	for _, item := range items {
+		titles = append(titles, item.Title)
+	}
+	return titles
+}
+
+func testAddSubscriptionIsIdempotent(t *testing.T, repo ports.SubscriptionRepository) {
+	ctx := context.Background()
+
+	sub, err := repo.AddSubscription(ctx, testChannel("a"), baseTime)
+	require.NoError(t, err)
+	assert.Equal(t, testChannel("a"), sub.Channel)
+	assert.True(t, baseTime.Equal(sub.SubscribedAt))
+	assert.True(t, sub.LastPolledAt.IsZero())
+
+	renamed := testChannel("a")
+	renamed.Title = "Renamed"
+	again, err := repo.AddSubscription(ctx, renamed, baseTime.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, "Channel a", again.Title)
+	assert.True(t, baseTime.Equal(again.SubscribedAt))
+
+	subs, err := repo.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.Len(t, subs, 1)
+}
+
+func testListSubscriptionsByTitle(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "b", "a", "c")
+	_, err := repo.AddFeedItems(context.Background(), []entities.FeedItem{
+		feedItem("b", "v1", 1),
+		feedItem("b", "v2", 2),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.SetFeedItemSeen(context.Background(), testVideo("v2").ID, baseTime))
+
+	subs, err := repo.ListSubscriptions(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, subs, 3)
+	assert.Equal(t, "Channel a", subs[0].Title)
+	assert.Equal(t, "Channel b", subs[1].Title)
+	assert.Equal(t, 1, subs[1].UnseenCount)
+	assert.Equal(t, 0, subs[2].UnseenCount)
+}
+
+func testRecordSubscriptionPoll(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a")
+	ctx := context.Background()
+	id := testChannel("a").ID
+	polled := baseTime.Add(time.Hour)
+
+	require.NoError(t, repo.RecordSubscriptionPoll(ctx, id, polled, "feed unavailable"))
+	subs, err := repo.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.True(t, polled.Equal(subs[0].LastPolledAt))
+	assert.Equal(t, "feed unavailable", subs[0].LastError)
+
+	require.NoError(t, repo.RecordSubscriptionPoll(ctx, id, polled.Add(time.Hour), ""))
+	subs, err = repo.ListSubscriptions(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, subs[0].LastError)
+
+	err = repo.RecordSubscriptionPoll(ctx, testChannel("missing").ID, polled, "")
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testRemoveSubscriptionDropsFeed(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a", "b")
+	ctx := context.Background()
+	_, err := repo.AddFeedItems(ctx, []entities.FeedItem{feedItem("a", "v1", 1), feedItem("b", "v2", 2)})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RemoveSubscription(ctx, testChannel("a").ID))
+
+	assert.Equal(t, []string{"Video v2"}, feedTitles(t, repo, entities.FeedFilter{}))
+	err = repo.RemoveSubscription(ctx, testChannel("a").ID)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+
+	// Following it again starts from an empty feed for that channel
+	subscribe(t, repo, "a")
+	added, err := repo.AddFeedItems(ctx, []entities.FeedItem{feedItem("a", "v1", 1)})
+	require.NoError(t, err)
+	assert.Len(t, added, 1)
+}
+
+func testAddFeedItemsKeepsExisting(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a")
+	ctx := context.Background()
+
+	added, err := repo.AddFeedItems(ctx, []entities.FeedItem{feedItem("a", "v1", 1), feedItem("a", "v2", 2)})
+	require.NoError(t, err)
+	assert.Len(t, added, 2)
+	require.NoError(t, repo.SetFeedItemSeen(ctx, testVideo("v1").ID, baseTime))
+
+	added, err = repo.AddFeedItems(ctx, []entities.FeedItem{feedItem("a", "v0", 0), feedItem("a", "v1", 1)})
+	require.NoError(t, err)
+	require.Len(t, added, 1, "only items not in the feed yet are returned")
+	assert.Equal(t, testVideo("v0").ID, added[0].ID)
+
+	items, err := repo.ListFeed(ctx, entities.FeedFilter{}, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, items, 3)
+	assert.True(t, items[1].Seen(), "re-polling must not reset the seen state")
+
+	want := feedItem("a", "v0", 0)
+	assert.Equal(t, want.ID, items[0].ID)
+	assert.Equal(t, want.Channel, items[0].Channel)
+	assert.Equal(t, want.ChannelID, items[0].ChannelID)
+	assert.Equal(t, want.Thumbnail, items[0].Thumbnail)
+	assert.True(t, want.PublishedAt.Equal(items[0].PublishedAt))
+	assert.True(t, baseTime.Equal(items[0].FetchedAt))
+	assert.False(t, items[0].Seen())
+
+	added, err = repo.AddFeedItems(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, added)
+}
+
+func testAddFeedItemsNeedsSubscription(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a")
+
+	_, err := repo.AddFeedItems(context.Background(), []entities.FeedItem{
+		feedItem("a", "v1", 1),
+		feedItem("gone", "v2", 2),
+	})
+
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	assert.Empty(t, feedTitles(t, repo, entities.FeedFilter{}))
+}
+
+func testFeedIsChronological(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a", "b")
+	_, err := repo.AddFeedItems(context.Background(), []entities.FeedItem{
+		feedItem("a", "old", 48),
+		feedItem("b", "new", 1),
+		feedItem("a", "mid", 24),
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Video new", "Video mid", "Video old"}, feedTitles(t, repo, entities.FeedFilter{}))
+}
+
+func testFeedFilters(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a", "b")
+	ctx := context.Background()
+	_, err := repo.AddFeedItems(ctx, []entities.FeedItem{
+		feedItem("a", "a1", 1),
+		feedItem("a", "a2", 2),
+		feedItem("b", "b1", 3),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.SetFeedItemSeen(ctx, testVideo("a1").ID, baseTime))
+
+	assert.Equal(t, []string{"Video a2", "Video b1"}, feedTitles(t, repo, entities.FeedFilter{UnseenOnly: true}))
+	assert.Equal(t, []string{"Video a1", "Video a2"}, feedTitles(t, repo, entities.FeedFilter{ChannelID: testChannel("a").ID}))
+	assert.Equal(t, []string{"Video a2"}, feedTitles(t, repo, entities.FeedFilter{UnseenOnly: true, ChannelID: testChannel("a").ID}))
+}
+
+func testFeedCursor(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a")
+	ctx := context.Background()
+	// v2 and v3 share a publish time; the video ID breaks the tie
+	_, err := repo.AddFeedItems(ctx, []entities.FeedItem{
+		feedItem("a", "v1", 1),
+		feedItem("a", "v2", 2),
+		feedItem("a", "v3", 2),
+		feedItem("a", "v4", 3),
+	})
+	require.NoError(t, err)
+
+	first, err := repo.ListFeed(ctx, entities.FeedFilter{}, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	assert.Equal(t, "Video v1", first[0].Title)
+	assert.Equal(t, "Video v3", first[1].Title)
+
+	last := first[1]
+	rest, err := repo.ListFeed(ctx, entities.FeedFilter{}, &entities.FeedCursor{PublishedAt: last.PublishedAt, VideoID: last.ID}, 10)
+	require.NoError(t, err)
+	require.Len(t, rest, 2)
+	assert.Equal(t, "Video v2", rest[0].Title)
+	assert.Equal(t, "Video v4", rest[1].Title)
+}
+
+func testSetFeedItemSeen(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a")
+	ctx := context.Background()
+	_, err := repo.AddFeedItems(ctx, []entities.FeedItem{feedItem("a", "v1", 1)})
+	require.NoError(t, err)
+	id := testVideo("v1").ID
+
+	require.NoError(t, repo.SetFeedItemSeen(ctx, id, baseTime.Add(time.Hour)))
+	items, err := repo.ListFeed(ctx, entities.FeedFilter{}, nil, 10)
+	require.NoError(t, err)
+	assert.True(t, baseTime.Add(time.Hour).Equal(items[0].SeenAt))
+
+	require.NoError(t, repo.SetFeedItemSeen(ctx, id, time.Time{}))
+	items, err = repo.ListFeed(ctx, entities.FeedFilter{}, nil, 10)
+	require.NoError(t, err)
+	assert.False(t, items[0].Seen())
+
+	err = repo.SetFeedItemSeen(ctx, testVideo("missing").ID, baseTime)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testMarkFeedSeen(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a", "b")
+	ctx := context.Background()
+	_, err := repo.AddFeedItems(ctx, []entities.FeedItem{
+		feedItem("a", "a1", 1),
+		feedItem("a", "a2", 2),
+		feedItem("b", "b1", 3),
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.SetFeedItemSeen(ctx, testVideo("a1").ID, baseTime))
+
+	marked, err := repo.MarkFeedSeen(ctx, entities.FeedFilter{ChannelID: testChannel("a").ID}, baseTime.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	assert.Equal(t, []string{"Video b1"}, feedTitles(t, repo, entities.FeedFilter{UnseenOnly: true}))
+
+	// Items that were already seen keep their time
+	items, err := repo.ListFeed(ctx, entities.FeedFilter{ChannelID: testChannel("a").ID}, nil, 10)
+	require.NoError(t, err)
+	assert.True(t, baseTime.Equal(items[0].SeenAt))
+
+	marked, err = repo.MarkFeedSeen(ctx, entities.FeedFilter{}, baseTime.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+	assert.Empty(t, feedTitles(t, repo, entities.FeedFilter{UnseenOnly: true}))
+}
+
+func testSubscriptionsCancelledContext(t *testing.T, repo ports.SubscriptionRepository) {
+	subscribe(t, repo, "a")
+	_, err := repo.AddFeedItems(context.Background(), []entities.FeedItem{feedItem("a", "v1", 1)})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = repo.AddSubscription(ctx, testChannel("b"), baseTime)
+	assert.Error(t, err)
+	assert.Error(t, repo.RemoveSubscription(ctx, testChannel("a").ID))
+	_, err = repo.ListSubscriptions(ctx)
+	assert.Error(t, err)
+	assert.Error(t, repo.RecordSubscriptionPoll(ctx, testChannel("a").ID, baseTime, "x"))
+	_, err = repo.AddFeedItems(ctx, []entities.FeedItem{feedItem("a", "v2", 2)})
+	assert.Error(t, err)
+	_, err = repo.ListFeed(ctx, entities.FeedFilter{}, nil, 10)
+	assert.Error(t, err)
+	assert.Error(t, repo.SetFeedItemSeen(ctx, testVideo("v1").ID, baseTime))
+	_, err = repo.MarkFeedSeen(ctx, entities.FeedFilter{}, baseTime)
+	assert.Error(t, err)
+
+	// Nothing changed
+	subs, err := repo.ListSubscriptions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, subs, 1)
+	assert.Empty(t, subs[0].LastError)
+	assert.Equal(t, 1, subs[0].UnseenCount)
+	assert.Equal(t, []string{"Video v1"}, feedTitles(t, repo, entities.FeedFilter{}))
+}
@@ -0,0 +1,77 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// VideoProgressRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type VideoProgressRepositoryFactory func(t *testing.T) ports.VideoProgressRepository
+
+// RunVideoProgressRepositoryTests checks that an adapter behaves like
+// ports.VideoProgressRepository expects
+func RunVideoProgressRepositoryTests(t *testing.T, factory VideoProgressRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.VideoProgressRepository)
+	}{
+		{"SaveAndGet", testSaveVideoProgress},
+		{"SaveReplaces", testReplaceVideoProgress},
+		{"VideoProgressCancelledContext", testVideoProgressCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+func testSaveVideoProgress(t *testing.T, repo ports.VideoProgressRepository) {
+	ctx := context.Background()
+	require.NoError(t, repo.SaveVideoProgress(ctx, entities.VideoProgress{VideoID: "a", Position: 90, Duration: 600, UpdatedAt: baseTime}))
+	require.NoError(t, repo.SaveVideoProgress(ctx, entities.VideoProgress{VideoID: "b", Position: 10, Duration: 60, UpdatedAt: baseTime}))
+
+	progress, err := repo.GetVideoProgress(ctx, []string{"a", "missing"})
+	require.NoError(t, err)
+	require.Len(t, progress, 1, "videos without a position are left out")
+
+	got := progress["a"]
+	assert.Equal(t, "a", got.VideoID)
+	assert.Equal(t, 90, got.Position)
+	assert.Equal(t, 600, got.Duration)
+	assert.True(t, baseTime.Equal(got.UpdatedAt))
+
+	progress, err = repo.GetVideoProgress(ctx, nil)
+	require.NoError(t, err)
+	assert.Empty(t, progress)
+}
+
+func testReplaceVideoProgress(t *testing.T, repo ports.VideoProgressRepository) {
+	ctx := context.Background()
+	require.NoError(t, repo.SaveVideoProgress(ctx, entities.VideoProgress{VideoID: "a", Position: 300, Duration: 600, UpdatedAt: baseTime}))
+	// Seeking back is a new position like any other
+	require.NoError(t, repo.SaveVideoProgress(ctx, entities.VideoProgress{VideoID: "a", Position: 30, Duration: 600, UpdatedAt: baseTime.Add(time.Hour)}))
+
+	progress, err := repo.GetVideoProgress(ctx, []string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, 30, progress["a"].Position)
+	assert.True(t, baseTime.Add(time.Hour).Equal(progress["a"].UpdatedAt))
+}
+
+func testVideoProgressCancelledContext(t *testing.T, repo ports.VideoProgressRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.SaveVideoProgress(ctx, entities.VideoProgress{VideoID: "a", Position: 1, Duration: 2, UpdatedAt: baseTime})
+	assert.Error(t, err)
+	_, err = repo.GetVideoProgress(ctx, []string{"a"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,196 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// WatchEventRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type WatchEventRepositoryFactory func(t *testing.T) ports.WatchEventRepository
+
+// RunWatchEventRepositoryTests checks that an adapter behaves like
+// ports.WatchEventRepository expects
+// Linking viewings to searches is backend specific and not covered here
+func RunWatchEventRepositoryTests(t *testing.T, factory WatchEventRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.WatchEventRepository)
+	}{
+		{"AddAndGet", testAddWatchEvent},
+		{"ListNewestFirst", testListWatchEvents},
+		{"ProgressNeverGoesDown", testUpdateWatchEvent},
+		{"CloseEndsUpdates", testCloseWatchEvent},
+		{"WatchedSecondsSince", testWatchedSecondsSince},
+		{"ProfilesAreSeparate", testWatchEventProfiles},
+		{"WatchEventsCancelledContext", testWatchEventsCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// startWatch stores a default profile viewing of videoID started at startedAt and returns its ID
+func startWatch(t *testing.T, repo ports.WatchEventRepository, videoID string, startedAt time.Time) int64 {
+	t.Helper()
+	return startProfileWatch(t, repo, entities.DefaultProfile, videoID, startedAt)
+}
+
+// startProfileWatch stores a viewing of videoID by profile started at startedAt and returns its ID
+func startProfileWatch(t *testing.T, repo ports.WatchEventRepository, profile, videoID string, startedAt time.Time) int64 {
+	t.Helper()
+	e := &entities.WatchEvent{Profile: profile, VideoID: videoID, Title: "Title of " + videoID, StartedAt: startedAt}
+	require.NoError(t, repo.AddWatchEvent(context.Background(), e))
+	return e.ID
+}
+
+// watchedVideoIDs returns the video IDs of events in order
+func watchedVideoIDs(events []entities.WatchEvent) []string {
+	ids := make([]string, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.VideoID)
+	}
+	return ids
+}
+
+func testAddWatchEvent(t *testing.T, repo ports.WatchEventRepository) {
+	ctx := context.Background()
+	first := startWatch(t, repo, "a", baseTime)
+	second := startWatch(t, repo, "b", baseTime)
+
+	assert.NotZero(t, first)
+	assert.NotEqual(t, first, second)
+
+	got, err := repo.GetWatchEvent(ctx, first)
+	require.NoError(t, err)
+	assert.Equal(t, entities.DefaultProfile, got.Profile)
+	assert.Equal(t, "a", got.VideoID)
+	assert.Equal(t, "Title of a", got.Title)
+	assert.True(t, baseTime.Equal(got.StartedAt))
+	assert.True(t, baseTime.Equal(got.UpdatedAt))
+	assert.False(t, got.Closed())
+	assert.Zero(t, got.WatchedSeconds)
+
+	_, err = repo.GetWatchEvent(ctx, second+100)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testListWatchEvents(t *testing.T, repo ports.WatchEventRepository) {
+	ctx := context.Background()
+	startWatch(t, repo, "middle", baseTime.Add(time.Minute))
+	startWatch(t, repo, "oldest", baseTime)
+	startWatch(t, repo, "newest", baseTime.Add(time.Hour))
+
+	events, err := repo.ListWatchEvents(ctx, entities.DefaultProfile, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"newest", "middle", "oldest"}, watchedVideoIDs(events))
+
+	events, err = repo.ListWatchEvents(ctx, entities.DefaultProfile, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"newest", "middle"}, watchedVideoIDs(events))
+}
+
+func testUpdateWatchEvent(t *testing.T, repo ports.WatchEventRepository) {
+	ctx := context.Background()
+	id := startWatch(t, repo, "a", baseTime)
+
+	require.NoError(t, repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: baseTime.Add(time.Minute), WatchedSeconds: 45}))
+	// A late event carrying an older total must not undo progress
+	require.NoError(t, repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: baseTime.Add(2 * time.Minute), WatchedSeconds: 30}))
+
+	got, err := repo.GetWatchEvent(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, 45, got.WatchedSeconds)
+	assert.True(t, baseTime.Add(2*time.Minute).Equal(got.UpdatedAt))
+	assert.False(t, got.Closed())
+
+	err = repo.UpdateWatchEvent(ctx, id+100, entities.WatchProgress{At: baseTime, WatchedSeconds: 1})
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testCloseWatchEvent(t *testing.T, repo ports.WatchEventRepository) {
+	ctx := context.Background()
+	id := startWatch(t, repo, "a", baseTime)
+
+	closedAt := baseTime.Add(time.Minute)
+	require.NoError(t, repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: closedAt, WatchedSeconds: 50, Closed: true}))
+
+	// Neither progress nor a second close reaches a closed viewing
+	err := repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: closedAt.Add(time.Minute), WatchedSeconds: 55})
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+	err = repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: closedAt.Add(time.Minute), WatchedSeconds: 55, Closed: true})
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+
+	got, err := repo.GetWatchEvent(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, got.Closed())
+	assert.True(t, closedAt.Equal(got.ClosedAt))
+	assert.True(t, closedAt.Equal(got.UpdatedAt))
+	assert.Equal(t, 50, got.WatchedSeconds)
+}
+
+func testWatchedSecondsSince(t *testing.T, repo ports.WatchEventRepository) {
+	ctx := context.Background()
+	before := startWatch(t, repo, "before", baseTime.Add(-time.Second))
+	atStart := startWatch(t, repo, "at-start", baseTime)
+	after := startWatch(t, repo, "after", baseTime.Add(time.Hour))
+	for id, seconds := range map[int64]int{before: 100, atStart: 20, after: 3} {
+		require.NoError(t, repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: baseTime.Add(2 * time.Hour), WatchedSeconds: seconds}))
+	}
+
+	total, err := repo.WatchedSecondsSince(ctx, entities.DefaultProfile, baseTime)
+	require.NoError(t, err)
+	assert.Equal(t, 23, total, "viewings started at or after since")
+
+	total, err = repo.WatchedSecondsSince(ctx, entities.DefaultProfile, baseTime.Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func testWatchEventProfiles(t *testing.T, repo ports.WatchEventRepository) {
+	ctx := context.Background()
+	own := startProfileWatch(t, repo, entities.DefaultProfile, "own", baseTime)
+	other := startProfileWatch(t, repo, "kids", "other", baseTime.Add(time.Minute))
+	for id, seconds := range map[int64]int{own: 30, other: 600} {
+		require.NoError(t, repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{At: baseTime.Add(time.Hour), WatchedSeconds: seconds}))
+	}
+
+	events, err := repo.ListWatchEvents(ctx, entities.DefaultProfile, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"own"}, watchedVideoIDs(events))
+
+	events, err = repo.ListWatchEvents(ctx, "kids", 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other"}, watchedVideoIDs(events))
+	assert.Equal(t, "kids", events[0].Profile)
+
+	total, err := repo.WatchedSecondsSince(ctx, entities.DefaultProfile, baseTime)
+	require.NoError(t, err)
+	assert.Equal(t, 30, total, "another profile's viewings do not count")
+}
+
+func testWatchEventsCancelledContext(t *testing.T, repo ports.WatchEventRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.AddWatchEvent(ctx, &entities.WatchEvent{VideoID: "a", StartedAt: baseTime})
+	assert.Error(t, err)
+	_, err = repo.GetWatchEvent(ctx, 1)
+	assert.Error(t, err)
+	err = repo.UpdateWatchEvent(ctx, 1, entities.WatchProgress{At: baseTime})
+	assert.Error(t, err)
+	_, err = repo.ListWatchEvents(ctx, entities.DefaultProfile, 10)
+	assert.Error(t, err)
+	_, err = repo.WatchedSecondsSince(ctx, entities.DefaultProfile, baseTime)
+	assert.Error(t, err)
+}
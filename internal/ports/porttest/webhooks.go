@@ -0,0 +1,212 @@
+package porttest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// WebhookDeliveryRepositoryFactory returns an empty repository
+// Register any cleanup with t.Cleanup
+type WebhookDeliveryRepositoryFactory func(t *testing.T) ports.WebhookDeliveryRepository
+
+// RunWebhookDeliveryRepositoryTests checks that an adapter behaves like
+// ports.WebhookDeliveryRepository expects
+func RunWebhookDeliveryRepositoryTests(t *testing.T, factory WebhookDeliveryRepositoryFactory) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, repo ports.WebhookDeliveryRepository)
+	}{
+		{"EnqueueAndList", testEnqueueWebhookDeliveries},
+		{"DueInOrder", testDueWebhookDeliveries},
+		{"RecordAttempt", testRecordWebhookAttempt},
+		{"Retry", testRetryWebhookDelivery},
+		{"PruneKeepsPending", testPruneWebhookDeliveries},
+		{"WebhooksCancelledContext", testWebhooksCancelledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, factory(t))
+		})
+	}
+}
+
+// enqueue stores a pending delivery for endpoint, created and due at dueAt, and returns its ID
+func enqueue(t *testing.T, repo ports.WebhookDeliveryRepository, endpoint string, dueAt time.Time) int64 {
+	t.Helper()
+	d := &entities.WebhookDelivery{
+		Endpoint:      endpoint,
+		Event:         entities.EventSavedSearchNewVideos,
+		Payload:       []byte(`{"endpoint":"` + endpoint + `"}`),
+		NextAttemptAt: dueAt,
+		CreatedAt:     dueAt,
+	}
+	require.NoError(t, repo.EnqueueWebhookDeliveries(context.Background(), []*entities.WebhookDelivery{d}))
+	return d.ID
+}
+
+// deliveryEndpoints returns the endpoints of deliveries in order
+func deliveryEndpoints(deliveries []entities.WebhookDelivery) []string {
+	endpoints := make([]string, 0, len(deliveries))
+	for _, d := range deliveries {
+		endpoints = append(endpoints, d.Endpoint)
+	}
+	return endpoints
+}
+
+func testEnqueueWebhookDeliveries(t *testing.T, repo ports.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	first := &entities.WebhookDelivery{
+		Endpoint: "slack", Event: entities.EventSubscriptionNewVideos, Payload: []byte(`{"text":"hi"}`),
+		NextAttemptAt: baseTime, CreatedAt: baseTime,
+	}
+	second := &entities.WebhookDelivery{
+		Endpoint: "ci", Event: entities.EventSubscriptionNewVideos, Payload: []byte(`{}`),
+		NextAttemptAt: baseTime, CreatedAt: baseTime.Add(time.Second),
+	}
+
+	require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, []*entities.WebhookDelivery{first, second}))
+	require.NoError(t, repo.EnqueueWebhookDeliveries(ctx, nil))
+
+	assert.NotZero(t, first.ID)
+	assert.NotEqual(t, first.ID, second.ID)
+
+	deliveries, err := repo.ListWebhookDeliveries(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci", "slack"}, deliveryEndpoints(deliveries), "most recent first")
+
+	got := deliveries[1]
+	assert.Equal(t, first.ID, got.ID)
+	assert.Equal(t, entities.EventSubscriptionNewVideos, got.Event)
+	assert.Equal(t, `{"text":"hi"}`, string(got.Payload))
+	assert.Equal(t, entities.WebhookPending, got.Status)
+	assert.Zero(t, got.Attempts)
+	assert.True(t, baseTime.Equal(got.NextAttemptAt))
+	assert.True(t, baseTime.Equal(got.CreatedAt))
+	assert.True(t, got.LastAttemptAt.IsZero())
+	assert.Empty(t, got.LastError)
+
+	deliveries, err = repo.ListWebhookDeliveries(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ci"}, deliveryEndpoints(deliveries))
+}
+
+func testDueWebhookDeliveries(t *testing.T, repo ports.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	enqueue(t, repo, "later", baseTime.Add(2*time.Minute))
+	enqueue(t, repo, "second", baseTime.Add(time.Minute))
+	enqueue(t, repo, "first", baseTime)
+	enqueue(t, repo, "future", baseTime.Add(time.Hour))
+
+	due, err := repo.DueWebhookDeliveries(ctx, baseTime.Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", "later"}, deliveryEndpoints(due), "due at or before now, oldest first")
+
+	due, err = repo.DueWebhookDeliveries(ctx, baseTime.Add(2*time.Minute), 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, deliveryEndpoints(due))
+}
+
+func testRecordWebhookAttempt(t *testing.T, repo ports.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	retried := enqueue(t, repo, "retried", baseTime)
+	delivered := enqueue(t, repo, "delivered", baseTime)
+
+	attemptedAt := baseTime.Add(time.Second)
+	require.NoError(t, repo.RecordWebhookAttempt(ctx, retried, entities.WebhookAttempt{
+		AttemptedAt:   attemptedAt,
+		Status:        entities.WebhookPending,
+		NextAttemptAt: baseTime.Add(time.Minute),
+		Error:         "receiver responded with HTTP 500",
+	}))
+	require.NoError(t, repo.RecordWebhookAttempt(ctx, delivered, entities.WebhookAttempt{
+		AttemptedAt: attemptedAt,
+		Status:      entities.WebhookDelivered,
+	}))
+
+	// Neither is due now: one waits for its retry, the other is finished
+	due, err := repo.DueWebhookDeliveries(ctx, baseTime.Add(30*time.Second), 10)
+	require.NoError(t, err)
+	assert.Empty(t, due)
+
+	due, err = repo.DueWebhookDeliveries(ctx, baseTime.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Equal(t, []string{"retried"}, deliveryEndpoints(due))
+	assert.Equal(t, 1, due[0].Attempts)
+	assert.True(t, attemptedAt.Equal(due[0].LastAttemptAt))
+	assert.Equal(t, "receiver responded with HTTP 500", due[0].LastError)
+
+	deliveries, err := repo.ListWebhookDeliveries(ctx, 10)
+	require.NoError(t, err)
+	for _, d := range deliveries {
+		if d.ID == delivered {
+			assert.Equal(t, entities.WebhookDelivered, d.Status)
+			assert.Equal(t, 1, d.Attempts)
+			assert.Empty(t, d.LastError)
+		}
+	}
+
+	err = repo.RecordWebhookAttempt(ctx, delivered+100, entities.WebhookAttempt{AttemptedAt: attemptedAt, Status: entities.WebhookDelivered})
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testRetryWebhookDelivery(t *testing.T, repo ports.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	id := enqueue(t, repo, "a", baseTime)
+	require.NoError(t, repo.RecordWebhookAttempt(ctx, id, entities.WebhookAttempt{
+		AttemptedAt: baseTime, Status: entities.WebhookFailed, Error: "could not reach the receiver",
+	}))
+
+	dueAt := baseTime.Add(time.Hour)
+	require.NoError(t, repo.RetryWebhookDelivery(ctx, id, dueAt))
+
+	due, err := repo.DueWebhookDeliveries(ctx, dueAt, 10)
+	require.NoError(t, err)
+	require.Len(t, due, 1)
+	assert.Equal(t, entities.WebhookPending, due[0].Status)
+	assert.Zero(t, due[0].Attempts, "a retry starts over")
+
+	err = repo.RetryWebhookDelivery(ctx, id+100, dueAt)
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
+
+func testPruneWebhookDeliveries(t *testing.T, repo ports.WebhookDeliveryRepository) {
+	ctx := context.Background()
+	oldDelivered := enqueue(t, repo, "old-delivered", baseTime)
+	oldFailed := enqueue(t, repo, "old-failed", baseTime)
+	enqueue(t, repo, "old-pending", baseTime)
+	recent := enqueue(t, repo, "recent", baseTime.Add(time.Hour))
+	for _, id := range []int64{oldDelivered, recent} {
+		require.NoError(t, repo.RecordWebhookAttempt(ctx, id, entities.WebhookAttempt{AttemptedAt: baseTime, Status: entities.WebhookDelivered}))
+	}
+	require.NoError(t, repo.RecordWebhookAttempt(ctx, oldFailed, entities.WebhookAttempt{AttemptedAt: baseTime, Status: entities.WebhookFailed}))
+
+	pruned, err := repo.PruneWebhookDeliveries(ctx, baseTime.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+
+	deliveries, err := repo.ListWebhookDeliveries(ctx, 10)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"old-pending", "recent"}, deliveryEndpoints(deliveries), "pending deliveries are never pruned")
+}
+
+func testWebhooksCancelledContext(t *testing.T, repo ports.WebhookDeliveryRepository) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := repo.EnqueueWebhookDeliveries(ctx, []*entities.WebhookDelivery{{
+		Endpoint: "a", Event: entities.EventSavedSearchNewVideos, Payload: []byte(`{}`), NextAttemptAt: baseTime, CreatedAt: baseTime,
+	}})
+	assert.Error(t, err)
+	_, err = repo.DueWebhookDeliveries(ctx, baseTime, 10)
+	assert.Error(t, err)
+	_, err = repo.ListWebhookDeliveries(ctx, 10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// SavedSearchRepository stores saved searches and the videos they have matched
+type SavedSearchRepository interface {
+	// CreateSavedSearch stores a search and sets its ID
+	// Validation AppError if another search has the same name (case-insensitive)
+	CreateSavedSearch(ctx context.Context, search *entities.SavedSearch) error
+	// ListSavedSearches returns every saved search by name, with ResultCount and NewCount set
+	ListSavedSearches(ctx context.Context) ([]entities.SavedSearch, error)
+	// GetSavedSearch returns a saved search with ResultCount and NewCount set
+	// NotFound AppError if it doesn't exist
+	GetSavedSearch(ctx context.Context, id int64) (*entities.SavedSearch, error)
+	// DeleteSavedSearch removes a saved search and its results
+	DeleteSavedSearch(ctx context.Context, id int64) error
+
+	// RecordSavedSearchCheck stores when a search was last run and the error, if any
+	// (empty checkErr clears it)
+	RecordSavedSearchCheck(ctx context.Context, id int64, checkedAt time.Time, checkErr string) error
+	// AddSavedSearchResults merges the videos of a successful refresh into the
+	// results and returns the ones that were not matched before
+	// Those are flagged New, except on the first successful refresh (the baseline)
+	AddSavedSearchResults(ctx context.Context, id int64, videos []entities.Video, refreshedAt time.Time) ([]entities.SavedSearchResult, error)
+	// ListSavedSearchResults returns a search's results: new ones first, then
+	// most recently found first
+	ListSavedSearchResults(ctx context.Context, id int64) ([]entities.SavedSearchResult, error)
+	// MarkSavedSearchSeen clears the New flag of a search's results and returns how many changed
+	MarkSavedSearchSeen(ctx context.Context, id int64) (int, error)
+}
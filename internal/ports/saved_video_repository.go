@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// SavedVideoRepository stores the watch-later list
+// Positions are 1-based and kept contiguous
+type SavedVideoRepository interface {
+	// AddSavedVideo appends a video to the end of the list and returns it
+	// Adding a video that is already saved returns it unchanged
+	// The video's metadata is only used if the catalog doesn't know the video yet
+	AddSavedVideo(ctx context.Context, video entities.Video, savedAt time.Time) (*entities.SavedVideo, error)
+	// RemoveSavedVideo takes a video off the list (NotFound AppError if it isn't saved)
+	RemoveSavedVideo(ctx context.Context, videoID string) error
+	// SetSavedVideoWatched records when a video was watched; zero watchedAt marks it unwatched
+	// NotFound AppError if the video isn't saved
+	SetSavedVideoWatched(ctx context.Context, videoID string, watchedAt time.Time) error
+	// MoveSavedVideo puts a video at position, shifting the videos in between
+	// Positions past the end move it to the end (NotFound AppError if it isn't saved)
+	MoveSavedVideo(ctx context.Context, videoID string, position int) error
+	// ListSavedVideos returns the whole list in position order
+	ListSavedVideos(ctx context.Context) ([]entities.SavedVideo, error)
+}
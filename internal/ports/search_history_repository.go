@@ -2,11 +2,50 @@ package ports
 
 import (
 	"context"
+	"time"
 
 	"github.com/uiansol/zentube/internal/entities"
 )
 
 type SearchHistoryRepository interface {
 	Save(ctx context.Context, history *entities.SearchHistory) error
+	// SaveBatch saves all histories in one transaction and sets their IDs
+	SaveBatch(ctx context.Context, histories []*entities.SearchHistory) error
 	GetLast(ctx context.Context, limit int) ([]entities.SearchHistory, error)
+	// GetTopQueries returns the most searched queries since the given time, most frequent first
+	GetTopQueries(ctx context.Context, since time.Time, limit int) ([]entities.QueryFrequency, error)
+	// List returns up to limit rows matching filter, newest first, starting after cursor (nil for the first page)
+	List(ctx context.Context, filter entities.SearchHistoryFilter, after *entities.SearchHistoryCursor, limit int) ([]entities.SearchHistory, error)
+	// Delete removes a single row (NotFound AppError if it doesn't exist)
+	Delete(ctx context.Context, id int64) error
+	// DeleteAll removes every row and returns how many were deleted
+	DeleteAll(ctx context.Context) (int64, error)
+}
+
+// SearchHistoryRetention prunes old search history in small batches
+// Each call deletes at most batchSize rows so write locks stay short
+type SearchHistoryRetention interface {
+	// PruneOlderThan deletes rows created before cutoff
+	PruneOlderThan(ctx context.Context, cutoff time.Time, batchSize int, rollup bool) (int64, error)
+	// PruneExcess deletes the oldest rows beyond maxRows
+	PruneExcess(ctx context.Context, maxRows, batchSize int, rollup bool) (int64, error)
+	// Optimize reclaims free space and refreshes planner statistics
+	Optimize(ctx context.Context) error
+}
+
+// SearchHistoryFullText finds past searches by words they contain or by the
+// titles and descriptions of the videos they returned
+type SearchHistoryFullText interface {
+	// FullTextSearch returns ranked, highlighted matches, best first
+	FullTextSearch(ctx context.Context, text string, limit int) ([]entities.SearchHistoryMatch, error)
+}
+
+// SearchAnalytics aggregates search history for the insights page
+// Totals include rows that retention has already rolled up
+type SearchAnalytics interface {
+	// QueryStats returns totals per distinct query since the given time, in no particular order
+	QueryStats(ctx context.Context, since time.Time) ([]entities.QueryStats, error)
+	// DailyStats returns totals per UTC day since the given time, oldest first
+	// Days without searches are left out
+	DailyStats(ctx context.Context, since time.Time) ([]entities.DailySearchStats, error)
 }
@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// SearchIntentRepository stores search intents and whether they were found
+type SearchIntentRepository interface {
+	// AddSearchIntent stores a new intent and sets its ID
+	AddSearchIntent(ctx context.Context, intent *entities.SearchIntent) error
+	// GetSearchIntent returns an intent (NotFound AppError if it doesn't exist)
+	GetSearchIntent(ctx context.Context, id int64) (*entities.SearchIntent, error)
+	// LatestSearchIntent returns the most recently created intent
+	// (NotFound AppError if there is none)
+	LatestSearchIntent(ctx context.Context) (*entities.SearchIntent, error)
+	// RateSearchIntent records whether the intent was found, replacing any earlier answer
+	// (NotFound AppError if it doesn't exist)
+	RateSearchIntent(ctx context.Context, id int64, outcome string, ratedAt time.Time) error
+	// ListSearchIntents returns up to limit intents with the searches and viewings
+	// made for them, most recently created first
+	ListSearchIntents(ctx context.Context, limit int) ([]entities.IntentActivity, error)
+}
@@ -0,0 +1,34 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// SubscriptionRepository stores followed channels and the uploads polled from them
+type SubscriptionRepository interface {
+	// AddSubscription follows a channel and returns it
+	// Following a channel twice returns the existing subscription unchanged
+	AddSubscription(ctx context.Context, channel entities.Channel, subscribedAt time.Time) (*entities.Subscription, error)
+	// RemoveSubscription unfollows a channel and drops its feed items
+	// NotFound AppError if the channel isn't followed
+	RemoveSubscription(ctx context.Context, channelID string) error
+	// ListSubscriptions returns every followed channel by title, with UnseenCount set
+	ListSubscriptions(ctx context.Context) ([]entities.Subscription, error)
+	// RecordSubscriptionPoll stores when a channel was polled and the error, if any
+	// (empty pollErr clears it); NotFound AppError if the channel isn't followed
+	RecordSubscriptionPoll(ctx context.Context, channelID string, polledAt time.Time, pollErr string) error
+
+	// AddFeedItems stores uploads of a followed channel and returns the ones that were new
+	// Items already in the feed are left as they are, including their seen state
+	AddFeedItems(ctx context.Context, items []entities.FeedItem) ([]entities.FeedItem, error)
+	// ListFeed returns feed items matching filter, newest upload first, after the cursor
+	ListFeed(ctx context.Context, filter entities.FeedFilter, after *entities.FeedCursor, limit int) ([]entities.FeedItem, error)
+	// SetFeedItemSeen marks an item seen at seenAt; zero seenAt marks it unseen
+	// NotFound AppError if the video isn't in the feed
+	SetFeedItemSeen(ctx context.Context, videoID string, seenAt time.Time) error
+	// MarkFeedSeen marks every unseen item matching filter as seen and returns how many changed
+	MarkFeedSeen(ctx context.Context, filter entities.FeedFilter, seenAt time.Time) (int, error)
+}
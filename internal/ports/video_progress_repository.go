@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// VideoProgressRepository keeps the last playback position of each video
+type VideoProgressRepository interface {
+	// SaveVideoProgress replaces the stored position of a video
+	SaveVideoProgress(ctx context.Context, progress entities.VideoProgress) error
+	// GetVideoProgress returns the stored positions of the given videos, keyed by video ID
+	// Videos without a stored position are left out
+	GetVideoProgress(ctx context.Context, videoIDs []string) (map[string]entities.VideoProgress, error)
+}
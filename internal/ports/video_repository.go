@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// VideoRepository is the local catalog of videos seen in search results
+type VideoRepository interface {
+	// SaveSearchResults upserts videos and links them, in rank order, to a search history row
+	// Viewings of those videos recorded before the row was saved are linked to it too
+	SaveSearchResults(ctx context.Context, searchID int64, videos []entities.Video) error
+	// GetByID returns a catalogued video (NotFound AppError if it was never seen)
+	GetByID(ctx context.Context, id string) (*entities.Video, error)
+	// GetSearchResults returns the videos a past search returned, in rank order
+	GetSearchResults(ctx context.Context, searchID int64) ([]entities.Video, error)
+}
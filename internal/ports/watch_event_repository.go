@@ -0,0 +1,28 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// WatchEventRepository stores what was watched in the player
+// Viewings belong to a profile; listing and totals only cover one profile
+type WatchEventRepository interface {
+	// AddWatchEvent stores a new viewing under its profile and sets its ID
+	// Backends that keep search results also set SearchID and SearchQuery
+	// to the latest search, started no later than the viewing, that returned the video
+	// A search saved after the viewing was added is linked when its results are saved
+	AddWatchEvent(ctx context.Context, event *entities.WatchEvent) error
+	// GetWatchEvent returns a viewing (NotFound AppError if it doesn't exist)
+	GetWatchEvent(ctx context.Context, id int64) (*entities.WatchEvent, error)
+	// UpdateWatchEvent records progress on a viewing (NotFound AppError if it doesn't exist)
+	// Watched seconds never go down, so late or reordered events are harmless
+	// A closed viewing can't be updated (Validation AppError)
+	UpdateWatchEvent(ctx context.Context, id int64, progress entities.WatchProgress) error
+	// ListWatchEvents returns up to limit viewings of a profile, most recently started first
+	ListWatchEvents(ctx context.Context, profile string, limit int) ([]entities.WatchEvent, error)
+	// WatchedSecondsSince totals the watched seconds of a profile's viewings started at or after since
+	WatchedSecondsSince(ctx context.Context, profile string, since time.Time) (int, error)
+}
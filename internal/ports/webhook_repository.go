@@ -0,0 +1,27 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// WebhookDeliveryRepository persists queued webhook deliveries and their outcome (the delivery log)
+type WebhookDeliveryRepository interface {
+	// EnqueueWebhookDeliveries stores pending deliveries in one transaction and sets their IDs
+	EnqueueWebhookDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error
+	// DueWebhookDeliveries returns up to limit pending deliveries due at or before now, oldest due first
+	DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]entities.WebhookDelivery, error)
+	// RecordWebhookAttempt counts an attempt and stores its outcome
+	// NotFound AppError if the delivery doesn't exist
+	RecordWebhookAttempt(ctx context.Context, id int64, attempt entities.WebhookAttempt) error
+	// ListWebhookDeliveries returns up to limit deliveries, most recent first
+	ListWebhookDeliveries(ctx context.Context, limit int) ([]entities.WebhookDelivery, error)
+	// RetryWebhookDelivery makes a delivered or failed delivery pending again, due at dueAt
+	// Attempts start over; NotFound AppError if the delivery doesn't exist
+	RetryWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) error
+	// PruneWebhookDeliveries deletes finished (delivered or failed) deliveries
+	// created before cutoff and returns how many it deleted
+	PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int, error)
+}
@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// WebhookSender POSTs webhook payloads
+type WebhookSender interface {
+	// SendWebhook POSTs body as JSON to url with the extra headers and returns
+	// the response status (0 if no response arrived)
+	// Anything but a 2xx status is reported as an error
+	SendWebhook(ctx context.Context, url string, body []byte, headers map[string]string) (int, error)
+}
@@ -0,0 +1,74 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// BackupJob takes scheduled database backups and keeps the newest few
+type BackupJob struct {
+	store    ports.BackupStore
+	interval time.Duration
+	keep     int
+	logger   *slog.Logger
+}
+
+// NewBackupJob creates a new scheduled backup job
+func NewBackupJob(store ports.BackupStore, interval time.Duration, keep int, logger *slog.Logger) *BackupJob {
+	return &BackupJob{
+		store:    store,
+		interval: interval,
+		keep:     keep,
+		logger:   logger,
+	}
+}
+
+// Run takes a backup on every interval until ctx is cancelled
+// The first backup is taken one interval after startup
+func (j *BackupJob) Run(ctx context.Context) {
+	j.logger.Info("backup job started",
+		slog.Duration("interval", j.interval),
+		slog.Int("keep", j.keep),
+	)
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			j.logger.Info("backup job stopped")
+			return
+		case <-ticker.C:
+			j.RunOnce(ctx)
+		}
+	}
+}
+
+// RunOnce takes a single backup and rotates old ones
+func (j *BackupJob) RunOnce(ctx context.Context) {
+	start := time.Now()
+
+	path, err := j.store.CreateBackup(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		j.logger.Error("database backup failed", slog.Any("error", err))
+		return
+	}
+
+	removed, err := j.store.PruneBackups(j.keep)
+	if err != nil {
+		j.logger.Error("backup rotation failed", slog.Any("error", err))
+	}
+
+	j.logger.Info("database backup completed",
+		slog.String("path", path),
+		slog.Int("rotated", len(removed)),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
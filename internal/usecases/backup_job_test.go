@@ -0,0 +1,138 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeBackupStore keeps backup names in memory, oldest first
+type fakeBackupStore struct {
+	mu        sync.Mutex
+	backups   []string
+	created   int
+	createErr error
+	pruneErr  error
+	prunes    []int // keep argument of every PruneBackups call
+}
+
+func (s *fakeBackupStore) CreateBackup(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.createErr != nil {
+		return "", s.createErr
+	}
+	s.created++
+	path := fmt.Sprintf("backup-%d.db", s.created)
+	s.backups = append(s.backups, path)
+	return path, nil
+}
+
+func (s *fakeBackupStore) PruneBackups(keep int) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prunes = append(s.prunes, keep)
+	if s.pruneErr != nil {
+		return nil, s.pruneErr
+	}
+	if len(s.backups) <= keep {
+		return nil, nil
+	}
+	removed := append([]string(nil), s.backups[:len(s.backups)-keep]...)
+	s.backups = s.backups[len(s.backups)-keep:]
+	return removed, nil
+}
+
+func (s *fakeBackupStore) snapshot() (backups []string, prunes []int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.backups...), append([]int(nil), s.prunes...)
+}
+
+func newTestBackupJob(store *fakeBackupStore, interval time.Duration, keep int) *BackupJob {
+	return NewBackupJob(store, interval, keep, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestBackupJob_RunOnce_RotatesOldBackups(t *testing.T) {
+	// Arrange
+	store := &fakeBackupStore{}
+	job := newTestBackupJob(store, time.Hour, 2)
+
+	// Act
+	for i := 0; i < 4; i++ {
+		job.RunOnce(context.Background())
+	}
+
+	// Assert - only the newest two survive
+	backups, prunes := store.snapshot()
+	assert.Equal(t, []string{"backup-3.db", "backup-4.db"}, backups)
+	assert.Equal(t, []int{2, 2, 2, 2}, prunes)
+}
+
+func TestBackupJob_RunOnce_FailedBackupSkipsRotation(t *testing.T) {
+	// Arrange - existing backups must not be pruned when no new one was written
+	store := &fakeBackupStore{backups: []string{"old-1.db", "old-2.db", "old-3.db"}}
+	store.createErr = errors.New("disk full")
+	job := newTestBackupJob(store, time.Hour, 1)
+
+	// Act
+	job.RunOnce(context.Background())
+
+	// Assert
+	backups, prunes := store.snapshot()
+	assert.Equal(t, []string{"old-1.db", "old-2.db", "old-3.db"}, backups)
+	assert.Empty(t, prunes)
+}
+
+func TestBackupJob_RunOnce_RotationFailureKeepsNewBackup(t *testing.T) {
+	// Arrange
+	store := &fakeBackupStore{pruneErr: errors.New("permission denied")}
+	job := newTestBackupJob(store, time.Hour, 1)
+
+	// Act
+	job.RunOnce(context.Background())
+	job.RunOnce(context.Background())
+
+	// Assert - backups are still taken, rotation is retried every run
+	backups, prunes := store.snapshot()
+	assert.Equal(t, []string{"backup-1.db", "backup-2.db"}, backups)
+	assert.Equal(t, []int{1, 1}, prunes)
+}
+
+func TestBackupJob_Run_BacksUpOnScheduleUntilCancelled(t *testing.T) {
+	// Arrange
+	store := &fakeBackupStore{}
+	job := newTestBackupJob(store, 10*time.Millisecond, 3)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		job.Run(ctx)
+		close(done)
+	}()
+
+	// Act
+	assert.Eventually(t, func() bool {
+		backups, _ := store.snapshot()
+		return len(backups) == 3
+	}, time.Second, 5*time.Millisecond)
+	cancel()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not stop after cancellation")
+	}
+	backups, _ := store.snapshot()
+	assert.LessOrEqual(t, len(backups), 3)
+}
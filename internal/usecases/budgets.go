@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// BudgetLimits are the configured viewing budgets (zero disables one)
+type BudgetLimits struct {
+	Daily  time.Duration
+	Weekly time.Duration
+}
+
+// SearchBudget decides whether a new search may run for the profile in ctx
+// (a BudgetExhausted AppError refuses it); Budgets implements it
+type SearchBudget interface {
+	CheckSearch(ctx context.Context) error
+}
+
+// Budgets measures each profile's watch time against its daily and weekly budgets
+// The profile comes from the request context (entities.ProfileFromContext)
+// Days start at local midnight and weeks on Monday
+type Budgets struct {
+	watch    ports.WatchEventRepository
+	limits   BudgetLimits
+	profiles map[string]BudgetLimits
+}
+
+// NewBudgets creates a new Budgets use case
+// limits apply to the default profile; profiles lists the other profiles with their own limits
+func NewBudgets(watch ports.WatchEventRepository, limits BudgetLimits, profiles map[string]BudgetLimits) *Budgets {
+	return &Budgets{watch: watch, limits: limits, profiles: profiles}
+}
+
+// Enabled reports whether any profile has a budget configured
+func (b *Budgets) Enabled() bool {
+	if b.limits.enabled() {
+		return true
+	}
+	for _, limits := range b.profiles {
+		if limits.enabled() {
+			return true
+		}
+	}
+	return false
+}
+
+// Profiles returns the known profiles, the default one first and the rest by name
+func (b *Budgets) Profiles() []string {
+	names := make([]string, 0, len(b.profiles)+1)
+	for name := range b.profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{entities.DefaultProfile}, names...)
+}
+
+// HasProfile reports whether name is the default profile or a configured one
+func (b *Budgets) HasProfile(name string) bool {
+	if name == entities.DefaultProfile {
+		return true
+	}
+	_, ok := b.profiles[name]
+	return ok
+}
+
+// Status returns how much of each budget of the profile in ctx is used
+func (b *Budgets) Status(ctx context.Context) (entities.BudgetStatus, error) {
+	profile := entities.ProfileFromContext(ctx)
+	limits := b.limits
+	if profile != entities.DefaultProfile {
+		limits = b.profiles[profile]
+	}
+
+	status := entities.BudgetStatus{Profile: profile}
+	now := time.Now()
+
+	for _, limit := range []struct {
+		period string
+		limit  time.Duration
+	}{
+		{entities.BudgetDaily, limits.Daily},
+		{entities.BudgetWeekly, limits.Weekly},
+	} {
+		if limit.limit <= 0 {
+			continue
+		}
+
+		start, next := budgetPeriod(limit.period, now)
+		seconds, err := b.watch.WatchedSecondsSince(ctx, profile, start)
+		if err != nil {
+			return status, err
+		}
+		status.Budgets = append(status.Budgets, entities.Budget{
+			Period:   limit.period,
+			Limit:    limit.limit,
+			Used:     time.Duration(seconds) * time.Second,
+			ResetsAt: next,
+		})
+	}
+	return status, nil
+}
+
+// CheckSearch refuses new searches once a budget of the profile in ctx is used up
+func (b *Budgets) CheckSearch(ctx context.Context) error {
+	if !b.Enabled() {
+		return nil
+	}
+
+	status, err := b.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	exhausted := status.Exhausted()
+	if exhausted == nil {
+		return nil
+	}
+
+	period := "today's"
+	if exhausted.Period == entities.BudgetWeekly {
+		period = "this week's"
+	}
+	return appErrors.NewBudgetExhaustedError(fmt.Sprintf(
+		"You've used up %s viewing budget of %s. Searching is paused until %s.",
+		period, shortDuration(exhausted.Limit), exhausted.ResetsAt.Format("Mon Jan 2 15:04"),
+	))
+}
+
+func (l BudgetLimits) enabled() bool {
+	return l.Daily > 0 || l.Weekly > 0
+}
+
+// budgetPeriod returns the start of the day or week containing now and the start of the next one
+func budgetPeriod(period string, now time.Time) (time.Time, time.Time) {
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if period == entities.BudgetWeekly {
+		// Weekday counts from Sunday; weeks start on Monday
+		start = start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+		return start, start.AddDate(0, 0, 7)
+	}
+	return start, start.AddDate(0, 0, 1)
+}
+
+// shortDuration renders a duration in whole minutes without zero units (e.g. "1h", "1h30m", "45m")
+func shortDuration(d time.Duration) string {
+	s := d.Round(time.Minute).String()
+	s = strings.TrimSuffix(s, "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	if s == "" {
+		return "0m"
+	}
+	return s
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+func TestBudgetPeriod(t *testing.T) {
+	// Wednesday afternoon
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, time.UTC)
+
+	start, next := budgetPeriod(entities.BudgetDaily, now)
+	assert.Equal(t, time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), next)
+
+	start, next = budgetPeriod(entities.BudgetWeekly, now)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), start, "weeks start on Monday")
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), next)
+
+	sunday := time.Date(2026, 10, 18, 23, 0, 0, 0, time.UTC)
+	start, _ = budgetPeriod(entities.BudgetWeekly, sunday)
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), start, "Sunday ends the week")
+}
+
+func TestBudgets_Status(t *testing.T) {
+	// Arrange
+	repo := new(MockWatchEventRepository)
+	dayStart, dayEnd := budgetPeriod(entities.BudgetDaily, time.Now())
+	weekStart, _ := budgetPeriod(entities.BudgetWeekly, time.Now())
+	repo.On("WatchedSecondsSince", mock.Anything, entities.DefaultProfile, dayStart).Return(20*60, nil)
+	repo.On("WatchedSecondsSince", mock.Anything, entities.DefaultProfile, weekStart).Return(5*3600, nil)
+
+	uc := NewBudgets(repo, BudgetLimits{Daily: time.Hour, Weekly: 5 * time.Hour}, nil)
+
+	// Act
+	status, err := uc.Status(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, entities.DefaultProfile, status.Profile)
+	require.Len(t, status.Budgets, 2)
+
+	daily := status.Budgets[0]
+	assert.Equal(t, entities.BudgetDaily, daily.Period)
+	assert.Equal(t, 20*time.Minute, daily.Used)
+	assert.Equal(t, 40*time.Minute, daily.Remaining())
+	assert.Equal(t, dayEnd, daily.ResetsAt)
+	assert.False(t, daily.Exhausted())
+
+	weekly := status.Budgets[1]
+	assert.Equal(t, entities.BudgetWeekly, weekly.Period)
+	assert.Zero(t, weekly.Remaining())
+	assert.True(t, weekly.Exhausted())
+	assert.Equal(t, &status.Budgets[1], status.Exhausted())
+}
+
+func TestBudgets_CheckSearch(t *testing.T) {
+	t.Run("no budget configured", func(t *testing.T) {
+		repo := new(MockWatchEventRepository)
+		uc := NewBudgets(repo, BudgetLimits{}, nil)
+
+		assert.NoError(t, uc.CheckSearch(context.Background()))
+		repo.AssertNotCalled(t, "WatchedSecondsSince", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("budget left", func(t *testing.T) {
+		repo := new(MockWatchEventRepository)
+		repo.On("WatchedSecondsSince", mock.Anything, mock.Anything, mock.Anything).Return(59*60, nil)
+		uc := NewBudgets(repo, BudgetLimits{Daily: time.Hour}, nil)
+
+		assert.NoError(t, uc.CheckSearch(context.Background()))
+	})
+
+	t.Run("budget used up", func(t *testing.T) {
+		repo := new(MockWatchEventRepository)
+		repo.On("WatchedSecondsSince", mock.Anything, mock.Anything, mock.Anything).Return(90*60, nil)
+		uc := NewBudgets(repo, BudgetLimits{Daily: 90 * time.Minute}, nil)
+
+		err := uc.CheckSearch(context.Background())
+
+		assert.Equal(t, appErrors.ErrCodeBudgetExhausted, appErrors.GetErrorCode(err))
+		assert.Contains(t, err.Error(), "today's viewing budget of 1h30m")
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := new(MockWatchEventRepository)
+		repo.On("WatchedSecondsSince", mock.Anything, mock.Anything, mock.Anything).Return(0, errors.New("database is locked"))
+		uc := NewBudgets(repo, BudgetLimits{Weekly: time.Hour}, nil)
+
+		assert.Error(t, uc.CheckSearch(context.Background()))
+	})
+}
+
+func TestBudgets_Profiles(t *testing.T) {
+	// Arrange - the default profile has no budget, kids has a used up one
+	repo := new(MockWatchEventRepository)
+	repo.On("WatchedSecondsSince", mock.Anything, "kids", mock.Anything).Return(45*60, nil)
+	uc := NewBudgets(repo, BudgetLimits{}, map[string]BudgetLimits{
+		"kids":  {Daily: 45 * time.Minute},
+		"adult": {},
+	})
+	kids := entities.WithProfile(context.Background(), "kids")
+
+	// Act
+	status, err := uc.Status(kids)
+	refused := uc.CheckSearch(kids)
+	allowed := uc.CheckSearch(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "kids", status.Profile)
+	require.Len(t, status.Budgets, 1)
+	assert.True(t, status.Budgets[0].Exhausted())
+	assert.Equal(t, appErrors.ErrCodeBudgetExhausted, appErrors.GetErrorCode(refused))
+	assert.NoError(t, allowed, "another profile's budget does not apply")
+	assert.True(t, uc.Enabled())
+	assert.Equal(t, []string{entities.DefaultProfile, "adult", "kids"}, uc.Profiles())
+	assert.True(t, uc.HasProfile("kids"))
+	assert.True(t, uc.HasProfile(entities.DefaultProfile))
+	assert.False(t, uc.HasProfile("guest"))
+}
+
+// refusingBudget refuses every search as if a budget were used up
+type refusingBudget struct{}
+
+func (refusingBudget) CheckSearch(context.Context) error {
+	return appErrors.NewBudgetExhaustedError("You've used up today's viewing budget of 1h")
+}
+
+func TestShortDuration(t *testing.T) {
+	tests := map[time.Duration]string{
+		0:                "0m",
+		45 * time.Minute: "45m",
+		time.Hour:        "1h",
+		90 * time.Minute: "1h30m",
+		10 * time.Hour:   "10h",
+	}
+	for d, want := range tests {
+		assert.Equal(t, want, shortDuration(d), d.String())
+	}
+}
@@ -0,0 +1,141 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// CacheWarmerConfig controls which queries are warmed and how often
+type CacheWarmerConfig struct {
+	TopN         int           // How many popular queries to keep warm
+	Window       time.Duration // How far back to look in search history
+	Interval     time.Duration // How often to run
+	RefreshAhead time.Duration // Refresh entries expiring within this window
+	QuotaPerRun  int           // Max upstream API calls per run (0 = no calls)
+	MaxResults   int64         // maxResults used by the search handler
+}
+
+// WarmResult summarizes a single warming run
+type WarmResult struct {
+	Candidates int
+	Refreshed  int
+	Fresh      int
+	Failed     int
+	OverQuota  int
+}
+
+// CacheWarmer keeps the most popular queries from search history in the cache
+// so that the first search after a restart (or TTL expiry) doesn't go upstream
+type CacheWarmer struct {
+	search      *SearchVideos
+	historyRepo ports.SearchHistoryRepository
+	cfg         CacheWarmerConfig
+	logger      *slog.Logger
+}
+
+// NewCacheWarmer creates a new cache warmer
+func NewCacheWarmer(search *SearchVideos, historyRepo ports.SearchHistoryRepository, cfg CacheWarmerConfig, logger *slog.Logger) *CacheWarmer {
+	return &CacheWarmer{
+		search:      search,
+		historyRepo: historyRepo,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// Run warms the cache immediately and then on every interval
+// Blocks until ctx is cancelled
+func (w *CacheWarmer) Run(ctx context.Context) {
+	w.logger.Info("cache warmer started",
+		slog.Int("top_n", w.cfg.TopN),
+		slog.Duration("window", w.cfg.Window),
+		slog.Duration("interval", w.cfg.Interval),
+		slog.Int("quota_per_run", w.cfg.QuotaPerRun),
+	)
+
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("cache warmer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single warming pass and logs the outcome
+func (w *CacheWarmer) runOnce(ctx context.Context) {
+	start := time.Now()
+
+	result, err := w.WarmOnce(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		w.logger.Error("cache warm run failed", slog.Any("error", err))
+		return
+	}
+
+	w.logger.Info("cache warm run completed",
+		slog.Int("candidates", result.Candidates),
+		slog.Int("refreshed", result.Refreshed),
+		slog.Int("fresh", result.Fresh),
+		slog.Int("failed", result.Failed),
+		slog.Int("over_quota", result.OverQuota),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// WarmOnce pre-fetches the top queries whose cache entries are missing or about to expire
+// Upstream calls are capped at QuotaPerRun; remaining candidates are counted as OverQuota
+func (w *CacheWarmer) WarmOnce(ctx context.Context) (WarmResult, error) {
+	var result WarmResult
+
+	since := time.Now().Add(-w.cfg.Window)
+	queries, err := w.historyRepo.GetTopQueries(ctx, since, w.cfg.TopN)
+	if err != nil {
+		return result, fmt.Errorf("failed to load top queries: %w", err)
+	}
+
+	result.Candidates = len(queries)
+	quota := w.cfg.QuotaPerRun
+
+	for _, q := range queries {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if !w.search.NeedsRefresh(q.Query, w.cfg.MaxResults, w.cfg.RefreshAhead) {
+			result.Fresh++
+			continue
+		}
+
+		if quota <= 0 {
+			result.OverQuota++
+			continue
+		}
+		quota--
+
+		if err := w.search.Refresh(ctx, q.Query, w.cfg.MaxResults); err != nil {
+			result.Failed++
+			w.logger.Warn("cache warm query failed",
+				slog.String("query", q.Query),
+				slog.Int("count", q.Count),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		result.Refreshed++
+	}
+
+	return result, nil
+}
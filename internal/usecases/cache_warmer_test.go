@@ -0,0 +1,143 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uiansol/zentube/internal/cache"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func newTestWarmer(client *MockYouTubeClient, repo *MockSearchHistoryRepository, c *cache.Cache, quota int) *CacheWarmer {
+	search := NewSearchVideosWithCache(client, repo, c, DefaultCachePolicy(c.DefaultTTL()))
+	cfg := CacheWarmerConfig{
+		TopN:         3,
+		Window:       24 * time.Hour,
+		Interval:     time.Minute,
+		RefreshAhead: 2 * time.Minute,
+		QuotaPerRun:  quota,
+		MaxResults:   10,
+	}
+	return NewCacheWarmer(search, repo, cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestCacheWarmer_WarmOnce_PrefetchesTopQueries(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	c := cache.NewCache(100, 5*time.Minute)
+
+	mockRepo.On("GetTopQueries", mock.Anything, mock.Anything, 3).Return([]entities.QueryFrequency{
+		{Query: "golang", Count: 5},
+		{Query: "rust", Count: 2},
+	}, nil)
+	mockClient.On("Search", "golang", int64(10)).Return([]entities.Video{{ID: "go1"}}, nil).Once()
+	mockClient.On("Search", "rust", int64(10)).Return([]entities.Video{{ID: "rs1"}}, nil).Once()
+
+	warmer := newTestWarmer(mockClient, mockRepo, c, 10)
+
+	// Act
+	result, err := warmer.WarmOnce(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.Candidates)
+	assert.Equal(t, 2, result.Refreshed)
+	assert.Equal(t, 2, c.Len())
+
+	// Warming never records search history
+	mockRepo.AssertNotCalled(t, "Save")
+
+	// A warmed query is served from cache without going upstream
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(h *entities.SearchHistory) bool {
+		return h.Query == "golang" && h.CacheHit
+	})).Return(nil).Once()
+
+	videos, err := warmer.search.Execute(context.Background(), "golang", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "go1", videos[0].ID)
+	mockClient.AssertNumberOfCalls(t, "Search", 2)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestCacheWarmer_WarmOnce_SkipsFreshEntries(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	c := cache.NewCache(100, 5*time.Minute)
+	c.Set(searchCacheKey("golang", 10), []entities.Video{{ID: "cached"}})
+
+	mockRepo.On("GetTopQueries", mock.Anything, mock.Anything, 3).Return([]entities.QueryFrequency{
+		{Query: "golang", Count: 5},
+	}, nil)
+
+	warmer := newTestWarmer(mockClient, mockRepo, c, 10)
+
+	// Act
+	result, err := warmer.WarmOnce(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Fresh)
+	assert.Equal(t, 0, result.Refreshed)
+	mockClient.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
+
+func TestCacheWarmer_WarmOnce_RespectsQuota(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	c := cache.NewCache(100, 5*time.Minute)
+
+	mockRepo.On("GetTopQueries", mock.Anything, mock.Anything, 3).Return([]entities.QueryFrequency{
+		{Query: "golang", Count: 5},
+		{Query: "rust", Count: 3},
+		{Query: "zig", Count: 1},
+	}, nil)
+	mockClient.On("Search", "golang", int64(10)).Return(nil, errors.New("API error")).Once()
+	mockClient.On("Search", "rust", int64(10)).Return([]entities.Video{{ID: "rs1"}}, nil).Once()
+
+	warmer := newTestWarmer(mockClient, mockRepo, c, 2)
+
+	// Act
+	result, err := warmer.WarmOnce(context.Background())
+
+	// Assert - failed calls still count against the quota
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, 1, result.Refreshed)
+	assert.Equal(t, 1, result.OverQuota)
+	mockClient.AssertNumberOfCalls(t, "Search", 2)
+}
+
+func TestCacheWarmer_Run_StopsOnCancel(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	mockRepo.On("GetTopQueries", mock.Anything, mock.Anything, 3).Return([]entities.QueryFrequency{}, nil)
+
+	warmer := newTestWarmer(mockClient, mockRepo, cache.NewCache(100, 5*time.Minute), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		warmer.Run(ctx)
+		close(done)
+	}()
+
+	// Act
+	cancel()
+
+	// Assert
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("warmer did not stop after cancel")
+	}
+}
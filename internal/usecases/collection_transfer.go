@@ -0,0 +1,330 @@
+package usecases
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// CollectionFormat is a file format collections can be exported to and imported from
+type CollectionFormat string
+
+// Supported collection formats
+// Only JSON keeps notes and full video details; M3U and plain URL lists are
+// for media players and spreadsheets
+const (
+	CollectionFormatJSON CollectionFormat = "json"
+	CollectionFormatM3U  CollectionFormat = "m3u"
+	CollectionFormatText CollectionFormat = "txt"
+)
+
+// MaxCollectionImportSize caps the size of an imported file in bytes
+const MaxCollectionImportSize = 1 << 20
+
+// collectionFileVersion is the version of the JSON export format
+const collectionFileVersion = 1
+
+// ParseCollectionFormat validates a format name ("" means JSON)
+func ParseCollectionFormat(s string) (CollectionFormat, error) {
+	switch f := CollectionFormat(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return CollectionFormatJSON, nil
+	case CollectionFormatJSON, CollectionFormatM3U, CollectionFormatText:
+		return f, nil
+	case "m3u8":
+		return CollectionFormatM3U, nil
+	default:
+		return "", appErrors.NewValidationError("format must be json, m3u or txt", nil)
+	}
+}
+
+// CollectionExport is an exported collection file
+type CollectionExport struct {
+	Filename string
+	Format   CollectionFormat
+	Data     []byte
+}
+
+// collectionFile is the JSON export format
+type collectionFile struct {
+	Version     int                  `json:"version"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	ExportedAt  time.Time            `json:"exported_at"`
+	Items       []collectionFileItem `json:"items"`
+}
+
+type collectionFileItem struct {
+	ID          string     `json:"id,omitempty"`
+	URL         string     `json:"url,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Channel     string     `json:"channel,omitempty"`
+	Thumbnail   string     `json:"thumbnail,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Note        string     `json:"note,omitempty"`
+}
+
+// Export renders a collection in the given format
+func (c *Collections) Export(ctx context.Context, id int64, format CollectionFormat) (*CollectionExport, error) {
+	collection, err := c.repo.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch format {
+	case CollectionFormatJSON:
+		data, err = exportCollectionJSON(collection)
+	case CollectionFormatM3U:
+		data = exportCollectionM3U(collection)
+	case CollectionFormatText:
+		data = exportCollectionText(collection)
+	default:
+		return nil, appErrors.NewValidationError("format must be json, m3u or txt", nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &CollectionExport{
+		Filename: collectionSlug(collection.Name) + "." + string(format),
+		Format:   format,
+		Data:     data,
+	}, nil
+}
+
+// Import creates a collection from an exported file
+// The format is detected from the content: JSON objects, M3U playlists
+// (#EXTM3U) or one link per line. name overrides the name stored in the
+// file, and is required for plain URL lists
+func (c *Collections) Import(ctx context.Context, data []byte, name string) (*entities.Collection, error) {
+	if len(data) > MaxCollectionImportSize {
+		return nil, appErrors.NewValidationError(
+			fmt.Sprintf("import file must be at most %d bytes", MaxCollectionImportSize), nil)
+	}
+
+	var parsed *collectionFile
+	var err error
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		parsed, err = parseCollectionJSON(trimmed)
+	} else {
+		parsed, err = parseCollectionLines(trimmed)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(name) != "" {
+		parsed.Name = name
+	}
+	if strings.TrimSpace(parsed.Name) == "" {
+		return nil, appErrors.NewValidationError("the file has no collection name, please give one", nil)
+	}
+
+	collectionName, description, err := validateCollection(parsed.Name, parsed.Description)
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Items) == 0 {
+		return nil, appErrors.NewValidationError("the file has no videos", nil)
+	}
+	if len(parsed.Items) > MaxCollectionItems {
+		return nil, appErrors.NewValidationError(
+			fmt.Sprintf("a collection can hold at most %d videos", MaxCollectionItems), nil)
+	}
+
+	now := time.Now()
+	collection := &entities.Collection{Name: collectionName, Description: description, CreatedAt: now, UpdatedAt: now}
+	for i, item := range parsed.Items {
+		video, note, err := c.importItem(ctx, item)
+		if err != nil {
+			var appErr *appErrors.AppError
+			if errors.As(err, &appErr) {
+				return nil, appErrors.NewValidationError(fmt.Sprintf("video %d: %s", i+1, appErr.Message), appErr.Err)
+			}
+			return nil, err
+		}
+		collection.Items = append(collection.Items, entities.CollectionItem{Video: video, Note: note, AddedAt: now})
+	}
+
+	if err := c.repo.CreateCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// importItem validates an imported item and fills in missing details from the catalog
+// The file's metadata is only stored for videos the catalog doesn't know yet
+func (c *Collections) importItem(ctx context.Context, item collectionFileItem) (entities.Video, string, error) {
+	ref := item.ID
+	if ref == "" {
+		ref = item.URL
+	}
+	videoID, err := validation.ParseVideoRef(ref)
+	if err != nil {
+		return entities.Video{}, "", err
+	}
+	note, err := validateNote(item.Note)
+	if err != nil {
+		return entities.Video{}, "", err
+	}
+	if err := validation.ValidateThumbnailURL(item.Thumbnail); err != nil {
+		return entities.Video{}, "", err
+	}
+
+	video := entities.Video{
+		ID:        videoID,
+		Title:     strings.TrimSpace(item.Title),
+		Channel:   strings.TrimSpace(item.Channel),
+		Thumbnail: item.Thumbnail,
+	}
+	// Titles and channels in a shared file are untrusted: cap them like notes
+	if err := validation.ValidateVideoMetadata(video.Title, video.Channel); err != nil {
+		return entities.Video{}, "", err
+	}
+	if item.PublishedAt != nil {
+		video.PublishedAt = *item.PublishedAt
+	}
+
+	// Lists only carry a link (and maybe a title): prefer what the catalog knows
+	if video.Channel == "" {
+		known := c.lookup(ctx, videoID)
+		if known.Title != videoID || video.Title == "" {
+			video = known
+		}
+	}
+	return video, note, nil
+}
+
+func exportCollectionJSON(collection *entities.Collection) ([]byte, error) {
+	file := collectionFile{
+		Version:     collectionFileVersion,
+		Name:        collection.Name,
+		Description: collection.Description,
+		ExportedAt:  time.Now().UTC(),
+		Items:       make([]collectionFileItem, 0, len(collection.Items)),
+	}
+	for _, item := range collection.Items {
+		fileItem := collectionFileItem{
+			ID:        item.ID,
+			URL:       watchURL(item.ID),
+			Title:     item.Title,
+			Channel:   item.Channel,
+			Thumbnail: item.Thumbnail,
+			Note:      item.Note,
+		}
+		if !item.PublishedAt.IsZero() {
+			published := item.PublishedAt.UTC()
+			fileItem.PublishedAt = &published
+		}
+		file.Items = append(file.Items, fileItem)
+	}
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode collection: %w", err)
+	}
+	return append(data, '\n'), nil
+}
+
+func exportCollectionM3U(collection *entities.Collection) []byte {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#PLAYLIST:%s\n", singleLine(collection.Name))
+	for _, item := range collection.Items {
+		fmt.Fprintf(&b, "#EXTINF:-1,%s\n%s\n", singleLine(item.Title), watchURL(item.ID))
+	}
+	return []byte(b.String())
+}
+
+func exportCollectionText(collection *entities.Collection) []byte {
+	var b strings.Builder
+	for _, item := range collection.Items {
+		b.WriteString(watchURL(item.ID))
+		b.WriteByte('\n')
+	}
+	return []byte(b.String())
+}
+
+func parseCollectionJSON(data []byte) (*collectionFile, error) {
+	var file collectionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, appErrors.NewValidationError("invalid collection JSON", err)
+	}
+	if file.Version > collectionFileVersion {
+		return nil, appErrors.NewValidationError(
+			fmt.Sprintf("collection file version %d is newer than this server supports", file.Version), nil)
+	}
+	return &file, nil
+}
+
+// parseCollectionLines reads an M3U playlist or a plain list of links
+// Blank lines and unknown # directives are skipped; #EXTINF supplies the
+// title of the link that follows it and #PLAYLIST the collection name
+func parseCollectionLines(data []byte) (*collectionFile, error) {
+	file := &collectionFile{}
+	var title string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			file.Name = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+		case strings.HasPrefix(line, "#EXTINF:"):
+			if _, t, ok := strings.Cut(line, ","); ok {
+				title = strings.TrimSpace(t)
+			}
+		case strings.HasPrefix(line, "#"):
+		default:
+			file.Items = append(file.Items, collectionFileItem{URL: line, Title: title})
+			title = ""
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, appErrors.NewValidationError("invalid playlist", err)
+	}
+	return file, nil
+}
+
+// watchURL returns the YouTube watch page of a video
+func watchURL(videoID string) string {
+	return "https://www.youtube.com/watch?v=" + videoID
+}
+
+// singleLine folds line breaks so a value fits on one playlist line
+func singleLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// collectionSlug turns a collection name into a safe file name
+func collectionSlug(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimSuffix(b.String(), "-")
+	if slug == "" {
+		return "collection"
+	}
+	return slug
+}
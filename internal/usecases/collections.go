@@ -0,0 +1,189 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// Collection limits
+const (
+	MaxCollectionNameLength        = 100
+	MaxCollectionDescriptionLength = 1000
+	MaxCollectionNoteLength        = 1000
+	MaxCollectionItems             = 1000
+)
+
+// Collections manages named collections: create, rename, delete and edit their items
+// Export and import live in collection_transfer.go
+type Collections struct {
+	repo   ports.CollectionRepository
+	videos ports.VideoRepository // optional catalog for filling in video details
+}
+
+// NewCollections creates a new Collections use case
+// videos may be nil; items added by link then use the video ID as their title
+func NewCollections(repo ports.CollectionRepository, videos ports.VideoRepository) *Collections {
+	return &Collections{repo: repo, videos: videos}
+}
+
+// Create creates an empty collection
+func (c *Collections) Create(ctx context.Context, name, description string) (*entities.Collection, error) {
+	name, description, err := validateCollection(name, description)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	collection := &entities.Collection{Name: name, Description: description, CreatedAt: now, UpdatedAt: now}
+	if err := c.repo.CreateCollection(ctx, collection); err != nil {
+		return nil, err
+	}
+	return collection, nil
+}
+
+// Update renames a collection and replaces its description
+func (c *Collections) Update(ctx context.Context, id int64, name, description string) error {
+	name, description, err := validateCollection(name, description)
+	if err != nil {
+		return err
+	}
+	return c.repo.UpdateCollection(ctx, id, name, description, time.Now())
+}
+
+// Delete removes a collection
+func (c *Collections) Delete(ctx context.Context, id int64) error {
+	return c.repo.DeleteCollection(ctx, id)
+}
+
+// List returns every collection by name, without items
+func (c *Collections) List(ctx context.Context) ([]entities.Collection, error) {
+	return c.repo.ListCollections(ctx)
+}
+
+// Get returns a collection with its items
+func (c *Collections) Get(ctx context.Context, id int64) (*entities.Collection, error) {
+	return c.repo.GetCollection(ctx, id)
+}
+
+// AddItem appends a video to a collection (adding it twice is a no-op)
+// An empty title is filled in from the catalog, or falls back to the video ID
+func (c *Collections) AddItem(ctx context.Context, id int64, video entities.Video, note string) (*entities.CollectionItem, error) {
+	if err := validation.ValidateVideoID(video.ID); err != nil {
+		return nil, err
+	}
+	note, err := validateNote(note)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateThumbnailURL(video.Thumbnail); err != nil {
+		return nil, err
+	}
+
+	collection, err := c.repo.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(collection.Items) >= MaxCollectionItems {
+		return nil, appErrors.NewValidationError(
+			fmt.Sprintf("a collection can hold at most %d videos", MaxCollectionItems), nil)
+	}
+
+	video.Title = strings.TrimSpace(video.Title)
+	video.Channel = strings.TrimSpace(video.Channel)
+	if err := validation.ValidateVideoMetadata(video.Title, video.Channel); err != nil {
+		return nil, err
+	}
+	if video.Title == "" {
+		video = c.lookup(ctx, video.ID)
+	}
+
+	return c.repo.AddCollectionItem(ctx, id, video, note, time.Now())
+}
+
+// AddLink appends the video a YouTube link (or bare ID) points to
+func (c *Collections) AddLink(ctx context.Context, id int64, link, note string) (*entities.CollectionItem, error) {
+	videoID, err := validation.ParseVideoRef(link)
+	if err != nil {
+		return nil, err
+	}
+	return c.AddItem(ctx, id, entities.Video{ID: videoID}, note)
+}
+
+// SetNote replaces the note on a collection item
+func (c *Collections) SetNote(ctx context.Context, id int64, videoID, note string) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+	note, err := validateNote(note)
+	if err != nil {
+		return err
+	}
+	return c.repo.SetCollectionItemNote(ctx, id, videoID, note)
+}
+
+// MoveItem puts an item at a 1-based position (past the end means last)
+func (c *Collections) MoveItem(ctx context.Context, id int64, videoID string, position int) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+	if position < 1 {
+		return appErrors.NewValidationError("position must be at least 1", nil)
+	}
+	return c.repo.MoveCollectionItem(ctx, id, videoID, position)
+}
+
+// RemoveItem takes a video out of a collection
+func (c *Collections) RemoveItem(ctx context.Context, id int64, videoID string) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+	return c.repo.RemoveCollectionItem(ctx, id, videoID)
+}
+
+// lookup returns the catalogued details of a video, or just its ID as the title
+// when there is no catalog or the video was never seen in a search
+func (c *Collections) lookup(ctx context.Context, videoID string) entities.Video {
+	if c.videos != nil {
+		if video, err := c.videos.GetByID(ctx, videoID); err == nil {
+			return *video
+		}
+	}
+	return entities.Video{ID: videoID, Title: videoID}
+}
+
+// validateCollection trims and checks a collection's name and description
+func validateCollection(name, description string) (string, string, error) {
+	name = strings.TrimSpace(name)
+	description = strings.TrimSpace(description)
+
+	if name == "" {
+		return "", "", appErrors.NewValidationError("collection name cannot be empty", nil)
+	}
+	if utf8.RuneCountInString(name) > MaxCollectionNameLength {
+		return "", "", appErrors.NewValidationError(
+			fmt.Sprintf("collection name must be at most %d characters", MaxCollectionNameLength), nil)
+	}
+	if utf8.RuneCountInString(description) > MaxCollectionDescriptionLength {
+		return "", "", appErrors.NewValidationError(
+			fmt.Sprintf("description must be at most %d characters", MaxCollectionDescriptionLength), nil)
+	}
+	return name, description, nil
+}
+
+// validateNote trims and checks an item note
+func validateNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if utf8.RuneCountInString(note) > MaxCollectionNoteLength {
+		return "", appErrors.NewValidationError(
+			fmt.Sprintf("note must be at most %d characters", MaxCollectionNoteLength), nil)
+	}
+	return note, nil
+}
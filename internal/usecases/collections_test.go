@@ -0,0 +1,393 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// MockCollectionRepository is a mock implementation of ports.CollectionRepository
+type MockCollectionRepository struct {
+	mock.Mock
+}
+
+func (m *MockCollectionRepository) CreateCollection(ctx context.Context, collection *entities.Collection) error {
+	args := m.Called(ctx, collection)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) ListCollections(ctx context.Context) ([]entities.Collection, error) {
+	args := m.Called(ctx)
+	collections, _ := args.Get(0).([]entities.Collection)
+	return collections, args.Error(1)
+}
+
+func (m *MockCollectionRepository) GetCollection(ctx context.Context, id int64) (*entities.Collection, error) {
+	args := m.Called(ctx, id)
+	collection, _ := args.Get(0).(*entities.Collection)
+	return collection, args.Error(1)
+}
+
+func (m *MockCollectionRepository) UpdateCollection(ctx context.Context, id int64, name, description string, updatedAt time.Time) error {
+	args := m.Called(ctx, id, name, description, updatedAt)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) DeleteCollection(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) AddCollectionItem(ctx context.Context, collectionID int64, video entities.Video, note string, addedAt time.Time) (*entities.CollectionItem, error) {
+	args := m.Called(ctx, collectionID, video, note, addedAt)
+	item, _ := args.Get(0).(*entities.CollectionItem)
+	return item, args.Error(1)
+}
+
+func (m *MockCollectionRepository) SetCollectionItemNote(ctx context.Context, collectionID int64, videoID, note string) error {
+	args := m.Called(ctx, collectionID, videoID, note)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) MoveCollectionItem(ctx context.Context, collectionID int64, videoID string, position int) error {
+	args := m.Called(ctx, collectionID, videoID, position)
+	return args.Error(0)
+}
+
+func (m *MockCollectionRepository) RemoveCollectionItem(ctx context.Context, collectionID int64, videoID string) error {
+	args := m.Called(ctx, collectionID, videoID)
+	return args.Error(0)
+}
+
+// testCollection returns a collection with two videos, the first one with a note
+func testCollection() *entities.Collection {
+	published := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	return &entities.Collection{
+		ID:          7,
+		Name:        "Go concurrency talks",
+		Description: "Watch in order",
+		Items: []entities.CollectionItem{
+			{
+				Video:    entities.Video{ID: testVideoID, Title: "Concurrency is not parallelism", Channel: "Golang", PublishedAt: published},
+				Position: 1,
+				Note:     "Start here",
+			},
+			{
+				Video:    entities.Video{ID: "f6kdp27TYZs", Title: "Go Concurrency Patterns", Channel: "Google for Developers"},
+				Position: 2,
+			},
+		},
+	}
+}
+
+// captureCreate expects one CreateCollection call and returns the collection it got
+func captureCreate(repo *MockCollectionRepository) *entities.Collection {
+	created := new(entities.Collection)
+	repo.On("CreateCollection", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*created = *args.Get(1).(*entities.Collection)
+	}).Return(nil).Once()
+	return created
+}
+
+func TestCollections_Create(t *testing.T) {
+	// Arrange
+	repo := new(MockCollectionRepository)
+	created := captureCreate(repo)
+	uc := NewCollections(repo, nil)
+
+	// Act
+	collection, err := uc.Create(context.Background(), "  Onboarding ", " First week ")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Onboarding", collection.Name)
+	assert.Equal(t, "Onboarding", created.Name)
+	assert.Equal(t, "First week", created.Description)
+	assert.False(t, created.CreatedAt.IsZero())
+	repo.AssertExpectations(t)
+}
+
+func TestCollections_ValidatesInput(t *testing.T) {
+	repo := new(MockCollectionRepository)
+	uc := NewCollections(repo, nil)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"empty name", func() error {
+			_, err := uc.Create(ctx, "   ", "")
+			return err
+		}},
+		{"long name", func() error {
+			_, err := uc.Create(ctx, strings.Repeat("a", MaxCollectionNameLength+1), "")
+			return err
+		}},
+		{"long description", func() error {
+			return uc.Update(ctx, 1, "Go", strings.Repeat("a", MaxCollectionDescriptionLength+1))
+		}},
+		{"bad link", func() error {
+			_, err := uc.AddLink(ctx, 1, "https://vimeo.com/12345", "")
+			return err
+		}},
+		{"long note", func() error {
+			return uc.SetNote(ctx, 1, testVideoID, strings.Repeat("a", MaxCollectionNoteLength+1))
+		}},
+		{"move to zero", func() error { return uc.MoveItem(ctx, 1, testVideoID, 0) }},
+		{"remove bad id", func() error { return uc.RemoveItem(ctx, 1, "../etc") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+		})
+	}
+	repo.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything)
+	repo.AssertNotCalled(t, "UpdateCollection", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCollections_AddLink(t *testing.T) {
+	catalogued := entities.Video{ID: testVideoID, Title: "Concurrency is not parallelism", Channel: "Golang"}
+
+	tests := []struct {
+		name    string
+		link    string
+		catalog bool
+		want    entities.Video
+	}{
+		{"watch URL from the catalog", "https://www.youtube.com/watch?v=" + testVideoID + "&t=42s", true, catalogued},
+		{"short link from the catalog", "youtu.be/" + testVideoID, true, catalogued},
+		{"shorts link without a catalog", "https://youtube.com/shorts/" + testVideoID, false, entities.Video{ID: testVideoID, Title: testVideoID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := new(MockCollectionRepository)
+			repo.On("GetCollection", mock.Anything, int64(7)).Return(testCollection(), nil)
+			repo.On("AddCollectionItem", mock.Anything, int64(7), tt.want, "keynote", mock.Anything).
+				Return(&entities.CollectionItem{Video: tt.want, Position: 3}, nil)
+
+			var uc *Collections
+			if tt.catalog {
+				videos := new(MockVideoRepository)
+				videos.On("GetByID", mock.Anything, testVideoID).Return(&catalogued, nil)
+				uc = NewCollections(repo, videos)
+			} else {
+				uc = NewCollections(repo, nil)
+			}
+
+			// Act
+			item, err := uc.AddLink(context.Background(), 7, tt.link, " keynote ")
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, 3, item.Position)
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCollections_AddItemRejectsFullCollection(t *testing.T) {
+	full := &entities.Collection{ID: 7, Items: make([]entities.CollectionItem, MaxCollectionItems)}
+	repo := new(MockCollectionRepository)
+	repo.On("GetCollection", mock.Anything, int64(7)).Return(full, nil)
+	uc := NewCollections(repo, nil)
+
+	_, err := uc.AddItem(context.Background(), 7, entities.Video{ID: testVideoID, Title: "x"}, "")
+
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+	repo.AssertNotCalled(t, "AddCollectionItem", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestCollections_Export(t *testing.T) {
+	repo := new(MockCollectionRepository)
+	repo.On("GetCollection", mock.Anything, int64(7)).Return(testCollection(), nil)
+	uc := NewCollections(repo, nil)
+	ctx := context.Background()
+
+	t.Run("json", func(t *testing.T) {
+		export, err := uc.Export(ctx, 7, CollectionFormatJSON)
+
+		require.NoError(t, err)
+		assert.Equal(t, "go-concurrency-talks.json", export.Filename)
+		var file collectionFile
+		require.NoError(t, json.Unmarshal(export.Data, &file))
+		assert.Equal(t, collectionFileVersion, file.Version)
+		assert.Equal(t, "Go concurrency talks", file.Name)
+		require.Len(t, file.Items, 2)
+		assert.Equal(t, testVideoID, file.Items[0].ID)
+		assert.Equal(t, "Start here", file.Items[0].Note)
+		require.NotNil(t, file.Items[0].PublishedAt)
+		assert.Nil(t, file.Items[1].PublishedAt)
+	})
+
+	t.Run("m3u", func(t *testing.T) {
+		export, err := uc.Export(ctx, 7, CollectionFormatM3U)
+
+		require.NoError(t, err)
+		assert.Equal(t, "go-concurrency-talks.m3u", export.Filename)
+		assert.Equal(t, "#EXTM3U\n"+
+			"#PLAYLIST:Go concurrency talks\n"+
+			"#EXTINF:-1,Concurrency is not parallelism\n"+
+			"https://www.youtube.com/watch?v="+testVideoID+"\n"+
+			"#EXTINF:-1,Go Concurrency Patterns\n"+
+			"https://www.youtube.com/watch?v=f6kdp27TYZs\n", string(export.Data))
+	})
+
+	t.Run("txt", func(t *testing.T) {
+		export, err := uc.Export(ctx, 7, CollectionFormatText)
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://www.youtube.com/watch?v="+testVideoID+"\n"+
+			"https://www.youtube.com/watch?v=f6kdp27TYZs\n", string(export.Data))
+	})
+}
+
+func TestCollections_ImportJSONRoundTrip(t *testing.T) {
+	// Arrange
+	source := new(MockCollectionRepository)
+	source.On("GetCollection", mock.Anything, int64(7)).Return(testCollection(), nil)
+	export, err := NewCollections(source, nil).Export(context.Background(), 7, CollectionFormatJSON)
+	require.NoError(t, err)
+
+	repo := new(MockCollectionRepository)
+	created := captureCreate(repo)
+	uc := NewCollections(repo, nil)
+
+	// Act
+	_, err = uc.Import(context.Background(), export.Data, "")
+
+	// Assert
+	require.NoError(t, err)
+	want := testCollection()
+	assert.Equal(t, want.Name, created.Name)
+	assert.Equal(t, want.Description, created.Description)
+	require.Len(t, created.Items, 2)
+	for i, item := range created.Items {
+		assert.Equal(t, want.Items[i].ID, item.ID)
+		assert.Equal(t, want.Items[i].Title, item.Title)
+		assert.Equal(t, want.Items[i].Channel, item.Channel)
+		assert.True(t, want.Items[i].PublishedAt.Equal(item.PublishedAt))
+		assert.Equal(t, want.Items[i].Note, item.Note)
+	}
+	repo.AssertExpectations(t)
+}
+
+func TestCollections_ImportM3U(t *testing.T) {
+	// Arrange
+	playlist := "\xef\xbb\xbf#EXTM3U\r\n" +
+		"#PLAYLIST:Onboarding\r\n" +
+		"#EXTINF:-1,Welcome to the team\r\n" +
+		"https://www.youtube.com/watch?v=" + testVideoID + "\r\n" +
+		"\r\n" +
+		"# a comment\r\n" +
+		"https://youtu.be/f6kdp27TYZs\r\n"
+
+	catalogued := entities.Video{ID: "f6kdp27TYZs", Title: "Go Concurrency Patterns", Channel: "Google for Developers"}
+	videos := new(MockVideoRepository)
+	videos.On("GetByID", mock.Anything, testVideoID).Return(nil, appErrors.NewNotFoundError("Video"))
+	videos.On("GetByID", mock.Anything, "f6kdp27TYZs").Return(&catalogued, nil)
+
+	repo := new(MockCollectionRepository)
+	created := captureCreate(repo)
+	uc := NewCollections(repo, videos)
+
+	// Act
+	_, err := uc.Import(context.Background(), []byte(playlist), "")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, "Onboarding", created.Name)
+	require.Len(t, created.Items, 2)
+	assert.Equal(t, entities.Video{ID: testVideoID, Title: "Welcome to the team"}, created.Items[0].Video)
+	assert.Equal(t, catalogued, created.Items[1].Video)
+}
+
+func TestCollections_ImportURLList(t *testing.T) {
+	list := testVideoID + "\nhttps://m.youtube.com/watch?v=f6kdp27TYZs\n"
+
+	t.Run("needs a name", func(t *testing.T) {
+		repo := new(MockCollectionRepository)
+		uc := NewCollections(repo, nil)
+
+		_, err := uc.Import(context.Background(), []byte(list), "")
+
+		assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+		repo.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything)
+	})
+
+	t.Run("uses the given name", func(t *testing.T) {
+		repo := new(MockCollectionRepository)
+		created := captureCreate(repo)
+		uc := NewCollections(repo, nil)
+
+		_, err := uc.Import(context.Background(), []byte(list), "From a spreadsheet")
+
+		require.NoError(t, err)
+		assert.Equal(t, "From a spreadsheet", created.Name)
+		require.Len(t, created.Items, 2)
+		assert.Equal(t, "f6kdp27TYZs", created.Items[1].ID)
+		assert.Equal(t, "f6kdp27TYZs", created.Items[1].Title)
+	})
+}
+
+func TestCollections_ImportRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		message string
+	}{
+		{"bad link", testVideoID + "\nhttps://example.com/video\n", "video 2: not a YouTube link"},
+		{"no videos", "#EXTM3U\n#PLAYLIST:Empty\n", "the file has no videos"},
+		{"broken JSON", `{"name": "Go", "items": [`, "invalid collection JSON"},
+		{"newer version", `{"version": 99, "name": "Go", "items": [{"id": "` + testVideoID + `"}]}`, "collection file version 99 is newer than this server supports"},
+		{"too large", strings.Repeat("x", MaxCollectionImportSize+1), "import file must be at most 1048576 bytes"},
+		{"long title", `{"name": "Go", "items": [{"id": "` + testVideoID + `", "title": "` + strings.Repeat("t", validation.MaxVideoTitleLength+1) + `"}]}`, "video 1: title must be at most 200 characters"},
+		{"long channel", `{"name": "Go", "items": [{"id": "` + testVideoID + `", "title": "Go", "channel": "` + strings.Repeat("c", validation.MaxChannelNameLength+1) + `"}]}`, "video 1: channel must be at most 200 characters"},
+		{"long M3U title", "#EXTM3U\n#EXTINF:-1," + strings.Repeat("t", validation.MaxVideoTitleLength+1) + "\nhttps://youtu.be/" + testVideoID + "\n", "video 1: title must be at most 200 characters"},
+		{"foreign thumbnail", `{"name": "Go", "items": [{"id": "` + testVideoID + `", "thumbnail": "https://tracker.example.com/pixel.gif"}]}`, "video 1: thumbnail must be an https YouTube image link"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockCollectionRepository)
+			uc := NewCollections(repo, nil)
+
+			_, err := uc.Import(context.Background(), []byte(tt.data), "Go")
+
+			var appErr *appErrors.AppError
+			require.ErrorAs(t, err, &appErr)
+			assert.Equal(t, appErrors.ErrCodeValidation, appErr.Code)
+			assert.Equal(t, tt.message, appErr.Message)
+			repo.AssertNotCalled(t, "CreateCollection", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestParseCollectionFormat(t *testing.T) {
+	for in, want := range map[string]CollectionFormat{
+		"":     CollectionFormatJSON,
+		"JSON": CollectionFormatJSON,
+		"m3u8": CollectionFormatM3U,
+		"txt":  CollectionFormatText,
+	} {
+		got, err := ParseCollectionFormat(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	_, err := ParseCollectionFormat("csv")
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+}
@@ -0,0 +1,27 @@
+package usecases
+
+import (
+	"context"
+
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// DeleteSearchHistory removes past searches
+type DeleteSearchHistory struct {
+	historyRepo ports.SearchHistoryRepository
+}
+
+// NewDeleteSearchHistory creates a new DeleteSearchHistory use case
+func NewDeleteSearchHistory(historyRepo ports.SearchHistoryRepository) *DeleteSearchHistory {
+	return &DeleteSearchHistory{historyRepo: historyRepo}
+}
+
+// Execute deletes a single search by ID
+func (d *DeleteSearchHistory) Execute(ctx context.Context, id int64) error {
+	return d.historyRepo.Delete(ctx, id)
+}
+
+// ExecuteAll deletes every search and returns how many were removed
+func (d *DeleteSearchHistory) ExecuteAll(ctx context.Context) (int64, error) {
+	return d.historyRepo.DeleteAll(ctx)
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DigestJobConfig controls when email digests go out
+type DigestJobConfig struct {
+	Interval  time.Duration // How often to check whether a digest is due
+	Every     time.Duration // Time between digests (a day or a week)
+	SendEmpty bool          // Send a digest even when nothing new was found
+}
+
+// DigestJob sends email digests in the background
+type DigestJob struct {
+	digests *Digests
+	cfg     DigestJobConfig
+	logger  *slog.Logger
+}
+
+// NewDigestJob creates a new digest job
+func NewDigestJob(digests *Digests, cfg DigestJobConfig, logger *slog.Logger) *DigestJob {
+	return &DigestJob{
+		digests: digests,
+		cfg:     cfg,
+		logger:  logger,
+	}
+}
+
+// Run sends a digest immediately if one is due, then checks on every interval
+// Blocks until ctx is cancelled
+func (j *DigestJob) Run(ctx context.Context) {
+	j.logger.Info("digest job started",
+		slog.Duration("interval", j.cfg.Interval),
+		slog.Duration("every", j.cfg.Every),
+		slog.Bool("send_empty", j.cfg.SendEmpty),
+	)
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		j.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			j.logger.Info("digest job stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single digest pass and logs the outcome
+// Passes where no digest was due are not logged
+func (j *DigestJob) runOnce(ctx context.Context) {
+	start := time.Now()
+
+	result, err := j.SendOnce(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		j.logger.Error("digest failed", slog.Any("error", err))
+		return
+	}
+	if !result.Due {
+		return
+	}
+
+	j.logger.Info("digest completed",
+		slog.Bool("sent", result.Sent),
+		slog.Int("videos", result.Videos),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// SendOnce sends a digest if one is due
+func (j *DigestJob) SendOnce(ctx context.Context) (DigestResult, error) {
+	return j.digests.SendDue(ctx, j.cfg.Every, j.cfg.SendEmpty)
+}
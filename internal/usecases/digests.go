@@ -0,0 +1,159 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// maxDigestVideos caps how many videos a digest lists per channel or search
+const maxDigestVideos = 10
+
+// digestFeedPageSize is how many feed items Compile reads per page
+const digestFeedPageSize = 200
+
+// DigestResult summarizes a single digest pass
+type DigestResult struct {
+	Due    bool // A digest was due
+	Sent   bool // It was sent; false for an empty digest that was skipped
+	Videos int
+}
+
+// Digests compiles the videos found since the last digest and emails them
+// Only what is still waiting for the user is included: unseen uploads from
+// followed channels and saved search matches that are still new
+type Digests struct {
+	subscriptions ports.SubscriptionRepository
+	searches      ports.SavedSearchRepository
+	repo          ports.DigestRepository
+	sender        ports.DigestSender
+}
+
+// NewDigests creates a new Digests use case
+func NewDigests(subscriptions ports.SubscriptionRepository, searches ports.SavedSearchRepository, repo ports.DigestRepository, sender ports.DigestSender) *Digests {
+	return &Digests{
+		subscriptions: subscriptions,
+		searches:      searches,
+		repo:          repo,
+		sender:        sender,
+	}
+}
+
+// SendDue sends a digest if the last one is at least every old
+// It covers everything found since the last digest (or the last period, for
+// the first one); an empty digest is recorded but only sent when sendEmpty
+// is set. A failed send is not recorded, so the next pass tries again
+func (d *Digests) SendDue(ctx context.Context, every time.Duration, sendEmpty bool) (DigestResult, error) {
+	var result DigestResult
+
+	now := time.Now()
+	last, err := d.repo.LastDigestAt(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to load last digest: %w", err)
+	}
+	if !last.IsZero() && now.Sub(last) < every {
+		return result, nil
+	}
+	result.Due = true
+
+	since := last
+	if since.IsZero() {
+		since = now.Add(-every)
+	}
+	digest, err := d.Compile(ctx, since, now)
+	if err != nil {
+		return result, err
+	}
+	result.Videos = digest.VideoCount()
+
+	if result.Videos > 0 || sendEmpty {
+		if err := d.sender.SendDigest(ctx, digest); err != nil {
+			return result, fmt.Errorf("failed to send digest: %w", err)
+		}
+		result.Sent = true
+	}
+
+	if err := d.repo.RecordDigest(ctx, now, result.Videos); err != nil {
+		return result, fmt.Errorf("failed to record digest: %w", err)
+	}
+	return result, nil
+}
+
+// Compile collects the unseen uploads fetched and the new saved search
+// matches found in (since, until]
+// Channels are ordered by title and searches by name; each lists at most
+// maxDigestVideos videos, newest first, and counts the rest in More
+func (d *Digests) Compile(ctx context.Context, since, until time.Time) (entities.Digest, error) {
+	digest := entities.Digest{Since: since, Until: until}
+	within := func(t time.Time) bool { return t.After(since) && !t.After(until) }
+
+	channels := make(map[string]*entities.DigestChannel)
+	var cursor *entities.FeedCursor
+	for {
+		items, err := d.subscriptions.ListFeed(ctx, entities.FeedFilter{UnseenOnly: true}, cursor, digestFeedPageSize)
+		if err != nil {
+			return digest, fmt.Errorf("failed to load feed: %w", err)
+		}
+		for _, item := range items {
+			if !within(item.FetchedAt) {
+				continue
+			}
+			c, ok := channels[item.ChannelID]
+			if !ok {
+				c = &entities.DigestChannel{Channel: entities.Channel{ID: item.ChannelID, Title: item.Channel}}
+				channels[item.ChannelID] = c
+			}
+			c.Videos, c.More = appendCapped(c.Videos, c.More, item.Video)
+		}
+		if len(items) < digestFeedPageSize {
+			break
+		}
+		last := items[len(items)-1]
+		cursor = &entities.FeedCursor{PublishedAt: last.PublishedAt, VideoID: last.ID}
+	}
+	for _, c := range channels {
+		digest.Channels = append(digest.Channels, *c)
+	}
+	sort.Slice(digest.Channels, func(i, j int) bool {
+		return strings.ToLower(digest.Channels[i].Title) < strings.ToLower(digest.Channels[j].Title)
+	})
+
+	searches, err := d.searches.ListSavedSearches(ctx)
+	if err != nil {
+		return digest, fmt.Errorf("failed to load saved searches: %w", err)
+	}
+	for _, s := range searches {
+		if s.NewCount == 0 {
+			continue
+		}
+		results, err := d.searches.ListSavedSearchResults(ctx, s.ID)
+		if err != nil {
+			return digest, fmt.Errorf("failed to load saved search results: %w", err)
+		}
+		entry := entities.DigestSearch{ID: s.ID, Name: s.Name}
+		for _, r := range results {
+			if r.New && within(r.FirstSeenAt) {
+				entry.Videos, entry.More = appendCapped(entry.Videos, entry.More, r.Video)
+			}
+		}
+		if len(entry.Videos) > 0 {
+			digest.Searches = append(digest.Searches, entry)
+		}
+	}
+
+	return digest, nil
+}
+
+// appendCapped appends v unless maxDigestVideos are listed already, in which
+// case it is counted in more instead
+func appendCapped(videos []entities.Video, more int, v entities.Video) ([]entities.Video, int) {
+	if len(videos) >= maxDigestVideos {
+		return videos, more + 1
+	}
+	return append(videos, v), more
+}
@@ -0,0 +1,184 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// MockDigestRepository is a mock implementation of ports.DigestRepository
+type MockDigestRepository struct {
+	mock.Mock
+}
+
+func (m *MockDigestRepository) LastDigestAt(ctx context.Context) (time.Time, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockDigestRepository) RecordDigest(ctx context.Context, sentAt time.Time, videoCount int) error {
+	args := m.Called(ctx, sentAt, videoCount)
+	return args.Error(0)
+}
+
+// MockDigestSender is a mock implementation of ports.DigestSender
+type MockDigestSender struct {
+	mock.Mock
+}
+
+func (m *MockDigestSender) SendDigest(ctx context.Context, digest entities.Digest) error {
+	args := m.Called(ctx, digest)
+	return args.Error(0)
+}
+
+var unseenFeed = entities.FeedFilter{UnseenOnly: true}
+
+func TestDigests_CompileGroupsWhatIsNewInThePeriod(t *testing.T) {
+	// Arrange
+	mockSubs := new(MockSubscriptionRepository)
+	mockSearches := new(MockSavedSearchRepository)
+	until := time.Date(2026, 3, 8, 9, 0, 0, 0, time.UTC)
+	since := until.Add(-24 * time.Hour)
+	item := func(id, channelID, channel string, fetched time.Time) entities.FeedItem {
+		return entities.FeedItem{Video: entities.Video{ID: id, Channel: channel}, ChannelID: channelID, FetchedAt: fetched}
+	}
+
+	mockSubs.On("ListFeed", mock.Anything, unseenFeed, (*entities.FeedCursor)(nil), digestFeedPageSize).Return([]entities.FeedItem{
+		item("zUpload0001", "UCz", "zig weekly", until.Add(-time.Hour)),
+		item("aUpload0001", "UCa", "Ardan Labs", until),
+		item("oldUpload01", "UCa", "Ardan Labs", since), // Fetched by the previous digest's period
+	}, nil)
+	mockSearches.On("ListSavedSearches", mock.Anything).Return([]entities.SavedSearch{
+		{ID: 1, Name: "golang", NewCount: 2},
+		{ID: 2, Name: "nothing new"},
+	}, nil)
+	mockSearches.On("ListSavedSearchResults", mock.Anything, int64(1)).Return([]entities.SavedSearchResult{
+		{Video: entities.Video{ID: "newMatch001"}, New: true, FirstSeenAt: until.Add(-2 * time.Hour)},
+		{Video: entities.Video{ID: "olderNew001"}, New: true, FirstSeenAt: since.Add(-time.Hour)},
+		{Video: entities.Video{ID: "seenMatch01"}, FirstSeenAt: until},
+	}, nil)
+
+	digests := NewDigests(mockSubs, mockSearches, new(MockDigestRepository), new(MockDigestSender))
+
+	// Act
+	digest, err := digests.Compile(context.Background(), since, until)
+
+	// Assert
+	require.NoError(t, err)
+	require.Len(t, digest.Channels, 2)
+	assert.Equal(t, "Ardan Labs", digest.Channels[0].Title, "channels by title")
+	assert.Equal(t, []entities.Video{{ID: "aUpload0001", Channel: "Ardan Labs"}}, digest.Channels[0].Videos)
+	assert.Equal(t, "UCz", digest.Channels[1].ID)
+	require.Len(t, digest.Searches, 1)
+	assert.Equal(t, "golang", digest.Searches[0].Name)
+	assert.Equal(t, []entities.Video{{ID: "newMatch001"}}, digest.Searches[0].Videos)
+	assert.Equal(t, 3, digest.VideoCount())
+	mockSearches.AssertNotCalled(t, "ListSavedSearchResults", mock.Anything, int64(2))
+}
+
+func TestDigests_CompilePagesAndCapsTheFeed(t *testing.T) {
+	// Arrange
+	mockSubs := new(MockSubscriptionRepository)
+	mockSearches := new(MockSavedSearchRepository)
+	until := time.Now()
+	page := func(from, n int) []entities.FeedItem {
+		items := make([]entities.FeedItem, n)
+		for i := range items {
+			items[i] = entities.FeedItem{
+				Video:     entities.Video{ID: fmt.Sprintf("video%06d", from+i), PublishedAt: until.Add(-time.Duration(from+i) * time.Minute)},
+				ChannelID: testChannelID,
+				FetchedAt: until,
+			}
+		}
+		return items
+	}
+	first := page(0, digestFeedPageSize)
+	last := first[len(first)-1]
+
+	mockSubs.On("ListFeed", mock.Anything, unseenFeed, (*entities.FeedCursor)(nil), digestFeedPageSize).Return(first, nil)
+	mockSubs.On("ListFeed", mock.Anything, unseenFeed, &entities.FeedCursor{PublishedAt: last.PublishedAt, VideoID: last.ID}, digestFeedPageSize).
+		Return(page(digestFeedPageSize, 5), nil)
+	mockSearches.On("ListSavedSearches", mock.Anything).Return([]entities.SavedSearch{}, nil)
+
+	digests := NewDigests(mockSubs, mockSearches, new(MockDigestRepository), new(MockDigestSender))
+
+	// Act
+	digest, err := digests.Compile(context.Background(), until.Add(-time.Hour), until)
+
+	// Assert
+	require.NoError(t, err)
+	mockSubs.AssertExpectations(t)
+	require.Len(t, digest.Channels, 1)
+	assert.Len(t, digest.Channels[0].Videos, maxDigestVideos)
+	assert.Equal(t, digestFeedPageSize+5-maxDigestVideos, digest.Channels[0].More)
+	assert.Equal(t, digestFeedPageSize+5, digest.VideoCount())
+}
+
+func TestDigests_SendDue(t *testing.T) {
+	newVideo := []entities.FeedItem{{Video: entities.Video{ID: testVideoID}, ChannelID: testChannelID, FetchedAt: time.Now()}}
+
+	tests := []struct {
+		name       string
+		lastAt     time.Time
+		feed       []entities.FeedItem
+		sendEmpty  bool
+		sendErr    error
+		wantResult DigestResult
+		wantSend   bool
+		wantRecord bool
+		wantErr    bool
+	}{
+		{name: "not due yet", lastAt: time.Now().Add(-time.Hour)},
+		{name: "first digest", feed: newVideo, wantResult: DigestResult{Due: true, Sent: true, Videos: 1}, wantSend: true, wantRecord: true},
+		{name: "due", lastAt: time.Now().Add(-25 * time.Hour), feed: newVideo, wantResult: DigestResult{Due: true, Sent: true, Videos: 1}, wantSend: true, wantRecord: true},
+		{name: "empty is recorded but not sent", lastAt: time.Now().Add(-25 * time.Hour), wantResult: DigestResult{Due: true}, wantRecord: true},
+		{name: "empty with send_empty", lastAt: time.Now().Add(-25 * time.Hour), sendEmpty: true, wantResult: DigestResult{Due: true, Sent: true}, wantSend: true, wantRecord: true},
+		{name: "failed send is retried later", feed: newVideo, sendErr: errors.New("connection refused"), wantSend: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockSubs := new(MockSubscriptionRepository)
+			mockSearches := new(MockSavedSearchRepository)
+			mockRepo := new(MockDigestRepository)
+			mockSender := new(MockDigestSender)
+
+			mockRepo.On("LastDigestAt", mock.Anything).Return(tt.lastAt, nil)
+			mockSubs.On("ListFeed", mock.Anything, unseenFeed, mock.Anything, mock.Anything).Return(tt.feed, nil)
+			mockSearches.On("ListSavedSearches", mock.Anything).Return([]entities.SavedSearch{}, nil)
+			mockSender.On("SendDigest", mock.Anything, mock.Anything).Return(tt.sendErr)
+			mockRepo.On("RecordDigest", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+			digests := NewDigests(mockSubs, mockSearches, mockRepo, mockSender)
+
+			// Act
+			result, err := digests.SendDue(context.Background(), 24*time.Hour, tt.sendEmpty)
+
+			// Assert
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.wantResult, result)
+			}
+			if tt.wantSend {
+				mockSender.AssertCalled(t, "SendDigest", mock.Anything, mock.Anything)
+			} else {
+				mockSender.AssertNotCalled(t, "SendDigest", mock.Anything, mock.Anything)
+			}
+			if tt.wantRecord {
+				mockRepo.AssertCalled(t, "RecordDigest", mock.Anything, mock.Anything, tt.wantResult.Videos)
+			} else {
+				mockRepo.AssertNotCalled(t, "RecordDigest", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// FindSearchHistory looks up past searches by the words they contained
+type FindSearchHistory struct {
+	index ports.SearchHistoryFullText
+}
+
+// NewFindSearchHistory creates a new FindSearchHistory use case
+func NewFindSearchHistory(index ports.SearchHistoryFullText) *FindSearchHistory {
+	return &FindSearchHistory{index: index}
+}
+
+// Execute returns ranked matches for text, or nothing for blank input
+func (f *FindSearchHistory) Execute(ctx context.Context, text string, limit int) ([]entities.SearchHistoryMatch, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	return f.index.FullTextSearch(ctx, text, limit)
+}
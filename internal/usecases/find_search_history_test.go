@@ -0,0 +1,35 @@
+package usecases
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestFindSearchHistory_ReturnsMatches(t *testing.T) {
+	mockRepo := new(MockSearchHistoryRepository)
+	matches := []entities.SearchHistoryMatch{
+		{SearchHistory: entities.SearchHistory{Query: "golang generics"}},
+	}
+	mockRepo.On("FullTextSearch", context.Background(), "gen", 20).Return(matches, nil)
+
+	uc := NewFindSearchHistory(mockRepo)
+	got, err := uc.Execute(context.Background(), "gen", 20)
+
+	assert.NoError(t, err)
+	assert.Equal(t, matches, got)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestFindSearchHistory_BlankTextSkipsLookup(t *testing.T) {
+	mockRepo := new(MockSearchHistoryRepository)
+
+	uc := NewFindSearchHistory(mockRepo)
+	got, err := uc.Execute(context.Background(), "   ", 20)
+
+	assert.NoError(t, err)
+	assert.Empty(t, got)
+	mockRepo.AssertNotCalled(t, "FullTextSearch")
+}
@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// MaxReflectionLength caps the note written when a focus session ends
+const MaxReflectionLength = 1000
+
+// FocusSessions runs the optional focus timer
+// A session runs for a fixed length, then waits for a reflection to end it
+// Each profile (from the request context) has its own sessions
+type FocusSessions struct {
+	repo   ports.FocusSessionRepository
+	length time.Duration
+}
+
+// NewFocusSessions creates a new FocusSessions use case
+// A zero length disables focus sessions
+func NewFocusSessions(repo ports.FocusSessionRepository, length time.Duration) *FocusSessions {
+	return &FocusSessions{repo: repo, length: length}
+}
+
+// Enabled reports whether focus sessions can be started
+func (f *FocusSessions) Enabled() bool {
+	return f.length > 0
+}
+
+// Length returns how long a focus session runs
+func (f *FocusSessions) Length() time.Duration {
+	return f.length
+}
+
+// Start begins a new session; one still running is ended without a reflection
+func (f *FocusSessions) Start(ctx context.Context) (*entities.FocusSession, error) {
+	if !f.Enabled() {
+		return nil, appErrors.NewValidationError("focus sessions are disabled", nil)
+	}
+
+	profile := entities.ProfileFromContext(ctx)
+	now := time.Now()
+	current, err := f.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil {
+		if err := f.repo.EndFocusSession(ctx, profile, current.ID, now, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &entities.FocusSession{Profile: profile, StartedAt: now, EndsAt: now.Add(f.length)}
+	if err := f.repo.AddFocusSession(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// Current returns the running session, or the one waiting for its reflection
+// It returns nil when there is neither
+func (f *FocusSessions) Current(ctx context.Context) (*entities.FocusSession, error) {
+	session, err := f.repo.CurrentFocusSession(ctx, entities.ProfileFromContext(ctx))
+	if appErrors.GetErrorCode(err) == appErrors.ErrCodeNotFound {
+		return nil, nil
+	}
+	return session, err
+}
+
+// End closes a session with a reflection on how it went
+func (f *FocusSessions) End(ctx context.Context, id int64, reflection string) error {
+	reflection = strings.TrimSpace(reflection)
+	if utf8.RuneCountInString(reflection) > MaxReflectionLength {
+		return appErrors.NewValidationError(
+			fmt.Sprintf("reflection must be at most %d characters", MaxReflectionLength), nil)
+	}
+	return f.repo.EndFocusSession(ctx, entities.ProfileFromContext(ctx), id, time.Now(), reflection)
+}
+
+// Recent returns up to limit sessions, most recent first
+func (f *FocusSessions) Recent(ctx context.Context, limit int) ([]entities.FocusSession, error) {
+	return f.repo.ListFocusSessions(ctx, entities.ProfileFromContext(ctx), limit)
+}
@@ -0,0 +1,116 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// MockFocusSessionRepository is a mock implementation of ports.FocusSessionRepository
+type MockFocusSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockFocusSessionRepository) AddFocusSession(ctx context.Context, session *entities.FocusSession) error {
+	args := m.Called(ctx, session)
+	return args.Error(0)
+}
+
+func (m *MockFocusSessionRepository) CurrentFocusSession(ctx context.Context, profile string) (*entities.FocusSession, error) {
+	args := m.Called(ctx, profile)
+	session, _ := args.Get(0).(*entities.FocusSession)
+	return session, args.Error(1)
+}
+
+func (m *MockFocusSessionRepository) EndFocusSession(ctx context.Context, profile string, id int64, endedAt time.Time, reflection string) error {
+	args := m.Called(ctx, profile, id, endedAt, reflection)
+	return args.Error(0)
+}
+
+func (m *MockFocusSessionRepository) ListFocusSessions(ctx context.Context, profile string, limit int) ([]entities.FocusSession, error) {
+	args := m.Called(ctx, profile, limit)
+	sessions, _ := args.Get(0).([]entities.FocusSession)
+	return sessions, args.Error(1)
+}
+
+func TestFocusSessions_Start(t *testing.T) {
+	// Arrange - a session still running is ended before the new one starts
+	repo := new(MockFocusSessionRepository)
+	repo.On("CurrentFocusSession", mock.Anything, "kids").Return(&entities.FocusSession{ID: 3, Profile: "kids"}, nil)
+	repo.On("EndFocusSession", mock.Anything, "kids", int64(3), mock.Anything, "").Return(nil)
+	repo.On("AddFocusSession", mock.Anything, mock.MatchedBy(func(s *entities.FocusSession) bool {
+		return s.Profile == "kids" && s.EndsAt.Sub(s.StartedAt) == 25*time.Minute
+	})).Return(nil)
+
+	uc := NewFocusSessions(repo, 25*time.Minute)
+
+	// Act
+	session, err := uc.Start(entities.WithProfile(context.Background(), "kids"))
+
+	// Assert
+	require.NoError(t, err)
+	assert.False(t, session.Ended())
+	repo.AssertExpectations(t)
+}
+
+func TestFocusSessions_StartFirst(t *testing.T) {
+	// Arrange
+	repo := new(MockFocusSessionRepository)
+	repo.On("CurrentFocusSession", mock.Anything, entities.DefaultProfile).Return(nil, appErrors.NewNotFoundError("Focus session"))
+	repo.On("AddFocusSession", mock.Anything, mock.Anything).Return(nil)
+
+	uc := NewFocusSessions(repo, 25*time.Minute)
+
+	// Act
+	_, err := uc.Start(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "EndFocusSession", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestFocusSessions_StartDisabled(t *testing.T) {
+	repo := new(MockFocusSessionRepository)
+	uc := NewFocusSessions(repo, 0)
+
+	_, err := uc.Start(context.Background())
+
+	assert.False(t, uc.Enabled())
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+	repo.AssertNotCalled(t, "AddFocusSession", mock.Anything, mock.Anything)
+}
+
+func TestFocusSessions_CurrentNone(t *testing.T) {
+	repo := new(MockFocusSessionRepository)
+	repo.On("CurrentFocusSession", mock.Anything, entities.DefaultProfile).Return(nil, appErrors.NewNotFoundError("Focus session"))
+	uc := NewFocusSessions(repo, 25*time.Minute)
+
+	session, err := uc.Current(context.Background())
+
+	require.NoError(t, err)
+	assert.Nil(t, session)
+}
+
+func TestFocusSessions_End(t *testing.T) {
+	// Arrange
+	repo := new(MockFocusSessionRepository)
+	repo.On("EndFocusSession", mock.Anything, entities.DefaultProfile, int64(3), mock.Anything, "Watched the talk I came for").Return(nil)
+
+	uc := NewFocusSessions(repo, 25*time.Minute)
+
+	// Act
+	err := uc.End(context.Background(), 3, "  Watched the talk I came for \n")
+	tooLong := uc.End(context.Background(), 3, strings.Repeat("a", MaxReflectionLength+1))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(tooLong))
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,174 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// HistoryRetentionConfig controls how search history is pruned
+type HistoryRetentionConfig struct {
+	MaxAge           time.Duration // Delete rows older than this (0 = no age limit)
+	MaxRows          int           // Keep at most this many rows (0 = no row limit)
+	BatchSize        int           // Rows deleted per transaction
+	BatchPause       time.Duration // Pause between batches so other writers get the lock
+	Rollup           bool          // Aggregate pruned rows into daily totals first
+	Interval         time.Duration // How often pruning runs
+	OptimizeInterval time.Duration // How often vacuum/optimize runs (0 = never)
+}
+
+// PruneResult summarizes a single pruning pass
+type PruneResult struct {
+	ByAge   int64
+	ByCount int64
+	Batches int
+}
+
+// HistoryRetention enforces the search history retention policy in the background
+type HistoryRetention struct {
+	repo   ports.SearchHistoryRetention
+	cfg    HistoryRetentionConfig
+	logger *slog.Logger
+}
+
+// NewHistoryRetention creates a new retention job
+func NewHistoryRetention(repo ports.SearchHistoryRetention, cfg HistoryRetentionConfig, logger *slog.Logger) *HistoryRetention {
+	return &HistoryRetention{
+		repo:   repo,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Run prunes immediately and then on every interval, optimizing on its own schedule
+// Blocks until ctx is cancelled
+func (h *HistoryRetention) Run(ctx context.Context) {
+	h.logger.Info("history retention started",
+		slog.Duration("max_age", h.cfg.MaxAge),
+		slog.Int("max_rows", h.cfg.MaxRows),
+		slog.Bool("rollup", h.cfg.Rollup),
+		slog.Duration("interval", h.cfg.Interval),
+	)
+
+	pruneTicker := time.NewTicker(h.cfg.Interval)
+	defer pruneTicker.Stop()
+
+	// A nil channel never fires, which disables optimizing
+	var optimizeC <-chan time.Time
+	if h.cfg.OptimizeInterval > 0 {
+		optimizeTicker := time.NewTicker(h.cfg.OptimizeInterval)
+		defer optimizeTicker.Stop()
+		optimizeC = optimizeTicker.C
+	}
+
+	h.runPrune(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			h.logger.Info("history retention stopped")
+			return
+		case <-pruneTicker.C:
+			h.runPrune(ctx)
+		case <-optimizeC:
+			h.runOptimize(ctx)
+		}
+	}
+}
+
+// runPrune runs a pruning pass and logs the outcome
+func (h *HistoryRetention) runPrune(ctx context.Context) {
+	start := time.Now()
+
+	result, err := h.PruneOnce(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		h.logger.Error("history prune failed",
+			slog.Int64("deleted_by_age", result.ByAge),
+			slog.Int64("deleted_by_count", result.ByCount),
+			slog.Any("error", err),
+		)
+		return
+	}
+
+	h.logger.Info("history prune completed",
+		slog.Int64("deleted_by_age", result.ByAge),
+		slog.Int64("deleted_by_count", result.ByCount),
+		slog.Int("batches", result.Batches),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// runOptimize reclaims space and logs the outcome
+func (h *HistoryRetention) runOptimize(ctx context.Context) {
+	start := time.Now()
+
+	if err := h.repo.Optimize(ctx); err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		h.logger.Error("database optimize failed", slog.Any("error", err))
+		return
+	}
+
+	h.logger.Info("database optimize completed", slog.Duration("duration", time.Since(start)))
+}
+
+// PruneOnce deletes rows past MaxAge and then rows beyond MaxRows, batch by batch
+func (h *HistoryRetention) PruneOnce(ctx context.Context) (PruneResult, error) {
+	var result PruneResult
+
+	if h.cfg.MaxAge > 0 {
+		cutoff := time.Now().Add(-h.cfg.MaxAge)
+		deleted, batches, err := h.pruneInBatches(ctx, func() (int64, error) {
+			return h.repo.PruneOlderThan(ctx, cutoff, h.cfg.BatchSize, h.cfg.Rollup)
+		})
+		result.ByAge, result.Batches = deleted, batches
+		if err != nil {
+			return result, err
+		}
+	}
+
+	if h.cfg.MaxRows > 0 {
+		deleted, batches, err := h.pruneInBatches(ctx, func() (int64, error) {
+			return h.repo.PruneExcess(ctx, h.cfg.MaxRows, h.cfg.BatchSize, h.cfg.Rollup)
+		})
+		result.ByCount = deleted
+		result.Batches += batches
+		if err != nil {
+			return result, err
+		}
+	}
+
+	return result, nil
+}
+
+// pruneInBatches calls prune until a batch comes back short, pausing in between
+func (h *HistoryRetention) pruneInBatches(ctx context.Context, prune func() (int64, error)) (int64, int, error) {
+	var total int64
+	batches := 0
+
+	for {
+		deleted, err := prune()
+		if err != nil {
+			return total, batches, err
+		}
+
+		total += deleted
+		batches++
+
+		if deleted < int64(h.cfg.BatchSize) {
+			return total, batches, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return total, batches, ctx.Err()
+		case <-time.After(h.cfg.BatchPause):
+		}
+	}
+}
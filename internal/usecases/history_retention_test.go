@@ -0,0 +1,98 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSearchHistoryRetention for testing
+type MockSearchHistoryRetention struct {
+	mock.Mock
+}
+
+func (m *MockSearchHistoryRetention) PruneOlderThan(ctx context.Context, cutoff time.Time, batchSize int, rollup bool) (int64, error) {
+	args := m.Called(ctx, cutoff, batchSize, rollup)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSearchHistoryRetention) PruneExcess(ctx context.Context, maxRows, batchSize int, rollup bool) (int64, error) {
+	args := m.Called(ctx, maxRows, batchSize, rollup)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSearchHistoryRetention) Optimize(ctx context.Context) error {
+	args := m.Called(ctx)
+	return args.Error(0)
+}
+
+func newTestRetention(repo *MockSearchHistoryRetention, maxAge time.Duration, maxRows int) *HistoryRetention {
+	return NewHistoryRetention(repo, HistoryRetentionConfig{
+		MaxAge:     maxAge,
+		MaxRows:    maxRows,
+		BatchSize:  100,
+		BatchPause: time.Millisecond,
+		Rollup:     true,
+		Interval:   time.Hour,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestHistoryRetention_PruneOnce_BatchesUntilShort(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSearchHistoryRetention)
+	mockRepo.On("PruneOlderThan", mock.Anything, mock.Anything, 100, true).Return(int64(100), nil).Twice()
+	mockRepo.On("PruneOlderThan", mock.Anything, mock.Anything, 100, true).Return(int64(42), nil).Once()
+	mockRepo.On("PruneExcess", mock.Anything, 1000, 100, true).Return(int64(7), nil).Once()
+
+	retention := newTestRetention(mockRepo, 24*time.Hour, 1000)
+
+	// Act
+	result, err := retention.PruneOnce(context.Background())
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(242), result.ByAge)
+	assert.Equal(t, int64(7), result.ByCount)
+	assert.Equal(t, 4, result.Batches)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestHistoryRetention_PruneOnce_CutoffUsesMaxAge(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSearchHistoryRetention)
+	maxAge := 30 * 24 * time.Hour
+	mockRepo.On("PruneOlderThan", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) >= maxAge && time.Since(cutoff) < maxAge+time.Minute
+	}), 100, true).Return(int64(0), nil).Once()
+
+	retention := newTestRetention(mockRepo, maxAge, 0)
+
+	// Act
+	_, err := retention.PruneOnce(context.Background())
+
+	// Assert - no row limit means PruneExcess is never called
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "PruneExcess", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestHistoryRetention_PruneOnce_StopsOnError(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSearchHistoryRetention)
+	mockRepo.On("PruneOlderThan", mock.Anything, mock.Anything, 100, true).Return(int64(0), errors.New("database is locked")).Once()
+
+	retention := newTestRetention(mockRepo, time.Hour, 1000)
+
+	// Act
+	_, err := retention.PruneOnce(context.Background())
+
+	// Assert
+	assert.Error(t, err)
+	mockRepo.AssertNotCalled(t, "PruneExcess", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
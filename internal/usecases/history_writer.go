@@ -0,0 +1,219 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// Overflow policies for a full history queue
+const (
+	HistoryOverflowDrop  = "drop"  // Drop the new entry immediately
+	HistoryOverflowBlock = "block" // Wait up to BlockTimeout for room, then drop
+)
+
+// historyWriteTimeout bounds a single batch write (including the drain on shutdown)
+const historyWriteTimeout = 5 * time.Second
+
+// HistoryWriterConfig controls how search history is buffered and written
+type HistoryWriterConfig struct {
+	QueueSize     int           // Entries buffered before the overflow policy applies
+	BatchSize     int           // Max entries written per transaction
+	FlushInterval time.Duration // Max time an entry waits before being written
+	Overflow      string        // HistoryOverflowDrop or HistoryOverflowBlock
+	BlockTimeout  time.Duration // How long Enqueue waits with HistoryOverflowBlock
+}
+
+// HistoryEntry is a search waiting to be written, with the videos it returned
+type HistoryEntry struct {
+	History entities.SearchHistory
+	Videos  []entities.Video
+}
+
+// HistoryWriterStats are the writer's counters since startup
+type HistoryWriterStats struct {
+	Queued   int    // Entries currently waiting
+	Enqueued uint64 // Entries accepted into the queue
+	Written  uint64 // Entries saved
+	Dropped  uint64 // Entries rejected because the queue was full
+	Failed   uint64 // Entries lost to failed writes
+	Batches  uint64 // Successful batch writes
+}
+
+// HistoryWriter saves search history in batches from a bounded queue
+// Searches never wait on the database, and a slow or failing database costs
+// dropped history entries instead of unbounded goroutines
+type HistoryWriter struct {
+	repo      ports.SearchHistoryRepository
+	videoRepo ports.VideoRepository // Optional, links returned videos to each entry
+	cfg       HistoryWriterConfig
+	logger    *slog.Logger
+	queue     chan HistoryEntry
+
+	enqueued atomic.Uint64
+	written  atomic.Uint64
+	dropped  atomic.Uint64
+	failed   atomic.Uint64
+	batches  atomic.Uint64
+}
+
+// NewHistoryWriter creates a new history writer
+// videoRepo is optional - pass nil to skip the video catalog
+func NewHistoryWriter(repo ports.SearchHistoryRepository, videoRepo ports.VideoRepository, cfg HistoryWriterConfig, logger *slog.Logger) *HistoryWriter {
+	return &HistoryWriter{
+		repo:      repo,
+		videoRepo: videoRepo,
+		cfg:       cfg,
+		logger:    logger,
+		queue:     make(chan HistoryEntry, cfg.QueueSize),
+	}
+}
+
+// Enqueue queues an entry for writing and reports whether it was accepted
+// It never blocks longer than BlockTimeout
+func (w *HistoryWriter) Enqueue(entry HistoryEntry) bool {
+	select {
+	case w.queue <- entry:
+		w.enqueued.Add(1)
+		return true
+	default:
+	}
+
+	if w.cfg.Overflow == HistoryOverflowBlock && w.cfg.BlockTimeout > 0 {
+		timer := time.NewTimer(w.cfg.BlockTimeout)
+		defer timer.Stop()
+
+		select {
+		case w.queue <- entry:
+			w.enqueued.Add(1)
+			return true
+		case <-timer.C:
+		}
+	}
+
+	w.dropped.Add(1)
+	return false
+}
+
+// Stats returns the writer's counters
+func (w *HistoryWriter) Stats() HistoryWriterStats {
+	return HistoryWriterStats{
+		Queued:   len(w.queue),
+		Enqueued: w.enqueued.Load(),
+		Written:  w.written.Load(),
+		Dropped:  w.dropped.Load(),
+		Failed:   w.failed.Load(),
+		Batches:  w.batches.Load(),
+	}
+}
+
+// Run writes queued entries in batches until ctx is cancelled, then drains
+// whatever is still queued before returning
+// Cancel ctx only after the HTTP server has stopped, and close the database
+// only after Run returns
+func (w *HistoryWriter) Run(ctx context.Context) {
+	w.logger.Info("history writer started",
+		slog.Int("queue_size", w.cfg.QueueSize),
+		slog.Int("batch_size", w.cfg.BatchSize),
+		slog.Duration("flush_interval", w.cfg.FlushInterval),
+		slog.String("overflow", w.cfg.Overflow),
+	)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]HistoryEntry, 0, w.cfg.BatchSize)
+	var reportedDrops uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			w.drain(batch)
+			w.logger.Info("history writer stopped", slog.Any("stats", w.Stats()))
+			return
+		case entry := <-w.queue:
+			batch = append(batch, entry)
+			if len(batch) >= w.cfg.BatchSize {
+				w.write(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.write(batch)
+				batch = batch[:0]
+			}
+			reportedDrops = w.reportDrops(reportedDrops)
+		}
+	}
+}
+
+// drain writes the pending batch and everything left in the queue
+func (w *HistoryWriter) drain(batch []HistoryEntry) {
+	for {
+		select {
+		case entry := <-w.queue:
+			batch = append(batch, entry)
+			if len(batch) >= w.cfg.BatchSize {
+				w.write(batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				w.write(batch)
+			}
+			return
+		}
+	}
+}
+
+// write saves a batch in one transaction, then links each entry's videos
+// Failures are logged and counted; the batch is not retried
+func (w *HistoryWriter) write(batch []HistoryEntry) {
+	// Not tied to Run's context, so the final drain still gets to write
+	ctx, cancel := context.WithTimeout(context.Background(), historyWriteTimeout)
+	defer cancel()
+
+	histories := make([]*entities.SearchHistory, len(batch))
+	for i := range batch {
+		histories[i] = &batch[i].History
+	}
+
+	if err := w.repo.SaveBatch(ctx, histories); err != nil {
+		w.failed.Add(uint64(len(batch)))
+		w.logger.Error("failed to write search history",
+			slog.Int("entries", len(batch)),
+			slog.Any("error", err),
+		)
+		return
+	}
+	w.written.Add(uint64(len(batch)))
+	w.batches.Add(1)
+
+	if w.videoRepo == nil {
+		return
+	}
+	for _, entry := range batch {
+		if err := w.videoRepo.SaveSearchResults(ctx, entry.History.ID, entry.Videos); err != nil {
+			w.logger.Warn("failed to save search results",
+				slog.Int64("search_id", entry.History.ID),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// reportDrops logs entries dropped since the last report and returns the new total
+func (w *HistoryWriter) reportDrops(reported uint64) uint64 {
+	dropped := w.dropped.Load()
+	if dropped > reported {
+		w.logger.Warn("search history queue full, entries dropped",
+			slog.Uint64("dropped", dropped-reported),
+			slog.Uint64("dropped_total", dropped),
+		)
+	}
+	return dropped
+}
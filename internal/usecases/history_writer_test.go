@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func newTestHistoryWriter(repo *MockSearchHistoryRepository, videos *MockVideoRepository, cfg HistoryWriterConfig) *HistoryWriter {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	if videos == nil {
+		return NewHistoryWriter(repo, nil, cfg, logger)
+	}
+	return NewHistoryWriter(repo, videos, cfg, logger)
+}
+
+func historyEntry(query string) HistoryEntry {
+	return HistoryEntry{History: entities.SearchHistory{Query: query, CreatedAt: time.Now()}}
+}
+
+// runWriter starts Run and returns a function that stops it and waits for the drain
+func runWriter(w *HistoryWriter) func() {
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+func TestHistoryWriter_WritesFullBatches(t *testing.T) {
+	// Arrange
+	repo := new(MockSearchHistoryRepository)
+	written := make(chan int, 10)
+	repo.On("SaveBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		written <- len(args.Get(1).([]*entities.SearchHistory))
+	}).Return(nil)
+
+	w := newTestHistoryWriter(repo, nil, HistoryWriterConfig{QueueSize: 10, BatchSize: 2, FlushInterval: time.Hour})
+	stop := runWriter(w)
+	defer stop()
+
+	// Act
+	w.Enqueue(historyEntry("a"))
+	w.Enqueue(historyEntry("b"))
+
+	// Assert - a full batch is written without waiting for the flush interval
+	select {
+	case n := <-written:
+		assert.Equal(t, 2, n)
+	case <-time.After(time.Second):
+		t.Fatal("batch was not written")
+	}
+}
+
+func TestHistoryWriter_DrainsOnShutdown(t *testing.T) {
+	// Arrange
+	repo := new(MockSearchHistoryRepository)
+	videos := new(MockVideoRepository)
+	repo.On("SaveBatch", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		for i, h := range args.Get(1).([]*entities.SearchHistory) {
+			h.ID = int64(i + 1)
+		}
+	}).Return(nil)
+	videos.On("SaveSearchResults", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	w := newTestHistoryWriter(repo, videos, HistoryWriterConfig{QueueSize: 10, BatchSize: 100, FlushInterval: time.Hour})
+	stop := runWriter(w)
+
+	// Act
+	for _, q := range []string{"a", "b", "c"} {
+		assert.True(t, w.Enqueue(historyEntry(q)))
+	}
+	stop()
+
+	// Assert
+	stats := w.Stats()
+	assert.Equal(t, uint64(3), stats.Written)
+	assert.Equal(t, 0, stats.Queued)
+	videos.AssertNumberOfCalls(t, "SaveSearchResults", 3)
+}
+
+func TestHistoryWriter_DropsWhenFull(t *testing.T) {
+	// Arrange - nothing drains the queue
+	w := newTestHistoryWriter(new(MockSearchHistoryRepository), nil, HistoryWriterConfig{
+		QueueSize: 1, BatchSize: 1, FlushInterval: time.Hour, Overflow: HistoryOverflowDrop,
+	})
+
+	// Act
+	first := w.Enqueue(historyEntry("a"))
+	second := w.Enqueue(historyEntry("b"))
+
+	// Assert
+	assert.True(t, first)
+	assert.False(t, second)
+	assert.Equal(t, uint64(1), w.Stats().Dropped)
+}
+
+func TestHistoryWriter_BlockPolicyWaitsForRoom(t *testing.T) {
+	// Arrange
+	w := newTestHistoryWriter(new(MockSearchHistoryRepository), nil, HistoryWriterConfig{
+		QueueSize: 1, BatchSize: 1, FlushInterval: time.Hour,
+		Overflow: HistoryOverflowBlock, BlockTimeout: time.Second,
+	})
+	w.Enqueue(historyEntry("a"))
+
+	// Act - free a slot while Enqueue is waiting
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-w.queue
+	}()
+	accepted := w.Enqueue(historyEntry("b"))
+
+	// Assert
+	assert.True(t, accepted)
+	assert.Equal(t, uint64(0), w.Stats().Dropped)
+}
+
+func TestHistoryWriter_CountsFailedWrites(t *testing.T) {
+	// Arrange
+	repo := new(MockSearchHistoryRepository)
+	repo.On("SaveBatch", mock.Anything, mock.Anything).Return(errors.New("disk full"))
+
+	w := newTestHistoryWriter(repo, nil, HistoryWriterConfig{QueueSize: 10, BatchSize: 10, FlushInterval: time.Hour})
+	stop := runWriter(w)
+
+	// Act
+	w.Enqueue(historyEntry("a"))
+	w.Enqueue(historyEntry("b"))
+	stop()
+
+	// Assert
+	stats := w.Stats()
+	assert.Equal(t, uint64(2), stats.Failed)
+	assert.Equal(t, uint64(0), stats.Written)
+}
@@ -0,0 +1,94 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// Search history page sizes
+const (
+	DefaultHistoryPageSize = 20
+	MaxHistoryPageSize     = 100
+)
+
+// ListSearchHistory pages through past searches, newest first
+type ListSearchHistory struct {
+	historyRepo ports.SearchHistoryRepository
+}
+
+// NewListSearchHistory creates a new ListSearchHistory use case
+func NewListSearchHistory(historyRepo ports.SearchHistoryRepository) *ListSearchHistory {
+	return &ListSearchHistory{historyRepo: historyRepo}
+}
+
+// Execute returns one page of history matching filter
+// cursor is the NextCursor of the previous page (empty for the first page)
+// pageSize 0 means DefaultHistoryPageSize
+func (l *ListSearchHistory) Execute(ctx context.Context, filter entities.SearchHistoryFilter, cursor string, pageSize int) (*entities.SearchHistoryPage, error) {
+	size, err := validation.ValidatePageSize(pageSize, DefaultHistoryPageSize, MaxHistoryPageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	var after *entities.SearchHistoryCursor
+	if cursor != "" {
+		after, err = decodeHistoryCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Fetch one extra row to know whether there is a next page
+	items, err := l.historyRepo.List(ctx, filter, after, size+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entities.SearchHistoryPage{Items: items}
+	if len(items) > size {
+		page.Items = items[:size]
+		last := page.Items[size-1]
+		page.NextCursor = encodeHistoryCursor(entities.SearchHistoryCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// encodeHistoryCursor renders a cursor as an opaque URL-safe string
+// The timestamp keeps its UTC offset so it compares equal to the stored value
+func encodeHistoryCursor(c entities.SearchHistoryCursor) string {
+	raw := c.CreatedAt.Format(time.RFC3339Nano) + "|" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeHistoryCursor parses a cursor produced by encodeHistoryCursor
+func decodeHistoryCursor(s string) (*entities.SearchHistoryCursor, error) {
+	invalid := appErrors.NewValidationError("invalid cursor", nil)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, invalid
+	}
+
+	c := &entities.SearchHistoryCursor{}
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return nil, invalid
+	}
+	if c.ID, err = strconv.ParseInt(id, 10, 64); err != nil {
+		return nil, invalid
+	}
+	return c, nil
+}
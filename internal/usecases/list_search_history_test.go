@@ -0,0 +1,78 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+func TestListSearchHistory_PagesWithCursor(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSearchHistoryRepository)
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.FixedZone("", -3*3600))
+	rows := []entities.SearchHistory{
+		{ID: 3, Query: "c", CreatedAt: now},
+		{ID: 2, Query: "b", CreatedAt: now.Add(-time.Minute)},
+		{ID: 1, Query: "a", CreatedAt: now.Add(-2 * time.Minute)},
+	}
+	filter := entities.SearchHistoryFilter{Contains: "x"}
+	mockRepo.On("List", mock.Anything, filter, (*entities.SearchHistoryCursor)(nil), 3).Return(rows, nil)
+	mockRepo.On("List", mock.Anything, filter, &entities.SearchHistoryCursor{CreatedAt: rows[1].CreatedAt, ID: 2}, 3).
+		Return(rows[2:], nil)
+
+	uc := NewListSearchHistory(mockRepo)
+
+	// Act
+	first, err := uc.Execute(context.Background(), filter, "", 2)
+	require.NoError(t, err)
+	second, err := uc.Execute(context.Background(), filter, first.NextCursor, 2)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Len(t, first.Items, 2)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Equal(t, []entities.SearchHistory{rows[2]}, second.Items)
+	assert.Empty(t, second.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListSearchHistory_DefaultPageSize(t *testing.T) {
+	mockRepo := new(MockSearchHistoryRepository)
+	mockRepo.On("List", mock.Anything, entities.SearchHistoryFilter{}, (*entities.SearchHistoryCursor)(nil), DefaultHistoryPageSize+1).
+		Return([]entities.SearchHistory{}, nil)
+
+	page, err := NewListSearchHistory(mockRepo).Execute(context.Background(), entities.SearchHistoryFilter{}, "", 0)
+
+	require.NoError(t, err)
+	assert.Empty(t, page.Items)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestListSearchHistory_RejectsBadInput(t *testing.T) {
+	uc := NewListSearchHistory(new(MockSearchHistoryRepository))
+
+	tests := []struct {
+		name     string
+		cursor   string
+		pageSize int
+	}{
+		{"page too large", "", MaxHistoryPageSize + 1},
+		{"negative page", "", -1},
+		{"garbage cursor", "!!!", 10},
+		{"cursor without id", base64.RawURLEncoding.EncodeToString([]byte("2025-03-01T12:00:00Z")), 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := uc.Execute(context.Background(), entities.SearchHistoryFilter{}, tt.cursor, tt.pageSize)
+			assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SavedSearchRefresherConfig controls how often saved searches are re-run
+type SavedSearchRefresherConfig struct {
+	Interval     time.Duration // How often to look for searches that are due
+	RefreshEvery time.Duration // Re-run a search once its last run is this old
+	CallsPerRun  int           // Max search calls per run (0 = no calls); each costs 100 quota units
+}
+
+// SavedSearchRefresher re-runs saved searches in the background to find new matches
+type SavedSearchRefresher struct {
+	searches *SavedSearches
+	cfg      SavedSearchRefresherConfig
+	logger   *slog.Logger
+}
+
+// NewSavedSearchRefresher creates a new saved search refresher
+func NewSavedSearchRefresher(searches *SavedSearches, cfg SavedSearchRefresherConfig, logger *slog.Logger) *SavedSearchRefresher {
+	return &SavedSearchRefresher{
+		searches: searches,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Run refreshes due searches immediately and then on every interval
+// Blocks until ctx is cancelled
+func (r *SavedSearchRefresher) Run(ctx context.Context) {
+	r.logger.Info("saved search refresher started",
+		slog.Duration("interval", r.cfg.Interval),
+		slog.Duration("refresh_every", r.cfg.RefreshEvery),
+		slog.Int("calls_per_run", r.cfg.CallsPerRun),
+	)
+
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("saved search refresher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single refresh pass and logs the outcome
+func (r *SavedSearchRefresher) runOnce(ctx context.Context) {
+	start := time.Now()
+
+	result, err := r.RefreshOnce(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		r.logger.Error("saved search refresh failed", slog.Any("error", err))
+		return
+	}
+
+	r.logger.Info("saved search refresh completed",
+		slog.Int("due", result.Due),
+		slog.Int("refreshed", result.Refreshed),
+		slog.Int("new_videos", result.NewVideos),
+		slog.Int("failed", result.Failed),
+		slog.Int("deferred", result.Deferred),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// RefreshOnce re-runs the searches that are due, within the per-run call limit
+// Searches left over by the limit are the first to run next time
+func (r *SavedSearchRefresher) RefreshOnce(ctx context.Context) (RefreshResult, error) {
+	return r.searches.RefreshDue(ctx, r.cfg.RefreshEvery, r.cfg.CallsPerRun)
+}
@@ -0,0 +1,251 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// MaxSavedSearchNameLength limits saved search names (in characters)
+const MaxSavedSearchNameLength = 100
+
+// RefreshResult summarizes a single run over the saved searches that are due
+type RefreshResult struct {
+	Due       int
+	Refreshed int
+	NewVideos int
+	Failed    int
+	Deferred  int // Due but left for the next run by the per-run call limit
+}
+
+// SavedSearches manages searches that are re-run on a schedule to find new matches
+// Refreshes go straight to the YouTube API: they skip the search cache and
+// are not recorded in search history
+type SavedSearches struct {
+	repo              ports.SavedSearchRepository
+	ytClient          ports.YouTubeClient
+	defaultMaxResults int64
+	notifier          NewVideosNotifier
+	budget            SearchBudget
+	logger            *slog.Logger
+}
+
+// NewSavedSearches creates a new SavedSearches use case
+// defaultMaxResults is used when a search is saved without a result count
+func NewSavedSearches(repo ports.SavedSearchRepository, ytClient ports.YouTubeClient, defaultMaxResults int64, logger *slog.Logger) *SavedSearches {
+	return &SavedSearches{repo: repo, ytClient: ytClient, defaultMaxResults: defaultMaxResults, logger: logger}
+}
+
+// WithNotifier reports new matches (e.g. to webhooks)
+// The baseline results of a search's first refresh are not reported
+func (s *SavedSearches) WithNotifier(notifier NewVideosNotifier) *SavedSearches {
+	s.notifier = notifier
+	return s
+}
+
+// WithBudget refuses to save or manually refresh searches once the viewing budget is used up
+// Scheduled refreshes are not checked: they run outside any profile
+func (s *SavedSearches) WithBudget(budget SearchBudget) *SavedSearches {
+	s.budget = budget
+	return s
+}
+
+// checkBudget returns the budget's refusal, if any
+func (s *SavedSearches) checkBudget(ctx context.Context) error {
+	if s.budget == nil {
+		return nil
+	}
+	return s.budget.CheckSearch(ctx)
+}
+
+// Create saves a search and runs it once to record the baseline results
+// An empty name defaults to the query; maxResults 0 uses the default
+// A failed first run doesn't fail the save; it is recorded and retried by the scheduler
+func (s *SavedSearches) Create(ctx context.Context, name, query string, maxResults int64) (*entities.SavedSearch, error) {
+	if maxResults == 0 {
+		maxResults = s.defaultMaxResults
+	}
+	input, err := validation.ValidateSearchQuery(query, maxResults)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = input.Query
+	}
+	if utf8.RuneCountInString(name) > MaxSavedSearchNameLength {
+		return nil, appErrors.NewValidationError(
+			fmt.Sprintf("name too long (maximum %d characters)", MaxSavedSearchNameLength), nil)
+	}
+	if err := s.checkBudget(ctx); err != nil {
+		return nil, err
+	}
+
+	search := &entities.SavedSearch{
+		Name:      name,
+		Options:   entities.SearchOptions{Query: input.Query, MaxResults: input.MaxResults},
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.CreateSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+
+	_, _ = s.Refresh(ctx, *search)
+	return search, nil
+}
+
+// List returns every saved search with its result counts
+func (s *SavedSearches) List(ctx context.Context) ([]entities.SavedSearch, error) {
+	return s.repo.ListSavedSearches(ctx)
+}
+
+// Get returns a saved search and its results, new ones first
+func (s *SavedSearches) Get(ctx context.Context, id int64) (*entities.SavedSearch, []entities.SavedSearchResult, error) {
+	search, err := s.repo.GetSavedSearch(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	results, err := s.repo.ListSavedSearchResults(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return search, results, nil
+}
+
+// Delete removes a saved search and its results
+func (s *SavedSearches) Delete(ctx context.Context, id int64) error {
+	return s.repo.DeleteSavedSearch(ctx, id)
+}
+
+// MarkSeen clears the "new" highlight of a search's results
+func (s *SavedSearches) MarkSeen(ctx context.Context, id int64) (int, error) {
+	return s.repo.MarkSavedSearchSeen(ctx, id)
+}
+
+// RefreshByID runs a saved search now and returns how many results were new
+func (s *SavedSearches) RefreshByID(ctx context.Context, id int64) (int, error) {
+	search, err := s.repo.GetSavedSearch(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkBudget(ctx); err != nil {
+		return 0, err
+	}
+	return s.Refresh(ctx, *search)
+}
+
+// Refresh runs a saved search (one API call) and merges its results
+// The outcome is recorded on the search either way
+func (s *SavedSearches) Refresh(ctx context.Context, search entities.SavedSearch) (int, error) {
+	now := time.Now()
+
+	added, err := s.fetch(ctx, search, now)
+
+	checkErr := ""
+	if err != nil {
+		checkErr = storedErrorMessage(err, "the search could not be run")
+		s.logger.Warn("saved search refresh failed",
+			slog.Int64("saved_search_id", search.ID),
+			slog.String("query", search.Options.Query),
+			slog.Any("error", err),
+		)
+	}
+	if recordErr := s.repo.RecordSavedSearchCheck(ctx, search.ID, now, checkErr); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return added, err
+}
+
+// RefreshDue re-runs the searches not checked within staleAfter, least recently
+// checked first, making at most maxCalls search calls; the rest are counted as Deferred
+// Each call is one search.list request, which costs 100 units of the daily API quota
+func (s *SavedSearches) RefreshDue(ctx context.Context, staleAfter time.Duration, maxCalls int) (RefreshResult, error) {
+	var result RefreshResult
+
+	searches, err := s.repo.ListSavedSearches(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to load saved searches: %w", err)
+	}
+
+	cutoff := time.Now().Add(-staleAfter)
+	var due []entities.SavedSearch
+	for _, search := range searches {
+		if search.LastCheckedAt.Before(cutoff) {
+			due = append(due, search)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool {
+		return due[i].LastCheckedAt.Before(due[j].LastCheckedAt)
+	})
+	result.Due = len(due)
+
+	for _, search := range due {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if maxCalls <= 0 {
+			result.Deferred++
+			continue
+		}
+		maxCalls--
+
+		added, err := s.Refresh(ctx, search)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		result.Refreshed++
+		result.NewVideos += added
+	}
+
+	return result, nil
+}
+
+// fetch runs a search against the API and stores the results
+func (s *SavedSearches) fetch(ctx context.Context, search entities.SavedSearch, now time.Time) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	videos, err := s.ytClient.Search(search.Options.Query, search.Options.MaxResults)
+	if err != nil {
+		return 0, err
+	}
+	added, err := s.repo.AddSavedSearchResults(ctx, search.ID, videos, now)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.notifier != nil {
+		var matches []entities.Video
+		for _, res := range added {
+			if res.New {
+				matches = append(matches, res.Video)
+			}
+		}
+		if len(matches) > 0 {
+			s.notifier.NotifyNewVideos(ctx, entities.NewVideosEvent{
+				Type:       entities.EventSavedSearchNewVideos,
+				SourceID:   strconv.FormatInt(search.ID, 10),
+				SourceName: search.Name,
+				SourcePath: "/saved-searches/" + strconv.FormatInt(search.ID, 10),
+				Videos:     matches,
+				OccurredAt: now,
+			})
+		}
+	}
+	return len(added), nil
+}
@@ -0,0 +1,245 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// MockSavedSearchRepository is a mock implementation of ports.SavedSearchRepository
+type MockSavedSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedSearchRepository) CreateSavedSearch(ctx context.Context, search *entities.SavedSearch) error {
+	args := m.Called(ctx, search)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) ListSavedSearches(ctx context.Context) ([]entities.SavedSearch, error) {
+	args := m.Called(ctx)
+	searches, _ := args.Get(0).([]entities.SavedSearch)
+	return searches, args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) GetSavedSearch(ctx context.Context, id int64) (*entities.SavedSearch, error) {
+	args := m.Called(ctx, id)
+	search, _ := args.Get(0).(*entities.SavedSearch)
+	return search, args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) DeleteSavedSearch(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) RecordSavedSearchCheck(ctx context.Context, id int64, checkedAt time.Time, checkErr string) error {
+	args := m.Called(ctx, id, checkedAt, checkErr)
+	return args.Error(0)
+}
+
+func (m *MockSavedSearchRepository) AddSavedSearchResults(ctx context.Context, id int64, videos []entities.Video, refreshedAt time.Time) ([]entities.SavedSearchResult, error) {
+	args := m.Called(ctx, id, videos, refreshedAt)
+	results, _ := args.Get(0).([]entities.SavedSearchResult)
+	return results, args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) ListSavedSearchResults(ctx context.Context, id int64) ([]entities.SavedSearchResult, error) {
+	args := m.Called(ctx, id)
+	results, _ := args.Get(0).([]entities.SavedSearchResult)
+	return results, args.Error(1)
+}
+
+func (m *MockSavedSearchRepository) MarkSavedSearchSeen(ctx context.Context, id int64) (int, error) {
+	args := m.Called(ctx, id)
+	return args.Int(0), args.Error(1)
+}
+
+func TestSavedSearches_CreateRunsBaseline(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSavedSearchRepository)
+	mockClient := new(MockYouTubeClient)
+	videos := []entities.Video{{ID: testVideoID, Title: "Storage at KubeCon"}}
+
+	mockRepo.On("CreateSavedSearch", mock.Anything, mock.MatchedBy(func(s *entities.SavedSearch) bool {
+		return s.Name == "kubecon 2026 storage" && s.Options == entities.SearchOptions{Query: "kubecon 2026 storage", MaxResults: 10}
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.SavedSearch).ID = 3
+	}).Return(nil)
+	mockClient.On("Search", "kubecon 2026 storage", int64(10)).Return(videos, nil).Once()
+	mockRepo.On("AddSavedSearchResults", mock.Anything, int64(3), videos, mock.Anything).
+		Return([]entities.SavedSearchResult{{Video: videos[0]}}, nil)
+	mockRepo.On("RecordSavedSearchCheck", mock.Anything, int64(3), mock.Anything, "").Return(nil)
+
+	searches := NewSavedSearches(mockRepo, mockClient, 10, discardLogger())
+
+	// Act: name defaults to the normalized query, max results to the default
+	search, err := searches.Create(context.Background(), "  ", "  kubecon   2026 storage ", 0)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), search.ID)
+	mockRepo.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func TestSavedSearches_CreateValidatesInput(t *testing.T) {
+	searches := NewSavedSearches(new(MockSavedSearchRepository), new(MockYouTubeClient), 10, discardLogger())
+
+	tests := []struct {
+		name, searchName, query string
+		maxResults              int64
+	}{
+		{name: "empty query", query: "   "},
+		{name: "too many results", query: "golang", maxResults: 51},
+		{name: "long name", searchName: strings.Repeat("n", MaxSavedSearchNameLength+1), query: "golang"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := searches.Create(context.Background(), tt.searchName, tt.query, tt.maxResults)
+			assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+		})
+	}
+}
+
+func TestSavedSearches_RefusedByBudget(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSavedSearchRepository)
+	mockClient := new(MockYouTubeClient)
+	mockRepo.On("GetSavedSearch", mock.Anything, int64(3)).
+		Return(&entities.SavedSearch{ID: 3, Options: entities.SearchOptions{Query: "golang", MaxResults: 10}}, nil)
+	searches := NewSavedSearches(mockRepo, mockClient, 10, discardLogger()).WithBudget(refusingBudget{})
+
+	// Act
+	_, createErr := searches.Create(context.Background(), "", "golang", 0)
+	_, refreshErr := searches.RefreshByID(context.Background(), 3)
+
+	// Assert
+	assert.Equal(t, appErrors.ErrCodeBudgetExhausted, appErrors.GetErrorCode(createErr))
+	assert.Equal(t, appErrors.ErrCodeBudgetExhausted, appErrors.GetErrorCode(refreshErr))
+	mockRepo.AssertNotCalled(t, "CreateSavedSearch", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "RecordSavedSearchCheck", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	mockClient.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
+
+func TestSavedSearches_RefreshRecordsSafeFailureMessage(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSavedSearchRepository)
+	mockClient := new(MockYouTubeClient)
+	search := entities.SavedSearch{ID: 4, Options: entities.SearchOptions{Query: "golang", MaxResults: 5}}
+
+	mockClient.On("Search", "golang", int64(5)).Return(nil,
+		appErrors.NewServiceUnavailableError("YouTube search", errors.New("GET https://example.com/?key=secret")))
+	mockRepo.On("RecordSavedSearchCheck", mock.Anything, int64(4), mock.Anything, "YouTube search is temporarily unavailable").Return(nil)
+
+	searches := NewSavedSearches(mockRepo, mockClient, 10, discardLogger())
+
+	// Act
+	_, err := searches.Refresh(context.Background(), search)
+
+	// Assert
+	assert.Error(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "AddSavedSearchResults")
+}
+
+func TestSavedSearches_RefreshNotifiesNewMatches(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSavedSearchRepository)
+	mockClient := new(MockYouTubeClient)
+	mockNotifier := new(MockNewVideosNotifier)
+	search := entities.SavedSearch{ID: 4, Name: "golang", Options: entities.SearchOptions{Query: "golang", MaxResults: 5}}
+	videos := []entities.Video{{ID: testVideoID, Title: "Go 1.26"}, {ID: "otherVideo1", Title: "Baseline"}}
+
+	mockClient.On("Search", "golang", int64(5)).Return(videos, nil)
+	mockRepo.On("AddSavedSearchResults", mock.Anything, int64(4), videos, mock.Anything).Return([]entities.SavedSearchResult{
+		{Video: videos[0], New: true},
+		{Video: videos[1]},
+	}, nil)
+	mockRepo.On("RecordSavedSearchCheck", mock.Anything, int64(4), mock.Anything, "").Return(nil)
+	mockNotifier.On("NotifyNewVideos", mock.Anything, mock.MatchedBy(func(e entities.NewVideosEvent) bool {
+		return e.Type == entities.EventSavedSearchNewVideos &&
+			e.SourceID == "4" && e.SourceName == "golang" && e.SourcePath == "/saved-searches/4" &&
+			len(e.Videos) == 1 && e.Videos[0].ID == testVideoID
+	})).Return().Once()
+
+	searches := NewSavedSearches(mockRepo, mockClient, 10, discardLogger()).WithNotifier(mockNotifier)
+
+	// Act
+	added, err := searches.Refresh(context.Background(), search)
+
+	// Assert: results not marked new (e.g. a baseline) are not reported
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestSavedSearches_RefreshDueRespectsCallLimit(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSavedSearchRepository)
+	mockClient := new(MockYouTubeClient)
+	now := time.Now()
+	search := func(id int64, query string, checked time.Time) entities.SavedSearch {
+		return entities.SavedSearch{ID: id, Options: entities.SearchOptions{Query: query, MaxResults: 5}, LastCheckedAt: checked}
+	}
+
+	mockRepo.On("ListSavedSearches", mock.Anything).Return([]entities.SavedSearch{
+		search(1, "fresh", now.Add(-time.Hour)),
+		search(2, "stale", now.Add(-48*time.Hour)),
+		search(3, "never", time.Time{}),
+		search(4, "older", now.Add(-72*time.Hour)),
+	}, nil)
+	mockClient.On("Search", "never", int64(5)).Return([]entities.Video{{ID: testVideoID}}, nil).Once()
+	mockClient.On("Search", "older", int64(5)).Return([]entities.Video{}, nil).Once()
+	mockRepo.On("AddSavedSearchResults", mock.Anything, int64(3), mock.Anything, mock.Anything).
+		Return([]entities.SavedSearchResult{{Video: entities.Video{ID: testVideoID}}}, nil)
+	mockRepo.On("AddSavedSearchResults", mock.Anything, int64(4), mock.Anything, mock.Anything).Return(nil, nil)
+	mockRepo.On("RecordSavedSearchCheck", mock.Anything, mock.Anything, mock.Anything, "").Return(nil)
+
+	searches := NewSavedSearches(mockRepo, mockClient, 10, discardLogger())
+
+	// Act
+	result, err := searches.RefreshDue(context.Background(), 24*time.Hour, 2)
+
+	// Assert: least recently checked first; the rest wait for the next run
+	require.NoError(t, err)
+	assert.Equal(t, RefreshResult{Due: 3, Refreshed: 2, NewVideos: 1, Deferred: 1}, result)
+	mockClient.AssertExpectations(t)
+	mockClient.AssertNotCalled(t, "Search", "stale", int64(5))
+	mockClient.AssertNotCalled(t, "Search", "fresh", int64(5))
+}
+
+func TestSavedSearchRefresher_Run_StopsOnCancel(t *testing.T) {
+	mockRepo := new(MockSavedSearchRepository)
+	mockRepo.On("ListSavedSearches", mock.Anything).Return([]entities.SavedSearch{}, nil)
+
+	refresher := NewSavedSearchRefresher(NewSavedSearches(mockRepo, new(MockYouTubeClient), 10, discardLogger()), SavedSearchRefresherConfig{
+		Interval:     time.Hour,
+		RefreshEvery: 24 * time.Hour,
+		CallsPerRun:  5,
+	}, discardLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		refresher.Run(ctx)
+		close(done)
+	}()
+
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("refresher did not stop after cancel")
+	}
+}
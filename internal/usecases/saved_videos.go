@@ -0,0 +1,81 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// SavedVideos manages the watch-later list: save, unsave, mark watched and reorder
+type SavedVideos struct {
+	repo ports.SavedVideoRepository
+}
+
+// NewSavedVideos creates a new SavedVideos use case
+func NewSavedVideos(repo ports.SavedVideoRepository) *SavedVideos {
+	return &SavedVideos{repo: repo}
+}
+
+// Save adds a video to the end of the list (saving it twice is a no-op)
+func (s *SavedVideos) Save(ctx context.Context, video entities.Video) (*entities.SavedVideo, error) {
+	if err := validation.ValidateVideoID(video.ID); err != nil {
+		return nil, err
+	}
+
+	// Title and channel come from the client, so they are bounded before reaching the catalog
+	video.Title = strings.TrimSpace(video.Title)
+	video.Channel = strings.TrimSpace(video.Channel)
+	if video.Title == "" {
+		return nil, appErrors.NewValidationError("video title cannot be empty", nil)
+	}
+	if err := validation.ValidateVideoMetadata(video.Title, video.Channel); err != nil {
+		return nil, err
+	}
+	if err := validation.ValidateThumbnailURL(video.Thumbnail); err != nil {
+		return nil, err
+	}
+
+	return s.repo.AddSavedVideo(ctx, video, time.Now())
+}
+
+// Unsave removes a video from the list
+func (s *SavedVideos) Unsave(ctx context.Context, videoID string) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+	return s.repo.RemoveSavedVideo(ctx, videoID)
+}
+
+// MarkWatched marks a saved video as watched now, or as not watched
+func (s *SavedVideos) MarkWatched(ctx context.Context, videoID string, watched bool) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+
+	var watchedAt time.Time
+	if watched {
+		watchedAt = time.Now()
+	}
+	return s.repo.SetSavedVideoWatched(ctx, videoID, watchedAt)
+}
+
+// Move puts a saved video at a 1-based position (past the end means last)
+func (s *SavedVideos) Move(ctx context.Context, videoID string, position int) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+	if position < 1 {
+		return appErrors.NewValidationError("position must be at least 1", nil)
+	}
+	return s.repo.MoveSavedVideo(ctx, videoID, position)
+}
+
+// List returns the watch-later list in order
+func (s *SavedVideos) List(ctx context.Context) ([]entities.SavedVideo, error) {
+	return s.repo.ListSavedVideos(ctx)
+}
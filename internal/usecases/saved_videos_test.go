@@ -0,0 +1,147 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// MockSavedVideoRepository is a mock implementation of ports.SavedVideoRepository
+type MockSavedVideoRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedVideoRepository) AddSavedVideo(ctx context.Context, video entities.Video, savedAt time.Time) (*entities.SavedVideo, error) {
+	args := m.Called(ctx, video, savedAt)
+	saved, _ := args.Get(0).(*entities.SavedVideo)
+	return saved, args.Error(1)
+}
+
+func (m *MockSavedVideoRepository) RemoveSavedVideo(ctx context.Context, videoID string) error {
+	args := m.Called(ctx, videoID)
+	return args.Error(0)
+}
+
+func (m *MockSavedVideoRepository) SetSavedVideoWatched(ctx context.Context, videoID string, watchedAt time.Time) error {
+	args := m.Called(ctx, videoID, watchedAt)
+	return args.Error(0)
+}
+
+func (m *MockSavedVideoRepository) MoveSavedVideo(ctx context.Context, videoID string, position int) error {
+	args := m.Called(ctx, videoID, position)
+	return args.Error(0)
+}
+
+func (m *MockSavedVideoRepository) ListSavedVideos(ctx context.Context) ([]entities.SavedVideo, error) {
+	args := m.Called(ctx)
+	videos, _ := args.Get(0).([]entities.SavedVideo)
+	return videos, args.Error(1)
+}
+
+const testVideoID = "dQw4w9WgXcQ"
+
+func TestSavedVideos_Save(t *testing.T) {
+	// Arrange
+	repo := new(MockSavedVideoRepository)
+	video := entities.Video{ID: testVideoID, Title: "  Go in 100 seconds ", Channel: "Fireship"}
+	repo.On("AddSavedVideo", mock.Anything, mock.MatchedBy(func(v entities.Video) bool {
+		return v.Title == "Go in 100 seconds"
+	}), mock.Anything).Return(&entities.SavedVideo{Video: video, Position: 1}, nil)
+
+	uc := NewSavedVideos(repo)
+
+	// Act
+	saved, err := uc.Save(context.Background(), video)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, saved.Position)
+	repo.AssertExpectations(t)
+}
+
+func TestSavedVideos_ValidatesInput(t *testing.T) {
+	repo := new(MockSavedVideoRepository)
+	uc := NewSavedVideos(repo)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"save with bad id", func() error {
+			_, err := uc.Save(ctx, entities.Video{ID: "../etc", Title: "x"})
+			return err
+		}},
+		{"save without title", func() error {
+			_, err := uc.Save(ctx, entities.Video{ID: testVideoID, Title: "  "})
+			return err
+		}},
+		{"save with long title", func() error {
+			_, err := uc.Save(ctx, entities.Video{ID: testVideoID, Title: strings.Repeat("a", validation.MaxVideoTitleLength+1)})
+			return err
+		}},
+		{"save with long channel", func() error {
+			_, err := uc.Save(ctx, entities.Video{ID: testVideoID, Title: "x", Channel: strings.Repeat("c", validation.MaxChannelNameLength+1)})
+			return err
+		}},
+		{"save with control characters", func() error {
+			_, err := uc.Save(ctx, entities.Video{ID: testVideoID, Title: "line\nbreak"})
+			return err
+		}},
+		{"unsave with bad id", func() error { return uc.Unsave(ctx, "short") }},
+		{"mark watched with bad id", func() error { return uc.MarkWatched(ctx, "", true) }},
+		{"move to position 0", func() error { return uc.Move(ctx, testVideoID, 0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+		})
+	}
+
+	// Invalid input never reaches the repository
+	assert.Empty(t, repo.Calls)
+}
+
+func TestSavedVideos_MarkWatched(t *testing.T) {
+	// Arrange
+	repo := new(MockSavedVideoRepository)
+	repo.On("SetSavedVideoWatched", mock.Anything, testVideoID, mock.MatchedBy(func(at time.Time) bool {
+		return !at.IsZero()
+	})).Return(nil).Once()
+	repo.On("SetSavedVideoWatched", mock.Anything, testVideoID, time.Time{}).Return(nil).Once()
+
+	uc := NewSavedVideos(repo)
+
+	// Act
+	err1 := uc.MarkWatched(context.Background(), testVideoID, true)
+	err2 := uc.MarkWatched(context.Background(), testVideoID, false)
+
+	// Assert
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	repo.AssertExpectations(t)
+}
+
+func TestSavedVideos_MoveNotFound(t *testing.T) {
+	// Arrange
+	repo := new(MockSavedVideoRepository)
+	repo.On("MoveSavedVideo", mock.Anything, testVideoID, 2).Return(appErrors.NewNotFoundError("Saved video"))
+
+	uc := NewSavedVideos(repo)
+
+	// Act
+	err := uc.Move(context.Background(), testVideoID, 2)
+
+	// Assert
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+}
@@ -0,0 +1,169 @@
+package usecases
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// Insights windows, in days
+const (
+	DefaultInsightsDays = 30
+	MaxInsightsDays     = 365
+)
+
+// insightsListSize is the number of queries in each insights list
+const insightsListSize = 10
+
+// GetSearchInsights summarizes search history: top queries, activity per
+// day and week, queries that found nothing, and how often the cache answered
+type GetSearchInsights struct {
+	analytics ports.SearchAnalytics
+}
+
+// NewGetSearchInsights creates a new GetSearchInsights use case
+func NewGetSearchInsights(analytics ports.SearchAnalytics) *GetSearchInsights {
+	return &GetSearchInsights{analytics: analytics}
+}
+
+// Execute summarizes the last days days, today (UTC) included
+// days 0 means DefaultInsightsDays
+func (g *GetSearchInsights) Execute(ctx context.Context, days int) (*entities.SearchInsights, error) {
+	if days == 0 {
+		days = DefaultInsightsDays
+	}
+	if days < 1 || days > MaxInsightsDays {
+		return nil, appErrors.NewValidationError("days must be between 1 and 365", nil)
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(days - 1))
+
+	queries, err := g.analytics.QueryStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	daily, err := g.analytics.DailyStats(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+
+	insights := &entities.SearchInsights{
+		Since: since,
+		Daily: fillDays(daily, since, today),
+	}
+	insights.Weekly = groupWeeks(insights.Daily)
+	for _, d := range insights.Daily {
+		insights.Searches += d.Searches
+		insights.CacheHits += d.CacheHits
+	}
+
+	grouped := groupQueries(queries)
+
+	top := append([]entities.QueryStats(nil), grouped...)
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Searches > top[j].Searches })
+	insights.TopQueries = firstN(top, insightsListSize)
+
+	var zero []entities.QueryStats
+	for _, q := range grouped {
+		if q.ZeroResults > 0 {
+			zero = append(zero, q)
+		}
+	}
+	sort.SliceStable(zero, func(i, j int) bool { return zero[i].ZeroResults > zero[j].ZeroResults })
+	insights.ZeroResultQueries = firstN(zero, insightsListSize)
+
+	return insights, nil
+}
+
+// NormalizeQuery folds case and collapses whitespace, so "Go  Tutorial" and
+// "go tutorial" count as the same query
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
+
+// groupQueries merges queries that normalize to the same text, sorted by
+// normalized query
+// Each group is shown as its most searched spelling
+func groupQueries(queries []entities.QueryStats) []entities.QueryStats {
+	type group struct {
+		stats    entities.QueryStats
+		spelling int // Searches of the spelling used as stats.Query
+	}
+
+	groups := make(map[string]*group)
+	for _, q := range queries {
+		key := NormalizeQuery(q.Query)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+		}
+		if q.Searches > g.spelling || (q.Searches == g.spelling && q.Query < g.stats.Query) {
+			g.stats.Query, g.spelling = q.Query, q.Searches
+		}
+		g.stats.Searches += q.Searches
+		g.stats.ZeroResults += q.ZeroResults
+		g.stats.CacheHits += q.CacheHits
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := make([]entities.QueryStats, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, groups[key].stats)
+	}
+	return result
+}
+
+// fillDays returns one entry per day from since to today, adding empty days
+func fillDays(daily []entities.DailySearchStats, since, today time.Time) []entities.DailySearchStats {
+	byDay := make(map[int64]entities.DailySearchStats, len(daily))
+	for _, d := range daily {
+		byDay[d.Day.Unix()] = d
+	}
+
+	var result []entities.DailySearchStats
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		d, ok := byDay[day.Unix()]
+		if !ok {
+			d = entities.DailySearchStats{Day: day}
+		}
+		result = append(result, d)
+	}
+	return result
+}
+
+// groupWeeks sums days (oldest first) into weeks starting on Monday
+func groupWeeks(daily []entities.DailySearchStats) []entities.DailySearchStats {
+	var weeks []entities.DailySearchStats
+	for _, d := range daily {
+		start := d.Day.AddDate(0, 0, -((int(d.Day.Weekday()) + 6) % 7))
+		if n := len(weeks); n == 0 || !weeks[n-1].Day.Equal(start) {
+			weeks = append(weeks, entities.DailySearchStats{Day: start})
+		}
+		w := &weeks[len(weeks)-1]
+		w.Searches += d.Searches
+		w.ZeroResults += d.ZeroResults
+		w.CacheHits += d.CacheHits
+	}
+	return weeks
+}
+
+// firstN returns at most n items
+func firstN(items []entities.QueryStats, n int) []entities.QueryStats {
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
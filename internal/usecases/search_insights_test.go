@@ -0,0 +1,127 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// MockSearchAnalytics is a mock implementation of ports.SearchAnalytics
+type MockSearchAnalytics struct {
+	mock.Mock
+}
+
+func (m *MockSearchAnalytics) QueryStats(ctx context.Context, since time.Time) ([]entities.QueryStats, error) {
+	args := m.Called(ctx, since)
+	stats, _ := args.Get(0).([]entities.QueryStats)
+	return stats, args.Error(1)
+}
+
+func (m *MockSearchAnalytics) DailyStats(ctx context.Context, since time.Time) ([]entities.DailySearchStats, error) {
+	args := m.Called(ctx, since)
+	stats, _ := args.Get(0).([]entities.DailySearchStats)
+	return stats, args.Error(1)
+}
+
+func TestGetSearchInsights_Execute_GroupsNormalizedQueries(t *testing.T) {
+	// Arrange
+	analytics := new(MockSearchAnalytics)
+	analytics.On("QueryStats", mock.Anything, mock.Anything).Return([]entities.QueryStats{
+		{Query: "Go  Tutorial", Searches: 1, ZeroResults: 1},
+		{Query: "go tutorial", Searches: 3, CacheHits: 2},
+		{Query: "rust", Searches: 2},
+		{Query: "zig", Searches: 1, ZeroResults: 1},
+		{Query: "qwertyuiop", Searches: 2, ZeroResults: 2},
+	}, nil)
+	analytics.On("DailyStats", mock.Anything, mock.Anything).Return([]entities.DailySearchStats(nil), nil)
+
+	uc := NewGetSearchInsights(analytics)
+
+	// Act
+	insights, err := uc.Execute(context.Background(), 7)
+
+	// Assert - spellings merge under the most searched one
+	require.NoError(t, err)
+	require.NotEmpty(t, insights.TopQueries)
+	assert.Equal(t, entities.QueryStats{Query: "go tutorial", Searches: 4, ZeroResults: 1, CacheHits: 2}, insights.TopQueries[0])
+	assert.Len(t, insights.TopQueries, 4)
+
+	var zero []string
+	for _, q := range insights.ZeroResultQueries {
+		zero = append(zero, q.Query)
+	}
+	assert.Equal(t, []string{"qwertyuiop", "go tutorial", "zig"}, zero)
+}
+
+func TestGetSearchInsights_Execute_FillsDaysAndWeeks(t *testing.T) {
+	// Arrange
+	analytics := new(MockSearchAnalytics)
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -13)
+
+	analytics.On("QueryStats", mock.Anything, since).Return([]entities.QueryStats(nil), nil)
+	analytics.On("DailyStats", mock.Anything, since).Return([]entities.DailySearchStats{
+		{Day: since, Searches: 4, CacheHits: 1},
+		{Day: today, Searches: 2, CacheHits: 2},
+	}, nil)
+
+	uc := NewGetSearchInsights(analytics)
+
+	// Act
+	insights, err := uc.Execute(context.Background(), 14)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, since, insights.Since)
+	require.Len(t, insights.Daily, 14)
+	assert.Equal(t, 4, insights.Daily[0].Searches)
+	assert.Zero(t, insights.Daily[1].Searches)
+	assert.True(t, insights.Daily[13].Day.Equal(today))
+	assert.Equal(t, 6, insights.Searches)
+	assert.InDelta(t, 0.5, insights.CacheHitRatio(), 0.001)
+
+	// Weeks start on Monday and add up to the same total
+	total := 0
+	for _, w := range insights.Weekly {
+		assert.Equal(t, time.Monday, w.Day.Weekday())
+		total += w.Searches
+	}
+	assert.Equal(t, 6, total)
+	assert.GreaterOrEqual(t, len(insights.Weekly), 2)
+}
+
+func TestGetSearchInsights_Execute_InvalidDays(t *testing.T) {
+	uc := NewGetSearchInsights(new(MockSearchAnalytics))
+
+	for _, days := range []int{-1, MaxInsightsDays + 1} {
+		_, err := uc.Execute(context.Background(), days)
+		assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err), "days=%d", days)
+	}
+}
+
+func TestGetSearchInsights_Execute_RepositoryError(t *testing.T) {
+	// Arrange
+	analytics := new(MockSearchAnalytics)
+	analytics.On("QueryStats", mock.Anything, mock.Anything).Return(nil, errors.New("database is locked"))
+
+	uc := NewGetSearchInsights(analytics)
+
+	// Act
+	insights, err := uc.Execute(context.Background(), 0)
+
+	// Assert
+	assert.Error(t, err)
+	assert.Nil(t, insights)
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	assert.Equal(t, "go tutorial", NormalizeQuery("  Go \t TUTORIAL "))
+	assert.Equal(t, "çava", NormalizeQuery("ÇAVA"))
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+const (
+	// MaxIntentLength caps the intent asked for before searching
+	MaxIntentLength = 120
+	// IntentSessionWindow is how long searches repeating the latest unrated
+	// intent keep counting toward it instead of starting a new one
+	IntentSessionWindow = 2 * time.Hour
+
+	DefaultIntentReviewLimit = 50
+	MaxIntentReviewLimit     = 200
+)
+
+// SearchIntents records why searches are made and whether they found what was wanted
+type SearchIntents struct {
+	repo    ports.SearchIntentRepository
+	enabled bool
+}
+
+// NewSearchIntents creates a new SearchIntents use case
+// When disabled, the search form does not ask for an intent and none is recorded
+func NewSearchIntents(repo ports.SearchIntentRepository, enabled bool) *SearchIntents {
+	return &SearchIntents{repo: repo, enabled: enabled}
+}
+
+// Enabled reports whether the search form asks for an intent
+func (s *SearchIntents) Enabled() bool {
+	return s.enabled
+}
+
+// Begin returns the intent a search is made for, or nil when none was given
+// Repeating the latest intent while it is unrated and recent continues it,
+// so refining a query stays one session
+// SearchVideos calls it only after the search succeeded (see SearchVideos.WithIntents)
+func (s *SearchIntents) Begin(ctx context.Context, intent string) (*entities.SearchIntent, error) {
+	intent, err := s.normalize(intent)
+	if err != nil || intent == "" {
+		return nil, err
+	}
+
+	now := time.Now()
+	latest, err := s.repo.LatestSearchIntent(ctx)
+	if err != nil && appErrors.GetErrorCode(err) != appErrors.ErrCodeNotFound {
+		return nil, err
+	}
+	if latest != nil && !latest.Rated() && strings.EqualFold(latest.Intent, intent) &&
+		now.Sub(latest.CreatedAt) < IntentSessionWindow {
+		return latest, nil
+	}
+
+	started := &entities.SearchIntent{Intent: intent, CreatedAt: now}
+	if err := s.repo.AddSearchIntent(ctx, started); err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+// normalize trims an intent and checks its length
+// It returns "" when intents are disabled (or s is nil) or none was given
+func (s *SearchIntents) normalize(intent string) (string, error) {
+	intent = strings.TrimSpace(intent)
+	if s == nil || !s.enabled || intent == "" {
+		return "", nil
+	}
+	if utf8.RuneCountInString(intent) > MaxIntentLength {
+		return "", appErrors.NewValidationError(
+			fmt.Sprintf("intent must be at most %d characters", MaxIntentLength), nil)
+	}
+	return intent, nil
+}
+
+// Rate records whether the intent was found and returns the rated intent
+func (s *SearchIntents) Rate(ctx context.Context, id int64, outcome string) (*entities.SearchIntent, error) {
+	if outcome != entities.IntentFound && outcome != entities.IntentNotFound {
+		return nil, appErrors.NewValidationError("outcome must be found or not_found", nil)
+	}
+	if err := s.repo.RateSearchIntent(ctx, id, outcome, time.Now()); err != nil {
+		return nil, err
+	}
+	return s.repo.GetSearchIntent(ctx, id)
+}
+
+// Review returns up to limit recent intents (default 50) with the time spent
+// on them, totalled by outcome
+func (s *SearchIntents) Review(ctx context.Context, limit int) (entities.IntentReview, error) {
+	limit, err := validation.ValidatePageSize(limit, DefaultIntentReviewLimit, MaxIntentReviewLimit)
+	if err != nil {
+		return entities.IntentReview{}, err
+	}
+
+	intents, err := s.repo.ListSearchIntents(ctx, limit)
+	if err != nil {
+		return entities.IntentReview{}, err
+	}
+
+	outcomes := []entities.IntentOutcomeStats{
+		{Outcome: entities.IntentFound},
+		{Outcome: entities.IntentNotFound},
+		{Outcome: ""},
+	}
+	for _, i := range intents {
+		for o := range outcomes {
+			if outcomes[o].Outcome == i.Outcome {
+				outcomes[o].Intents++
+				outcomes[o].Videos += i.Videos
+				outcomes[o].WatchedSeconds += i.WatchedSeconds
+			}
+		}
+	}
+	return entities.IntentReview{Intents: intents, Outcomes: outcomes}, nil
+}
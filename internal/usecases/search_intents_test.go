@@ -0,0 +1,182 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// MockSearchIntentRepository is a mock implementation of ports.SearchIntentRepository
+type MockSearchIntentRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchIntentRepository) AddSearchIntent(ctx context.Context, intent *entities.SearchIntent) error {
+	args := m.Called(ctx, intent)
+	return args.Error(0)
+}
+
+func (m *MockSearchIntentRepository) GetSearchIntent(ctx context.Context, id int64) (*entities.SearchIntent, error) {
+	args := m.Called(ctx, id)
+	intent, _ := args.Get(0).(*entities.SearchIntent)
+	return intent, args.Error(1)
+}
+
+func (m *MockSearchIntentRepository) LatestSearchIntent(ctx context.Context) (*entities.SearchIntent, error) {
+	args := m.Called(ctx)
+	intent, _ := args.Get(0).(*entities.SearchIntent)
+	return intent, args.Error(1)
+}
+
+func (m *MockSearchIntentRepository) RateSearchIntent(ctx context.Context, id int64, outcome string, ratedAt time.Time) error {
+	args := m.Called(ctx, id, outcome, ratedAt)
+	return args.Error(0)
+}
+
+func (m *MockSearchIntentRepository) ListSearchIntents(ctx context.Context, limit int) ([]entities.IntentActivity, error) {
+	args := m.Called(ctx, limit)
+	intents, _ := args.Get(0).([]entities.IntentActivity)
+	return intents, args.Error(1)
+}
+
+func TestSearchIntents_BeginNew(t *testing.T) {
+	// Arrange
+	repo := new(MockSearchIntentRepository)
+	repo.On("LatestSearchIntent", mock.Anything).Return(nil, appErrors.NewNotFoundError("Search intent"))
+	repo.On("AddSearchIntent", mock.Anything, mock.MatchedBy(func(i *entities.SearchIntent) bool {
+		return i.Intent == "learn pgx pooling"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.SearchIntent).ID = 4
+	}).Return(nil)
+
+	uc := NewSearchIntents(repo, true)
+
+	// Act
+	intent, err := uc.Begin(context.Background(), "  learn pgx pooling ")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), intent.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestSearchIntents_BeginContinuesLatest(t *testing.T) {
+	tests := []struct {
+		name      string
+		latest    entities.SearchIntent
+		continues bool
+	}{
+		{"same and recent", entities.SearchIntent{ID: 2, Intent: "Learn PGX pooling", CreatedAt: time.Now().Add(-time.Hour)}, true},
+		{"different intent", entities.SearchIntent{ID: 2, Intent: "fix the bike", CreatedAt: time.Now()}, false},
+		{"already rated", entities.SearchIntent{ID: 2, Intent: "learn pgx pooling", CreatedAt: time.Now(), Outcome: entities.IntentFound}, false},
+		{"too old", entities.SearchIntent{ID: 2, Intent: "learn pgx pooling", CreatedAt: time.Now().Add(-3 * time.Hour)}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			repo := new(MockSearchIntentRepository)
+			latest := tt.latest
+			repo.On("LatestSearchIntent", mock.Anything).Return(&latest, nil)
+			repo.On("AddSearchIntent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				args.Get(1).(*entities.SearchIntent).ID = 3
+			}).Return(nil)
+
+			uc := NewSearchIntents(repo, true)
+
+			// Act
+			intent, err := uc.Begin(context.Background(), "learn pgx pooling")
+
+			// Assert
+			require.NoError(t, err)
+			if tt.continues {
+				assert.Equal(t, int64(2), intent.ID)
+				repo.AssertNotCalled(t, "AddSearchIntent", mock.Anything, mock.Anything)
+			} else {
+				assert.Equal(t, int64(3), intent.ID)
+			}
+		})
+	}
+}
+
+func TestSearchIntents_BeginWithoutIntent(t *testing.T) {
+	repo := new(MockSearchIntentRepository)
+
+	tests := []struct {
+		name    string
+		enabled bool
+		intent  string
+	}{
+		{"empty", true, "  "},
+		{"disabled", false, "learn pgx pooling"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			intent, err := NewSearchIntents(repo, tt.enabled).Begin(context.Background(), tt.intent)
+
+			require.NoError(t, err)
+			assert.Nil(t, intent)
+		})
+	}
+	repo.AssertNotCalled(t, "LatestSearchIntent", mock.Anything)
+}
+
+func TestSearchIntents_BeginTooLong(t *testing.T) {
+	uc := NewSearchIntents(new(MockSearchIntentRepository), true)
+
+	_, err := uc.Begin(context.Background(), strings.Repeat("a", MaxIntentLength+1))
+
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+}
+
+func TestSearchIntents_Rate(t *testing.T) {
+	// Arrange
+	repo := new(MockSearchIntentRepository)
+	repo.On("RateSearchIntent", mock.Anything, int64(4), entities.IntentFound, mock.Anything).Return(nil)
+	repo.On("GetSearchIntent", mock.Anything, int64(4)).Return(&entities.SearchIntent{ID: 4, Outcome: entities.IntentFound}, nil)
+
+	uc := NewSearchIntents(repo, true)
+
+	// Act
+	intent, err := uc.Rate(context.Background(), 4, entities.IntentFound)
+	_, invalidErr := uc.Rate(context.Background(), 4, "maybe")
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, intent.Rated())
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(invalidErr))
+	repo.AssertExpectations(t)
+}
+
+func TestSearchIntents_Review(t *testing.T) {
+	// Arrange
+	repo := new(MockSearchIntentRepository)
+	repo.On("ListSearchIntents", mock.Anything, DefaultIntentReviewLimit).Return([]entities.IntentActivity{
+		{SearchIntent: entities.SearchIntent{Outcome: entities.IntentFound}, Videos: 1, WatchedSeconds: 300},
+		{SearchIntent: entities.SearchIntent{Outcome: entities.IntentFound}, Videos: 2, WatchedSeconds: 900},
+		{SearchIntent: entities.SearchIntent{Outcome: entities.IntentNotFound}, Videos: 4, WatchedSeconds: 3000},
+	}, nil)
+
+	uc := NewSearchIntents(repo, true)
+
+	// Act
+	review, err := uc.Review(context.Background(), 0)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, review.Intents, 3)
+	require.Len(t, review.Outcomes, 3)
+	assert.Equal(t, entities.IntentOutcomeStats{Outcome: entities.IntentFound, Intents: 2, Videos: 3, WatchedSeconds: 1200}, review.Outcomes[0])
+	assert.Equal(t, 600, review.Outcomes[0].AverageSeconds())
+	assert.Equal(t, 3000, review.Outcomes[1].AverageSeconds())
+	assert.Zero(t, review.Outcomes[2].Intents, "nothing unrated")
+	assert.Zero(t, review.Outcomes[2].AverageSeconds())
+}
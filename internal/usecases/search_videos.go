@@ -6,37 +6,195 @@ import (
 
 	"github.com/uiansol/zentube/internal/cache"
 	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
 	"github.com/uiansol/zentube/internal/ports"
 )
 
+// Cache entry sources, visible in cache stats and the admin page
+const (
+	CacheSourceResults = "results" // Non-empty result set
+	CacheSourceEmpty   = "empty"   // Search succeeded with zero results
+	CacheSourceError   = "error"   // Negative cache entry for a failed search
+)
+
+// CachePolicy controls how long each kind of search outcome stays cached
+type CachePolicy struct {
+	ResultsTTL time.Duration // TTL for non-empty result sets
+	EmptyTTL   time.Duration // TTL for empty result sets
+	ErrorTTL   time.Duration // TTL for cacheable errors (0 disables negative caching)
+	ErrorCodes []string      // AppError codes that are safe to cache (e.g. BAD_REQUEST)
+}
+
+// DefaultCachePolicy caches every successful search for ttl and never caches errors
+func DefaultCachePolicy(ttl time.Duration) CachePolicy {
+	return CachePolicy{ResultsTTL: ttl, EmptyTTL: ttl}
+}
+
+// negativeResult is stored in the cache in place of videos for a failed search
+type negativeResult struct {
+	Err error
+}
+
 type SearchVideos struct {
 	ytClient    ports.YouTubeClient
 	historyRepo ports.SearchHistoryRepository
-	cache       *cache.Cache // Optional cache for reducing API calls
+	videoRepo   ports.VideoRepository // Optional local catalog of returned videos
+	writer      *HistoryWriter        // Optional background history writer
+	cache       *cache.Cache          // Optional cache for reducing API calls
+	budget      SearchBudget          // Optional viewing budget that can refuse searches
+	intents     *SearchIntents        // Optional, records what searches are made for
+	policy      CachePolicy
 }
 
 // NewSearchVideos creates a new SearchVideos use case
-// Cache is optional - pass nil to disable caching
 func NewSearchVideos(ytClient ports.YouTubeClient, historyRepo ports.SearchHistoryRepository) *SearchVideos {
+	// Initialize cache with 1000 entries max, 5-minute TTL
+	// This prevents hammering the YouTube API with duplicate searches
+	ttl := 5 * time.Minute
+	return NewSearchVideosWithCache(ytClient, historyRepo, cache.NewCache(1000, ttl), DefaultCachePolicy(ttl))
+}
+
+// NewSearchVideosWithCache creates a new SearchVideos use case with a caller-provided cache
+// Cache is optional - pass nil to disable caching
+func NewSearchVideosWithCache(ytClient ports.YouTubeClient, historyRepo ports.SearchHistoryRepository, c *cache.Cache, policy CachePolicy) *SearchVideos {
 	return &SearchVideos{
 		ytClient:    ytClient,
 		historyRepo: historyRepo,
-		// Initialize cache with 1000 entries max, 5-minute TTL
-		// This prevents hammering the YouTube API with duplicate searches
-		cache: cache.NewCache(1000, 5*time.Minute),
+		cache:       c,
+		policy:      policy,
 	}
 }
 
+// WithVideoCatalog records the videos each search returns in the local catalog
+func (s *SearchVideos) WithVideoCatalog(videoRepo ports.VideoRepository) *SearchVideos {
+	s.videoRepo = videoRepo
+	return s
+}
+
+// WithHistoryWriter hands search history to a background writer
+// Without one, history is saved inline before Execute returns
+func (s *SearchVideos) WithHistoryWriter(writer *HistoryWriter) *SearchVideos {
+	s.writer = writer
+	return s
+}
+
+// WithBudget refuses searches once the viewing budget is used up, cached ones included
+func (s *SearchVideos) WithBudget(budget SearchBudget) *SearchVideos {
+	s.budget = budget
+	return s
+}
+
+// WithIntents stores the intent given with a search once the search has succeeded,
+// so refused and failed searches leave no intent behind
+func (s *SearchVideos) WithIntents(intents *SearchIntents) *SearchVideos {
+	s.intents = intents
+	return s
+}
+
+// SearchCacheNamespace is the cache namespace used for search results
+const SearchCacheNamespace = "search"
+
+// searchCacheKey generates the cache key for a query and maxResults pair
+func searchCacheKey(query string, maxResults int64) string {
+	return cache.NamespacedKey(SearchCacheNamespace, query, maxResults)
+}
+
+// setCached stores search results labeled with their query
+// Empty result sets use their own (usually shorter) TTL
+func (s *SearchVideos) setCached(query string, maxResults int64, videos []entities.Video) {
+	ttl, source := s.policy.ResultsTTL, CacheSourceResults
+	if len(videos) == 0 {
+		ttl, source = s.policy.EmptyTTL, CacheSourceEmpty
+	}
+
+	s.cache.SetWithMeta(searchCacheKey(query, maxResults), videos, ttl, cache.EntryMeta{
+		Label:  query,
+		Source: source,
+	})
+}
+
+// setCachedError stores a negative cache entry if the error class is cacheable
+// This keeps repeated retries of a permanently failing query from draining quota
+func (s *SearchVideos) setCachedError(query string, maxResults int64, err error) {
+	if s.policy.ErrorTTL <= 0 || !s.isCacheableError(err) {
+		return
+	}
+
+	s.cache.SetWithMeta(searchCacheKey(query, maxResults), negativeResult{Err: err}, s.policy.ErrorTTL, cache.EntryMeta{
+		Label:  query,
+		Source: CacheSourceError,
+	})
+}
+
+// isCacheableError reports whether the error's AppError code is in the policy
+func (s *SearchVideos) isCacheableError(err error) bool {
+	if !appErrors.IsAppError(err) {
+		return false
+	}
+
+	code := appErrors.GetErrorCode(err)
+	for _, c := range s.policy.ErrorCodes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *SearchVideos) Execute(ctx context.Context, query string, maxResults int64) ([]entities.Video, error) {
+	videos, _, err := s.ExecuteWithIntent(ctx, query, maxResults, "")
+	return videos, err
+}
+
+// ExecuteWithIntent searches like Execute and records the search under intent (empty for none)
+// The intent is checked up front but only stored once the search has succeeded;
+// the returned intent is nil when none was recorded
+func (s *SearchVideos) ExecuteWithIntent(ctx context.Context, query string, maxResults int64, intent string) ([]entities.Video, *entities.SearchIntent, error) {
+	if s.budget != nil {
+		if err := s.budget.CheckSearch(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	intent, err := s.intents.normalize(intent)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	videos, cacheHit, err := s.fetch(query, maxResults)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var started *entities.SearchIntent
+	var intentID int64
+	if intent != "" {
+		if started, err = s.intents.Begin(ctx, intent); err != nil {
+			return nil, nil, err
+		}
+		intentID = started.ID
+	}
+
+	s.recordHistory(ctx, query, intentID, videos, cacheHit)
+
+	return videos, started, nil
+}
+
+// fetch returns the results for a query from the cache or the YouTube API
+// and reports whether they came from the cache
+func (s *SearchVideos) fetch(query string, maxResults int64) ([]entities.Video, bool, error) {
 	// Generate cache key from query and maxResults
-	cacheKey := cache.GenerateKey("search", query, maxResults)
+	cacheKey := searchCacheKey(query, maxResults)
 
 	// Try to get from cache first
 	if s.cache != nil {
 		if cached, found := s.cache.Get(cacheKey); found {
-			// Cache hit! Return cached results
-			if videos, ok := cached.([]entities.Video); ok {
-				return videos, nil
+			// Cache hit! Return cached results (or the cached failure)
+			switch v := cached.(type) {
+			case []entities.Video:
+				return v, true, nil
+			case negativeResult:
+				return nil, false, v.Err
 			}
 		}
 	}
@@ -44,29 +202,81 @@ func (s *SearchVideos) Execute(ctx context.Context, query string, maxResults int
 	// Cache miss - fetch from YouTube API
 	videos, err := s.ytClient.Search(query, maxResults)
 	if err != nil {
-		return nil, err
+		if s.cache != nil {
+			s.setCachedError(query, maxResults, err)
+		}
+		return nil, false, err
 	}
 
 	// Store in cache for future requests
 	if s.cache != nil {
-		s.cache.Set(cacheKey, videos)
+		s.setCached(query, maxResults, videos)
 	}
 
-	// Save search history asynchronously with a timeout
-	// Use background context with timeout to avoid blocking the response
-	go func() {
-		saveCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-		defer cancel()
+	return videos, false, nil
+}
 
-		history := &entities.SearchHistory{
+// recordHistory queues the search for the history writer, or saves it inline
+// Cache hits are recorded too, so history reflects every successful search
+// History is best effort - it never fails the search
+func (s *SearchVideos) recordHistory(ctx context.Context, query string, intentID int64, videos []entities.Video, cacheHit bool) {
+	entry := HistoryEntry{
+		History: entities.SearchHistory{
 			Query:     query,
 			Results:   len(videos),
+			CacheHit:  cacheHit,
+			IntentID:  intentID,
 			CreatedAt: time.Now(),
-		}
+		},
+		Videos: videos,
+	}
 
-		// Don't fail the search if history save fails
-		_ = s.historyRepo.Save(saveCtx, history)
-	}()
+	if s.writer != nil {
+		s.writer.Enqueue(entry)
+		return
+	}
+
+	// Keep saving if the client goes away, but don't hang on a stuck database
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 3*time.Second)
+	defer cancel()
+
+	if err := s.historyRepo.Save(saveCtx, &entry.History); err != nil {
+		return
+	}
+
+	// Link the returned videos to the saved history row
+	if s.videoRepo != nil {
+		_ = s.videoRepo.SaveSearchResults(saveCtx, entry.History.ID, videos)
+	}
+}
+
+// NeedsRefresh reports whether the cached results for a query are missing
+// or will expire within the given window
+func (s *SearchVideos) NeedsRefresh(query string, maxResults int64, within time.Duration) bool {
+	if s.cache == nil {
+		return false
+	}
+
+	remaining, found := s.cache.TTL(searchCacheKey(query, maxResults))
+	return !found || remaining <= within
+}
+
+// Refresh fetches a query from the YouTube API and stores it in the cache
+// Unlike Execute, it does not record search history (used by the cache warmer)
+func (s *SearchVideos) Refresh(ctx context.Context, query string, maxResults int64) error {
+	if s.cache == nil {
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	videos, err := s.ytClient.Search(query, maxResults)
+	if err != nil {
+		return err
+	}
 
-	return videos, nil
+	s.setCached(query, maxResults, videos)
+	return nil
 }
@@ -3,12 +3,16 @@ package usecases
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/cache"
 	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
 )
 
 // MockYouTubeClient for testing
@@ -34,6 +38,11 @@ func (m *MockSearchHistoryRepository) Save(ctx context.Context, history *entitie
 	return args.Error(0)
 }
 
+func (m *MockSearchHistoryRepository) SaveBatch(ctx context.Context, histories []*entities.SearchHistory) error {
+	args := m.Called(ctx, histories)
+	return args.Error(0)
+}
+
 func (m *MockSearchHistoryRepository) GetLast(ctx context.Context, limit int) ([]entities.SearchHistory, error) {
 	args := m.Called(ctx, limit)
 	if args.Get(0) == nil {
@@ -42,6 +51,66 @@ func (m *MockSearchHistoryRepository) GetLast(ctx context.Context, limit int) ([
 	return args.Get(0).([]entities.SearchHistory), args.Error(1)
 }
 
+func (m *MockSearchHistoryRepository) GetTopQueries(ctx context.Context, since time.Time, limit int) ([]entities.QueryFrequency, error) {
+	args := m.Called(ctx, since, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.QueryFrequency), args.Error(1)
+}
+
+func (m *MockSearchHistoryRepository) List(ctx context.Context, filter entities.SearchHistoryFilter, after *entities.SearchHistoryCursor, limit int) ([]entities.SearchHistory, error) {
+	args := m.Called(ctx, filter, after, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.SearchHistory), args.Error(1)
+}
+
+func (m *MockSearchHistoryRepository) Delete(ctx context.Context, id int64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSearchHistoryRepository) DeleteAll(ctx context.Context) (int64, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSearchHistoryRepository) FullTextSearch(ctx context.Context, text string, limit int) ([]entities.SearchHistoryMatch, error) {
+	args := m.Called(ctx, text, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.SearchHistoryMatch), args.Error(1)
+}
+
+// MockVideoRepository for testing
+type MockVideoRepository struct {
+	mock.Mock
+}
+
+func (m *MockVideoRepository) SaveSearchResults(ctx context.Context, searchID int64, videos []entities.Video) error {
+	args := m.Called(ctx, searchID, videos)
+	return args.Error(0)
+}
+
+func (m *MockVideoRepository) GetByID(ctx context.Context, id string) (*entities.Video, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entities.Video), args.Error(1)
+}
+
+func (m *MockVideoRepository) GetSearchResults(ctx context.Context, searchID int64) ([]entities.Video, error) {
+	args := m.Called(ctx, searchID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entities.Video), args.Error(1)
+}
+
 func TestSearchVideos_Execute_Success(t *testing.T) {
 	// Arrange
 	mockClient := new(MockYouTubeClient)
@@ -74,6 +143,111 @@ func TestSearchVideos_Execute_Success(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestSearchVideos_Execute_SavesToVideoCatalog(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	mockVideos := new(MockVideoRepository)
+	expectedVideos := []entities.Video{{ID: "a"}, {ID: "b"}}
+
+	mockClient.On("Search", "golang", int64(10)).Return(expectedVideos, nil)
+	mockRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.SearchHistory).ID = 42
+	}).Return(nil)
+	mockVideos.On("SaveSearchResults", mock.Anything, int64(42), expectedVideos).Return(nil)
+
+	uc := NewSearchVideos(mockClient, mockRepo).WithVideoCatalog(mockVideos)
+
+	// Act
+	_, err := uc.Execute(context.Background(), "golang", 10)
+
+	// Assert
+	assert.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	mockRepo.AssertExpectations(t)
+	mockVideos.AssertExpectations(t)
+}
+
+func TestSearchVideos_ExecuteWithIntent(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+
+	intents := new(MockSearchIntentRepository)
+
+	mockClient.On("Search", "pgx pool", int64(10)).Return([]entities.Video{{ID: "a"}}, nil)
+	intents.On("LatestSearchIntent", mock.Anything).Return(nil, appErrors.NewNotFoundError("Search intent"))
+	intents.On("AddSearchIntent", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.SearchIntent).ID = 7
+	}).Return(nil)
+	mockRepo.On("Save", mock.Anything, mock.MatchedBy(func(h *entities.SearchHistory) bool {
+		return h.IntentID == 7
+	})).Return(nil)
+
+	uc := NewSearchVideos(mockClient, mockRepo).WithIntents(NewSearchIntents(intents, true))
+
+	// Act
+	_, intent, err := uc.ExecuteWithIntent(context.Background(), "pgx pool", 10, " learn pgx pooling ")
+
+	// Assert
+	assert.NoError(t, err)
+	require.NotNil(t, intent)
+	assert.Equal(t, "learn pgx pooling", intent.Intent)
+	mockRepo.AssertExpectations(t)
+	intents.AssertExpectations(t)
+}
+
+func TestSearchVideos_ExecuteWithIntent_FailedSearchStoresNoIntent(t *testing.T) {
+	tests := []struct {
+		name   string
+		intent string
+		code   string
+	}{
+		{"upstream error", "learn pgx pooling", appErrors.ErrCodeServiceUnavail},
+		{"intent too long", strings.Repeat("a", MaxIntentLength+1), appErrors.ErrCodeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			mockClient := new(MockYouTubeClient)
+			mockClient.On("Search", "pgx pool", int64(10)).
+				Return(nil, appErrors.NewServiceUnavailableError("YouTube is unavailable", nil))
+			intents := new(MockSearchIntentRepository)
+			uc := NewSearchVideos(mockClient, new(MockSearchHistoryRepository)).WithIntents(NewSearchIntents(intents, true))
+
+			// Act
+			_, intent, err := uc.ExecuteWithIntent(context.Background(), "pgx pool", 10, tt.intent)
+
+			// Assert
+			assert.Nil(t, intent)
+			assert.Equal(t, tt.code, appErrors.GetErrorCode(err))
+			intents.AssertNotCalled(t, "AddSearchIntent", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSearchVideos_Execute_QueuesHistoryWithWriter(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	mockClient.On("Search", "golang", int64(10)).Return([]entities.Video{{ID: "a"}}, nil)
+
+	writer := newTestHistoryWriter(mockRepo, nil, HistoryWriterConfig{QueueSize: 1, BatchSize: 1, FlushInterval: time.Hour})
+	uc := NewSearchVideos(mockClient, mockRepo).WithHistoryWriter(writer)
+
+	// Act
+	_, err := uc.Execute(context.Background(), "golang", 10)
+
+	// Assert - queued for the writer, not saved inline
+	assert.NoError(t, err)
+	assert.Equal(t, 1, writer.Stats().Queued)
+	entry := <-writer.queue
+	assert.Equal(t, "golang", entry.History.Query)
+	assert.Equal(t, 1, entry.History.Results)
+	mockRepo.AssertNotCalled(t, "Save")
+}
+
 func TestSearchVideos_Execute_Error(t *testing.T) {
 	// Arrange
 	mockClient := new(MockYouTubeClient)
@@ -97,6 +271,23 @@ func TestSearchVideos_Execute_Error(t *testing.T) {
 	mockRepo.AssertNotCalled(t, "Save")
 }
 
+func TestSearchVideos_Execute_RefusedByBudget(t *testing.T) {
+	// Arrange - cached results are refused too
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	uc := NewSearchVideos(mockClient, mockRepo).WithBudget(refusingBudget{})
+	uc.setCached("golang", 10, []entities.Video{{ID: testVideoID}})
+
+	// Act
+	videos, err := uc.Execute(context.Background(), "golang", 10)
+
+	// Assert
+	assert.Nil(t, videos)
+	assert.Equal(t, appErrors.ErrCodeBudgetExhausted, appErrors.GetErrorCode(err))
+	mockClient.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+}
+
 func TestSearchVideos_Execute_EmptyQuery(t *testing.T) {
 	// Arrange
 	mockClient := new(MockYouTubeClient)
@@ -162,6 +353,34 @@ func TestSearchVideos_Execute_CacheHit(t *testing.T) {
 	mockClient.AssertNumberOfCalls(t, "Search", 1)
 }
 
+func TestSearchVideos_Execute_CacheHitRecordsHistory(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	videos := []entities.Video{{ID: "vid1"}}
+
+	var saved []entities.SearchHistory
+	mockClient.On("Search", "golang", int64(10)).Return(videos, nil).Once()
+	mockRepo.On("Save", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		saved = append(saved, *args.Get(1).(*entities.SearchHistory))
+	}).Return(nil)
+
+	uc := NewSearchVideos(mockClient, mockRepo)
+	ctx := context.Background()
+
+	// Act
+	_, err1 := uc.Execute(ctx, "golang", 10)
+	_, err2 := uc.Execute(ctx, "golang", 10)
+
+	// Assert - both searches are in history, only the second from the cache
+	assert.NoError(t, err1)
+	assert.NoError(t, err2)
+	require.Len(t, saved, 2)
+	assert.False(t, saved[0].CacheHit)
+	assert.True(t, saved[1].CacheHit)
+	assert.Equal(t, 1, saved[1].Results)
+}
+
 func TestSearchVideos_Execute_CacheMissDifferentParams(t *testing.T) {
 	// Arrange
 	mockClient := new(MockYouTubeClient)
@@ -190,3 +409,88 @@ func TestSearchVideos_Execute_CacheMissDifferentParams(t *testing.T) {
 	// Both calls should hit the API (different cache keys)
 	mockClient.AssertNumberOfCalls(t, "Search", 2)
 }
+
+func TestSearchVideos_Execute_NegativeCache(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	badRequest := appErrors.NewBadRequestError("YouTube rejected the search parameters", nil)
+
+	// Only the first call should reach the API
+	mockClient.On("Search", "golang", int64(10)).Return(nil, badRequest).Once()
+
+	c := cache.NewCache(100, 5*time.Minute)
+	uc := NewSearchVideosWithCache(mockClient, mockRepo, c, CachePolicy{
+		ResultsTTL: 5 * time.Minute,
+		EmptyTTL:   time.Minute,
+		ErrorTTL:   30 * time.Second,
+		ErrorCodes: []string{appErrors.ErrCodeBadRequest},
+	})
+	ctx := context.Background()
+
+	// Act
+	_, err1 := uc.Execute(ctx, "golang", 10)
+	_, err2 := uc.Execute(ctx, "golang", 10)
+
+	// Assert
+	assert.Equal(t, badRequest, err1)
+	assert.Equal(t, badRequest, err2)
+	mockClient.AssertNumberOfCalls(t, "Search", 1)
+	assert.Equal(t, 1, c.GetStats().BySource[CacheSourceError])
+
+	ttl, found := c.TTL(searchCacheKey("golang", 10))
+	assert.True(t, found)
+	assert.LessOrEqual(t, ttl, 30*time.Second)
+}
+
+func TestSearchVideos_Execute_TransientErrorNotCached(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+
+	mockClient.On("Search", "golang", int64(10)).Return(nil, errors.New("connection reset"))
+
+	uc := NewSearchVideosWithCache(mockClient, mockRepo, cache.NewCache(100, 5*time.Minute), CachePolicy{
+		ResultsTTL: 5 * time.Minute,
+		EmptyTTL:   time.Minute,
+		ErrorTTL:   30 * time.Second,
+		ErrorCodes: []string{appErrors.ErrCodeBadRequest},
+	})
+	ctx := context.Background()
+
+	// Act
+	_, err1 := uc.Execute(ctx, "golang", 10)
+	_, err2 := uc.Execute(ctx, "golang", 10)
+
+	// Assert - every retry goes upstream
+	assert.Error(t, err1)
+	assert.Error(t, err2)
+	mockClient.AssertNumberOfCalls(t, "Search", 2)
+}
+
+func TestSearchVideos_Execute_EmptyResultsUseEmptyTTL(t *testing.T) {
+	// Arrange
+	mockClient := new(MockYouTubeClient)
+	mockRepo := new(MockSearchHistoryRepository)
+	mockClient.On("Search", "nothing", int64(10)).Return([]entities.Video{}, nil).Once()
+	mockRepo.On("Save", mock.Anything, mock.Anything).Return(nil)
+
+	c := cache.NewCache(100, 5*time.Minute)
+	uc := NewSearchVideosWithCache(mockClient, mockRepo, c, CachePolicy{
+		ResultsTTL: 5 * time.Minute,
+		EmptyTTL:   time.Minute,
+	})
+
+	// Act
+	_, err := uc.Execute(context.Background(), "nothing", 10)
+
+	// Assert
+	assert.NoError(t, err)
+	ttl, found := c.TTL(searchCacheKey("nothing", 10))
+	assert.True(t, found)
+	assert.LessOrEqual(t, ttl, time.Minute)
+	assert.Equal(t, 1, c.GetStats().BySource[CacheSourceEmpty])
+
+	// Wait for async save
+	time.Sleep(100 * time.Millisecond)
+}
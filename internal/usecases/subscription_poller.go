@@ -0,0 +1,68 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// SubscriptionPoller polls every followed channel for new uploads on an interval
+type SubscriptionPoller struct {
+	subs     *Subscriptions
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewSubscriptionPoller creates a new subscription poller
+func NewSubscriptionPoller(subs *Subscriptions, interval time.Duration, logger *slog.Logger) *SubscriptionPoller {
+	return &SubscriptionPoller{
+		subs:     subs,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Run polls immediately and then on every interval until ctx is cancelled
+func (p *SubscriptionPoller) Run(ctx context.Context) {
+	p.logger.Info("subscription poller started", slog.Duration("interval", p.interval))
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			p.logger.Info("subscription poller stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single polling pass and logs the outcome
+func (p *SubscriptionPoller) runOnce(ctx context.Context) {
+	start := time.Now()
+
+	result, err := p.PollOnce(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		p.logger.Error("subscription poll run failed", slog.Any("error", err))
+		return
+	}
+
+	p.logger.Info("subscription poll run completed",
+		slog.Int("channels", result.Channels),
+		slog.Int("new_videos", result.NewVideos),
+		slog.Int("failed", result.Failed),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// PollOnce polls every followed channel once
+func (p *SubscriptionPoller) PollOnce(ctx context.Context) (PollResult, error) {
+	return p.subs.PollAll(ctx)
+}
@@ -0,0 +1,286 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// Feed page sizes
+const (
+	DefaultFeedPageSize = 30
+	MaxFeedPageSize     = 100
+)
+
+// PollResult summarizes a single polling run over every followed channel
+type PollResult struct {
+	Channels  int
+	NewVideos int
+	Failed    int
+}
+
+// Subscriptions manages followed channels and their strictly chronological feed
+// There is no ranking: the feed is every polled upload, newest first
+type Subscriptions struct {
+	repo     ports.SubscriptionRepository
+	source   ports.ChannelSource
+	notifier NewVideosNotifier
+	logger   *slog.Logger
+}
+
+// NewSubscriptions creates a new Subscriptions use case
+func NewSubscriptions(repo ports.SubscriptionRepository, source ports.ChannelSource, logger *slog.Logger) *Subscriptions {
+	return &Subscriptions{repo: repo, source: source, logger: logger}
+}
+
+// WithNotifier reports uploads that reach the feed unseen (e.g. to webhooks)
+// The back catalogue found when subscribing is not reported
+func (s *Subscriptions) WithNotifier(notifier NewVideosNotifier) *Subscriptions {
+	s.notifier = notifier
+	return s
+}
+
+// Subscribe follows a channel (ID, @handle or channel link) and polls it right away
+// A failed first poll doesn't fail the subscription; it is recorded and retried
+// by the next scheduled poll
+func (s *Subscriptions) Subscribe(ctx context.Context, ref string) (*entities.Subscription, error) {
+	ref, err := validation.ParseChannelRef(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	channel, err := s.source.LookupChannel(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.repo.AddSubscription(ctx, *channel, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	_, _ = s.Poll(ctx, *sub)
+	return sub, nil
+}
+
+// Unsubscribe unfollows a channel and drops its uploads from the feed
+func (s *Subscriptions) Unsubscribe(ctx context.Context, channelID string) error {
+	if err := validation.ValidateChannelID(channelID); err != nil {
+		return err
+	}
+	return s.repo.RemoveSubscription(ctx, channelID)
+}
+
+// List returns every followed channel with its unseen count
+func (s *Subscriptions) List(ctx context.Context) ([]entities.Subscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// Feed returns one page of the feed, newest upload first
+// cursor is the NextCursor of the previous page (empty for the first page)
+// pageSize 0 means DefaultFeedPageSize
+func (s *Subscriptions) Feed(ctx context.Context, filter entities.FeedFilter, cursor string, pageSize int) (*entities.FeedPage, error) {
+	size, err := validation.ValidatePageSize(pageSize, DefaultFeedPageSize, MaxFeedPageSize)
+	if err != nil {
+		return nil, err
+	}
+	if filter.ChannelID != "" {
+		if err := validation.ValidateChannelID(filter.ChannelID); err != nil {
+			return nil, err
+		}
+	}
+
+	var after *entities.FeedCursor
+	if cursor != "" {
+		if after, err = decodeFeedCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fetch one extra item to know whether there is a next page
+	items, err := s.repo.ListFeed(ctx, filter, after, size+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &entities.FeedPage{Items: items}
+	if len(items) > size {
+		page.Items = items[:size]
+		last := page.Items[size-1]
+		page.NextCursor = encodeFeedCursor(entities.FeedCursor{PublishedAt: last.PublishedAt, VideoID: last.ID})
+	}
+	return page, nil
+}
+
+// SetSeen marks a feed item as seen now, or as unseen
+func (s *Subscriptions) SetSeen(ctx context.Context, videoID string, seen bool) error {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return err
+	}
+
+	var seenAt time.Time
+	if seen {
+		seenAt = time.Now()
+	}
+	return s.repo.SetFeedItemSeen(ctx, videoID, seenAt)
+}
+
+// MarkAllSeen marks every unseen item as seen, optionally for one channel only
+func (s *Subscriptions) MarkAllSeen(ctx context.Context, channelID string) (int, error) {
+	if channelID != "" {
+		if err := validation.ValidateChannelID(channelID); err != nil {
+			return 0, err
+		}
+	}
+	return s.repo.MarkFeedSeen(ctx, entities.FeedFilter{ChannelID: channelID}, time.Now())
+}
+
+// Poll fetches a channel's recent uploads into the feed and returns how many were new
+// Uploads published before the subscription started arrive already seen, so
+// following a channel doesn't flood the feed with its back catalogue
+// The outcome is recorded on the subscription either way
+func (s *Subscriptions) Poll(ctx context.Context, sub entities.Subscription) (int, error) {
+	now := time.Now()
+
+	added, err := s.fetch(ctx, sub, now)
+
+	pollErr := ""
+	if err != nil {
+		pollErr = storedErrorMessage(err, "could not fetch the channel's uploads")
+		s.logger.Warn("channel poll failed",
+			slog.String("channel_id", sub.ID),
+			slog.String("channel", sub.Title),
+			slog.Any("error", err),
+		)
+	}
+	if recordErr := s.repo.RecordSubscriptionPoll(ctx, sub.ID, now, pollErr); recordErr != nil && err == nil {
+		err = recordErr
+	}
+	return added, err
+}
+
+// PollAll polls every followed channel once
+// A failing channel is counted and recorded on its subscription; it doesn't stop the others
+func (s *Subscriptions) PollAll(ctx context.Context) (PollResult, error) {
+	var result PollResult
+
+	subs, err := s.repo.ListSubscriptions(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to load subscriptions: %w", err)
+	}
+
+	for _, sub := range subs {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		result.Channels++
+		added, err := s.Poll(ctx, sub)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		result.NewVideos += added
+	}
+
+	return result, nil
+}
+
+// fetch reads a channel's uploads and stores them as feed items
+func (s *Subscriptions) fetch(ctx context.Context, sub entities.Subscription, now time.Time) (int, error) {
+	videos, err := s.source.RecentUploads(ctx, sub.ID)
+	if err != nil {
+		return 0, err
+	}
+
+	items := make([]entities.FeedItem, 0, len(videos))
+	for _, v := range videos {
+		if validation.ValidateVideoID(v.ID) != nil {
+			continue
+		}
+		if v.Channel == "" {
+			v.Channel = sub.Title
+		}
+		if v.PublishedAt.IsZero() {
+			v.PublishedAt = now
+		}
+
+		item := entities.FeedItem{Video: v, ChannelID: sub.ID, FetchedAt: now}
+		if v.PublishedAt.Before(sub.SubscribedAt) {
+			item.SeenAt = now
+		}
+		items = append(items, item)
+	}
+
+	added, err := s.repo.AddFeedItems(ctx, items)
+	if err != nil {
+		return 0, err
+	}
+
+	if s.notifier != nil {
+		var unseen []entities.Video
+		for _, item := range added {
+			if !item.Seen() {
+				unseen = append(unseen, item.Video)
+			}
+		}
+		if len(unseen) > 0 {
+			s.notifier.NotifyNewVideos(ctx, entities.NewVideosEvent{
+				Type:       entities.EventSubscriptionNewVideos,
+				SourceID:   sub.ID,
+				SourceName: sub.Title,
+				SourcePath: "/feed?channel=" + sub.ID,
+				Videos:     unseen,
+				OccurredAt: now,
+			})
+		}
+	}
+	return len(added), nil
+}
+
+// storedErrorMessage is the message of err that is safe to store and show
+// Raw upstream errors can carry request URLs with the API key, so only the
+// AppError message is kept; anything else gets fallback
+func storedErrorMessage(err error, fallback string) string {
+	var appErr *appErrors.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return fallback
+}
+
+// encodeFeedCursor renders a cursor as an opaque URL-safe string
+func encodeFeedCursor(c entities.FeedCursor) string {
+	raw := c.PublishedAt.Format(time.RFC3339Nano) + "|" + c.VideoID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeFeedCursor parses a cursor produced by encodeFeedCursor
+func decodeFeedCursor(s string) (*entities.FeedCursor, error) {
+	invalid := appErrors.NewValidationError("invalid cursor", nil)
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, invalid
+	}
+
+	publishedAt, videoID, ok := strings.Cut(string(raw), "|")
+	if !ok || validation.ValidateVideoID(videoID) != nil {
+		return nil, invalid
+	}
+
+	c := &entities.FeedCursor{VideoID: videoID}
+	if c.PublishedAt, err = time.Parse(time.RFC3339Nano, publishedAt); err != nil {
+		return nil, invalid
+	}
+	return c, nil
+}
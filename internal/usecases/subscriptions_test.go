@@ -0,0 +1,279 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// discardLogger returns a logger that drops everything
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// MockSubscriptionRepository is a mock implementation of ports.SubscriptionRepository
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSubscriptionRepository) AddSubscription(ctx context.Context, channel entities.Channel, subscribedAt time.Time) (*entities.Subscription, error) {
+	args := m.Called(ctx, channel, subscribedAt)
+	sub, _ := args.Get(0).(*entities.Subscription)
+	return sub, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) RemoveSubscription(ctx context.Context, channelID string) error {
+	args := m.Called(ctx, channelID)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) ListSubscriptions(ctx context.Context) ([]entities.Subscription, error) {
+	args := m.Called(ctx)
+	subs, _ := args.Get(0).([]entities.Subscription)
+	return subs, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) RecordSubscriptionPoll(ctx context.Context, channelID string, polledAt time.Time, pollErr string) error {
+	args := m.Called(ctx, channelID, polledAt, pollErr)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) AddFeedItems(ctx context.Context, items []entities.FeedItem) ([]entities.FeedItem, error) {
+	args := m.Called(ctx, items)
+	added, _ := args.Get(0).([]entities.FeedItem)
+	return added, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) ListFeed(ctx context.Context, filter entities.FeedFilter, after *entities.FeedCursor, limit int) ([]entities.FeedItem, error) {
+	args := m.Called(ctx, filter, after, limit)
+	items, _ := args.Get(0).([]entities.FeedItem)
+	return items, args.Error(1)
+}
+
+func (m *MockSubscriptionRepository) SetFeedItemSeen(ctx context.Context, videoID string, seenAt time.Time) error {
+	args := m.Called(ctx, videoID, seenAt)
+	return args.Error(0)
+}
+
+func (m *MockSubscriptionRepository) MarkFeedSeen(ctx context.Context, filter entities.FeedFilter, seenAt time.Time) (int, error) {
+	args := m.Called(ctx, filter, seenAt)
+	return args.Int(0), args.Error(1)
+}
+
+// MockChannelSource is a mock implementation of ports.ChannelSource
+type MockChannelSource struct {
+	mock.Mock
+}
+
+func (m *MockChannelSource) LookupChannel(ctx context.Context, ref string) (*entities.Channel, error) {
+	args := m.Called(ctx, ref)
+	channel, _ := args.Get(0).(*entities.Channel)
+	return channel, args.Error(1)
+}
+
+func (m *MockChannelSource) RecentUploads(ctx context.Context, channelID string) ([]entities.Video, error) {
+	args := m.Called(ctx, channelID)
+	videos, _ := args.Get(0).([]entities.Video)
+	return videos, args.Error(1)
+}
+
+const testChannelID = "UC_x5XG1OV2P6uZZ5FSM9Ttw"
+
+func testSubscription(subscribedAt time.Time) entities.Subscription {
+	return entities.Subscription{
+		Channel:      entities.Channel{ID: testChannelID, Title: "Google for Developers"},
+		SubscribedAt: subscribedAt,
+	}
+}
+
+func TestSubscriptions_Subscribe(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSubscriptionRepository)
+	mockSource := new(MockChannelSource)
+	channel := &entities.Channel{ID: testChannelID, Title: "Google for Developers"}
+	sub := testSubscription(time.Now())
+
+	mockSource.On("LookupChannel", mock.Anything, testChannelID).Return(channel, nil)
+	mockRepo.On("AddSubscription", mock.Anything, *channel, mock.Anything).Return(&sub, nil)
+	mockSource.On("RecentUploads", mock.Anything, testChannelID).Return(nil, errors.New("feed down"))
+	mockRepo.On("RecordSubscriptionPoll", mock.Anything, testChannelID, mock.Anything, "could not fetch the channel's uploads").Return(nil)
+
+	subs := NewSubscriptions(mockRepo, mockSource, discardLogger())
+
+	// Act
+	got, err := subs.Subscribe(context.Background(), "https://www.youtube.com/channel/"+testChannelID)
+
+	// Assert: a failed first poll is recorded but doesn't fail the subscription
+	require.NoError(t, err)
+	assert.Equal(t, testChannelID, got.ID)
+	mockRepo.AssertExpectations(t)
+	mockSource.AssertExpectations(t)
+}
+
+func TestSubscriptions_SubscribeRejectsBadRef(t *testing.T) {
+	subs := NewSubscriptions(new(MockSubscriptionRepository), new(MockChannelSource), discardLogger())
+
+	_, err := subs.Subscribe(context.Background(), "https://example.com/not-a-channel")
+
+	var appErr *appErrors.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, appErrors.ErrCodeValidation, appErr.Code)
+}
+
+func TestSubscriptions_PollMarksBackCatalogueSeen(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSubscriptionRepository)
+	mockSource := new(MockChannelSource)
+	subscribedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	mockSource.On("RecentUploads", mock.Anything, testChannelID).Return([]entities.Video{
+		{ID: "newUpload01", Title: "New", PublishedAt: subscribedAt.Add(time.Hour)},
+		{ID: "oldUpload01", Title: "Old", PublishedAt: subscribedAt.Add(-time.Hour)},
+		{ID: "bad id", Title: "Skipped"},
+	}, nil)
+
+	var stored []entities.FeedItem
+	mockRepo.On("AddFeedItems", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).([]entities.FeedItem)
+	}).Return(make([]entities.FeedItem, 2), nil)
+	mockRepo.On("RecordSubscriptionPoll", mock.Anything, testChannelID, mock.Anything, "").Return(nil)
+
+	subs := NewSubscriptions(mockRepo, mockSource, discardLogger())
+
+	// Act
+	added, err := subs.Poll(context.Background(), testSubscription(subscribedAt))
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	require.Len(t, stored, 2)
+	assert.False(t, stored[0].Seen(), "uploads after subscribing start unseen")
+	assert.True(t, stored[1].Seen(), "uploads before subscribing start seen")
+	assert.Equal(t, "Google for Developers", stored[0].Channel)
+	assert.Equal(t, testChannelID, stored[0].ChannelID)
+}
+
+func TestSubscriptions_PollNotifiesUnseenUploads(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSubscriptionRepository)
+	mockSource := new(MockChannelSource)
+	mockNotifier := new(MockNewVideosNotifier)
+	subscribedAt := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	fresh := entities.FeedItem{Video: entities.Video{ID: "newUpload01", Title: "New"}}
+	old := entities.FeedItem{Video: entities.Video{ID: "oldUpload01", Title: "Old"}, SeenAt: subscribedAt}
+
+	mockSource.On("RecentUploads", mock.Anything, testChannelID).Return([]entities.Video{
+		{ID: "newUpload01", Title: "New", PublishedAt: subscribedAt.Add(time.Hour)},
+		{ID: "oldUpload01", Title: "Old", PublishedAt: subscribedAt.Add(-time.Hour)},
+	}, nil)
+	mockRepo.On("AddFeedItems", mock.Anything, mock.Anything).Return([]entities.FeedItem{fresh, old}, nil)
+	mockRepo.On("RecordSubscriptionPoll", mock.Anything, testChannelID, mock.Anything, "").Return(nil)
+	mockNotifier.On("NotifyNewVideos", mock.Anything, mock.MatchedBy(func(e entities.NewVideosEvent) bool {
+		return e.Type == entities.EventSubscriptionNewVideos &&
+			e.SourceID == testChannelID &&
+			e.SourcePath == "/feed?channel="+testChannelID &&
+			len(e.Videos) == 1 && e.Videos[0].ID == "newUpload01"
+	})).Return().Once()
+
+	subs := NewSubscriptions(mockRepo, mockSource, discardLogger()).WithNotifier(mockNotifier)
+
+	// Act
+	_, err := subs.Poll(context.Background(), testSubscription(subscribedAt))
+
+	// Assert: only uploads that reach the feed unseen are reported
+	require.NoError(t, err)
+	mockNotifier.AssertExpectations(t)
+}
+
+func TestSubscriptions_FeedPaginates(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSubscriptionRepository)
+	published := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	items := []entities.FeedItem{
+		{Video: entities.Video{ID: "video000001", PublishedAt: published}},
+		{Video: entities.Video{ID: "video000002", PublishedAt: published.Add(-time.Hour)}},
+		{Video: entities.Video{ID: "video000003", PublishedAt: published.Add(-2 * time.Hour)}},
+	}
+	filter := entities.FeedFilter{UnseenOnly: true}
+
+	mockRepo.On("ListFeed", mock.Anything, filter, (*entities.FeedCursor)(nil), 3).Return(items, nil).Once()
+	mockRepo.On("ListFeed", mock.Anything, filter, &entities.FeedCursor{PublishedAt: items[1].PublishedAt, VideoID: "video000002"}, 3).
+		Return(items[2:], nil).Once()
+
+	subs := NewSubscriptions(mockRepo, new(MockChannelSource), discardLogger())
+
+	// Act
+	first, err := subs.Feed(context.Background(), filter, "", 2)
+	require.NoError(t, err)
+	second, err := subs.Feed(context.Background(), filter, first.NextCursor, 2)
+	require.NoError(t, err)
+
+	// Assert
+	assert.Len(t, first.Items, 2)
+	assert.NotEmpty(t, first.NextCursor)
+	assert.Len(t, second.Items, 1)
+	assert.Empty(t, second.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSubscriptions_FeedRejectsBadInput(t *testing.T) {
+	subs := NewSubscriptions(new(MockSubscriptionRepository), new(MockChannelSource), discardLogger())
+
+	tests := []struct {
+		name   string
+		filter entities.FeedFilter
+		cursor string
+		size   int
+	}{
+		{name: "garbage cursor", cursor: "not-a-cursor"},
+		{name: "page too large", size: MaxFeedPageSize + 1},
+		{name: "bad channel", filter: entities.FeedFilter{ChannelID: "nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := subs.Feed(context.Background(), tt.filter, tt.cursor, tt.size)
+
+			var appErr *appErrors.AppError
+			require.True(t, errors.As(err, &appErr))
+			assert.Equal(t, appErrors.ErrCodeValidation, appErr.Code)
+		})
+	}
+}
+
+func TestSubscriptionPoller_PollOnceContinuesPastFailures(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSubscriptionRepository)
+	mockSource := new(MockChannelSource)
+	broken := entities.Subscription{Channel: entities.Channel{ID: "UCbroken________________", Title: "Broken"}}
+	working := testSubscription(time.Now().Add(-time.Hour))
+
+	mockRepo.On("ListSubscriptions", mock.Anything).Return([]entities.Subscription{broken, working}, nil)
+	mockSource.On("RecentUploads", mock.Anything, broken.ID).Return(nil,
+		appErrors.NewServiceUnavailableError("YouTube channel feed", errors.New("GET https://example.com/?key=secret")))
+	mockSource.On("RecentUploads", mock.Anything, working.ID).Return([]entities.Video{
+		{ID: "newUpload01", PublishedAt: time.Now()},
+	}, nil)
+	mockRepo.On("AddFeedItems", mock.Anything, mock.Anything).Return(make([]entities.FeedItem, 1), nil)
+	mockRepo.On("RecordSubscriptionPoll", mock.Anything, broken.ID, mock.Anything, "YouTube channel feed is temporarily unavailable").Return(nil)
+	mockRepo.On("RecordSubscriptionPoll", mock.Anything, working.ID, mock.Anything, "").Return(nil)
+
+	poller := NewSubscriptionPoller(NewSubscriptions(mockRepo, mockSource, discardLogger()), time.Minute, discardLogger())
+
+	// Act
+	result, err := poller.PollOnce(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, PollResult{Channels: 2, NewVideos: 1, Failed: 1}, result)
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,327 @@
+package usecases
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// SyndicationFormat is a feed format saved searches and collections can be followed in
+type SyndicationFormat string
+
+// Supported syndication formats
+const (
+	SyndicationAtom SyndicationFormat = "atom"
+	SyndicationRSS  SyndicationFormat = "rss"
+)
+
+// MaxSyndicatedEntries caps a feed to its most recent entries
+const MaxSyndicatedEntries = 100
+
+// ParseSyndicationFormat validates a feed format name (a file extension such as "atom")
+func ParseSyndicationFormat(s string) (SyndicationFormat, error) {
+	switch f := SyndicationFormat(strings.ToLower(s)); f {
+	case SyndicationAtom, SyndicationRSS:
+		return f, nil
+	default:
+		return "", appErrors.NewValidationError("feed format must be atom or rss", nil)
+	}
+}
+
+// SyndicatedFeed is a saved search or collection ready to be rendered as a feed
+type SyndicatedFeed struct {
+	Title    string
+	Subtitle string
+	PagePath string    // App page the feed mirrors, e.g. /collections/3
+	Updated  time.Time // Latest change to the entries (or the source itself)
+	Entries  []SyndicatedEntry
+}
+
+// SyndicatedEntry is a video in a feed
+type SyndicatedEntry struct {
+	entities.Video
+	Summary string
+	Updated time.Time // When the video appeared in the feed's source
+}
+
+// SyndicatedDocument is a rendered feed
+// ETag is a strong validator of Data; Updated doubles as Last-Modified
+type SyndicatedDocument struct {
+	Format  SyndicationFormat
+	Data    []byte
+	ETag    string
+	Updated time.Time
+}
+
+// Syndication publishes saved search results and collections as Atom and RSS feeds
+// so they can be followed from a feed reader. Feeds only read stored data: they
+// never trigger a search or cost API quota
+type Syndication struct {
+	searches    ports.SavedSearchRepository
+	collections ports.CollectionRepository
+	appName     string
+}
+
+// NewSyndication creates a new Syndication use case
+// appName is used as the feeds' author and generator
+func NewSyndication(searches ports.SavedSearchRepository, collections ports.CollectionRepository, appName string) *Syndication {
+	return &Syndication{searches: searches, collections: collections, appName: appName}
+}
+
+// SavedSearchFeed returns a saved search's results, most recently found first
+func (s *Syndication) SavedSearchFeed(ctx context.Context, id int64) (*SyndicatedFeed, error) {
+	search, err := s.searches.GetSavedSearch(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	results, err := s.searches.ListSavedSearchResults(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &SyndicatedFeed{
+		Title:    search.Name,
+		Subtitle: fmt.Sprintf("New YouTube results for %q", search.Options.Query),
+		PagePath: "/saved-searches/" + strconv.FormatInt(search.ID, 10),
+		Updated:  search.CreatedAt,
+	}
+	for _, r := range results {
+		feed.Entries = append(feed.Entries, SyndicatedEntry{Video: r.Video, Summary: r.Channel, Updated: r.FirstSeenAt})
+	}
+	feed.finish()
+	return feed, nil
+}
+
+// CollectionFeed returns a collection's videos, most recently added first
+func (s *Syndication) CollectionFeed(ctx context.Context, id int64) (*SyndicatedFeed, error) {
+	collection, err := s.collections.GetCollection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	feed := &SyndicatedFeed{
+		Title:    collection.Name,
+		Subtitle: collection.Description,
+		PagePath: "/collections/" + strconv.FormatInt(collection.ID, 10),
+		Updated:  collection.UpdatedAt,
+	}
+	for _, item := range collection.Items {
+		summary := item.Note
+		if summary == "" {
+			summary = item.Channel
+		}
+		feed.Entries = append(feed.Entries, SyndicatedEntry{Video: item.Video, Summary: summary, Updated: item.AddedAt})
+	}
+	feed.finish()
+	return feed, nil
+}
+
+// Render renders a feed in the given format
+// baseURL is the app's public origin (e.g. https://zentube.example.com), which
+// feed readers need because every link in a feed must be absolute; selfPath is
+// the path the feed itself is served from
+func (s *Syndication) Render(feed *SyndicatedFeed, format SyndicationFormat, baseURL, selfPath string) (*SyndicatedDocument, error) {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+
+	var doc any
+	switch format {
+	case SyndicationAtom:
+		doc = s.atomFeed(feed, baseURL, selfPath)
+	case SyndicationRSS:
+		doc = s.rssFeed(feed, baseURL, selfPath)
+	default:
+		return nil, appErrors.NewValidationError("feed format must be atom or rss", nil)
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode feed: %w", err)
+	}
+	data := append([]byte(xml.Header), body...)
+	data = append(data, '\n')
+
+	sum := sha256.Sum256(data)
+	return &SyndicatedDocument{
+		Format:  format,
+		Data:    data,
+		ETag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Updated: feed.Updated,
+	}, nil
+}
+
+// finish orders the entries newest first, keeps the most recent ones and
+// moves Updated forward to the newest entry
+func (f *SyndicatedFeed) finish() {
+	sort.SliceStable(f.Entries, func(i, j int) bool {
+		a, b := f.Entries[i], f.Entries[j]
+		if !a.Updated.Equal(b.Updated) {
+			return a.Updated.After(b.Updated)
+		}
+		return a.ID < b.ID
+	})
+	if len(f.Entries) > MaxSyndicatedEntries {
+		f.Entries = f.Entries[:MaxSyndicatedEntries]
+	}
+	if len(f.Entries) > 0 && f.Entries[0].Updated.After(f.Updated) {
+		f.Updated = f.Entries[0].Updated
+	}
+	f.Updated = f.Updated.UTC().Truncate(time.Second)
+}
+
+// Atom 1.0 (RFC 4287)
+
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	Subtitle  string      `xml:"subtitle,omitempty"`
+	ID        string      `xml:"id"`
+	Links     []atomLink  `xml:"link"`
+	Updated   string      `xml:"updated"`
+	Author    atomPerson  `xml:"author"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+	Href string `xml:"href,attr"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Link      atomLink    `xml:"link"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published,omitempty"`
+	Author    *atomPerson `xml:"author,omitempty"`
+	Summary   string      `xml:"summary,omitempty"`
+}
+
+func (s *Syndication) atomFeed(feed *SyndicatedFeed, baseURL, selfPath string) atomFeed {
+	pageURL := baseURL + feed.PagePath
+	doc := atomFeed{
+		Title:    feed.Title,
+		Subtitle: feed.Subtitle,
+		ID:       pageURL,
+		Links: []atomLink{
+			{Rel: "self", Type: "application/atom+xml", Href: baseURL + selfPath},
+			{Rel: "alternate", Type: "text/html", Href: pageURL},
+		},
+		Updated:   atomTime(feed.Updated),
+		Author:    atomPerson{Name: s.appName},
+		Generator: s.appName,
+		Entries:   make([]atomEntry, 0, len(feed.Entries)),
+	}
+	for _, e := range feed.Entries {
+		entry := atomEntry{
+			Title:   videoTitle(e.Video),
+			ID:      pageURL + "#" + e.ID,
+			Link:    atomLink{Rel: "alternate", Type: "text/html", Href: watchURL(e.ID)},
+			Updated: atomTime(e.Updated),
+			Summary: e.Summary,
+		}
+		if !e.PublishedAt.IsZero() {
+			entry.Published = atomTime(e.PublishedAt)
+		}
+		if e.Channel != "" {
+			entry.Author = &atomPerson{Name: e.Channel}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+	return doc
+}
+
+func atomTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// RSS 2.0, with an atom:link to itself as recommended by the RSS Advisory Board
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	SelfLink      rssSelf   `xml:"http://www.w3.org/2005/Atom link"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Generator     string    `xml:"generator"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssSelf struct {
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func (s *Syndication) rssFeed(feed *SyndicatedFeed, baseURL, selfPath string) rssFeed {
+	pageURL := baseURL + feed.PagePath
+	description := feed.Subtitle
+	if description == "" {
+		description = feed.Title
+	}
+	channel := rssChannel{
+		Title:         feed.Title,
+		Link:          pageURL,
+		Description:   description,
+		SelfLink:      rssSelf{Rel: "self", Type: "application/rss+xml", Href: baseURL + selfPath},
+		LastBuildDate: rssTime(feed.Updated),
+		Generator:     s.appName,
+		Items:         make([]rssItem, 0, len(feed.Entries)),
+	}
+	for _, e := range feed.Entries {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       videoTitle(e.Video),
+			Link:        watchURL(e.ID),
+			GUID:        rssGUID{Value: pageURL + "#" + e.ID},
+			PubDate:     rssTime(e.Updated),
+			Description: e.Summary,
+		})
+	}
+	return rssFeed{Version: "2.0", Channel: channel}
+}
+
+func rssTime(t time.Time) string {
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+// videoTitle falls back to the video ID for videos stored without a title
+func videoTitle(v entities.Video) string {
+	if v.Title == "" {
+		return v.ID
+	}
+	return v.Title
+}
@@ -0,0 +1,145 @@
+package usecases
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+func TestSyndication_SavedSearchAtom(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockSavedSearchRepository)
+	created := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	found := created.Add(48 * time.Hour)
+
+	mockRepo.On("GetSavedSearch", mock.Anything, int64(7)).Return(&entities.SavedSearch{
+		ID: 7, Name: "Go talks", Options: entities.SearchOptions{Query: "golang"}, CreatedAt: created,
+	}, nil)
+	mockRepo.On("ListSavedSearchResults", mock.Anything, int64(7)).Return([]entities.SavedSearchResult{
+		{Video: entities.Video{ID: "oldVideo001", Title: "Old & good", Channel: "Gophers"}, FirstSeenAt: created},
+		{Video: entities.Video{ID: "newVideo001", Title: "New"}, FirstSeenAt: found, New: true},
+	}, nil)
+
+	syndication := NewSyndication(mockRepo, new(MockCollectionRepository), "zentube")
+
+	// Act
+	feed, err := syndication.SavedSearchFeed(context.Background(), 7)
+	require.NoError(t, err)
+	doc, err := syndication.Render(feed, SyndicationAtom, "https://zt.example.com/", "/feeds/search/7.atom")
+	require.NoError(t, err)
+
+	// Assert: newest first, absolute links, escaped text
+	var parsed struct {
+		XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+		ID      string   `xml:"id"`
+		Updated string   `xml:"updated"`
+		Links   []struct {
+			Rel  string `xml:"rel,attr"`
+			Href string `xml:"href,attr"`
+		} `xml:"link"`
+		Entries []struct {
+			Title string `xml:"title"`
+			ID    string `xml:"id"`
+		} `xml:"entry"`
+	}
+	require.NoError(t, xml.Unmarshal(doc.Data, &parsed))
+	assert.Equal(t, "https://zt.example.com/saved-searches/7", parsed.ID)
+	assert.Equal(t, "2024-06-03T00:00:00Z", parsed.Updated)
+	assert.Equal(t, "https://zt.example.com/feeds/search/7.atom", parsed.Links[0].Href)
+	require.Len(t, parsed.Entries, 2)
+	assert.Equal(t, "New", parsed.Entries[0].Title)
+	assert.Equal(t, "Old & good", parsed.Entries[1].Title)
+	assert.Equal(t, "https://zt.example.com/saved-searches/7#newVideo001", parsed.Entries[0].ID)
+	assert.Equal(t, found, doc.Updated)
+	assert.NotEmpty(t, doc.ETag)
+}
+
+func TestSyndication_CollectionRSS(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockCollectionRepository)
+	added := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	collection := &entities.Collection{
+		ID: 2, Name: "Learning path", UpdatedAt: added.Add(-time.Hour),
+		Items: []entities.CollectionItem{
+			{Video: entities.Video{ID: testVideoID, Title: "Intro", Channel: "Gophers"}, Note: "start here", AddedAt: added},
+		},
+	}
+	mockRepo.On("GetCollection", mock.Anything, int64(2)).Return(collection, nil)
+
+	syndication := NewSyndication(new(MockSavedSearchRepository), mockRepo, "zentube")
+
+	// Act
+	feed, err := syndication.CollectionFeed(context.Background(), 2)
+	require.NoError(t, err)
+	doc, err := syndication.Render(feed, SyndicationRSS, "http://localhost:8080", "/feeds/collection/2.rss")
+	require.NoError(t, err)
+	again, err := syndication.Render(feed, SyndicationRSS, "http://localhost:8080", "/feeds/collection/2.rss")
+	require.NoError(t, err)
+
+	// Assert
+	var parsed struct {
+		Version string `xml:"version,attr"`
+		Channel struct {
+			Link          string `xml:"link"`
+			Description   string `xml:"description"`
+			LastBuildDate string `xml:"lastBuildDate"`
+			Items         []struct {
+				Link        string `xml:"link"`
+				PubDate     string `xml:"pubDate"`
+				Description string `xml:"description"`
+			} `xml:"item"`
+		} `xml:"channel"`
+	}
+	require.NoError(t, xml.Unmarshal(doc.Data, &parsed))
+	assert.Equal(t, "2.0", parsed.Version)
+	assert.Equal(t, "Learning path", parsed.Channel.Description, "an empty description falls back to the title")
+	assert.Equal(t, "Sat, 01 Jun 2024 12:00:00 +0000", parsed.Channel.LastBuildDate)
+	require.Len(t, parsed.Channel.Items, 1)
+	assert.Equal(t, "https://www.youtube.com/watch?v="+testVideoID, parsed.Channel.Items[0].Link)
+	assert.Equal(t, "start here", parsed.Channel.Items[0].Description)
+	assert.Equal(t, doc.ETag, again.ETag, "unchanged feeds keep their ETag")
+
+	// A new note changes the feed, and so its ETag
+	collection.Items[0].Note = "start here, then watch part 2"
+	changedFeed, err := syndication.CollectionFeed(context.Background(), 2)
+	require.NoError(t, err)
+	changed, err := syndication.Render(changedFeed, SyndicationRSS, "http://localhost:8080", "/feeds/collection/2.rss")
+	require.NoError(t, err)
+	assert.NotEqual(t, doc.ETag, changed.ETag)
+}
+
+func TestSyndication_FeedCapsEntries(t *testing.T) {
+	mockRepo := new(MockCollectionRepository)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	collection := &entities.Collection{ID: 1, Name: "Big"}
+	for i := 0; i < MaxSyndicatedEntries+5; i++ {
+		collection.Items = append(collection.Items, entities.CollectionItem{
+			Video:   entities.Video{ID: fmt.Sprintf("video%06d", i)},
+			AddedAt: start.Add(time.Duration(i) * time.Minute),
+		})
+	}
+	mockRepo.On("GetCollection", mock.Anything, int64(1)).Return(collection, nil)
+
+	feed, err := NewSyndication(new(MockSavedSearchRepository), mockRepo, "zentube").CollectionFeed(context.Background(), 1)
+
+	require.NoError(t, err)
+	require.Len(t, feed.Entries, MaxSyndicatedEntries)
+	assert.Equal(t, collection.Items[len(collection.Items)-1].ID, feed.Entries[0].ID, "most recently added first")
+}
+
+func TestParseSyndicationFormat(t *testing.T) {
+	format, err := ParseSyndicationFormat("ATOM")
+	require.NoError(t, err)
+	assert.Equal(t, SyndicationAtom, format)
+
+	_, err = ParseSyndicationFormat("json")
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+}
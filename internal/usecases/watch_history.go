@@ -0,0 +1,129 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// Watch history limits
+const (
+	DefaultWatchedLimit = 50
+	MaxWatchedLimit     = 200
+	watchClockAllowance = 5 * time.Second // Player timers may run slightly ahead of the server clock
+)
+
+// WatchHistory records what the player reports as watched
+// A viewing is opened, then updated with progress until it is closed;
+// the playback position reported along the way is kept per video
+// Viewings belong to the profile in the request context
+type WatchHistory struct {
+	repo     ports.WatchEventRepository
+	progress ports.VideoProgressRepository
+}
+
+// PlaybackReport is what the player sends with progress and close events
+type PlaybackReport struct {
+	WatchedSeconds int // Total for the viewing so far
+	Position       int // Seconds into the video
+	Duration       int // Length of the video in seconds, 0 if the player didn't report a position
+}
+
+// NewWatchHistory creates a new WatchHistory use case
+func NewWatchHistory(repo ports.WatchEventRepository, progress ports.VideoProgressRepository) *WatchHistory {
+	return &WatchHistory{repo: repo, progress: progress}
+}
+
+// Open starts a viewing of a video in the player
+func (w *WatchHistory) Open(ctx context.Context, videoID, title string) (*entities.WatchEvent, error) {
+	if err := validation.ValidateVideoID(videoID); err != nil {
+		return nil, err
+	}
+
+	// The title is whatever the page sent, so it gets the same bounds as other client metadata
+	title = strings.TrimSpace(title)
+	if err := validation.ValidateVideoMetadata(title, ""); err != nil {
+		return nil, err
+	}
+
+	event := &entities.WatchEvent{
+		Profile:   entities.ProfileFromContext(ctx),
+		VideoID:   videoID,
+		Title:     title,
+		StartedAt: time.Now(),
+	}
+	if err := w.repo.AddWatchEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Progress records how long an open viewing has been watched so far
+func (w *WatchHistory) Progress(ctx context.Context, id int64, report PlaybackReport) error {
+	return w.update(ctx, id, report, false)
+}
+
+// Close ends a viewing with its final watched time
+func (w *WatchHistory) Close(ctx context.Context, id int64, report PlaybackReport) error {
+	return w.update(ctx, id, report, true)
+}
+
+// Positions returns where playback of the given videos last stopped, keyed by video ID
+// Videos never played are left out
+func (w *WatchHistory) Positions(ctx context.Context, videoIDs []string) (map[string]entities.VideoProgress, error) {
+	return w.progress.GetVideoProgress(ctx, videoIDs)
+}
+
+// List returns up to limit viewings, most recent first (0 means DefaultWatchedLimit)
+func (w *WatchHistory) List(ctx context.Context, limit int) ([]entities.WatchEvent, error) {
+	limit, err := validation.ValidatePageSize(limit, DefaultWatchedLimit, MaxWatchedLimit)
+	if err != nil {
+		return nil, err
+	}
+	return w.repo.ListWatchEvents(ctx, entities.ProfileFromContext(ctx), limit)
+}
+
+// update stores progress, capping watched time at the time since the viewing started
+// so a misbehaving client cannot report more than could have been watched
+func (w *WatchHistory) update(ctx context.Context, id int64, report PlaybackReport, closed bool) error {
+	if report.WatchedSeconds < 0 {
+		return appErrors.NewValidationError("watched seconds cannot be negative", nil)
+	}
+	if report.Duration < 0 || report.Position < 0 || report.Position > report.Duration {
+		return appErrors.NewValidationError("position must be between 0 and the video duration", nil)
+	}
+
+	event, err := w.repo.GetWatchEvent(ctx, id)
+	if err != nil {
+		return err
+	}
+	// Another profile's viewing is reported as missing rather than updated
+	if event.Profile != entities.ProfileFromContext(ctx) {
+		return appErrors.NewNotFoundError("Watch event")
+	}
+
+	now := time.Now()
+	elapsed := int((now.Sub(event.StartedAt) + watchClockAllowance) / time.Second)
+	if err := w.repo.UpdateWatchEvent(ctx, id, entities.WatchProgress{
+		At:             now,
+		WatchedSeconds: min(report.WatchedSeconds, max(elapsed, 0)),
+		Closed:         closed,
+	}); err != nil {
+		return err
+	}
+
+	if report.Duration == 0 {
+		return nil
+	}
+	return w.progress.SaveVideoProgress(ctx, entities.VideoProgress{
+		VideoID:   event.VideoID,
+		Position:  report.Position,
+		Duration:  report.Duration,
+		UpdatedAt: now,
+	})
+}
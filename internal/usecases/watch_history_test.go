@@ -0,0 +1,236 @@
+package usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/validation"
+)
+
+// MockWatchEventRepository is a mock implementation of ports.WatchEventRepository
+type MockWatchEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockWatchEventRepository) AddWatchEvent(ctx context.Context, event *entities.WatchEvent) error {
+	args := m.Called(ctx, event)
+	return args.Error(0)
+}
+
+func (m *MockWatchEventRepository) GetWatchEvent(ctx context.Context, id int64) (*entities.WatchEvent, error) {
+	args := m.Called(ctx, id)
+	event, _ := args.Get(0).(*entities.WatchEvent)
+	return event, args.Error(1)
+}
+
+func (m *MockWatchEventRepository) UpdateWatchEvent(ctx context.Context, id int64, progress entities.WatchProgress) error {
+	args := m.Called(ctx, id, progress)
+	return args.Error(0)
+}
+
+func (m *MockWatchEventRepository) ListWatchEvents(ctx context.Context, profile string, limit int) ([]entities.WatchEvent, error) {
+	args := m.Called(ctx, profile, limit)
+	events, _ := args.Get(0).([]entities.WatchEvent)
+	return events, args.Error(1)
+}
+
+func (m *MockWatchEventRepository) WatchedSecondsSince(ctx context.Context, profile string, since time.Time) (int, error) {
+	args := m.Called(ctx, profile, since)
+	return args.Int(0), args.Error(1)
+}
+
+// MockVideoProgressRepository is a mock implementation of ports.VideoProgressRepository
+type MockVideoProgressRepository struct {
+	mock.Mock
+}
+
+func (m *MockVideoProgressRepository) SaveVideoProgress(ctx context.Context, progress entities.VideoProgress) error {
+	args := m.Called(ctx, progress)
+	return args.Error(0)
+}
+
+func (m *MockVideoProgressRepository) GetVideoProgress(ctx context.Context, videoIDs []string) (map[string]entities.VideoProgress, error) {
+	args := m.Called(ctx, videoIDs)
+	progress, _ := args.Get(0).(map[string]entities.VideoProgress)
+	return progress, args.Error(1)
+}
+
+func TestWatchHistory_Open(t *testing.T) {
+	// Arrange
+	repo := new(MockWatchEventRepository)
+	repo.On("AddWatchEvent", mock.Anything, mock.MatchedBy(func(e *entities.WatchEvent) bool {
+		return e.Profile == "kids" && e.VideoID == testVideoID && e.Title == "Go in 100 seconds" && !e.StartedAt.IsZero()
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*entities.WatchEvent).ID = 7
+	}).Return(nil)
+
+	uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+	// Act
+	event, err := uc.Open(entities.WithProfile(context.Background(), "kids"), testVideoID, "  Go in 100 seconds ")
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), event.ID)
+	repo.AssertExpectations(t)
+}
+
+func TestWatchHistory_OpenValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		videoID string
+		title   string
+	}{
+		{"invalid video ID", "not a video", "Title"},
+		{"title too long", testVideoID, strings.Repeat("a", validation.MaxVideoTitleLength+1)},
+		{"control characters in title", testVideoID, "Go\x00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWatchEventRepository)
+			uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+			_, err := uc.Open(context.Background(), tt.videoID, tt.title)
+
+			assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+			repo.AssertNotCalled(t, "AddWatchEvent", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestWatchHistory_Progress(t *testing.T) {
+	// Arrange
+	repo := new(MockWatchEventRepository)
+	repo.On("GetWatchEvent", mock.Anything, int64(7)).
+		Return(&entities.WatchEvent{ID: 7, Profile: entities.DefaultProfile, VideoID: testVideoID, StartedAt: time.Now().Add(-time.Minute)}, nil)
+	repo.On("UpdateWatchEvent", mock.Anything, int64(7), mock.MatchedBy(func(p entities.WatchProgress) bool {
+		return p.WatchedSeconds == 42 && !p.Closed && !p.At.IsZero()
+	})).Return(nil)
+	progress := new(MockVideoProgressRepository)
+	progress.On("SaveVideoProgress", mock.Anything, mock.MatchedBy(func(p entities.VideoProgress) bool {
+		return p.VideoID == testVideoID && p.Position == 1250 && p.Duration == 3600 && !p.UpdatedAt.IsZero()
+	})).Return(nil)
+
+	uc := NewWatchHistory(repo, progress)
+
+	// Act
+	err := uc.Progress(context.Background(), 7, PlaybackReport{WatchedSeconds: 42, Position: 1250, Duration: 3600})
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+	progress.AssertExpectations(t)
+}
+
+func TestWatchHistory_ProgressWithoutPosition(t *testing.T) {
+	// Arrange - the embed never reported its position, so only watched time is kept
+	repo := new(MockWatchEventRepository)
+	repo.On("GetWatchEvent", mock.Anything, int64(7)).
+		Return(&entities.WatchEvent{ID: 7, Profile: entities.DefaultProfile, VideoID: testVideoID, StartedAt: time.Now().Add(-time.Minute)}, nil)
+	repo.On("UpdateWatchEvent", mock.Anything, int64(7), mock.Anything).Return(nil)
+	progress := new(MockVideoProgressRepository)
+
+	uc := NewWatchHistory(repo, progress)
+
+	// Act
+	err := uc.Close(context.Background(), 7, PlaybackReport{WatchedSeconds: 42})
+
+	// Assert
+	require.NoError(t, err)
+	progress.AssertNotCalled(t, "SaveVideoProgress", mock.Anything, mock.Anything)
+}
+
+func TestWatchHistory_CloseCapsWatchedTime(t *testing.T) {
+	// Arrange - a viewing opened a minute ago cannot have been watched for an hour
+	repo := new(MockWatchEventRepository)
+	repo.On("GetWatchEvent", mock.Anything, int64(7)).
+		Return(&entities.WatchEvent{ID: 7, Profile: entities.DefaultProfile, StartedAt: time.Now().Add(-time.Minute)}, nil)
+	repo.On("UpdateWatchEvent", mock.Anything, int64(7), mock.MatchedBy(func(p entities.WatchProgress) bool {
+		return p.Closed && p.WatchedSeconds >= 60 && p.WatchedSeconds <= 60+int(watchClockAllowance/time.Second)
+	})).Return(nil)
+
+	uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+	// Act
+	err := uc.Close(context.Background(), 7, PlaybackReport{WatchedSeconds: 3600})
+
+	// Assert
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestWatchHistory_ProgressErrors(t *testing.T) {
+	invalid := []struct {
+		name   string
+		report PlaybackReport
+	}{
+		{"negative watched time", PlaybackReport{WatchedSeconds: -1}},
+		{"position past the end", PlaybackReport{Position: 61, Duration: 60}},
+		{"position without duration", PlaybackReport{Position: 30}},
+		{"negative position", PlaybackReport{Position: -1, Duration: 60}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockWatchEventRepository)
+			uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+			err := uc.Progress(context.Background(), 7, tt.report)
+
+			assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err))
+			repo.AssertNotCalled(t, "GetWatchEvent", mock.Anything, mock.Anything)
+		})
+	}
+
+	t.Run("unknown viewing", func(t *testing.T) {
+		repo := new(MockWatchEventRepository)
+		repo.On("GetWatchEvent", mock.Anything, int64(7)).Return(nil, appErrors.NewNotFoundError("Watch event"))
+		uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+		err := uc.Close(context.Background(), 7, PlaybackReport{WatchedSeconds: 10})
+
+		assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+		repo.AssertNotCalled(t, "UpdateWatchEvent", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestWatchHistory_OtherProfilesViewing(t *testing.T) {
+	// Arrange - a viewing can only be updated from the profile that opened it
+	repo := new(MockWatchEventRepository)
+	repo.On("GetWatchEvent", mock.Anything, int64(7)).
+		Return(&entities.WatchEvent{ID: 7, Profile: "kids", StartedAt: time.Now().Add(-time.Minute)}, nil)
+	uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+	// Act
+	err := uc.Close(context.Background(), 7, PlaybackReport{WatchedSeconds: 10})
+
+	// Assert
+	assert.Equal(t, appErrors.ErrCodeNotFound, appErrors.GetErrorCode(err))
+	repo.AssertNotCalled(t, "UpdateWatchEvent", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestWatchHistory_List(t *testing.T) {
+	// Arrange
+	repo := new(MockWatchEventRepository)
+	repo.On("ListWatchEvents", mock.Anything, entities.DefaultProfile, DefaultWatchedLimit).
+		Return([]entities.WatchEvent{{ID: 1, VideoID: testVideoID}}, nil)
+
+	uc := NewWatchHistory(repo, new(MockVideoProgressRepository))
+
+	// Act
+	events, err := uc.List(context.Background(), 0)
+	_, tooMany := uc.List(context.Background(), MaxWatchedLimit+1)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+	assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(tooMany))
+	repo.AssertExpectations(t)
+}
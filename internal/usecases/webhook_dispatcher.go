@@ -0,0 +1,99 @@
+package usecases
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WebhookDispatcherConfig controls how queued webhook deliveries are sent
+type WebhookDispatcherConfig struct {
+	Interval  time.Duration // How often to look for deliveries that are due
+	BatchSize int           // Max deliveries attempted per run
+	Keep      time.Duration // Finished deliveries older than this are pruned (0 = keep forever)
+}
+
+// WebhookDispatcher sends queued webhook deliveries in the background
+type WebhookDispatcher struct {
+	webhooks *Webhooks
+	cfg      WebhookDispatcherConfig
+	logger   *slog.Logger
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(webhooks *Webhooks, cfg WebhookDispatcherConfig, logger *slog.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		webhooks: webhooks,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+// Run sends due deliveries immediately and then on every interval
+// Blocks until ctx is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	d.logger.Info("webhook dispatcher started",
+		slog.Duration("interval", d.cfg.Interval),
+		slog.Int("batch_size", d.cfg.BatchSize),
+		slog.Duration("keep", d.cfg.Keep),
+	)
+
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.runOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			d.logger.Info("webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runOnce runs a single delivery pass and logs the outcome
+// Quiet passes are not logged, since most runs find nothing to send
+func (d *WebhookDispatcher) runOnce(ctx context.Context) {
+	start := time.Now()
+
+	result, err := d.DeliverOnce(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		d.logger.Error("webhook delivery failed", slog.Any("error", err))
+		return
+	}
+	if result.Attempted == 0 {
+		return
+	}
+
+	d.logger.Info("webhook delivery completed",
+		slog.Int("attempted", result.Attempted),
+		slog.Int("delivered", result.Delivered),
+		slog.Int("retrying", result.Retrying),
+		slog.Int("failed", result.Failed),
+		slog.Duration("duration", time.Since(start)),
+	)
+}
+
+// DeliverOnce sends the deliveries that are due, then prunes the log
+func (d *WebhookDispatcher) DeliverOnce(ctx context.Context) (DeliveryResult, error) {
+	result, err := d.webhooks.DeliverDue(ctx, d.cfg.BatchSize)
+	if err != nil {
+		return result, err
+	}
+
+	if d.cfg.Keep > 0 {
+		pruned, err := d.webhooks.Prune(ctx, d.cfg.Keep)
+		if err != nil {
+			return result, err
+		}
+		if pruned > 0 {
+			d.logger.Info("webhook deliveries pruned", slog.Int("pruned", pruned))
+		}
+	}
+	return result, nil
+}
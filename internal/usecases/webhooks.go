@@ -0,0 +1,376 @@
+package usecases
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+	"github.com/uiansol/zentube/internal/ports"
+)
+
+// NewVideosNotifier is told when a saved search or followed channel finds new videos
+type NewVideosNotifier interface {
+	NotifyNewVideos(ctx context.Context, event entities.NewVideosEvent)
+}
+
+// WebhookFormat is the payload format of a webhook endpoint
+type WebhookFormat string
+
+// Supported webhook formats
+const (
+	WebhookFormatJSON  WebhookFormat = "json"  // The event as JSON (see webhookPayload)
+	WebhookFormatSlack WebhookFormat = "slack" // {"text": ...} for Slack and Mattermost incoming webhooks
+)
+
+// Webhook request headers
+// The signature is "sha256=" followed by the hex HMAC-SHA256 of the body,
+// keyed with the endpoint's secret; it is only sent when a secret is set
+const (
+	WebhookSignatureHeader = "X-Zentube-Signature-256"
+	WebhookEventHeader     = "X-Zentube-Event"
+	WebhookDeliveryHeader  = "X-Zentube-Delivery"
+)
+
+// Webhook delivery log sizes
+const (
+	DefaultWebhookLogSize = 50
+	MaxWebhookLogSize     = 500
+)
+
+// maxSlackVideos caps how many videos a Slack message lists
+const maxSlackVideos = 10
+
+// WebhookEndpoint is a configured webhook receiver
+type WebhookEndpoint struct {
+	Name   string
+	URL    string
+	Secret string // Signs payloads when set
+	Format WebhookFormat
+	Events []string // Event types to send; empty for every event
+}
+
+// wants reports whether the endpoint is subscribed to an event type
+func (e WebhookEndpoint) wants(event string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, event)
+}
+
+// WebhookRetryPolicy controls how failed deliveries are retried
+type WebhookRetryPolicy struct {
+	MaxAttempts int           // Attempts before a delivery is given up as failed
+	Backoff     time.Duration // Wait before the first retry, doubled after each failure
+	MaxBackoff  time.Duration // Cap on the wait between attempts
+}
+
+// DeliveryResult summarizes a single pass over the due webhook deliveries
+type DeliveryResult struct {
+	Attempted int
+	Delivered int
+	Retrying  int
+	Failed    int
+}
+
+// Webhooks POSTs new-video events to the configured endpoints
+// Events are queued in the delivery log first and sent by the dispatcher, so
+// a slow or broken receiver never holds up a poll or refresh
+type Webhooks struct {
+	repo      ports.WebhookDeliveryRepository
+	sender    ports.WebhookSender
+	endpoints []WebhookEndpoint
+	policy    WebhookRetryPolicy
+	baseURL   string
+	logger    *slog.Logger
+}
+
+// NewWebhooks creates a new Webhooks use case
+// baseURL is the app's public origin, used for links back to the app; when
+// empty, payloads carry no such links
+func NewWebhooks(repo ports.WebhookDeliveryRepository, sender ports.WebhookSender, endpoints []WebhookEndpoint, policy WebhookRetryPolicy, baseURL string, logger *slog.Logger) *Webhooks {
+	return &Webhooks{
+		repo:      repo,
+		sender:    sender,
+		endpoints: endpoints,
+		policy:    policy,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+		logger:    logger,
+	}
+}
+
+// NotifyNewVideos queues an event for every endpoint that wants it
+// Failures are logged rather than returned: a webhook problem must not fail
+// the poll or refresh that found the videos
+func (w *Webhooks) NotifyNewVideos(ctx context.Context, event entities.NewVideosEvent) {
+	if len(event.Videos) == 0 {
+		return
+	}
+
+	var deliveries []*entities.WebhookDelivery
+	for _, endpoint := range w.endpoints {
+		if !endpoint.wants(event.Type) {
+			continue
+		}
+		payload, err := w.payload(endpoint.Format, event)
+		if err != nil {
+			w.logger.Error("failed to build webhook payload",
+				slog.String("endpoint", endpoint.Name),
+				slog.String("event", event.Type),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		deliveries = append(deliveries, &entities.WebhookDelivery{
+			Endpoint:      endpoint.Name,
+			Event:         event.Type,
+			Payload:       payload,
+			NextAttemptAt: event.OccurredAt,
+			CreatedAt:     event.OccurredAt,
+		})
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	if err := w.repo.EnqueueWebhookDeliveries(ctx, deliveries); err != nil {
+		w.logger.Error("failed to queue webhook deliveries",
+			slog.String("event", event.Type),
+			slog.String("source_id", event.SourceID),
+			slog.Any("error", err),
+		)
+	}
+}
+
+// DeliverDue sends up to limit deliveries that are due
+// Failed attempts are retried with exponential backoff until the policy's
+// attempts run out
+func (w *Webhooks) DeliverDue(ctx context.Context, limit int) (DeliveryResult, error) {
+	var result DeliveryResult
+
+	due, err := w.repo.DueWebhookDeliveries(ctx, time.Now(), limit)
+	if err != nil {
+		return result, fmt.Errorf("failed to load due webhook deliveries: %w", err)
+	}
+
+	for _, d := range due {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		attempt := w.deliver(ctx, d)
+		if err := w.repo.RecordWebhookAttempt(ctx, d.ID, attempt); err != nil {
+			return result, fmt.Errorf("failed to record webhook attempt: %w", err)
+		}
+
+		result.Attempted++
+		switch attempt.Status {
+		case entities.WebhookDelivered:
+			result.Delivered++
+		case entities.WebhookPending:
+			result.Retrying++
+		default:
+			result.Failed++
+		}
+	}
+
+	return result, nil
+}
+
+// Deliveries returns the delivery log, most recent first (limit 0 uses the default)
+func (w *Webhooks) Deliveries(ctx context.Context, limit int) ([]entities.WebhookDelivery, error) {
+	if limit == 0 {
+		limit = DefaultWebhookLogSize
+	}
+	if limit < 1 || limit > MaxWebhookLogSize {
+		return nil, appErrors.NewValidationError(
+			fmt.Sprintf("limit must be between 1 and %d", MaxWebhookLogSize), nil)
+	}
+	return w.repo.ListWebhookDeliveries(ctx, limit)
+}
+
+// Retry queues a delivery again right away, with a fresh set of attempts
+func (w *Webhooks) Retry(ctx context.Context, id int64) error {
+	return w.repo.RetryWebhookDelivery(ctx, id, time.Now())
+}
+
+// Prune removes finished deliveries older than keep from the log
+func (w *Webhooks) Prune(ctx context.Context, keep time.Duration) (int, error) {
+	return w.repo.PruneWebhookDeliveries(ctx, time.Now().Add(-keep))
+}
+
+// deliver makes one attempt at a delivery and returns its outcome
+func (w *Webhooks) deliver(ctx context.Context, d entities.WebhookDelivery) entities.WebhookAttempt {
+	now := time.Now()
+
+	i := slices.IndexFunc(w.endpoints, func(e WebhookEndpoint) bool { return e.Name == d.Endpoint })
+	if i < 0 {
+		return entities.WebhookAttempt{AttemptedAt: now, Status: entities.WebhookFailed, Error: "endpoint is no longer configured"}
+	}
+	endpoint := w.endpoints[i]
+
+	headers := map[string]string{
+		WebhookEventHeader:    d.Event,
+		WebhookDeliveryHeader: strconv.FormatInt(d.ID, 10),
+	}
+	if endpoint.Secret != "" {
+		headers[WebhookSignatureHeader] = SignWebhookPayload(endpoint.Secret, d.Payload)
+	}
+
+	status, err := w.sender.SendWebhook(ctx, endpoint.URL, d.Payload, headers)
+	if err == nil {
+		return entities.WebhookAttempt{AttemptedAt: now, Status: entities.WebhookDelivered}
+	}
+
+	// The URL may embed a token, so only the status is stored
+	message := "could not reach the receiver"
+	if status != 0 {
+		message = fmt.Sprintf("receiver responded with HTTP %d", status)
+	}
+	w.logger.Warn("webhook delivery failed",
+		slog.Int64("delivery_id", d.ID),
+		slog.String("endpoint", d.Endpoint),
+		slog.Int("attempt", d.Attempts+1),
+		slog.Any("error", err),
+	)
+
+	attempts := d.Attempts + 1
+	if attempts >= w.policy.MaxAttempts {
+		return entities.WebhookAttempt{AttemptedAt: now, Status: entities.WebhookFailed, Error: message}
+	}
+	return entities.WebhookAttempt{
+		AttemptedAt:   now,
+		Status:        entities.WebhookPending,
+		NextAttemptAt: now.Add(w.backoff(attempts)),
+		Error:         message,
+	}
+}
+
+// backoff returns the wait after the given number of failed attempts:
+// Backoff, then doubling up to MaxBackoff
+func (w *Webhooks) backoff(failures int) time.Duration {
+	wait := w.policy.Backoff
+	for i := 1; i < failures && wait < w.policy.MaxBackoff; i++ {
+		wait *= 2
+	}
+	return min(wait, w.policy.MaxBackoff)
+}
+
+// SignWebhookPayload returns the signature header value for a payload:
+// "sha256=" followed by the hex HMAC-SHA256 of body keyed with secret
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookPayload is the JSON webhook format
+type webhookPayload struct {
+	Event      string         `json:"event"`
+	OccurredAt time.Time      `json:"occurred_at"`
+	Source     webhookSource  `json:"source"`
+	Videos     []webhookVideo `json:"videos"`
+}
+
+type webhookSource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+type webhookVideo struct {
+	ID          string     `json:"id"`
+	Title       string     `json:"title"`
+	Channel     string     `json:"channel,omitempty"`
+	URL         string     `json:"url"`
+	Thumbnail   string     `json:"thumbnail,omitempty"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+}
+
+// slackPayload is the incoming webhook format shared by Slack and Mattermost
+type slackPayload struct {
+	Text string `json:"text"`
+}
+
+// payload renders an event in an endpoint's format
+func (w *Webhooks) payload(format WebhookFormat, event entities.NewVideosEvent) ([]byte, error) {
+	sourceURL := ""
+	if w.baseURL != "" && event.SourcePath != "" {
+		sourceURL = w.baseURL + event.SourcePath
+	}
+
+	switch format {
+	case WebhookFormatSlack:
+		return json.Marshal(slackPayload{Text: slackText(event, sourceURL)})
+	case WebhookFormatJSON, "":
+		p := webhookPayload{
+			Event:      event.Type,
+			OccurredAt: event.OccurredAt.UTC(),
+			Source:     webhookSource{ID: event.SourceID, Name: event.SourceName, URL: sourceURL},
+			Videos:     make([]webhookVideo, 0, len(event.Videos)),
+		}
+		for _, v := range event.Videos {
+			video := webhookVideo{ID: v.ID, Title: v.Title, Channel: v.Channel, URL: watchURL(v.ID), Thumbnail: v.Thumbnail}
+			if !v.PublishedAt.IsZero() {
+				published := v.PublishedAt.UTC()
+				video.PublishedAt = &published
+			}
+			p.Videos = append(p.Videos, video)
+		}
+		return json.Marshal(p)
+	default:
+		return nil, fmt.Errorf("unknown webhook format %q", format)
+	}
+}
+
+// slackText renders an event as a Slack/Mattermost message
+func slackText(event entities.NewVideosEvent, sourceURL string) string {
+	source := "*" + slackEscape(event.SourceName) + "*"
+	if sourceURL != "" {
+		source = slackLink(sourceURL, event.SourceName)
+	}
+
+	var b strings.Builder
+	count := pluralVideos(len(event.Videos))
+	if event.Type == entities.EventSavedSearchNewVideos {
+		fmt.Fprintf(&b, "%s for saved search %s", count, source)
+	} else {
+		fmt.Fprintf(&b, "%s from %s", count, source)
+	}
+
+	for i, v := range event.Videos {
+		if i == maxSlackVideos {
+			fmt.Fprintf(&b, "\n…and %d more", len(event.Videos)-maxSlackVideos)
+			break
+		}
+		b.WriteString("\n• " + slackLink(watchURL(v.ID), videoTitle(v)))
+		if v.Channel != "" && event.Type == entities.EventSavedSearchNewVideos {
+			b.WriteString(" — " + slackEscape(v.Channel))
+		}
+	}
+	return b.String()
+}
+
+// pluralVideos returns "1 new video" or "n new videos"
+func pluralVideos(n int) string {
+	if n == 1 {
+		return "1 new video"
+	}
+	return fmt.Sprintf("%d new videos", n)
+}
+
+// slackLink renders a link; "|" would end the URL, so it is dropped from the label
+func slackLink(url, label string) string {
+	return "<" + url + "|" + slackEscape(strings.ReplaceAll(label, "|", "")) + ">"
+}
+
+// slackEscape escapes the characters Slack and Mattermost treat as markup
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
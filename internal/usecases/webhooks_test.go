@@ -0,0 +1,242 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+	appErrors "github.com/uiansol/zentube/internal/errors"
+)
+
+// MockWebhookDeliveryRepository is a mock implementation of ports.WebhookDeliveryRepository
+type MockWebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepository) EnqueueWebhookDeliveries(ctx context.Context, deliveries []*entities.WebhookDelivery) error {
+	args := m.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) DueWebhookDeliveries(ctx context.Context, now time.Time, limit int) ([]entities.WebhookDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	deliveries, _ := args.Get(0).([]entities.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) RecordWebhookAttempt(ctx context.Context, id int64, attempt entities.WebhookAttempt) error {
+	args := m.Called(ctx, id, attempt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) ListWebhookDeliveries(ctx context.Context, limit int) ([]entities.WebhookDelivery, error) {
+	args := m.Called(ctx, limit)
+	deliveries, _ := args.Get(0).([]entities.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func (m *MockWebhookDeliveryRepository) RetryWebhookDelivery(ctx context.Context, id int64, dueAt time.Time) error {
+	args := m.Called(ctx, id, dueAt)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) PruneWebhookDeliveries(ctx context.Context, cutoff time.Time) (int, error) {
+	args := m.Called(ctx, cutoff)
+	return args.Int(0), args.Error(1)
+}
+
+// MockWebhookSender is a mock implementation of ports.WebhookSender
+type MockWebhookSender struct {
+	mock.Mock
+}
+
+func (m *MockWebhookSender) SendWebhook(ctx context.Context, url string, body []byte, headers map[string]string) (int, error) {
+	args := m.Called(ctx, url, body, headers)
+	return args.Int(0), args.Error(1)
+}
+
+// MockNewVideosNotifier is a mock implementation of NewVideosNotifier
+type MockNewVideosNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNewVideosNotifier) NotifyNewVideos(ctx context.Context, event entities.NewVideosEvent) {
+	m.Called(ctx, event)
+}
+
+var testRetryPolicy = WebhookRetryPolicy{MaxAttempts: 3, Backoff: time.Minute, MaxBackoff: 90 * time.Second}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// Well-known vector: HMAC-SHA256("key", "The quick brown fox jumps over the lazy dog")
+	got := SignWebhookPayload("key", []byte("The quick brown fox jumps over the lazy dog"))
+
+	assert.Equal(t, "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8", got)
+}
+
+func TestWebhooks_NotifyQueuesForSubscribedEndpoints(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockWebhookDeliveryRepository)
+	occurred := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	event := entities.NewVideosEvent{
+		Type:       entities.EventSavedSearchNewVideos,
+		SourceID:   "3",
+		SourceName: "kubecon <storage>",
+		SourcePath: "/saved-searches/3",
+		Videos:     []entities.Video{{ID: testVideoID, Title: "Storage & you", Channel: "CNCF"}},
+		OccurredAt: occurred,
+	}
+
+	var queued []*entities.WebhookDelivery
+	mockRepo.On("EnqueueWebhookDeliveries", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]*entities.WebhookDelivery)
+	}).Return(nil)
+
+	webhooks := NewWebhooks(mockRepo, new(MockWebhookSender), []WebhookEndpoint{
+		{Name: "ci", URL: "https://ci.example.com/hook", Format: WebhookFormatJSON},
+		{Name: "chat", URL: "https://chat.example.com/hook", Format: WebhookFormatSlack},
+		{Name: "feeds-only", URL: "https://feeds.example.com/hook", Events: []string{entities.EventSubscriptionNewVideos}},
+	}, testRetryPolicy, "https://tube.example.com/", discardLogger())
+
+	// Act
+	webhooks.NotifyNewVideos(context.Background(), event)
+
+	// Assert: one delivery per endpoint that wants the event, due right away
+	require.Len(t, queued, 2)
+	assert.Equal(t, "ci", queued[0].Endpoint)
+	assert.Equal(t, "chat", queued[1].Endpoint)
+	assert.True(t, occurred.Equal(queued[0].NextAttemptAt))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(queued[0].Payload, &payload))
+	assert.Equal(t, entities.EventSavedSearchNewVideos, payload["event"])
+	assert.Equal(t, map[string]any{"id": "3", "name": "kubecon <storage>", "url": "https://tube.example.com/saved-searches/3"}, payload["source"])
+	videos := payload["videos"].([]any)
+	require.Len(t, videos, 1)
+	assert.Equal(t, "https://www.youtube.com/watch?v="+testVideoID, videos[0].(map[string]any)["url"])
+
+	var slack slackPayload
+	require.NoError(t, json.Unmarshal(queued[1].Payload, &slack))
+	assert.Equal(t,
+		"1 new video for saved search <https://tube.example.com/saved-searches/3|kubecon &lt;storage&gt;>\n"+
+			"• <https://www.youtube.com/watch?v="+testVideoID+"|Storage &amp; you> — CNCF",
+		slack.Text)
+}
+
+func TestWebhooks_NotifyIgnoresEmptyEvents(t *testing.T) {
+	mockRepo := new(MockWebhookDeliveryRepository)
+	webhooks := NewWebhooks(mockRepo, new(MockWebhookSender), []WebhookEndpoint{{Name: "ci"}}, testRetryPolicy, "", discardLogger())
+
+	webhooks.NotifyNewVideos(context.Background(), entities.NewVideosEvent{Type: entities.EventSubscriptionNewVideos})
+
+	mockRepo.AssertNotCalled(t, "EnqueueWebhookDeliveries")
+}
+
+func TestWebhooks_DeliverDueSignsAndRetries(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockWebhookDeliveryRepository)
+	mockSender := new(MockWebhookSender)
+	body := []byte(`{"event":"subscription.new_videos"}`)
+	due := []entities.WebhookDelivery{
+		{ID: 1, Endpoint: "ci", Event: entities.EventSubscriptionNewVideos, Payload: body},
+		{ID: 2, Endpoint: "ci", Event: entities.EventSubscriptionNewVideos, Payload: body, Attempts: 1},
+		{ID: 3, Endpoint: "ci", Event: entities.EventSubscriptionNewVideos, Payload: body, Attempts: 2},
+		{ID: 4, Endpoint: "removed", Event: entities.EventSubscriptionNewVideos, Payload: body},
+	}
+	mockRepo.On("DueWebhookDeliveries", mock.Anything, mock.Anything, 10).Return(due, nil)
+
+	signed := func(id string) map[string]string {
+		return map[string]string{
+			WebhookEventHeader:     entities.EventSubscriptionNewVideos,
+			WebhookDeliveryHeader:  id,
+			WebhookSignatureHeader: SignWebhookPayload("s3cret", body),
+		}
+	}
+	mockSender.On("SendWebhook", mock.Anything, "https://ci.example.com/hook", body, signed("1")).Return(204, nil)
+	mockSender.On("SendWebhook", mock.Anything, "https://ci.example.com/hook", body, signed("2")).Return(503, errors.New("unexpected status 503"))
+	mockSender.On("SendWebhook", mock.Anything, "https://ci.example.com/hook", body, signed("3")).Return(0, errors.New("connection refused"))
+
+	attempts := map[int64]entities.WebhookAttempt{}
+	mockRepo.On("RecordWebhookAttempt", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		attempts[args.Get(1).(int64)] = args.Get(2).(entities.WebhookAttempt)
+	}).Return(nil)
+
+	webhooks := NewWebhooks(mockRepo, mockSender, []WebhookEndpoint{
+		{Name: "ci", URL: "https://ci.example.com/hook", Secret: "s3cret"},
+	}, testRetryPolicy, "", discardLogger())
+
+	// Act
+	result, err := webhooks.DeliverDue(context.Background(), 10)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryResult{Attempted: 4, Delivered: 1, Retrying: 1, Failed: 2}, result)
+	mockSender.AssertExpectations(t)
+
+	assert.Equal(t, entities.WebhookDelivered, attempts[1].Status)
+	assert.Empty(t, attempts[1].Error)
+
+	// Second failure: backoff doubled, then capped at MaxBackoff
+	assert.Equal(t, entities.WebhookPending, attempts[2].Status)
+	assert.Equal(t, "receiver responded with HTTP 503", attempts[2].Error)
+	assert.Equal(t, 90*time.Second, attempts[2].NextAttemptAt.Sub(attempts[2].AttemptedAt))
+
+	assert.Equal(t, entities.WebhookFailed, attempts[3].Status, "out of attempts")
+	assert.Equal(t, "could not reach the receiver", attempts[3].Error)
+
+	assert.Equal(t, entities.WebhookFailed, attempts[4].Status)
+	assert.Equal(t, "endpoint is no longer configured", attempts[4].Error)
+}
+
+func TestWebhooks_Backoff(t *testing.T) {
+	webhooks := NewWebhooks(nil, nil, nil, WebhookRetryPolicy{MaxAttempts: 10, Backoff: time.Minute, MaxBackoff: 10 * time.Minute}, "", discardLogger())
+
+	assert.Equal(t, time.Minute, webhooks.backoff(1))
+	assert.Equal(t, 2*time.Minute, webhooks.backoff(2))
+	assert.Equal(t, 8*time.Minute, webhooks.backoff(4))
+	assert.Equal(t, 10*time.Minute, webhooks.backoff(5))
+	assert.Equal(t, 10*time.Minute, webhooks.backoff(9))
+}
+
+func TestWebhooks_DeliveriesValidatesLimit(t *testing.T) {
+	mockRepo := new(MockWebhookDeliveryRepository)
+	mockRepo.On("ListWebhookDeliveries", mock.Anything, DefaultWebhookLogSize).Return([]entities.WebhookDelivery{}, nil)
+	webhooks := NewWebhooks(mockRepo, new(MockWebhookSender), nil, testRetryPolicy, "", discardLogger())
+
+	_, err := webhooks.Deliveries(context.Background(), 0)
+	require.NoError(t, err)
+
+	for _, limit := range []int{-1, MaxWebhookLogSize + 1} {
+		_, err := webhooks.Deliveries(context.Background(), limit)
+		assert.Equal(t, appErrors.ErrCodeValidation, appErrors.GetErrorCode(err), "limit %d", limit)
+	}
+	mockRepo.AssertExpectations(t)
+}
+
+func TestWebhookDispatcher_DeliverOncePrunes(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockWebhookDeliveryRepository)
+	mockRepo.On("DueWebhookDeliveries", mock.Anything, mock.Anything, 25).Return([]entities.WebhookDelivery{}, nil)
+	mockRepo.On("PruneWebhookDeliveries", mock.Anything, mock.MatchedBy(func(cutoff time.Time) bool {
+		return time.Since(cutoff) >= 24*time.Hour && time.Since(cutoff) < 25*time.Hour
+	})).Return(3, nil)
+
+	dispatcher := NewWebhookDispatcher(NewWebhooks(mockRepo, new(MockWebhookSender), nil, testRetryPolicy, "", discardLogger()), WebhookDispatcherConfig{
+		Interval:  time.Minute,
+		BatchSize: 25,
+		Keep:      24 * time.Hour,
+	}, discardLogger())
+
+	// Act
+	result, err := dispatcher.DeliverOnce(context.Background())
+
+	// Assert
+	require.NoError(t, err)
+	assert.Zero(t, result.Attempted)
+	mockRepo.AssertExpectations(t)
+}
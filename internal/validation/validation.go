@@ -1,8 +1,11 @@
 package validation
 
 import (
+	"fmt"
+	"net/url"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 
 	appErrors "github.com/uiansol/zentube/internal/errors"
 )
@@ -162,3 +165,175 @@ func ValidateEmail(email string) error {
 
 	return nil
 }
+
+// ValidateVideoID checks that id looks like a YouTube video ID
+// IDs are 11 characters from the URL-safe base64 alphabet
+func ValidateVideoID(id string) error {
+	const videoIDLength = 11
+	if len(id) != videoIDLength || !isBase64URL(id) {
+		return appErrors.NewValidationError("invalid video id", nil)
+	}
+	return nil
+}
+
+// ParseVideoRef extracts a video ID from a bare ID or a YouTube URL
+// Accepts watch?v=, youtu.be/, /shorts/, /embed/ and /live/ links
+func ParseVideoRef(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ValidateVideoID(ref) == nil {
+		return ref, nil
+	}
+
+	if !strings.Contains(ref, "://") {
+		ref = "https://" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", appErrors.NewValidationError("invalid video link", err)
+	}
+
+	var id string
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch host {
+	case "youtu.be":
+		id = strings.Trim(u.Path, "/")
+	case "youtube.com", "m.youtube.com", "music.youtube.com", "youtube-nocookie.com":
+		if u.Path == "/watch" {
+			id = u.Query().Get("v")
+			break
+		}
+		for _, prefix := range []string{"/shorts/", "/embed/", "/live/", "/v/"} {
+			if rest, ok := strings.CutPrefix(u.Path, prefix); ok {
+				id = strings.Trim(rest, "/")
+			}
+		}
+	default:
+		return "", appErrors.NewValidationError("not a YouTube link", nil)
+	}
+
+	if err := ValidateVideoID(id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// ValidateThumbnailURL checks that a thumbnail is an https image on YouTube's
+// image hosts (*.ytimg.com, img.youtube.com or *.ggpht.com); empty is allowed
+func ValidateThumbnailURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.User != nil {
+		return appErrors.NewValidationError("thumbnail must be an https YouTube image link", err)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "img.youtube.com" || strings.HasSuffix(host, ".ytimg.com") || strings.HasSuffix(host, ".ggpht.com") {
+		return nil
+	}
+	return appErrors.NewValidationError("thumbnail must be an https YouTube image link", nil)
+}
+
+// Limits for video titles and channel names that don't come from the YouTube API
+// YouTube itself caps titles at 100 characters; the rest is room for other sources
+const (
+	MaxVideoTitleLength  = 200
+	MaxChannelNameLength = 200
+)
+
+// ValidateVideoMetadata checks a title and channel name supplied by a client or
+// an imported file before they reach the shared video catalog
+// Both are length-capped and must not contain control characters
+func ValidateVideoMetadata(title, channel string) error {
+	for _, field := range []struct {
+		name  string
+		value string
+		max   int
+	}{
+		{"title", title, MaxVideoTitleLength},
+		{"channel", channel, MaxChannelNameLength},
+	} {
+		if utf8.RuneCountInString(field.value) > field.max {
+			return appErrors.NewValidationError(
+				fmt.Sprintf("%s must be at most %d characters", field.name, field.max), nil)
+		}
+		if strings.IndexFunc(field.value, unicode.IsControl) >= 0 {
+			return appErrors.NewValidationError(field.name+" cannot contain control characters", nil)
+		}
+	}
+	return nil
+}
+
+// ValidateChannelID checks that id looks like a YouTube channel ID
+// IDs are "UC" followed by 22 characters from the URL-safe base64 alphabet
+func ValidateChannelID(id string) error {
+	const channelIDLength = 24
+	if len(id) != channelIDLength || !strings.HasPrefix(id, "UC") || !isBase64URL(id) {
+		return appErrors.NewValidationError("invalid channel id", nil)
+	}
+	return nil
+}
+
+// ParseChannelRef normalizes a channel reference to a channel ID or an @handle
+// Accepts IDs, @handles and youtube.com/channel/ or youtube.com/@ links
+func ParseChannelRef(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if ValidateChannelID(ref) == nil {
+		return ref, nil
+	}
+	if strings.HasPrefix(ref, "@") {
+		return validateHandle(ref)
+	}
+
+	if !strings.Contains(ref, "://") {
+		ref = "https://" + ref
+	}
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", appErrors.NewValidationError("invalid channel link", err)
+	}
+
+	switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+	case "youtube.com", "m.youtube.com":
+	default:
+		return "", appErrors.NewValidationError("not a YouTube channel link", nil)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	switch {
+	case len(segments) >= 2 && segments[0] == "channel":
+		if err := ValidateChannelID(segments[1]); err != nil {
+			return "", err
+		}
+		return segments[1], nil
+	case strings.HasPrefix(segments[0], "@"):
+		return validateHandle(segments[0])
+	default:
+		return "", appErrors.NewValidationError("not a YouTube channel link", nil)
+	}
+}
+
+// validateHandle checks an @handle (3 to 30 letters, digits, '.', '_' or '-')
+func validateHandle(handle string) (string, error) {
+	name := strings.TrimPrefix(handle, "@")
+	if len(name) < 3 || len(name) > 30 {
+		return "", appErrors.NewValidationError("invalid channel handle", nil)
+	}
+	for _, r := range name {
+		if !(unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' || r == '_' || r == '-') {
+			return "", appErrors.NewValidationError("invalid channel handle", nil)
+		}
+	}
+	return "@" + name, nil
+}
+
+// isBase64URL reports whether s only uses the URL-safe base64 alphabet
+func isBase64URL(s string) bool {
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
@@ -2,10 +2,24 @@
 (function() {
   'use strict';
 
+  const YOUTUBE_ORIGIN = 'https://www.youtube.com';
+  const WATCH_ENDPOINT = '/events/watch';
+  const PROGRESS_EVERY = 15; // Watched seconds between progress events
+
+  // YouTube player states (see the IFrame Player API)
+  const STATE_PLAYING = 1;
+
   let player = null;
   let iframe = null;
   let playerTitle = null;
 
+  // The viewing being tracked: { id, seconds, reported, playing, position, duration }
+  // id is null until the server answers the open event; playing is null
+  // until the embed reports its state, and time counts while it is unknown.
+  // position and duration come from the embed, duration stays 0 if it never reports
+  let watch = null;
+  let ticker = null;
+
   // Initialize player on page load
   document.addEventListener('DOMContentLoaded', function() {
     player = document.getElementById('video-player');
@@ -18,13 +32,139 @@
         closeVideoPlayer();
       }
     });
+
+    // Ask the embed to report its state once it has loaded
+    if (iframe) {
+      iframe.addEventListener('load', function() {
+        if (watch && iframe.contentWindow) {
+          iframe.contentWindow.postMessage(JSON.stringify({ event: 'listening' }), YOUTUBE_ORIGIN);
+        }
+      });
+    }
+  });
+
+  // Player state updates from the embed
+  window.addEventListener('message', function(e) {
+    if (!watch || e.origin !== YOUTUBE_ORIGIN || !iframe || e.source !== iframe.contentWindow) return;
+
+    let data;
+    try {
+      data = JSON.parse(e.data);
+    } catch (err) {
+      return;
+    }
+
+    if (data.event === 'onStateChange' && typeof data.info === 'number') {
+      watch.playing = data.info === STATE_PLAYING;
+    } else if (data.event === 'infoDelivery' && data.info) {
+      if (typeof data.info.playerState === 'number') {
+        watch.playing = data.info.playerState === STATE_PLAYING;
+      }
+      if (typeof data.info.currentTime === 'number') {
+        watch.position = data.info.currentTime;
+      }
+      if (typeof data.info.duration === 'number') {
+        watch.duration = data.info.duration;
+      }
+    }
+  });
+
+  // Report progress before the tab is hidden (it may never come back) or left
+  document.addEventListener('visibilitychange', function() {
+    if (document.hidden) {
+      reportProgress(true);
+    }
+  });
+  window.addEventListener('pagehide', function() {
+    stopWatch();
   });
 
+  // Send a player event; beacons survive the page being closed
+  function sendWatchEvent(fields, beacon) {
+    const body = new URLSearchParams(fields);
+    if (beacon && navigator.sendBeacon) {
+      navigator.sendBeacon(WATCH_ENDPOINT, body);
+      return Promise.resolve(null);
+    }
+    return fetch(WATCH_ENDPOINT, { method: 'POST', body: body, keepalive: true })
+      .then(function(resp) { return resp.ok ? resp.json() : null; })
+      .catch(function() { return null; });
+  }
+
+  // Fields for progress and close events
+  function playbackFields(event, current) {
+    const fields = { event: event, id: current.id, watched_seconds: current.seconds };
+    if (current.duration > 0) {
+      fields.position = Math.floor(current.position);
+      fields.duration = Math.floor(current.duration);
+    }
+    return fields;
+  }
+
+  // Start tracking a viewing
+  // Resolves to the position to start playback at once the server answers
+  function startWatch(videoId, videoTitle) {
+    const current = { id: null, seconds: 0, reported: 0, playing: null, position: 0, duration: 0 };
+    watch = current;
+
+    const opened = sendWatchEvent({ event: 'open', video_id: videoId, title: videoTitle || '' }).then(function(resp) {
+      if (!resp || !resp.data) return 0;
+      current.id = resp.data.id;
+      // Closed before the server answered
+      if (watch !== current) {
+        sendWatchEvent(playbackFields('close', current), true);
+      }
+      return resp.data.resume_at || 0;
+    });
+
+    ticker = setInterval(function() {
+      if (document.hidden || current.playing === false) return;
+      current.seconds++;
+      if (current.seconds - current.reported >= PROGRESS_EVERY) {
+        reportProgress(false);
+      }
+    }, 1000);
+
+    return opened;
+  }
+
+  // Send the watched time so far, if it changed
+  function reportProgress(beacon) {
+    if (!watch || !watch.id || watch.seconds === watch.reported) return;
+    watch.reported = watch.seconds;
+    sendWatchEvent(playbackFields('progress', watch), beacon);
+  }
+
+  // Stop tracking the current viewing and close it
+  function stopWatch() {
+    clearInterval(ticker);
+    ticker = null;
+
+    const current = watch;
+    watch = null;
+    if (current && current.id) {
+      sendWatchEvent(playbackFields('close', current), true);
+    }
+  }
+
   // Open video player
   window.openVideoPlayer = function(videoId, videoTitle) {
     if (!player || !iframe) return;
-    
-    iframe.src = `https://www.youtube.com/embed/${videoId}?autoplay=1`;
+
+    stopWatch();
+
+    // Wait for the server so a partly watched video resumes where it stopped
+    const opening = startWatch(videoId, videoTitle);
+    const current = watch;
+    opening.then(function(resumeAt) {
+      if (watch !== current) return;
+      const origin = encodeURIComponent(window.location.origin);
+      let src = `https://www.youtube.com/embed/${videoId}?autoplay=1&enablejsapi=1&origin=${origin}`;
+      if (resumeAt > 0) {
+        src += `&start=${resumeAt}`;
+      }
+      iframe.src = src;
+    });
     
     if (playerTitle && videoTitle) {
       playerTitle.textContent = videoTitle;
@@ -41,6 +181,8 @@
   // Close video player
   window.closeVideoPlayer = function() {
     if (!player || !iframe) return;
+
+    stopWatch();
     
     player.classList.remove('active');
     iframe.src = '';
@@ -10,7 +10,9 @@ import templruntime "github.com/a-h/templ/runtime"
 
 import "github.com/uiansol/zentube/internal/entities"
 
-func SearchResults(videos []entities.Video) templ.Component {
+// SearchResults renders search results with where playback of each last stopped
+// progress may be nil when positions are unavailable, and intent when the search has none
+func SearchResults(videos []entities.Video, progress map[string]entities.VideoProgress, intent *entities.SearchIntent) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -35,6 +37,12 @@ func SearchResults(videos []entities.Video) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
+		if intent != nil {
+			templ_7745c5c3_Err = IntentPrompt(*intent).Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
+		}
 		if len(videos) == 0 {
 			templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "<p class=\"no-results\">No results found.</p>")
 			if templ_7745c5c3_Err != nil {
@@ -42,7 +50,7 @@ func SearchResults(videos []entities.Video) templ.Component {
 			}
 		} else {
 			for _, v := range videos {
-				templ_7745c5c3_Err = VideoResult(v).Render(ctx, templ_7745c5c3_Buffer)
+				templ_7745c5c3_Err = VideoResult(v, progress[v.ID]).Render(ctx, templ_7745c5c3_Buffer)
 				if templ_7745c5c3_Err != nil {
 					return templ_7745c5c3_Err
 				}
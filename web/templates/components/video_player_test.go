@@ -0,0 +1,32 @@
+package components
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+// injectedTitle tries to close the openVideoPlayer call and run its own script
+const injectedTitle = `x');alert(document.cookie);('`
+
+// render renders c to a string
+func render(t *testing.T, c templ.Component) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, c.Render(context.Background(), &buf))
+	return buf.String()
+}
+
+func TestPlayVideo_EscapesTitle(t *testing.T) {
+	// Act
+	html := render(t, VideoResult(entities.Video{ID: "dQw4w9WgXcQ", Title: injectedTitle}, entities.VideoProgress{}))
+
+	// Assert - the title stays one JSON string argument inside the attribute
+	assert.Contains(t, html, `onclick="openVideoPlayer(&#34;dQw4w9WgXcQ&#34;,&#34;x&#39;);alert(document.cookie);(&#39;&#34;)"`)
+	assert.NotContains(t, html, `'x');alert`)
+}
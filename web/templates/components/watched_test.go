@@ -0,0 +1,21 @@
+package components
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/uiansol/zentube/internal/entities"
+)
+
+func TestWatchedRow_EscapesTitle(t *testing.T) {
+	// Arrange - viewing titles are sent by the page, so anyone can report one
+	event := entities.WatchEvent{VideoID: "dQw4w9WgXcQ", Title: injectedTitle}
+
+	// Act
+	html := render(t, WatchedRow(event))
+
+	// Assert
+	assert.Contains(t, html, `onclick="openVideoPlayer(&#34;dQw4w9WgXcQ&#34;,&#34;x&#39;);alert(document.cookie);(&#39;&#34;)"`)
+	assert.NotContains(t, html, `'x');alert`)
+	assert.Contains(t, html, `x&#39;);alert(document.cookie);(&#39;</div>`, "the visible title is HTML-escaped")
+}
@@ -42,7 +42,7 @@ func Layout(title string) templ.Component {
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
-		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</title><link rel=\"stylesheet\" href=\"/static/css/styles.css\"><script src=\"/static/js/htmx.min.js\"></script><script src=\"/static/js/video-modal.js\"></script></head><body><div class=\"container\">")
+		templ_7745c5c3_Err = templruntime.WriteString(templ_7745c5c3_Buffer, 2, "</title><link rel=\"stylesheet\" href=\"/static/css/styles.css\"><script src=\"/static/js/htmx.min.js\"></script><script src=\"/static/js/video-modal.js\"></script></head><body><div class=\"container\"><nav class=\"site-nav\"><a href=\"/\">Search</a> <a href=\"/feed\">Feed</a> <a href=\"/saved\">Watch later</a> <a href=\"/saved-searches\">Saved searches</a> <a href=\"/collections\">Collections</a> <a href=\"/watched\">Watched</a> <a href=\"/intents\">Intents</a> <a href=\"/history\">History</a> <a href=\"/insights\">Insights</a></nav><div id=\"budget-bar\" hx-get=\"/budget/bar\" hx-trigger=\"load\" hx-swap=\"outerHTML\"></div>")
 		if templ_7745c5c3_Err != nil {
 			return templ_7745c5c3_Err
 		}
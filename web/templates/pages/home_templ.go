@@ -14,7 +14,8 @@ import (
 	"github.com/uiansol/zentube/web/templates/layouts"
 )
 
-func HomePage(query string, videos []entities.Video) templ.Component {
+// HomePage renders the search page; askIntent adds the intent field to the search form
+func HomePage(query string, intent *entities.SearchIntent, askIntent bool, videos []entities.Video, progress map[string]entities.VideoProgress) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -51,7 +52,7 @@ func HomePage(query string, videos []entities.Video) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = components.SearchForm(query).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.SearchForm(query, intentText(intent), askIntent).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -67,7 +68,7 @@ func HomePage(query string, videos []entities.Video) templ.Component {
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = components.SearchResults(videos).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.SearchResults(videos, progress, intent).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -81,4 +82,11 @@ func HomePage(query string, videos []entities.Video) templ.Component {
 	})
 }
 
+func intentText(intent *entities.SearchIntent) string {
+	if intent == nil {
+		return ""
+	}
+	return intent.Intent
+}
+
 var _ = templruntime.GeneratedTemplate